@@ -0,0 +1,208 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+
+// Package api defines the JSON contract of the bot's read-only endpoints.
+// Its types are kept separate from the structs bcc scrapes into so that
+// those can change without breaking external consumers such as widgets.
+//
+// Every response carries the contract's Version. Adding a field is a
+// compatible change and keeps the version; renaming or removing a field, or
+// changing its meaning, bumps it.
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+)
+
+// Version is the version of the JSON contract responses conform to.
+const Version = 1
+
+// dateLayout formats the dates of the contract.
+const dateLayout = "2006-01-02"
+
+// VersionResponse is the response of the version endpoint.
+type VersionResponse struct {
+	Version int `json:"version"`
+}
+
+// EventsResponse lists the club's upcoming events.
+type EventsResponse struct {
+	Version int     `json:"version"`
+	Events  []Event `json:"events"`
+}
+
+// Event is one of the club's events.
+type Event struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	// StartDate and EndDate are formatted as YYYY-MM-DD; they are the same
+	// for a one day event.
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+}
+
+// PairingsResponse lists the current round's pairings of an event.
+type PairingsResponse struct {
+	Version int `json:"version"`
+	EventID int `json:"eventId"`
+	// Predicted is set when the pairings have not been posted yet and are
+	// instead the bot's prediction of them.
+	Predicted bool      `json:"predicted"`
+	Pairings  []Pairing `json:"pairings"`
+}
+
+// Pairing is one board, or bye, of a round.
+type Pairing struct {
+	Section string `json:"section"`
+	Round   int    `json:"round"`
+	// Board is omitted for a bye.
+	Board int           `json:"board,omitempty"`
+	White *PairedPlayer `json:"white"`
+	// Black is omitted for a bye, whose player is White.
+	Black *PairedPlayer `json:"black,omitempty"`
+	Bye   bool          `json:"bye"`
+	// Result is the posted result as white's points then black's, e.g.
+	// "1-0" or "1/2-1/2", and for a bye only the bye's points, e.g. "1/2".
+	// It is omitted until posted.
+	Result string `json:"result,omitempty"`
+	// Forfeit is set when the result was decided by forfeit.
+	Forfeit bool `json:"forfeit,omitempty"`
+}
+
+// PairedPlayer is a player of a pairing.
+type PairedPlayer struct {
+	Name string `json:"name"`
+	// UscfID is omitted when the player has none.
+	UscfID string `json:"uscfId,omitempty"`
+	// Rating is 0 for an unrated player.
+	Rating int `json:"rating"`
+	// Score is the player's score before the round.
+	Score float64 `json:"score"`
+}
+
+// NewEventsResponse converts events, as returned by bcc.GetEvents, to their
+// contract form.
+func NewEventsResponse(events []bcc.Event) EventsResponse {
+	resp := EventsResponse{Version: Version, Events: make([]Event, 0,
+		len(events))}
+	for _, e := range events {
+		start := e.StartDate
+		if start.IsZero() {
+			start = e.Date
+		}
+		end := e.EndDate
+		if end.IsZero() {
+			end = start
+		}
+		resp.Events = append(resp.Events, Event{
+			ID:        e.EventID,
+			Title:     e.Title,
+			StartDate: formatDate(start),
+			EndDate:   formatDate(end),
+		})
+	}
+
+	return resp
+}
+
+// NewPairingsResponse converts the current pairings of event eventID to
+// their contract form.
+func NewPairingsResponse(eventID int, t *bcc.Tournament) PairingsResponse {
+	resp := PairingsResponse{
+		Version:   Version,
+		EventID:   eventID,
+		Predicted: t.IsPredicted(),
+		Pairings:  make([]Pairing, 0, len(t.CurrentPairings)),
+	}
+	for _, p := range t.CurrentPairings {
+		pairing := Pairing{
+			Section: p.Section,
+			Round:   p.RoundNumber,
+			Bye:     p.IsByePairing,
+		}
+		if p.IsByePairing {
+			if points, ok := p.ByePoints(); ok {
+				pairing.Result = formatPoints(points)
+			}
+			pairing.White = newPairedPlayer(p.ByePlayer())
+			resp.Pairings = append(resp.Pairings, pairing)
+			continue
+		}
+		pairing.Board = p.BoardNumber
+		pairing.White = newPairedPlayer(p.WhitePlayer)
+		pairing.Black = newPairedPlayer(p.BlackPlayer)
+		whitePoints, whiteOk := sidePoints(p.WhitePoints, p.WhiteOutcome)
+		blackPoints, blackOk := sidePoints(p.BlackPoints, p.BlackOutcome)
+		if whiteOk && blackOk {
+			pairing.Result = formatPoints(whitePoints) + "-" +
+				formatPoints(blackPoints)
+		}
+		pairing.Forfeit = isForfeit(p.WhiteOutcome) ||
+			isForfeit(p.BlackOutcome)
+		resp.Pairings = append(resp.Pairings, pairing)
+	}
+
+	return resp
+}
+
+func newPairedPlayer(p bcc.Player) *PairedPlayer {
+	player := &PairedPlayer{
+		Name:   p.DisplayName,
+		Rating: max(p.PrimaryRating, 0),
+		Score:  p.CurrentScore,
+	}
+	if p.UscfID > 0 {
+		player.UscfID = strconv.Itoa(p.UscfID)
+	}
+
+	return player
+}
+
+// sidePoints returns the points one side of a board scored, from its posted
+// points or else its parsed outcome, and whether they are known.
+func sidePoints(points *float64, outcome bcc.GameResult) (float64, bool) {
+	if points != nil {
+		return *points, true
+	}
+	switch outcome {
+	case bcc.ResultWin, bcc.ResultForfeitWin:
+		return 1, true
+	case bcc.ResultDraw:
+		return 0.5, true
+	case bcc.ResultLoss, bcc.ResultForfeitLoss:
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func isForfeit(outcome bcc.GameResult) bool {
+	return outcome == bcc.ResultForfeitWin || outcome == bcc.ResultForfeitLoss
+}
+
+// formatPoints formats points in ASCII, e.g. "1", "1/2" or "0".
+func formatPoints(points float64) string {
+	switch points {
+	case 0.5:
+		return "1/2"
+	case 1:
+		return "1"
+	case 0:
+		return "0"
+	}
+
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(dateLayout)
+}
@@ -0,0 +1,146 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+)
+
+// The golden JSON below is the contract consumers depend on. A change which
+// breaks one of these tests other than by adding a field must bump Version.
+
+func assertGolden(t *testing.T, v any, golden string) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("MarshalIndent() err = %v", err)
+	}
+	if string(got) != golden {
+		t.Errorf("JSON =\n%s\nwant\n%s", got, golden)
+	}
+}
+
+func TestVersionResponseJSON(t *testing.T) {
+	assertGolden(t, VersionResponse{Version: 1}, `{
+  "version": 1
+}`)
+	if Version != 1 {
+		t.Errorf("Version = %v; update the golden JSON of each response",
+			Version)
+	}
+}
+
+func TestEventsResponseJSON(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, time.March, d, 18, 30, 0, 0, time.UTC)
+	}
+	events := []bcc.Event{
+		{EventID: 1234, Title: "Tuesday Night Swiss", Date: day(3),
+			StartDate: day(3), EndDate: day(24), DayOfWeek: "Tuesday"},
+		{EventID: 1240, Title: "Saturday Quads", Date: day(7)},
+	}
+
+	assertGolden(t, NewEventsResponse(events), `{
+  "version": 1,
+  "events": [
+    {
+      "id": 1234,
+      "title": "Tuesday Night Swiss",
+      "startDate": "2026-03-03",
+      "endDate": "2026-03-24"
+    },
+    {
+      "id": 1240,
+      "title": "Saturday Quads",
+      "startDate": "2026-03-07",
+      "endDate": "2026-03-07"
+    }
+  ]
+}`)
+	assertGolden(t, NewEventsResponse(nil), `{
+  "version": 1,
+  "events": []
+}`)
+}
+
+func TestPairingsResponseJSON(t *testing.T) {
+	half, one := 0.5, 1.0
+	tourney := &bcc.Tournament{CurrentPairings: []bcc.Pairing{
+		{Section: "Open", RoundNumber: 3, BoardNumber: 1,
+			WhitePlayer: bcc.Player{DisplayName: "Alice Smith",
+				UscfID: 12345678, PrimaryRating: 2100, CurrentScore: 2},
+			BlackPlayer: bcc.Player{DisplayName: "Bob Jones",
+				UscfID: 23456789, PrimaryRating: 1950, CurrentScore: 1.5},
+			WhitePoints: &half, BlackPoints: &half},
+		{Section: "Open", RoundNumber: 3, BoardNumber: 2,
+			WhitePlayer: bcc.Player{DisplayName: "Carol White",
+				PrimaryRating: 1800, CurrentScore: 1},
+			BlackPlayer:  bcc.Player{DisplayName: "Dan Brown", CurrentScore: 1},
+			WhiteOutcome: bcc.ResultPending, BlackOutcome: bcc.ResultPending},
+		{Section: "Open", RoundNumber: 3, IsByePairing: true,
+			WhitePlayer: bcc.Player{DisplayName: "Eve Green",
+				UscfID: 34567890, PrimaryRating: 1700, CurrentScore: 0.5},
+			WhitePoints: &one},
+	}}
+
+	assertGolden(t, NewPairingsResponse(1234, tourney), `{
+  "version": 1,
+  "eventId": 1234,
+  "predicted": false,
+  "pairings": [
+    {
+      "section": "Open",
+      "round": 3,
+      "board": 1,
+      "white": {
+        "name": "Alice Smith",
+        "uscfId": "12345678",
+        "rating": 2100,
+        "score": 2
+      },
+      "black": {
+        "name": "Bob Jones",
+        "uscfId": "23456789",
+        "rating": 1950,
+        "score": 1.5
+      },
+      "bye": false,
+      "result": "1/2-1/2"
+    },
+    {
+      "section": "Open",
+      "round": 3,
+      "board": 2,
+      "white": {
+        "name": "Carol White",
+        "rating": 1800,
+        "score": 1
+      },
+      "black": {
+        "name": "Dan Brown",
+        "rating": 0,
+        "score": 1
+      },
+      "bye": false
+    },
+    {
+      "section": "Open",
+      "round": 3,
+      "white": {
+        "name": "Eve Green",
+        "uscfId": "34567890",
+        "rating": 1700,
+        "score": 0.5
+      },
+      "bye": true,
+      "result": "1"
+    }
+  ]
+}`)
+}
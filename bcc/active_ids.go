@@ -6,11 +6,15 @@ package bcc
 
 import (
 	"bufio"
+	"context"
 	_ "embed"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
 	uschess "github.com/mikeb26/uschess-go"
 )
 
@@ -22,11 +26,28 @@ var activePlayerTIds string
 
 // ActivePlayerMemIds returns USCF member IDs of individuals active since
 // 10/11/2024 (2024 October Friday Night Blitz event id 1200). This list is
-// up to date as of 7/2/2025
+// up to date as of 7/2/2025. See ActiveMemIdsSince for a current roster.
 func ActivePlayerMemIds() []uschess.MemberID {
 	return stringToIDSlice[uschess.MemberID]("memid", activePlayerMemIds)
 }
 
+// ActiveMemIdsSince returns USCF member IDs of individuals who played in the
+// club's rated events since the given date, derived from the club's
+// crosstables. When they cannot be retrieved the embedded
+// ActivePlayerMemIds list is returned instead.
+func ActiveMemIdsSince(ctx context.Context, client *uscfutils.Client,
+	since time.Time) []uschess.MemberID {
+
+	ids, err := client.GetActiveMembers(ctx, since)
+	if err != nil || len(ids) == 0 {
+		log.Printf("bcc: active members since %v unavailable; using embedded roster: %v",
+			since.Format(time.DateOnly), err)
+		return ActivePlayerMemIds()
+	}
+
+	return ids
+}
+
 // ActivePlayerTIds returns USCF event IDs for the 3 most recent tournaments
 // per active player.
 func ActivePlayerTIds() []uschess.EventID {
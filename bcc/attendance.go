@@ -0,0 +1,214 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+const (
+	// a change in average attendance between the earlier and later halves of
+	// a window smaller than this is reported as steady
+	attendanceTrendThreshold = 0.10
+)
+
+var (
+	// parenthesized qualifiers, e.g. "(Oct 2026)" or "(G/45 d5)"
+	seriesParenRegex = regexp.MustCompile(`\([^)]*\)`)
+	// tokens which vary from one event of a series to the next, e.g. "#12",
+	// "10/14", or "2026"
+	seriesNumberRegex = regexp.MustCompile(`\S*\d\S*`)
+	seriesMonthRegex  = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\b\.?`)
+)
+
+// AttendancePoint is the number of entries in one past event.
+type AttendancePoint struct {
+	EventID int
+	Title   string
+	Date    time.Time
+	Entries int
+}
+
+// AttendanceStats summarizes the entry counts of a set of events.
+type AttendanceStats struct {
+	Events int
+	Min    int
+	Max    int
+	Avg    float64
+	// Trend is the relative change in average attendance from the earlier
+	// half of the events to the later half, e.g. 0.25 for 25% growth.
+	Trend float64
+}
+
+// EventSeries returns the name of the series an event belongs to, derived
+// from its title by dropping the parts which vary between events of the same
+// series, e.g. "Tuesday Night Swiss #12 (Oct 2026)" becomes "Tuesday Night
+// Swiss".
+func EventSeries(title string) string {
+	series := seriesParenRegex.ReplaceAllString(title, " ")
+	series = seriesNumberRegex.ReplaceAllString(series, " ")
+	series = seriesMonthRegex.ReplaceAllString(series, " ")
+	series = strings.Join(strings.Fields(series), " ")
+	series = strings.Trim(series, " -:,")
+	if series == "" {
+		return title
+	}
+
+	return series
+}
+
+// GetAttendance fetches the entry counts of the given events concurrently.
+// Events without any entries (e.g. those not yet open for registration) and
+// events which fail to fetch are omitted. The result is ordered by date.
+func GetAttendance(ctx context.Context, events []Event) []AttendancePoint {
+	return getAttendanceWithLookup(ctx, events, GetEventDetail)
+}
+
+func getAttendanceWithLookup(ctx context.Context, events []Event,
+	lookup eventDetailLookup) []AttendancePoint {
+
+	eventIds := make([]int64, 0, len(events))
+	for _, ev := range events {
+		eventIds = append(eventIds, int64(ev.EventID))
+	}
+	details := getEventDetailsWithLookup(ctx, eventIds, lookup)
+
+	points := make([]AttendancePoint, 0, len(details))
+	seen := make(map[int]bool)
+	for _, ev := range events {
+		detail, ok := details[int64(ev.EventID)]
+		if !ok || detail.NumEntries <= 0 || seen[ev.EventID] {
+			continue
+		}
+		seen[ev.EventID] = true
+		points = append(points, AttendancePoint{
+			EventID: ev.EventID,
+			Title:   ev.Title,
+			Date:    ev.Date,
+			Entries: detail.NumEntries,
+		})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Date.Before(points[j].Date)
+	})
+
+	return points
+}
+
+// ComputeAttendanceStats summarizes points, which must be ordered by date.
+// Points without entries are excluded.
+func ComputeAttendanceStats(points []AttendancePoint) AttendanceStats {
+	counts := make([]int, 0, len(points))
+	for _, p := range points {
+		if p.Entries > 0 {
+			counts = append(counts, p.Entries)
+		}
+	}
+	var stats AttendanceStats
+	stats.Events = len(counts)
+	if stats.Events == 0 {
+		return stats
+	}
+
+	stats.Min, stats.Max = counts[0], counts[0]
+	total := 0
+	for _, c := range counts {
+		stats.Min = min(stats.Min, c)
+		stats.Max = max(stats.Max, c)
+		total += c
+	}
+	stats.Avg = float64(total) / float64(stats.Events)
+
+	if stats.Events >= 2 {
+		half := stats.Events / 2
+		earlier := averageOf(counts[:half])
+		later := averageOf(counts[stats.Events-half:])
+		stats.Trend = (later - earlier) / earlier
+	}
+
+	return stats
+}
+
+func averageOf(counts []int) float64 {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	return float64(total) / float64(len(counts))
+}
+
+// trendString describes an attendance trend, e.g. "growing (+25%)".
+func (s AttendanceStats) trendString() string {
+	switch {
+	case s.Events < 2:
+		return "not enough events for a trend"
+	case s.Trend >= attendanceTrendThreshold:
+		return fmt.Sprintf("growing (+%.0f%%)", s.Trend*100)
+	case s.Trend <= -attendanceTrendThreshold:
+		return fmt.Sprintf("declining (%.0f%%)", s.Trend*100)
+	default:
+		return fmt.Sprintf("steady (%+.0f%%)", s.Trend*100)
+	}
+}
+
+// BuildAttendanceOutput formats the entry counts of past events along with
+// their min/max/avg and whether attendance is growing or declining. When
+// bySeries is set events are grouped and summarized per series.
+func BuildAttendanceOutput(points []AttendancePoint, bySeries bool) string {
+	if len(points) == 0 {
+		return "No past events with entries found.\n"
+	}
+
+	groups := make(map[string][]AttendancePoint)
+	var names []string
+	for _, p := range points {
+		name := "All events"
+		if bySeries {
+			name = EventSeries(p.Title)
+		}
+		if _, ok := groups[name]; !ok {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], p)
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return len(groups[names[i]]) > len(groups[names[j]])
+	})
+
+	var sb strings.Builder
+	for _, name := range names {
+		group := groups[name]
+		maxTitle := 0
+		for _, p := range group {
+			maxTitle = max(maxTitle, internal.DisplayWidth(p.Title))
+		}
+
+		sb.WriteString(fmt.Sprintf("%v\n", name))
+		for _, p := range group {
+			sb.WriteString(fmt.Sprintf("  %v  %-*s  %3d\n",
+				p.Date.Format("2006-01-02"), maxTitle, p.Title, p.Entries))
+		}
+		stats := ComputeAttendanceStats(group)
+		sb.WriteString(fmt.Sprintf("  min %d, max %d, avg %.1f over %d %s; %v\n\n",
+			stats.Min, stats.Max, stats.Avg, stats.Events,
+			pluralEvents(stats.Events), stats.trendString()))
+	}
+
+	return sb.String()
+}
+
+func pluralEvents(n int) string {
+	if n == 1 {
+		return "event"
+	}
+	return "events"
+}
@@ -0,0 +1,95 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventSeries(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Tuesday Night Swiss #12 (Oct 2026)", "Tuesday Night Swiss"},
+		{"Tuesday Night Swiss #13", "Tuesday Night Swiss"},
+		{"Thursday Night Action - September 2026", "Thursday Night Action"},
+		{"Saturday Quads 10/18", "Saturday Quads"},
+		{"2026", "2026"},
+	}
+	for _, tc := range tests {
+		if got := EventSeries(tc.title); got != tc.want {
+			t.Errorf("EventSeries(%q) = %q; want %q", tc.title, got, tc.want)
+		}
+	}
+}
+
+func TestComputeAttendanceStats(t *testing.T) {
+	points := []AttendancePoint{
+		{Entries: 10}, {Entries: 0}, {Entries: 14}, {Entries: 16},
+		{Entries: 20},
+	}
+	stats := ComputeAttendanceStats(points)
+	if stats.Events != 4 || stats.Min != 10 || stats.Max != 20 ||
+		stats.Avg != 15 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	// earlier half averages 12, later half 18
+	if stats.Trend != 0.5 {
+		t.Errorf("Trend = %v; want 0.5", stats.Trend)
+	}
+	if got := stats.trendString(); got != "growing (+50%)" {
+		t.Errorf("trendString() = %q", got)
+	}
+}
+
+func TestGetAttendanceWithLookup(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{EventID: 3, Title: "Tuesday Night Swiss #3", Date: base.AddDate(0, 0, 14)},
+		{EventID: 1, Title: "Tuesday Night Swiss #1", Date: base},
+		{EventID: 2, Title: "Tuesday Night Swiss #2", Date: base.AddDate(0, 0, 7)},
+		{EventID: 4, Title: "Saturday Quads 9/5", Date: base.AddDate(0, 0, 4)},
+		{EventID: 5, Title: "Saturday Quads 9/12", Date: base.AddDate(0, 0, 11)},
+		{EventID: 6, Title: "Saturday Quads 9/19", Date: base.AddDate(0, 0, 18)},
+	}
+	entries := map[int64]int{1: 30, 2: 24, 3: 20, 4: 8, 5: 8, 6: 0}
+	lookup := func(_ context.Context, eventId int64) (EventDetail, error) {
+		if eventId == 5 {
+			return EventDetail{}, fmt.Errorf("boom")
+		}
+		return EventDetail{EventID: int(eventId),
+			NumEntries: entries[eventId]}, nil
+	}
+
+	points := getAttendanceWithLookup(context.Background(), events, lookup)
+	if len(points) != 4 {
+		t.Fatalf("got %v points; want 4: %+v", len(points), points)
+	}
+	for i := 1; i < len(points); i++ {
+		if points[i].Date.Before(points[i-1].Date) {
+			t.Errorf("points not ordered by date: %+v", points)
+		}
+	}
+
+	output := BuildAttendanceOutput(points, true)
+	for _, want := range []string{
+		"Tuesday Night Swiss\n",
+		"min 20, max 30, avg 24.7 over 3 events; declining (-33%)",
+		"Saturday Quads\n",
+		"not enough events for a trend",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "Tuesday") > strings.Index(output, "Saturday") {
+		t.Errorf("larger series should be listed first:\n%s", output)
+	}
+}
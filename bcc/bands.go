@@ -0,0 +1,94 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	ratingBandFloor = 1000
+	ratingBandCeil  = 2000
+	ratingBandWidth = 200
+)
+
+// RatingBandCount is the number of players within one rating band.
+type RatingBandCount struct {
+	Band  string
+	Count int
+}
+
+// EntryRatings returns the primary rating of each entry in an event. Unrated
+// entries are returned as 0.
+func EntryRatings(detail *EventDetail) []int {
+	ratings := make([]int, 0, len(detail.Entries))
+	for _, entry := range detail.Entries {
+		ratings = append(ratings, strRatingToInt(entry.PrimaryRating))
+	}
+
+	return ratings
+}
+
+// ratingBands returns the names of each rating band in ascending order,
+// starting with the bucket for unrated players.
+func ratingBands() []string {
+	bands := []string{"Unrated", fmt.Sprintf("Under %d", ratingBandFloor)}
+	for lo := ratingBandFloor; lo < ratingBandCeil; lo += ratingBandWidth {
+		bands = append(bands, fmt.Sprintf("%d-%d", lo, lo+ratingBandWidth-1))
+	}
+	bands = append(bands, fmt.Sprintf("%d+", ratingBandCeil))
+
+	return bands
+}
+
+// ratingBandIndex returns the index into ratingBands() for rating.
+func ratingBandIndex(rating int) int {
+	switch {
+	case rating <= 0:
+		return 0
+	case rating < ratingBandFloor:
+		return 1
+	case rating >= ratingBandCeil:
+		return len(ratingBands()) - 1
+	default:
+		return 2 + (rating-ratingBandFloor)/ratingBandWidth
+	}
+}
+
+// CountRatingBands buckets ratings into rating bands (Under 1000,
+// 1000-1199, ..., 2000+). Ratings <= 0 are counted as unrated.
+func CountRatingBands(ratings []int) []RatingBandCount {
+	bands := ratingBands()
+	counts := make([]RatingBandCount, len(bands))
+	for i, band := range bands {
+		counts[i].Band = band
+	}
+	for _, rating := range ratings {
+		counts[ratingBandIndex(rating)].Count++
+	}
+
+	return counts
+}
+
+// BuildRatingBandsOutput formats the number of players in each rating band.
+func BuildRatingBandsOutput(ratings []int) string {
+	var sb strings.Builder
+
+	counts := CountRatingBands(ratings)
+	bandWidth := len("Band")
+	for _, c := range counts {
+		if len(c.Band) > bandWidth {
+			bandWidth = len(c.Band)
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%-*s  %s\n", bandWidth, "Band", "Players"))
+	for _, c := range counts {
+		sb.WriteString(fmt.Sprintf("%-*s  %d\n", bandWidth, c.Band, c.Count))
+	}
+	sb.WriteString(fmt.Sprintf("%-*s  %d\n", bandWidth, "Total", len(ratings)))
+
+	return sb.String()
+}
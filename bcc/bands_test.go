@@ -0,0 +1,53 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountRatingBands(t *testing.T) {
+	detail := &EventDetail{
+		Entries: []Entry{
+			{PrimaryRating: ""},
+			{PrimaryRating: "850"},
+			{PrimaryRating: "1000"},
+			{PrimaryRating: "1199/12"},
+			{PrimaryRating: "1654P11"},
+			{PrimaryRating: "1999"},
+			{PrimaryRating: "2000"},
+			{PrimaryRating: "2450"},
+		},
+	}
+
+	want := map[string]int{
+		"Unrated":    1,
+		"Under 1000": 1,
+		"1000-1199":  2,
+		"1600-1799":  1,
+		"1800-1999":  1,
+		"2000+":      2,
+	}
+	counts := CountRatingBands(EntryRatings(detail))
+	if len(counts) != 8 {
+		t.Fatalf("expected 8 bands, got %v", len(counts))
+	}
+	for _, c := range counts {
+		if c.Count != want[c.Band] {
+			t.Errorf("band %q count = %v; want %v", c.Band, c.Count, want[c.Band])
+		}
+	}
+}
+
+func TestBuildRatingBandsOutput(t *testing.T) {
+	out := BuildRatingBandsOutput([]int{0, 1500, 1550})
+	if !strings.Contains(out, "1400-1599   2\n") {
+		t.Errorf("expected 2 players in 1400-1599, got:\n%v", out)
+	}
+	if !strings.Contains(out, "Total       3\n") {
+		t.Errorf("expected total of 3, got:\n%v", out)
+	}
+}
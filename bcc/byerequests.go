@@ -0,0 +1,218 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+var (
+	// "round", "rounds", "rnd", "rnds", "rd", "rds", optionally abbreviated
+	// with a period
+	byeKeywordRe = regexp.MustCompile(`\b(?:rounds?|rnds?|rds?)\.?`)
+	// "r1", "R3"
+	byeShortKeywordRe = regexp.MustCompile(`\br(\d)`)
+	byeSeparatorRe    = regexp.MustCompile(`\band\b|[,&;/+:]`)
+	// a round list following a keyword anywhere within free form text, e.g.
+	// "please give me a bye in round 1,5"
+	byeListRe = regexp.MustCompile(`\b(?:round|rnd|rounds|rnds)\b[\s:]*((?:\d+(?:\s*[,&;/]\s*\d+)*))`)
+	digitsRe  = regexp.MustCompile(`\d+`)
+)
+
+// ByeRequest is one entry's requested byes.
+type ByeRequest struct {
+	Name   string
+	Raw    string
+	Rounds []int
+	// NeedsReview is set when the request could not be parsed as a plain
+	// list of rounds, so the director should confirm it with the player.
+	NeedsReview bool
+}
+
+// ByeRequestSection lists the bye requests within one section.
+type ByeRequestSection struct {
+	Name     string
+	Requests []ByeRequest
+}
+
+// parseByeRequest parses a free form bye request such as "1", "rounds 2 & 4",
+// or "R1, R3" into the requested rounds in ascending order. ok is false when
+// the request is more than a list of rounds; any round list following a
+// "round" keyword is still returned in that case.
+func parseByeRequest(req string) (rounds []int, ok bool) {
+	s := strings.ToLower(strings.TrimSpace(req))
+	if s == "" {
+		return nil, true
+	}
+
+	list := byeKeywordRe.ReplaceAllString(s, " ")
+	list = byeShortKeywordRe.ReplaceAllString(list, " $1")
+	list = byeSeparatorRe.ReplaceAllString(list, " ")
+	fields := strings.Fields(list)
+	ok = len(fields) > 0
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n <= 0 {
+			ok = false
+			break
+		}
+		rounds = append(rounds, n)
+	}
+	if !ok {
+		rounds = nil
+		if matches := byeListRe.FindStringSubmatch(s); matches != nil {
+			for _, m := range digitsRe.FindAllString(matches[1], -1) {
+				if n, err := strconv.Atoi(m); err == nil && n > 0 {
+					rounds = append(rounds, n)
+				}
+			}
+		}
+	}
+
+	sort.Ints(rounds)
+	return slices.Compact(rounds), ok
+}
+
+// ByeRequestSummary lists the entries of an event which requested byes,
+// grouped by section and ordered by name. Requests which are not a plain list
+// of rounds are flagged for the director to confirm.
+func ByeRequestSummary(detail *EventDetail) []ByeRequestSection {
+	bySection := make(map[string][]ByeRequest)
+	for _, entry := range detail.Entries {
+		raw := strings.TrimSpace(entry.ByeRequests)
+		if raw == "" {
+			continue
+		}
+		rounds, ok := parseByeRequest(raw)
+		bySection[entry.SectionName] = append(bySection[entry.SectionName],
+			ByeRequest{
+				Name:        entryToPlayer(entry).DisplayName,
+				Raw:         raw,
+				Rounds:      rounds,
+				NeedsReview: !ok,
+			})
+	}
+
+	var sectionNames []string
+	for sec := range bySection {
+		sectionNames = append(sectionNames, sec)
+	}
+	sort.Sort(SectionSorter(sectionNames))
+
+	sections := make([]ByeRequestSection, 0, len(sectionNames))
+	for _, sec := range sectionNames {
+		requests := bySection[sec]
+		sort.SliceStable(requests, func(i, j int) bool {
+			return requests[i].Name < requests[j].Name
+		})
+		sections = append(sections, ByeRequestSection{Name: sec,
+			Requests: requests})
+	}
+
+	return sections
+}
+
+// BuildByeRequestsOutput formats a bye request summary as one line per
+// request, e.g. "  Alice Smith  rounds 1, 3", marking requests which need
+// review.
+func BuildByeRequestsOutput(sections []ByeRequestSection) string {
+	if len(sections) == 0 {
+		return "No bye requests.\n"
+	}
+
+	var sb strings.Builder
+	needsReview := false
+	for _, sec := range sections {
+		sb.WriteString(internal.DisplaySectionName(sec.Name) + "\n")
+
+		maxN := 0
+		for _, req := range sec.Requests {
+			maxN = max(maxN, internal.DisplayWidth(req.Name))
+		}
+		for _, req := range sec.Requests {
+			rounds := formatByeRounds(req.Rounds)
+			if req.NeedsReview {
+				rounds = fmt.Sprintf("%s ** please confirm: %q", rounds,
+					req.Raw)
+				needsReview = true
+			}
+			sb.WriteString(fmt.Sprintf("  %-*s  %s\n", maxN, req.Name, rounds))
+		}
+		sb.WriteString("\n")
+	}
+	if needsReview {
+		sb.WriteString("** request could not be fully parsed; confirm with the player\n")
+	}
+
+	return sb.String()
+}
+
+// formatByeRounds describes requested bye rounds, e.g. "round 2" or "rounds
+// 1, 3".
+func formatByeRounds(rounds []int) string {
+	switch len(rounds) {
+	case 0:
+		return "unknown rounds"
+	case 1:
+		return fmt.Sprintf("round %d", rounds[0])
+	default:
+		strs := make([]string, 0, len(rounds))
+		for _, r := range rounds {
+			strs = append(strs, strconv.Itoa(r))
+		}
+		return "rounds " + strings.Join(strs, ", ")
+	}
+}
+
+// MemberByeRequest returns the bye request of the entry with the given USCF
+// id. ok is false when no such member is entered in the event; a member who
+// requested no byes has a request with no Rounds.
+func MemberByeRequest(detail *EventDetail, uscfID int) (ByeRequest, bool) {
+	for _, entry := range detail.Entries {
+		if uscfID <= 0 || entry.UscfID != uscfID {
+			continue
+		}
+		raw := strings.TrimSpace(entry.ByeRequests)
+		rounds, ok := parseByeRequest(raw)
+		return ByeRequest{
+			Name:        entryToPlayer(entry).DisplayName,
+			Raw:         raw,
+			Rounds:      rounds,
+			NeedsReview: !ok,
+		}, true
+	}
+
+	return ByeRequest{}, false
+}
+
+// BuildMyByesOutput describes the byes a member requested in an event so
+// that they can confirm their request registered.
+func BuildMyByesOutput(detail *EventDetail, uscfID int) string {
+	req, ok := MemberByeRequest(detail, uscfID)
+	if !ok {
+		return fmt.Sprintf("USCF id %v is not entered in %v.\n", uscfID,
+			detail.Title)
+	}
+	if req.Raw == "" {
+		return fmt.Sprintf("%v has no bye requests registered for %v.\n",
+			req.Name, detail.Title)
+	}
+
+	out := fmt.Sprintf("%v requested byes for %v of %v.\n", req.Name,
+		formatByeRounds(req.Rounds), detail.Title)
+	if req.NeedsReview {
+		out += fmt.Sprintf("Your request %q could not be fully read; please confirm it with the tournament director.\n",
+			req.Raw)
+	}
+
+	return out
+}
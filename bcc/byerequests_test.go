@@ -0,0 +1,102 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseByeRequest(t *testing.T) {
+	tests := []struct {
+		req    string
+		rounds []int
+		ok     bool
+	}{
+		{"", nil, true},
+		{"1", []int{1}, true},
+		{"1,3", []int{1, 3}, true},
+		{"rounds 2 & 4", []int{2, 4}, true},
+		{"Rnd 5", []int{5}, true},
+		{"R1, R3", []int{1, 3}, true},
+		{"round 3 and round 1", []int{1, 3}, true},
+		{"please give me a bye in round 1,5", []int{1, 5}, false},
+		{"last round", nil, false},
+		{"0", nil, false},
+	}
+	for _, tc := range tests {
+		rounds, ok := parseByeRequest(tc.req)
+		if !reflect.DeepEqual(rounds, tc.rounds) || ok != tc.ok {
+			t.Errorf("parseByeRequest(%q) = %v, %v; want %v, %v", tc.req,
+				rounds, ok, tc.rounds, tc.ok)
+		}
+	}
+}
+
+func TestByeRequestSummary(t *testing.T) {
+	detail := &EventDetail{
+		Entries: []Entry{
+			{FirstName: "Zoe", LastName: "Last", SectionName: "Open",
+				ByeRequests: "rounds 2 & 4"},
+			{FirstName: "Alice", LastName: "First", SectionName: "Open",
+				ByeRequests: "1"},
+			{FirstName: "Bob", LastName: "None", SectionName: "Open"},
+			{FirstName: "Carol", LastName: "Vague", SectionName: "U1800",
+				ByeRequests: "last round"},
+		},
+	}
+
+	sections := ByeRequestSummary(detail)
+	if len(sections) != 2 || sections[0].Name != "Open" ||
+		len(sections[0].Requests) != 2 {
+		t.Fatalf("unexpected summary: %+v", sections)
+	}
+	if sections[0].Requests[0].Name != "Alice First" {
+		t.Errorf("requests not ordered by name: %+v", sections[0].Requests)
+	}
+	if !sections[1].Requests[0].NeedsReview {
+		t.Errorf("vague request not flagged: %+v", sections[1].Requests[0])
+	}
+
+	output := BuildByeRequestsOutput(sections)
+	for _, want := range []string{
+		"Open Section\n",
+		"Alice First  round 1\n",
+		"Zoe Last     rounds 2, 4\n",
+		`unknown rounds ** please confirm: "last round"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestBuildMyByesOutput(t *testing.T) {
+	detail := &EventDetail{Title: "Tuesday Night Swiss", Entries: []Entry{
+		{FirstName: "Alice", LastName: "Smith", UscfID: 10,
+			ByeRequests: "rounds 2 & 4"},
+		{FirstName: "Bob", LastName: "Jones", UscfID: 20},
+		{FirstName: "Carol", LastName: "White", UscfID: 30,
+			ByeRequests: "round 1, but maybe 3 too"},
+	}}
+
+	for _, tt := range []struct {
+		uscfID int
+		want   string
+	}{
+		{10, "Alice Smith requested byes for rounds 2, 4 of Tuesday Night Swiss.\n"},
+		{20, "Bob Jones has no bye requests registered for Tuesday Night Swiss.\n"},
+		{30, "please confirm it with the tournament director"},
+		{40, "USCF id 40 is not entered in Tuesday Night Swiss.\n"},
+	} {
+		if got := BuildMyByesOutput(detail, tt.uscfID); !strings.Contains(got,
+			tt.want) {
+
+			t.Errorf("BuildMyByesOutput(%v) = %q; want %q", tt.uscfID, got,
+				tt.want)
+		}
+	}
+}
@@ -5,9 +5,15 @@
 package bcc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
@@ -25,35 +31,203 @@ type Event struct {
 	DateDisplay string    `json:"dateDisplay"`
 }
 
-// GetEvents fetches events from the Boylston Chess API and returns a slice
+// eventsMemoTTL bounds how long GetEvents reuses the events list it last
+// fetched so that bursts of interactions share a single fetch.
+const eventsMemoTTL = 60 * time.Second
+
+// eventsMemo memoizes the events list in process, independent of any http
+// caching.
+type eventsMemo struct {
+	mu      sync.Mutex
+	fetch   func(context.Context) ([]Event, error)
+	now     func() time.Time
+	ttl     time.Duration
+	events  []Event
+	fetched time.Time
+}
+
+var defaultEventsMemo = &eventsMemo{
+	fetch: fetchEvents,
+	// read internal.Now on each call so that tests may pin the clock
+	now: func() time.Time { return internal.Now() },
+	ttl: eventsMemoTTL,
+}
+
+// get returns the memoized events list, fetching it when it is older than
+// the memo's ttl or when refresh is set. Callers receive their own copy.
+func (m *eventsMemo) get(ctx context.Context,
+	refresh bool) ([]Event, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if refresh || m.fetched.IsZero() || now.Sub(m.fetched) >= m.ttl {
+		events, err := m.fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		m.events = events
+		m.fetched = now
+	}
+
+	return slices.Clone(m.events), nil
+}
+
+// GetEvents returns the events from the Boylston Chess API. The list is
+// memoized for up to a minute; use RefreshEvents to bypass the memo.
+func GetEvents(ctx context.Context) ([]Event, error) {
+	return defaultEventsMemo.get(ctx, false)
+}
+
+// RefreshEvents is like GetEvents but always fetches a fresh events list.
+func RefreshEvents(ctx context.Context) ([]Event, error) {
+	return defaultEventsMemo.get(ctx, true)
+}
+
+// fetchEvents fetches events from the Boylston Chess API and returns a slice
 // of Event.
-func GetEvents() ([]Event, error) {
+func fetchEvents(ctx context.Context) ([]Event, error) {
 	const url = "https://beta.boylstonchess.org/api/events"
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch bcc events (new): %w", err)
 	}
 	req.Header.Set("User-Agent", internal.UserAgent)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := internal.HTTPClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch bcc events (do): %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unable to fetch bcc events (http): %v", resp.StatusCode)
+		return nil, fmt.Errorf("unable to fetch bcc events (http): %w",
+			internal.HTTPStatusError(resp.StatusCode))
 	}
 
-	var events []Event
-	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+	return decodeEvents(resp.Body)
+}
+
+// decodeEvents decodes an events list. Events which cannot be decoded, or
+// whose date is given but cannot be parsed, are skipped with a logged warning
+// rather than failing the whole list. Events whose date is empty or null are
+// kept; the site lists such events before their date is settled.
+func decodeEvents(r io.Reader) ([]Event, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
 		return nil, fmt.Errorf("unable to parse bcc events: %w", err)
 	}
 
+	events := make([]Event, 0, len(raw))
+	for idx, data := range raw {
+		var ev Event
+		if err := json.Unmarshal(data, &ev); err != nil {
+			log.Printf("bcc: events: skipping undecodable event %v: %v", idx,
+				err)
+			continue
+		}
+		if ev.Date.IsZero() && hasDate(data) {
+			log.Printf("bcc: events: skipping event %v (%v) with an unparseable date",
+				ev.EventID, ev.Title)
+			continue
+		}
+		events = append(events, ev)
+	}
+
 	return events, nil
 }
 
+// hasDate reports whether an encoded event gives a date, i.e. one which is
+// neither empty nor null.
+func hasDate(data []byte) bool {
+	var aux struct {
+		Date string `json:"date"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return false
+	}
+
+	return aux.Date != "" && aux.Date != "null"
+}
+
+// CalWindow returns the first and last dates (at local midnight) of a
+// calendar covering days days from today, per internal.Now. A negative
+// number of days looks back, ending today.
+func CalWindow(days int) (time.Time, time.Time) {
+	now := internal.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0,
+		now.Location())
+	end := today.AddDate(0, 0, days)
+	if end.Before(today) {
+		return end, today
+	}
+
+	return today, end
+}
+
+// EventsByDate groups the events whose date falls within [start, end], as
+// returned by CalWindow, by date ("2006-01-02"). Events are compared by their
+// date alone so that every event on the end date is included.
+func EventsByDate(events []Event, start time.Time,
+	end time.Time) map[string][]Event {
+
+	eventsByDate := make(map[string][]Event)
+	for _, ev := range events {
+		// truncate event date to local date for inclusive comparison
+		evDate := time.Date(ev.Date.Year(), ev.Date.Month(), ev.Date.Day(), 0,
+			0, 0, 0, start.Location())
+		if evDate.Before(start) || evDate.After(end) {
+			continue
+		}
+		key := ev.Date.Format("2006-01-02")
+		eventsByDate[key] = append(eventsByDate[key], ev)
+	}
+
+	return eventsByDate
+}
+
+// ParseWeekday parses a day of the week such as "tuesday" or "Tue",
+// ignoring case.
+func ParseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.HasPrefix(strings.ToLower(day.String()), s) {
+				return day, nil
+			}
+		}
+	}
+
+	return time.Sunday, fmt.Errorf("unknown day of the week %q", s)
+}
+
+// FilterEventsByWeekday returns the events which take place on day. A
+// multi-day event matches when any of its days falls on day.
+func FilterEventsByWeekday(events []Event, day time.Weekday) []Event {
+	filtered := make([]Event, 0, len(events))
+	for _, ev := range events {
+		start := ev.StartDate
+		if start.IsZero() {
+			start = ev.Date
+		}
+		end := ev.EndDate
+		if end.Before(start) {
+			end = start
+		}
+		// a week covers every day, so look no further than that
+		for d, n := start, 0; !d.After(end) && n < 7; d, n = d.AddDate(0, 0, 1), n+1 {
+			if d.Weekday() == day {
+				filtered = append(filtered, ev)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
+
 // Custom unmarshaller to handle non-RFC3339 timestamps, "null", and empty strings.
 func (e *Event) UnmarshalJSON(data []byte) error {
 	type Alias Event
@@ -68,21 +242,10 @@ func (e *Event) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return fmt.Errorf("Event unmarshal: %w", err)
 	}
-	var err error
-	// Parse Date
-	e.Date, err = internal.ParseDateOrZero(aux.Date)
-	if err != nil {
-		return fmt.Errorf("parsing Event.Date: %w", err)
-	}
-	// Parse StartDate
-	e.StartDate, err = internal.ParseDateOrZero(aux.StartDate)
-	if err != nil {
-		return fmt.Errorf("parsing Event.StartDate: %w", err)
-	}
-	// Parse EndDate
-	e.EndDate, err = internal.ParseDateOrZero(aux.EndDate)
-	if err != nil {
-		return fmt.Errorf("parsing Event.EndDate: %w", err)
-	}
+	// a malformed date leaves the field unset rather than failing the
+	// whole events list
+	e.Date = internal.ParseDateLenient(aux.Date, "Event.Date")
+	e.StartDate = internal.ParseDateLenient(aux.StartDate, "Event.StartDate")
+	e.EndDate = internal.ParseDateLenient(aux.EndDate, "Event.EndDate")
 	return nil
 }
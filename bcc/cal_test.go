@@ -5,11 +5,19 @@
 package bcc
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 )
 
 func TestGetEvents(t *testing.T) {
-	events, err := GetEvents()
+	events, err := GetEvents(context.Background())
 	if err != nil {
 		t.Fatalf("GetEvents returned error: %v", err)
 	}
@@ -38,3 +46,196 @@ func TestGetEvents(t *testing.T) {
 		t.Error("expected DateDisplay to be non-empty")
 	}
 }
+
+func TestEventsMemo(t *testing.T) {
+	fetches := 0
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	memo := &eventsMemo{
+		fetch: func(context.Context) ([]Event, error) {
+			fetches++
+			return []Event{{EventID: fetches}}, nil
+		},
+		now: func() time.Time { return now },
+		ttl: time.Minute,
+	}
+
+	expect := func(refresh bool, wantID int, wantFetches int) {
+		t.Helper()
+		events, err := memo.get(context.Background(), refresh)
+		if err != nil {
+			t.Fatalf("get() err = %v", err)
+		}
+		if len(events) != 1 || events[0].EventID != wantID {
+			t.Fatalf("get() = %+v; want event %v", events, wantID)
+		}
+		if fetches != wantFetches {
+			t.Fatalf("fetches = %v; want %v", fetches, wantFetches)
+		}
+		// callers own their copy
+		events[0].EventID = -1
+	}
+
+	expect(false, 1, 1)
+	now = now.Add(30 * time.Second)
+	expect(false, 1, 1)
+	expect(true, 2, 2)
+	now = now.Add(time.Minute)
+	expect(false, 3, 3)
+
+	memo.fetch = func(context.Context) ([]Event, error) {
+		return nil, errors.New("boom")
+	}
+	if _, err := memo.get(context.Background(), true); err == nil {
+		t.Fatalf("get() with failing fetch succeeded; want error")
+	}
+	now = now.Add(30 * time.Second)
+	expect(false, 3, 3)
+}
+
+func TestEventUnmarshalMalformedDates(t *testing.T) {
+	data := `[
+		{"eventId":1,"title":"Tuesday Night Swiss","date":"2026-03-10T19:00:00",
+		 "startDate":"sometime soon","endDate":"2026-03-31T23:00:00"},
+		{"eventId":2,"title":"Thursday Night Blitz","date":"2026-99-99",
+		 "startDate":null,"endDate":""}]`
+	var events []Event
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		t.Fatalf("Unmarshal() err = %v", err)
+	}
+	if len(events) != 2 || events[1].Title != "Thursday Night Blitz" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[0].Date.Day() != 10 || !events[0].StartDate.IsZero() ||
+		events[0].EndDate.Day() != 31 {
+		t.Errorf("unexpected dates: %+v", events[0])
+	}
+	if !events[1].Date.IsZero() {
+		t.Errorf("malformed date = %v; want zero", events[1].Date)
+	}
+
+	var detail EventDetail
+	if err := json.Unmarshal([]byte(`{"eventId":3,"startDate":"2026-04-01",
+		"endDate":"TBD","entries":[{"name":"Alice Smith",
+		"registrationDate":"yesterday"}]}`), &detail); err != nil {
+		t.Fatalf("Unmarshal() err = %v", err)
+	}
+	if detail.StartDate.IsZero() || !detail.EndDate.IsZero() ||
+		len(detail.Entries) != 1 || !detail.Entries[0].RegistrationDate.IsZero() {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+}
+
+func TestDecodeEventsSkipsBadEvents(t *testing.T) {
+	data := `[
+		{"eventId":1,"title":"Tuesday Night Swiss","date":"2026-03-10T19:00:00"},
+		{"eventId":2,"title":"Bad Date","date":"2026-99-99"},
+		{"eventId":"three","title":"Bad Id","date":"2026-03-12T19:00:00"},
+		{"eventId":4,"title":"No Date","date":null},
+		{"eventId":5,"title":"Thursday Night Blitz","date":"2026-03-12T19:00:00"},
+		{"eventId":6,"title":"Date TBD","date":""}]`
+	events, err := decodeEvents(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("decodeEvents() err = %v", err)
+	}
+	var ids []int
+	for _, ev := range events {
+		ids = append(ids, ev.EventID)
+	}
+	if want := []int{1, 4, 5, 6}; !slices.Equal(ids, want) {
+		t.Errorf("decodeEvents() kept events %v; want %v", ids, want)
+	}
+
+	if _, err := decodeEvents(strings.NewReader(`{"error":"oops"}`)); err == nil {
+		t.Errorf("decodeEvents() of a non-list succeeded")
+	}
+}
+
+func TestCalWindowBoundaries(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	fixed := time.Date(2026, time.March, 10, 15, 30, 0, 0, loc)
+	origNow := internal.Now
+	internal.Now = func() time.Time { return fixed }
+	defer func() { internal.Now = origNow }()
+
+	at := func(day int, hour int, minute int) Event {
+		return Event{EventID: day*10000 + hour*100 + minute,
+			Date: time.Date(2026, time.March, day, hour, minute, 0, 0, loc)}
+	}
+	events := []Event{
+		at(9, 23, 59),  // the day before today
+		at(10, 0, 0),   // earliest today
+		at(10, 9, 0),   // earlier today than now
+		at(17, 23, 59), // latest on the last day
+		at(18, 0, 0),   // the day after the last day
+	}
+
+	start, end := CalWindow(7)
+	byDate := EventsByDate(events, start, end)
+	if len(byDate) != 2 || len(byDate["2026-03-10"]) != 2 ||
+		len(byDate["2026-03-17"]) != 1 {
+		t.Errorf("EventsByDate(next 7 days) = %+v", byDate)
+	}
+
+	// looking back ends today
+	start, end = CalWindow(-1)
+	byDate = EventsByDate(events, start, end)
+	if len(byDate) != 2 || len(byDate["2026-03-09"]) != 1 ||
+		len(byDate["2026-03-10"]) != 2 {
+		t.Errorf("EventsByDate(past day) = %+v", byDate)
+	}
+
+	start, end = CalWindow(0)
+	if !start.Equal(end) || len(EventsByDate(events, start, end)) != 1 {
+		t.Errorf("CalWindow(0) = %v, %v", start, end)
+	}
+}
+
+func TestFilterEventsByWeekday(t *testing.T) {
+	day := func(d int) time.Time {
+		// March 3rd 2026 is a Tuesday
+		return time.Date(2026, time.March, d, 18, 30, 0, 0, time.UTC)
+	}
+	events := []Event{
+		{EventID: 1, Title: "Tuesday Night Swiss", Date: day(3)},
+		{EventID: 2, Title: "Thursday Blitz", Date: day(5)},
+		{EventID: 3, Title: "Weekend Open", Date: day(7), StartDate: day(7),
+			EndDate: day(8)},
+		{EventID: 4, Title: "Spring Camp", Date: day(9), StartDate: day(9),
+			EndDate: day(20)},
+		{EventID: 5, Title: "Next Tuesday", Date: day(10),
+			EndDate: day(1)}, // an end before the start is ignored
+	}
+	ids := func(events []Event) []int {
+		got := make([]int, 0, len(events))
+		for _, ev := range events {
+			got = append(got, ev.EventID)
+		}
+		return got
+	}
+
+	for _, tc := range []struct {
+		dow  string
+		want []int
+	}{
+		{"tuesday", []int{1, 4, 5}},
+		{"Thu", []int{2, 4}},
+		{"SUNDAY", []int{3, 4}},
+		{" sat ", []int{3, 4}},
+	} {
+		weekday, err := ParseWeekday(tc.dow)
+		if err != nil {
+			t.Fatalf("ParseWeekday(%q) err = %v", tc.dow, err)
+		}
+		got := ids(FilterEventsByWeekday(events, weekday))
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("FilterEventsByWeekday(%v) = %v; want %v", weekday, got,
+				tc.want)
+		}
+	}
+
+	for _, bad := range []string{"", "tu", "tuesdays", "someday"} {
+		if _, err := ParseWeekday(bad); err == nil {
+			t.Errorf("ParseWeekday(%q) succeeded", bad)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// PairingDiscrepancy describes one posted board (or predicted board with no
+// posted counterpart) which differs from the predicted pairings.
+type PairingDiscrepancy struct {
+	Section string
+	Round   int
+	// Board is the posted board number, or the predicted board number when
+	// Posted is empty. Byes have no board number.
+	Board     int
+	Posted    string
+	Predicted string
+	// ColorsReversed is set when the posted players are as predicted but
+	// with colors reversed.
+	ColorsReversed bool
+}
+
+// PredictTournament builds the predicted pairings of an event from its
+// entries, as GetTournament does before pairings are posted.
+func PredictTournament(ctx context.Context,
+	eventId int64) (*Tournament, error) {
+
+	return PredictTournamentWithOptions(ctx, eventId, PairingOptions{})
+}
+
+// PredictTournamentWithOptions builds the predicted pairings of an event from
+// its entries as PredictTournament does, adjusted by opts.
+func PredictTournamentWithOptions(ctx context.Context, eventId int64,
+	opts PairingOptions) (*Tournament, error) {
+
+	detail, err := GetEventDetail(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	return predictTournamentWithOptions(ctx, &detail, opts), nil
+}
+
+func predictTournament(ctx context.Context, detail *EventDetail) *Tournament {
+	return predictTournamentWithOptions(ctx, detail, PairingOptions{})
+}
+
+func predictTournamentWithOptions(ctx context.Context, detail *EventDetail,
+	opts PairingOptions) *Tournament {
+
+	detail.Entries = correctRound1PairingEntries(ctx, detail.Entries)
+	return eventDetailToTournamentWithOptions(detail, opts)
+}
+
+// ComparePairings compares posted pairings with predicted ones, section by
+// section, and returns each board where a player has a different opponent
+// or color than predicted. Only rounds present in both are compared, since
+// swiss pairings can only be predicted for round 1.
+func ComparePairings(predicted *Tournament,
+	posted *Tournament) []PairingDiscrepancy {
+
+	predictedByPlayer := make(map[string]Pairing)
+	predictedRounds := make(map[int]bool)
+	for _, p := range predicted.CurrentPairings {
+		predictedRounds[p.RoundNumber] = true
+		if p.IsByePairing {
+			predictedByPlayer[pairingPlayerKey(p, p.ByePlayer())] = p
+			continue
+		}
+		predictedByPlayer[pairingPlayerKey(p, p.WhitePlayer)] = p
+		predictedByPlayer[pairingPlayerKey(p, p.BlackPlayer)] = p
+	}
+
+	var discrepancies []PairingDiscrepancy
+	matched := make(map[string]bool)
+	for _, p := range posted.CurrentPairings {
+		if !predictedRounds[p.RoundNumber] {
+			continue
+		}
+		firstPlayer := p.WhitePlayer
+		if p.IsByePairing {
+			firstPlayer = p.ByePlayer()
+		}
+		expected, ok := predictedByPlayer[pairingPlayerKey(p, firstPlayer)]
+		if !ok && !p.IsByePairing {
+			expected, ok = predictedByPlayer[pairingPlayerKey(p, p.BlackPlayer)]
+		}
+		if ok {
+			matched[pairingKey(expected)] = true
+		}
+		if ok && samePairing(expected, p) {
+			continue
+		}
+		d := PairingDiscrepancy{
+			Section: p.Section,
+			Round:   p.RoundNumber,
+			Board:   p.BoardNumber,
+			Posted:  formatComparedPairing(p),
+		}
+		if ok {
+			d.Predicted = formatComparedPairing(expected)
+			d.ColorsReversed = !p.IsByePairing && !expected.IsByePairing &&
+				pairingPlayerKey(p, p.WhitePlayer) ==
+					pairingPlayerKey(expected, expected.BlackPlayer) &&
+				pairingPlayerKey(p, p.BlackPlayer) ==
+					pairingPlayerKey(expected, expected.WhitePlayer)
+		}
+		discrepancies = append(discrepancies, d)
+	}
+
+	postedRounds := make(map[int]bool)
+	for _, p := range posted.CurrentPairings {
+		postedRounds[p.RoundNumber] = true
+	}
+	for _, p := range predicted.CurrentPairings {
+		if !postedRounds[p.RoundNumber] || matched[pairingKey(p)] {
+			continue
+		}
+		discrepancies = append(discrepancies, PairingDiscrepancy{
+			Section:   p.Section,
+			Round:     p.RoundNumber,
+			Board:     p.BoardNumber,
+			Predicted: formatComparedPairing(p),
+		})
+	}
+
+	sort.SliceStable(discrepancies, func(i, j int) bool {
+		a, b := discrepancies[i], discrepancies[j]
+		if a.Section != b.Section {
+			return SectionSorter{a.Section, b.Section}.Less(0, 1)
+		}
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		// byes after boards
+		if (a.Board == 0) != (b.Board == 0) {
+			return b.Board == 0
+		}
+		return a.Board < b.Board
+	})
+
+	return discrepancies
+}
+
+// BuildComparePairingsOutput formats the differences between predicted and
+// posted pairings.
+func BuildComparePairingsOutput(posted *Tournament,
+	discrepancies []PairingDiscrepancy) string {
+
+	if posted.IsPredicted() || len(posted.CurrentPairings) == 0 {
+		return "Pairings are not yet posted; nothing to compare.\n"
+	}
+	if len(discrepancies) == 0 {
+		return "Posted pairings match the predicted pairings.\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%v posted pairing(s) differ from the prediction:\n",
+		len(discrepancies)))
+	for idx, d := range discrepancies {
+		if idx == 0 || d.Section != discrepancies[idx-1].Section ||
+			d.Round != discrepancies[idx-1].Round {
+			sb.WriteString(fmt.Sprintf("\n%v Round %v:\n",
+				internal.DisplaySectionName(d.Section), d.Round))
+		}
+		board := "Bye"
+		if d.Board > 0 {
+			board = fmt.Sprintf("Board %v", d.Board)
+		}
+		switch {
+		case d.ColorsReversed:
+			sb.WriteString(fmt.Sprintf("  %v: %v; colors reversed\n", board,
+				d.Posted))
+		case d.Posted == "":
+			sb.WriteString(fmt.Sprintf("  %v: predicted %v; not posted\n",
+				board, d.Predicted))
+		case d.Predicted == "":
+			sb.WriteString(fmt.Sprintf("  %v: %v; not predicted\n", board,
+				d.Posted))
+		default:
+			sb.WriteString(fmt.Sprintf("  %v: %v; predicted %v\n", board,
+				d.Posted, d.Predicted))
+		}
+	}
+
+	return sb.String()
+}
+
+// samePairing reports whether two pairings have the same players with the
+// same colors.
+func samePairing(a Pairing, b Pairing) bool {
+	if a.IsByePairing || b.IsByePairing {
+		return a.IsByePairing == b.IsByePairing &&
+			pairingPlayerKey(a, a.ByePlayer()) == pairingPlayerKey(b, b.ByePlayer())
+	}
+
+	return pairingPlayerKey(a, a.WhitePlayer) == pairingPlayerKey(b, b.WhitePlayer) &&
+		pairingPlayerKey(a, a.BlackPlayer) == pairingPlayerKey(b, b.BlackPlayer)
+}
+
+// pairingPlayerKey identifies a player within a section and round by USCF id
+// when known, or by name otherwise.
+func pairingPlayerKey(p Pairing, player Player) string {
+	id := strings.ToLower(strings.TrimSpace(player.DisplayName))
+	if player.UscfID > 0 {
+		id = fmt.Sprintf("%v", player.UscfID)
+	}
+
+	return fmt.Sprintf("%v/%v/%v", strings.ToLower(p.Section), p.RoundNumber,
+		id)
+}
+
+func pairingKey(p Pairing) string {
+	if p.IsByePairing {
+		return pairingPlayerKey(p, p.ByePlayer())
+	}
+
+	return pairingPlayerKey(p, p.WhitePlayer) + "-" +
+		pairingPlayerKey(p, p.BlackPlayer)
+}
+
+func formatComparedPairing(p Pairing) string {
+	if p.IsByePairing {
+		return fmt.Sprintf("%v (bye)", p.ByePlayer().DisplayName)
+	}
+
+	return fmt.Sprintf("%v (W) - %v (B)", p.WhitePlayer.DisplayName,
+		p.BlackPlayer.DisplayName)
+}
@@ -0,0 +1,89 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComparePairings(t *testing.T) {
+	alice := Player{DisplayName: "Alice Smith", UscfID: 1}
+	bob := Player{DisplayName: "Bob Jones", UscfID: 2}
+	carol := Player{DisplayName: "Carol White", UscfID: 3}
+	dan := Player{DisplayName: "Dan Brown", UscfID: 4}
+	eve := Player{DisplayName: "Eve Black"}
+	frank := Player{DisplayName: "Frank Green", UscfID: 6}
+	gina := Player{DisplayName: "Gina Gray", UscfID: 7}
+	hal := Player{DisplayName: "Hal Blue", UscfID: 8}
+
+	predicted := &Tournament{isPredicted: true, CurrentPairings: []Pairing{
+		{WhitePlayer: alice, BlackPlayer: bob, Section: "Open", RoundNumber: 1,
+			BoardNumber: 1},
+		{WhitePlayer: carol, BlackPlayer: dan, Section: "Open", RoundNumber: 1,
+			BoardNumber: 2},
+		{WhitePlayer: eve, Section: "Open", RoundNumber: 1, IsByePairing: true},
+		{WhitePlayer: frank, BlackPlayer: gina, Section: "U1800",
+			RoundNumber: 1, BoardNumber: 3},
+	}}
+	posted := &Tournament{
+		source: SourceAPI,
+		CurrentPairings: []Pairing{
+			// as predicted
+			{WhitePlayer: alice, BlackPlayer: bob, Section: "Open",
+				RoundNumber: 1, BoardNumber: 1},
+			// colors reversed
+			{WhitePlayer: dan, BlackPlayer: carol, Section: "Open",
+				RoundNumber: 1, BoardNumber: 2},
+			// matched by name, with the bye listed on black
+			{WhitePlayer: Player{DisplayName: "BYE"},
+				BlackPlayer: Player{DisplayName: "eve black"}, Section: "Open",
+				RoundNumber: 1, IsByePairing: true},
+			// different opponent
+			{WhitePlayer: frank, BlackPlayer: hal, Section: "U1800",
+				RoundNumber: 1, BoardNumber: 1},
+		},
+	}
+
+	got := ComparePairings(predicted, posted)
+	if len(got) != 2 {
+		t.Fatalf("ComparePairings() = %+v; want 2 discrepancies", got)
+	}
+	if got[0].Section != "Open" || got[0].Board != 2 || !got[0].ColorsReversed {
+		t.Errorf("unexpected discrepancy: %+v", got[0])
+	}
+	if got[1].Section != "U1800" || got[1].Board != 1 ||
+		got[1].ColorsReversed ||
+		got[1].Predicted != "Frank Green (W) - Gina Gray (B)" {
+		t.Errorf("unexpected discrepancy: %+v", got[1])
+	}
+
+	output := BuildComparePairingsOutput(posted, got)
+	for _, want := range []string{
+		"2 posted pairing(s) differ",
+		"Open Section Round 1:",
+		"Board 2: Dan Brown (W) - Carol White (B); colors reversed",
+		"Board 1: Frank Green (W) - Hal Blue (B); predicted Frank Green (W) - Gina Gray (B)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	// a withdrawn pair is reported as not posted; later rounds are skipped
+	posted.CurrentPairings = append(posted.CurrentPairings[:1],
+		posted.CurrentPairings[2:]...)
+	posted.CurrentPairings = append(posted.CurrentPairings, Pairing{
+		WhitePlayer: carol, BlackPlayer: dan, Section: "Open",
+		RoundNumber: 2, BoardNumber: 1})
+	got = ComparePairings(predicted, posted)
+	if len(got) != 2 || got[0].Posted != "" || got[0].Board != 2 {
+		t.Errorf("ComparePairings() = %+v", got)
+	}
+	if output := BuildComparePairingsOutput(predicted, nil); !strings.Contains(
+		output, "not yet posted") {
+		t.Errorf("unexpected output for predicted pairings: %q", output)
+	}
+}
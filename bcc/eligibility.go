@@ -0,0 +1,100 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// EligibilityIssue is an entrant whose rating is outside their section's
+// cap or floor.
+type EligibilityIssue struct {
+	Name    string
+	Section string
+	Rating  int
+	// Cap is the lowest rating which is ineligible for the section, e.g.
+	// 1800 for "U1800", when the entrant's rating is at or above it.
+	Cap int
+	// Floor is the lowest rating which is eligible for the section, e.g.
+	// 2000 for "Championship (2000+)", when the entrant's rating is below
+	// it.
+	Floor int
+}
+
+// SectionEligibilityIssues reports the entrants of an event whose primary
+// rating is at or above their section's rating cap, e.g. a 1900 in "U1800",
+// or below its rating floor, e.g. a 1500 in "Championship (2000+)", ordered
+// by section and name. Sections without a numeric cap or floor accept any
+// rating, and unrated entrants are eligible for every section.
+func SectionEligibilityIssues(detail *EventDetail) []EligibilityIssue {
+	sections := make(map[string]Section)
+	for _, section := range ParseSections(detail) {
+		sections[section.Name] = section
+	}
+
+	var issues []EligibilityIssue
+	for _, entry := range detail.Entries {
+		section, ok := sections[entry.SectionName]
+		if !ok {
+			// an entry's section may be missing from the advertised list
+			section.Cap, _ = sectionRatingCap(entry.SectionName)
+			section.Floor, _ = sectionRatingFloor(entry.SectionName)
+		}
+		rating := strRatingToInt(entry.PrimaryRating)
+		if rating <= 0 {
+			continue
+		}
+		issue := EligibilityIssue{
+			Name:    entryToPlayer(entry).DisplayName,
+			Section: entry.SectionName,
+			Rating:  rating,
+		}
+		switch {
+		case section.Cap > 0 && rating >= section.Cap:
+			issue.Cap = section.Cap
+		case section.Floor > 0 && rating < section.Floor:
+			issue.Floor = section.Floor
+		default:
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Section != issues[j].Section {
+			return SectionSorter{issues[i].Section, issues[j].Section}.Less(0, 1)
+		}
+		return issues[i].Name < issues[j].Name
+	})
+
+	return issues
+}
+
+// BuildEligibilityOutput formats section eligibility issues, one per line.
+func BuildEligibilityOutput(issues []EligibilityIssue) string {
+	if len(issues) == 0 {
+		return "All rated entrants are eligible for their sections.\n"
+	}
+
+	var sb strings.Builder
+	maxN := 0
+	for _, issue := range issues {
+		maxN = max(maxN, internal.DisplayWidth(issue.Name))
+	}
+	for _, issue := range issues {
+		if issue.Floor > 0 {
+			sb.WriteString(fmt.Sprintf("%-*s  %d in %v (must be at least %d)\n",
+				maxN, issue.Name, issue.Rating, issue.Section, issue.Floor))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("%-*s  %d in %v (must be under %d)\n",
+			maxN, issue.Name, issue.Rating, issue.Section, issue.Cap))
+	}
+
+	return sb.String()
+}
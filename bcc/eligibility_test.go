@@ -0,0 +1,76 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSectionEligibilityIssues(t *testing.T) {
+	detail := &EventDetail{Entries: []Entry{
+		{FirstName: "Alice", LastName: "Smith", SectionName: "Open",
+			PrimaryRating: "2200"},
+		{FirstName: "Bob", LastName: "Jones", SectionName: "U1800",
+			PrimaryRating: "1900"},
+		{FirstName: "Carol", LastName: "White", SectionName: "U1800",
+			PrimaryRating: "1799"},
+		{FirstName: "Dan", LastName: "Brown", SectionName: "U1800",
+			PrimaryRating: "1800"},
+		{FirstName: "Eve", LastName: "Black", SectionName: "U1200",
+			PrimaryRating: ""},
+	}}
+
+	issues := SectionEligibilityIssues(detail)
+	if len(issues) != 2 || issues[0].Name != "Bob Jones" ||
+		issues[1].Name != "Dan Brown" || issues[1].Cap != 1800 {
+		t.Fatalf("SectionEligibilityIssues() = %+v", issues)
+	}
+	output := BuildEligibilityOutput(issues)
+	if !strings.Contains(output, "Bob Jones  1900 in U1800 (must be under 1800)\n") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	detail.Entries = detail.Entries[:1]
+	if output := BuildEligibilityOutput(SectionEligibilityIssues(detail)); !strings.Contains(output, "All rated entrants") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
+
+func TestSectionEligibilityFloorsAndCaps(t *testing.T) {
+	detail := &EventDetail{
+		Sections: []string{"Championship (2000+)", "1600-1999", "U1600"},
+		Entries: []Entry{
+			{FirstName: "Alice", LastName: "Smith",
+				SectionName: "Championship (2000+)", PrimaryRating: "1500"},
+			{FirstName: "Bob", LastName: "Jones",
+				SectionName: "Championship (2000+)", PrimaryRating: "2000"},
+			{FirstName: "Carol", LastName: "White", SectionName: "1600-1999",
+				PrimaryRating: "2050"},
+			{FirstName: "Dan", LastName: "Brown", SectionName: "1600-1999",
+				PrimaryRating: "1550"},
+			{FirstName: "Eve", LastName: "Black", SectionName: "U1600",
+				PrimaryRating: "1650"},
+			{FirstName: "Frank", LastName: "Green",
+				SectionName: "Championship (2000+)", PrimaryRating: ""},
+		},
+	}
+
+	issues := SectionEligibilityIssues(detail)
+	if len(issues) != 4 {
+		t.Fatalf("SectionEligibilityIssues() = %+v; want 4 issues", issues)
+	}
+	output := BuildEligibilityOutput(issues)
+	for _, want := range []string{
+		"Alice Smith  1500 in Championship (2000+) (must be at least 2000)\n",
+		"Carol White  2050 in 1600-1999 (must be under 2000)\n",
+		"Dan Brown    1550 in 1600-1999 (must be at least 1600)\n",
+		"Eve Black    1650 in U1600 (must be under 1600)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
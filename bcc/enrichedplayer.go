@@ -0,0 +1,83 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// EnrichedPlayer combines a club entry with the player's US Chess record,
+// for features which check or fill in what was registered with what US Chess
+// has on file. Fields US Chess knows take precedence over what was entered;
+// see MergePlayer.
+type EnrichedPlayer struct {
+	// UscfID is 0 when neither the entry nor US Chess has an id.
+	UscfID    int
+	FirstName string
+	LastName  string
+	Section   string
+	// EntryRating is the rating the player registered with, 0 if unrated.
+	EntryRating int
+	// Rating is the official Regular rating when US Chess has the player,
+	// and EntryRating otherwise. 0 is unrated.
+	Rating int
+	FideID string
+	// EventCount is the number of rated events US Chess lists for the
+	// player, when their events were fetched.
+	EventCount int
+	// Expiration is when the player's US Chess membership expires, zero if
+	// unknown.
+	Expiration time.Time
+	// Found is set when US Chess has a record of the player.
+	Found bool
+}
+
+// MergePlayer enriches a club entry with official, the player's US Chess
+// record as fetched by uscfutils.Client.GetPlayer or FetchRatingsOnly, which
+// may be nil when there is none. Official's name, Regular rating (even when
+// unrated) and FIDE id take precedence over the entry's; the entry's USCF id
+// and section are kept, with official's id filling in a missing one.
+func MergePlayer(entry Entry, official *uschess.Player) EnrichedPlayer {
+	rating := strRatingToInt(entry.PrimaryRating)
+	merged := EnrichedPlayer{
+		UscfID:      max(entry.UscfID, 0),
+		FirstName:   strings.TrimSpace(entry.FirstName),
+		LastName:    strings.TrimSpace(entry.LastName),
+		Section:     entry.SectionName,
+		EntryRating: rating,
+		Rating:      rating,
+	}
+	if official == nil {
+		return merged
+	}
+
+	merged.Found = true
+	if merged.UscfID == 0 {
+		merged.UscfID, _ = strconv.Atoi(string(official.Id))
+	}
+	if first := strings.TrimSpace(official.FirstName); first != "" {
+		merged.FirstName = first
+	}
+	if last := strings.TrimSpace(official.LastName); last != "" {
+		merged.LastName = last
+	}
+	merged.Rating = uscfutils.OfficialRegularRating(official)
+	merged.FideID = string(official.FideId)
+	merged.EventCount = len(official.MemberEvents)
+	merged.Expiration = official.ExpirationDate.Time
+
+	return merged
+}
+
+// Expired reports whether the player's US Chess membership had expired as of
+// now. An unknown expiration is not expired.
+func (p EnrichedPlayer) Expired(now time.Time) bool {
+	return !p.Expiration.IsZero() && p.Expiration.Before(now)
+}
@@ -0,0 +1,63 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestMergePlayer(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := Entry{FirstName: " Bob ", LastName: "Jones", UscfID: 12345678,
+		SectionName: "U1800", PrimaryRating: "1650"}
+
+	// without a US Chess record the entry stands as registered
+	merged := MergePlayer(entry, nil)
+	want := EnrichedPlayer{UscfID: 12345678, FirstName: "Bob",
+		LastName: "Jones", Section: "U1800", EntryRating: 1650, Rating: 1650}
+	if merged != want {
+		t.Errorf("MergePlayer(nil) = %+v; want %+v", merged, want)
+	}
+
+	// US Chess's name, rating, and FIDE id win; the section is the club's
+	official := &uschess.Player{
+		MemberDetail: uschess.MemberDetail{
+			Id: "12345678", FirstName: "Robert", LastName: "Jones",
+			FideId: "2000123",
+			Ratings: []uschess.MemberRating{
+				{RatingType: uschess.RatingTypeQ, Rating: 1600},
+				{RatingType: uschess.RatingTypeR, Rating: 1702}},
+			ExpirationDate: openapi_types.Date{Time: now.AddDate(0, 0, -1)},
+		},
+		MemberEvents: make([]uschess.RatedEvent, 42),
+	}
+	merged = MergePlayer(entry, official)
+	want = EnrichedPlayer{UscfID: 12345678, FirstName: "Robert",
+		LastName: "Jones", Section: "U1800", EntryRating: 1650, Rating: 1702,
+		FideID: "2000123", EventCount: 42,
+		Expiration: official.ExpirationDate.Time, Found: true}
+	if merged != want {
+		t.Errorf("MergePlayer() = %+v; want %+v", merged, want)
+	}
+	if !merged.Expired(now) || merged.Expired(now.AddDate(0, 0, -2)) {
+		t.Errorf("Expired() wrong around %v", merged.Expiration)
+	}
+
+	// an official record without a rating makes the player unrated, and
+	// one without a name keeps the entry's; a missing id is filled in
+	entry.UscfID = 0
+	merged = MergePlayer(entry, &uschess.Player{
+		MemberDetail: uschess.MemberDetail{Id: "12345678"}})
+	if merged.Rating != 0 || merged.EntryRating != 1650 ||
+		merged.FirstName != "Bob" || merged.UscfID != 12345678 ||
+		merged.Expired(now) {
+
+		t.Errorf("MergePlayer(unrated) = %+v", merged)
+	}
+}
@@ -7,11 +7,36 @@ package bcc
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
 )
 
-// buildEntriesOutput formats entries into grouped, aligned string output
-func BuildEntriesOutput(t *Tournament) string {
+// buildEntriesOutput formats entries into grouped, aligned string output.
+// With maskIDs only the last 4 digits of each USCF id are shown, for output
+// which is shared publicly.
+func BuildEntriesOutput(t *Tournament, maskIDs bool) string {
+	return buildEntriesOutput(t, nil, maskIDs)
+}
+
+// BuildEntriesOutputWithNewcomers is like BuildEntriesOutput but also notes
+// whether each entrant is returning to the club or playing here for the
+// first time, i.e. whether they are among clubPlayers (e.g. as returned by
+// uscfutils.GetAffiliatePlayers).
+func BuildEntriesOutputWithNewcomers(t *Tournament,
+	clubPlayers map[uschess.MemberID]bool) string {
+
+	if clubPlayers == nil {
+		clubPlayers = make(map[uschess.MemberID]bool)
+	}
+	return buildEntriesOutput(t, clubPlayers, false)
+}
+
+func buildEntriesOutput(t *Tournament,
+	clubPlayers map[uschess.MemberID]bool, maskIDs bool) string {
+
 	secPlayers := getPlayersBySection(t)
 	// Sort section names using custom criteria
 	var sectionNames []string
@@ -26,54 +51,80 @@ func BuildEntriesOutput(t *Tournament) string {
 		list := secPlayers[sec]
 
 		type row struct {
-			player, rating   string
-			memid, ratingInt int
+			player, rating, club, memid string
+			ratingInt                   int
 		}
 		var rows []row
 		for _, player := range list {
 			n := player.DisplayName
-			r := "unrated"
-			if player.PrimaryRating != 0 {
-				r = fmt.Sprintf("%v", player.PrimaryRating)
-			}
+			r := displayRating(*player)
 			id := player.UscfID
-			rows = append(rows, row{player: n, rating: r, memid: id,
-				ratingInt: player.PrimaryRating})
+			club := "first time"
+			if clubPlayers[uschess.MemberID(strconv.Itoa(id))] {
+				club = "returning"
+			}
+			memid := strconv.Itoa(id)
+			if maskIDs {
+				memid = internal.MaskMemberID(memid)
+			}
+			rows = append(rows, row{player: n, rating: r, club: club,
+				memid: memid, ratingInt: player.PrimaryRating})
 		}
 
-		sort.Slice(rows, func(i, j int) bool {
+		sort.SliceStable(rows, func(i, j int) bool {
 			return rows[i].ratingInt > rows[j].ratingInt
 		})
 
 		// Compute column widths
 		maxP, maxR, maxM := len("Player"), len("Rating"), len("USCF memid")
 		for _, r := range rows {
-			if l := len(r.player); l > maxP {
+			if l := internal.DisplayWidth(r.player); l > maxP {
 				maxP = l
 			}
-			if l := len(r.rating); l > maxR {
+			if l := internal.DisplayWidth(r.rating); l > maxR {
 				maxR = l
 			}
-			if l := len(fmt.Sprintf("%v", r.memid)); l > maxM {
+			if l := internal.DisplayWidth(r.memid); l > maxM {
 				maxM = l
 			}
 		}
 
 		// Write section header and table
 		if len(sectionNames) > 1 {
-			if sec == "" {
-				sec = "UNNAMED"
-			}
-			sb.WriteString(fmt.Sprintf("%s Section\n", sec))
+			sb.WriteString(internal.DisplaySectionName(sec) + "\n")
+		}
+		if clubPlayers == nil {
+			sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s\n", maxP, "Player",
+				maxR, "Rating", maxM, "USCF memid"))
+		} else {
+			sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s  %s\n", maxP,
+				"Player", maxR, "Rating", maxM, "USCF memid", "Club"))
 		}
-		sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s\n", maxP, "Player", maxR,
-			"Rating", maxM, "USCF memid"))
 		for _, r := range rows {
-			sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*v\n", maxP, r.player,
-				maxR, r.rating, maxM, r.memid))
+			if clubPlayers == nil {
+				sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*v\n", maxP, r.player,
+					maxR, r.rating, maxM, r.memid))
+			} else {
+				sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*v  %s\n", maxP,
+					r.player, maxR, r.rating, maxM, r.memid, r.club))
+			}
 		}
 		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
+
+// EntryRatingsByID returns the rating each entrant of detail registered
+// with, keyed by USCF id, for those with both. For a player unrated in US
+// Chess this may be a rating they reported themselves.
+func EntryRatingsByID(detail *EventDetail) map[uschess.MemberID]int {
+	ratings := make(map[uschess.MemberID]int)
+	for _, entry := range detail.Entries {
+		if r := strRatingToInt(entry.PrimaryRating); entry.UscfID > 0 && r > 0 {
+			ratings[uschess.MemberID(strconv.Itoa(entry.UscfID))] = r
+		}
+	}
+
+	return ratings
+}
@@ -0,0 +1,137 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestBuildEntriesOutputDeterministic(t *testing.T) {
+	players := []Player{
+		{DisplayName: "Alice Smith", UscfID: 30, PrimaryRating: 1500, SectionName: "Open"},
+		{DisplayName: "Bob Jones", UscfID: 10, PrimaryRating: 1500, SectionName: "Open"},
+		{DisplayName: "Carol White", PrimaryRating: 1500, SectionName: "Open"},
+		{DisplayName: "Dan Brown", UscfID: 20, PrimaryRating: 1600, SectionName: "Open"},
+		// duplicate listing of the same player via another pairing
+		{DisplayName: "Bob Jones", UscfID: 10, PrimaryRating: 1500, SectionName: "Open"},
+	}
+
+	var want string
+	for run := 0; run < len(players); run++ {
+		rotated := append(append([]Player(nil), players[run:]...),
+			players[:run]...)
+		got := BuildEntriesOutput(&Tournament{Players: rotated}, false)
+		if run == 0 {
+			want = got
+			continue
+		}
+		if got != want {
+			t.Fatalf("run %v: BuildEntriesOutput() =\n%v\nwant\n%v", run, got,
+				want)
+		}
+	}
+
+	if n := strings.Count(want, "Bob Jones"); n != 1 {
+		t.Errorf("Bob Jones listed %v times; want 1:\n%v", n, want)
+	}
+	order := []string{"Dan Brown", "Carol White", "Bob Jones", "Alice Smith"}
+	last := -1
+	for _, name := range order {
+		idx := strings.Index(want, name)
+		if idx < last {
+			t.Errorf("%v out of order:\n%v", name, want)
+		}
+		last = idx
+	}
+}
+
+func TestBuildEntriesOutputWithNewcomers(t *testing.T) {
+	tourney := &Tournament{Players: []Player{
+		{DisplayName: "Alice Smith", UscfID: 30, PrimaryRating: 1600},
+		{DisplayName: "Bob Jones", UscfID: 10, PrimaryRating: 1500},
+	}}
+	output := BuildEntriesOutputWithNewcomers(tourney,
+		map[uschess.MemberID]bool{"30": true})
+	for _, want := range []string{
+		"USCF memid  Club\n",
+		"Alice Smith  1600    30          returning\n",
+		"Bob Jones    1500    10          first time\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+	if strings.Contains(BuildEntriesOutput(tourney, false), "Club") {
+		t.Errorf("BuildEntriesOutput() unexpectedly notes newcomers")
+	}
+}
+
+func TestBuildEntriesOutputMaskIDs(t *testing.T) {
+	tourney := &Tournament{Players: []Player{
+		{DisplayName: "Alice Smith", UscfID: 12345678, PrimaryRating: 1600},
+		{DisplayName: "Bob Jones", UscfID: 30, PrimaryRating: 1500},
+	}}
+	output := BuildEntriesOutput(tourney, true)
+	for _, want := range []string{
+		"Alice Smith  1600    ****5678  \n",
+		"Bob Jones    1500    30        \n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+	if !strings.Contains(BuildEntriesOutput(tourney, false), "12345678") {
+		t.Errorf("BuildEntriesOutput() masked ids when not asked to")
+	}
+}
+
+func TestEntriesReportedRatings(t *testing.T) {
+	page := `<table id="members"><tbody>
+<tr><td>1</td><td>ALICE SMITH</td><td>1650</td><td>10</td></tr>
+<tr><td>2</td><td>BOB JONES</td><td>1500*</td><td>20</td></tr>
+<tr><td>3</td><td>CAROL WHITE</td><td>Unrated</td><td>30</td></tr>
+</tbody></table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("NewDocumentFromReader: %v", err)
+	}
+	var tourney Tournament
+	if err := parsePlayers(doc, &tourney); err != nil {
+		t.Fatalf("parsePlayers: %v", err)
+	}
+	if len(tourney.Players) != 3 || tourney.Players[0].RatingReported ||
+		!tourney.Players[1].RatingReported ||
+		tourney.Players[1].PrimaryRating != 1500 ||
+		tourney.Players[2].RatingReported {
+
+		t.Fatalf("players = %+v", tourney.Players)
+	}
+
+	output := buildEntriesOutput(&tourney, nil, false)
+	for _, want := range []string{"1650 ", "1500*", "unrated"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("entries output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "1650*") {
+		t.Errorf("official rating marked as reported:\n%s", output)
+	}
+
+	tourney.CurrentPairings = []Pairing{{WhitePlayer: tourney.Players[0],
+		BlackPlayer: tourney.Players[1], RoundNumber: 1, BoardNumber: 1}}
+	output = BuildPairingsOutput(&tourney, false, "", 0)
+	if !strings.Contains(output, "Bob Jones(1500* 0)") {
+		t.Errorf("pairings output missing reported rating:\n%s", output)
+	}
+
+	// the marker survives the round trip through an Entry
+	if p := entryToPlayer(playerToEntry(tourney.Players[1])); !p.RatingReported {
+		t.Errorf("entryToPlayer(playerToEntry()) lost the reported marker")
+	}
+}
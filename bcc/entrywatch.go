@@ -0,0 +1,123 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultEntryWatchStep is how many entries apart an EntryWatch notifies
+// when not told otherwise.
+const DefaultEntryWatchStep = 10
+
+// entryWatchesKey is the key under which SaveEntryWatches persists the
+// current watches.
+const entryWatchesKey = "bcc:entry-watches"
+
+// EntryWatch is a user's request to be notified as an event's registration
+// fills.
+type EntryWatch struct {
+	EventID int64  `json:"eventId"`
+	Title   string `json:"title"`
+	UserID  string `json:"userId"`
+	// Step is the number of entries between notifications, e.g. 10 to be
+	// notified at 10, 20, 30... entries.
+	Step int `json:"step"`
+	// Entries is the number of entries as of the last check.
+	Entries int `json:"entries"`
+	// Notified is the highest multiple of Step notified so far, so that
+	// withdrawals and re-entries around a multiple notify only once.
+	Notified int `json:"notified"`
+}
+
+// NewEntryWatch returns userID's watch of detail's registration, notifying
+// every step entries (DefaultEntryWatchStep when not positive) beyond those
+// already entered.
+func NewEntryWatch(detail *EventDetail, userID string, step int) EntryWatch {
+	if step <= 0 {
+		step = DefaultEntryWatchStep
+	}
+	entries := max(detail.NumEntries, len(detail.Entries))
+
+	return EntryWatch{
+		EventID:  int64(detail.EventID),
+		Title:    detail.Title,
+		UserID:   userID,
+		Step:     step,
+		Entries:  entries,
+		Notified: entries / step * step,
+	}
+}
+
+// CheckEntryWatch compares detail, as fetched at now, with w and returns the
+// notifications due: a multiple of w.Step higher than any already notified,
+// and registration closing. done is set once registration has closed or the
+// event has started, after which the watch should be dropped. w.Entries is
+// updated to detail's count.
+//
+// The event page read when the club's API is unavailable does not report
+// registration, so registration is only judged closed from details which
+// include its deadline.
+func CheckEntryWatch(w *EntryWatch, detail *EventDetail,
+	now time.Time) (notes []string, done bool) {
+
+	entries := max(detail.NumEntries, len(detail.Entries))
+	step := w.Step
+	if step <= 0 {
+		step = DefaultEntryWatchStep
+	}
+	if filled := entries / step * step; filled > w.Notified {
+		notes = append(notes, fmt.Sprintf("%v: %v spots filled (%v entries).",
+			w.Title, filled, entries))
+		w.Notified = filled
+	}
+	w.Entries = entries
+
+	if eventStatusAt(detail, now) != EventNotStarted {
+		notes = append(notes, fmt.Sprintf("%v has started with %v entries; no longer watching its registration.",
+			w.Title, entries))
+		return notes, true
+	}
+	if !detail.RegistrationEndDate.IsZero() && (!detail.IsRegistrationOpen ||
+		!now.Before(detail.RegistrationEndDate)) {
+
+		notes = append(notes, fmt.Sprintf("Registration for %v has closed with %v entries.",
+			w.Title, entries))
+		return notes, true
+	}
+
+	return notes, false
+}
+
+// LoadEntryWatches returns the watches last saved by SaveEntryWatches.
+func LoadEntryWatches(store SnapshotStore) []EntryWatch {
+	data, ok := store.Get(entryWatchesKey)
+	if !ok {
+		return nil
+	}
+	var watches []EntryWatch
+	if err := json.Unmarshal(data, &watches); err != nil {
+		log.Printf("bcc: entry watches: discarding unreadable watches: %v",
+			err)
+		return nil
+	}
+
+	return watches
+}
+
+// SaveEntryWatches persists watches for LoadEntryWatches.
+func SaveEntryWatches(store SnapshotStore, watches []EntryWatch) {
+	data, err := json.Marshal(watches)
+	if err != nil {
+		log.Printf("bcc: entry watches: unable to encode watches: %v", err)
+		return
+	}
+	if err := store.Put(entryWatchesKey, data); err != nil {
+		log.Printf("bcc: entry watches: unable to save watches: %v", err)
+	}
+}
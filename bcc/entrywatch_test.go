@@ -0,0 +1,85 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckEntryWatch(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	detail := &EventDetail{
+		EventID:             1234,
+		Title:               "March Swiss",
+		StartDate:           now.AddDate(0, 0, 7),
+		IsRegistrationOpen:  true,
+		RegistrationEndDate: now.AddDate(0, 0, 6),
+		NumEntries:          8,
+	}
+	watch := NewEntryWatch(detail, "42", 0)
+	w := &watch
+	if w.Step != DefaultEntryWatchStep || w.Entries != 8 || w.Notified != 0 {
+		t.Errorf("NewEntryWatch() = %+v", watch)
+	}
+
+	notes, done := CheckEntryWatch(w, detail, now)
+	if len(notes) != 0 || done {
+		t.Errorf("CheckEntryWatch(unchanged) = %v, %v", notes, done)
+	}
+
+	// crossing two thresholds at once notifies once, for the higher
+	detail.NumEntries = 23
+	notes, done = CheckEntryWatch(w, detail, now)
+	if want := []string{"March Swiss: 20 spots filled (23 entries)."}; !reflect.DeepEqual(notes, want) || done {
+		t.Errorf("CheckEntryWatch(23) = %v, %v; want %v", notes, done, want)
+	}
+	if w.Entries != 23 {
+		t.Errorf("w.Entries = %v; want 23", w.Entries)
+	}
+
+	// a withdrawal and re-entry doesn't renotify
+	detail.NumEntries = 19
+	CheckEntryWatch(w, detail, now)
+	detail.NumEntries = 20
+	if notes, _ := CheckEntryWatch(w, detail, now); len(notes) != 0 {
+		t.Errorf("CheckEntryWatch(back to 20) = %v", notes)
+	}
+
+	// the web fallback reports registration closed without a deadline
+	web := *detail
+	web.IsRegistrationOpen = false
+	web.RegistrationEndDate = time.Time{}
+	if notes, done := CheckEntryWatch(w, &web, now); len(notes) != 0 || done {
+		t.Errorf("CheckEntryWatch(web) = %v, %v", notes, done)
+	}
+
+	detail.IsRegistrationOpen = false
+	notes, done = CheckEntryWatch(w, detail, now)
+	if want := []string{"Registration for March Swiss has closed with 20 entries."}; !reflect.DeepEqual(notes, want) || !done {
+		t.Errorf("CheckEntryWatch(closed) = %v, %v; want %v", notes, done,
+			want)
+	}
+
+	// watches expire once the event starts
+	detail.IsRegistrationOpen = true
+	if _, done := CheckEntryWatch(w, detail, now.AddDate(0, 0, 8)); !done {
+		t.Errorf("CheckEntryWatch(started) done = false")
+	}
+}
+
+func TestEntryWatchesRoundTrip(t *testing.T) {
+	store := memSnapshotStore{}
+	if watches := LoadEntryWatches(store); watches != nil {
+		t.Errorf("LoadEntryWatches(empty) = %v", watches)
+	}
+	want := []EntryWatch{{EventID: 1234, Title: "March Swiss", UserID: "42",
+		Step: 5, Entries: 12, Notified: 10}}
+	SaveEntryWatches(store, want)
+	if got := LoadEntryWatches(store); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadEntryWatches() = %v; want %v", got, want)
+	}
+}
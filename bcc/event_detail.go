@@ -5,16 +5,33 @@
 package bcc
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
 )
 
+const eventDetailPrefetchConcurrency = 4
+
+var (
+	eventDetailClientOnce sync.Once
+	eventDetailClient     *http.Client
+)
+
+type eventDetailLookup func(ctx context.Context,
+	eventId int64) (EventDetail, error)
+
 // vended by https://beta.boylstonchess.org/api/event/<eventId>
 // EventDetail represents detailed information about a specific event.
 type EventDetail struct {
@@ -60,26 +77,53 @@ type Entry struct {
 	SecondaryRating     string    `json:"secondaryRating"`
 	SecondaryRatingType string    `json:"secondaryRatingType"`
 	SecondaryRatingDate string    `json:"secondaryRatingDate"`
+	PairingNumber       int       `json:"pairingNumber,omitempty"`
+}
+
+// GetEventDetail fetches detailed event info for a given eventId and returns
+// an EventDetail. The API is preferred; when it fails the public event page is
+// scraped instead.
+func GetEventDetail(ctx context.Context, eventId int64) (EventDetail, error) {
+	detail, apiErr := getEventDetailViaApi(ctx, eventId)
+	if apiErr == nil {
+		return detail, nil
+	}
+
+	detail, webErr := getEventDetailViaWeb(ctx, eventId)
+	if webErr != nil {
+		// both errored; prefer the api error response
+		return EventDetail{}, apiErr
+	}
+	log.Printf("bcc: event detail: using website after api failure: %v", apiErr)
+
+	return detail, nil
 }
 
-// GetEventDetail fetches detailed event info from the Boylston Chess API
-// for a given eventId and returns an EventDetail.
-func GetEventDetail(eventId int64) (EventDetail, error) {
-	url := fmt.Sprintf("https://beta.boylstonchess.org/api/event/%d", eventId)
-	req, err := http.NewRequest("GET", url, nil)
+// getEventDetailViaApi fetches detailed event info for a given eventId from
+// the JSON API.
+func getEventDetailViaApi(ctx context.Context,
+	eventId int64) (EventDetail, error) {
+
+	req, err := http.NewRequestWithContext(ctx, "GET", eventDetailURL(eventId),
+		nil)
 	if err != nil {
 		return EventDetail{}, fmt.Errorf("unable to fetch bcc event detail (new): %w", err)
 	}
 	req.Header.Set("User-Agent", internal.UserAgent)
 
-	resp, err := http.DefaultClient.Do(req)
+	eventDetailClientOnce.Do(func() {
+		eventDetailClient = httpcache.NewCachedHttpClientWithTTL(
+			context.Background(), eventDetailResponseTTL)
+	})
+	resp, err := eventDetailClient.Do(req)
 	if err != nil {
 		return EventDetail{}, fmt.Errorf("unable to fetch bcc event detail (do): %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return EventDetail{}, fmt.Errorf("unable to fetch bcc event detail (http): %v", resp.StatusCode)
+		return EventDetail{}, fmt.Errorf("unable to fetch bcc event detail (http): %w",
+			internal.HTTPStatusError(resp.StatusCode))
 	}
 
 	var detail EventDetail
@@ -90,6 +134,183 @@ func GetEventDetail(eventId int64) (EventDetail, error) {
 	return detail, nil
 }
 
+// eventDetailResponseTTL decodes an event detail response in order to
+// determine how long it may be cached.
+func eventDetailResponseTTL(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusOK {
+		return 0
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return 0
+	}
+
+	var detail EventDetail
+	if err := json.Unmarshal(body, &detail); err != nil {
+		return 0
+	}
+
+	return eventDetailTTL(&detail, internal.Now())
+}
+
+// eventDetailTTL returns how long an event's detail may be cached. Details
+// of events that are imminent, underway, recently changed, or open for
+// registration change frequently and are cached briefly, whereas completed
+// and far future events rarely change and are cached longer.
+func eventDetailTTL(detail *EventDetail, now time.Time) time.Duration {
+	const (
+		imminentWindow     = 48 * time.Hour
+		recentChangeWindow = time.Hour
+		farFutureWindow    = 14 * 24 * time.Hour
+	)
+
+	state := eventStatusAt(detail, now)
+	start, _ := eventDateRange(detail)
+	if state == EventInProgress ||
+		(state == EventNotStarted && start.Sub(now) < imminentWindow) {
+		return time.Minute
+	}
+	if !detail.LastChangeDate.IsZero() &&
+		now.Sub(detail.LastChangeDate) < recentChangeWindow {
+		return time.Minute
+	}
+	if detail.IsRegistrationOpen {
+		return 5 * time.Minute
+	}
+	if state == EventCompleted {
+		return 6 * time.Hour
+	}
+	if start.Sub(now) > farFutureWindow {
+		return 12 * time.Hour
+	}
+
+	return 30 * time.Minute
+}
+
+// eventDetailMemoTTL bounds how long GetEventDetails reuses a detail it
+// fetched so that repeated calendar listings share their fetches.
+const eventDetailMemoTTL = 60 * time.Second
+
+// eventDetailMemo memoizes event details in process. The http cache alone
+// does not suffice: each hit there is still an S3 round trip, and a
+// detailed calendar needs one detail per listed event.
+type eventDetailMemo struct {
+	mu      sync.Mutex
+	fetch   eventDetailLookup
+	now     func() time.Time
+	ttl     time.Duration
+	details map[int64]memoizedEventDetail
+}
+
+type memoizedEventDetail struct {
+	detail  EventDetail
+	fetched time.Time
+}
+
+var defaultEventDetailMemo = &eventDetailMemo{
+	fetch: GetEventDetail,
+	// as for defaultEventsMemo, follow any clock pinned via internal.Now
+	now: func() time.Time { return internal.Now() },
+	ttl: eventDetailMemoTTL,
+}
+
+// get returns the memoized detail of an event, fetching it when it is older
+// than the memo's ttl. Failed fetches are not memoized. Callers receive
+// their own copy.
+func (m *eventDetailMemo) get(ctx context.Context,
+	eventId int64) (EventDetail, error) {
+
+	m.mu.Lock()
+	memoized, ok := m.details[eventId]
+	m.mu.Unlock()
+	if ok && m.now().Sub(memoized.fetched) < m.ttl {
+		return memoized.detail.clone(), nil
+	}
+
+	// fetch without holding the lock so that concurrent prefetches of
+	// different events proceed in parallel
+	detail, err := m.fetch(ctx, eventId)
+	if err != nil {
+		return EventDetail{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if m.details == nil {
+		m.details = make(map[int64]memoizedEventDetail)
+	}
+	for id, memoized := range m.details {
+		if now.Sub(memoized.fetched) >= m.ttl {
+			delete(m.details, id)
+		}
+	}
+	m.details[eventId] = memoizedEventDetail{detail: detail, fetched: now}
+
+	return detail.clone(), nil
+}
+
+// clone returns a copy of detail which shares none of its slices.
+func (detail EventDetail) clone() EventDetail {
+	detail.Dates = slices.Clone(detail.Dates)
+	detail.Sections = slices.Clone(detail.Sections)
+	detail.Entries = slices.Clone(detail.Entries)
+
+	return detail
+}
+
+// GetEventDetails concurrently fetches the EventDetail for each of the given
+// eventIds. Each distinct eventId is fetched at most once and events which
+// fail to fetch are omitted from the returned map. Details are memoized for
+// up to a minute.
+func GetEventDetails(ctx context.Context,
+	eventIds []int64) map[int64]*EventDetail {
+
+	return getEventDetailsWithLookup(ctx, eventIds, defaultEventDetailMemo.get)
+}
+
+func getEventDetailsWithLookup(ctx context.Context, eventIds []int64,
+	lookup eventDetailLookup) map[int64]*EventDetail {
+
+	details := make(map[int64]*EventDetail)
+	seen := make(map[int64]bool)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, eventDetailPrefetchConcurrency)
+
+	for _, eventId := range eventIds {
+		if seen[eventId] {
+			continue
+		}
+		seen[eventId] = true
+
+		eventId := eventId
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			detail, err := lookup(ctx, eventId)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			details[eventId] = &detail
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return details
+}
+
 // Custom unmarshaller for EventDetail to handle flexible date parsing.
 func (ed *EventDetail) UnmarshalJSON(data []byte) error {
 	type Alias EventDetail
@@ -107,27 +328,17 @@ func (ed *EventDetail) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return fmt.Errorf("EventDetail unmarshal: %w", err)
 	}
-	var err error
-	ed.StartDate, err = internal.ParseDateOrZero(aux.StartDate)
-	if err != nil {
-		return fmt.Errorf("parsing EventDetail.StartDate: %w", err)
-	}
-	ed.EndDate, err = internal.ParseDateOrZero(aux.EndDate)
-	if err != nil {
-		return fmt.Errorf("parsing EventDetail.EndDate: %w", err)
-	}
-	ed.RegistrationEndDate, err = internal.ParseDateOrZero(aux.RegistrationEndDate)
-	if err != nil {
-		return fmt.Errorf("parsing EventDetail.RegistrationEndDate: %w", err)
-	}
-	ed.CreationDate, err = internal.ParseDateOrZero(aux.CreationDate)
-	if err != nil {
-		return fmt.Errorf("parsing EventDetail.CreationDate: %w", err)
-	}
-	ed.LastChangeDate, err = internal.ParseDateOrZero(aux.LastChangeDate)
-	if err != nil {
-		return fmt.Errorf("parsing EventDetail.LastChangeDate: %w", err)
-	}
+	// a malformed date leaves the field unset rather than failing the
+	// whole event
+	ed.StartDate = internal.ParseDateLenient(aux.StartDate,
+		"EventDetail.StartDate")
+	ed.EndDate = internal.ParseDateLenient(aux.EndDate, "EventDetail.EndDate")
+	ed.RegistrationEndDate = internal.ParseDateLenient(aux.RegistrationEndDate,
+		"EventDetail.RegistrationEndDate")
+	ed.CreationDate = internal.ParseDateLenient(aux.CreationDate,
+		"EventDetail.CreationDate")
+	ed.LastChangeDate = internal.ParseDateLenient(aux.LastChangeDate,
+		"EventDetail.LastChangeDate")
 	// copy parsed entries
 	ed.Entries = aux.Entries
 	return nil
@@ -145,11 +356,8 @@ func (e *Entry) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return fmt.Errorf("Entry unmarshal: %w", err)
 	}
-	var err error
-	e.RegistrationDate, err = internal.ParseDateOrZero(aux.RegistrationDate)
-	if err != nil {
-		return fmt.Errorf("parsing Entry.RegistrationDate: %w", err)
-	}
+	e.RegistrationDate = internal.ParseDateLenient(aux.RegistrationDate,
+		"Entry.RegistrationDate")
 	return nil
 }
 
@@ -194,6 +402,10 @@ func BuildEventOutput(detail *EventDetail, boldTag string, includeTitle,
 		sb.WriteString(fmt.Sprintf("%vPrizes%v: %s\n", boldTag, boldTag,
 			detail.PrizeSummary))
 	}
+	if res, ok := ResolvePrizeFund(detail); ok {
+		sb.WriteString(fmt.Sprintf("%vExpected Prize Fund%v: %v\n", boldTag,
+			boldTag, res))
+	}
 	if detail.RegistrationTime != "" {
 		sb.WriteString(fmt.Sprintf("%vRegistration Time%v: %s\n", boldTag,
 			boldTag, detail.RegistrationTime))
@@ -202,12 +414,36 @@ func BuildEventOutput(detail *EventDetail, boldTag string, includeTitle,
 		detail.RoundTimes))
 	sb.WriteString(fmt.Sprintf("%v[Entries](https://boylstonchess.org/tournament/entries/%v)%v: %v\n",
 		boldTag, detail.EventID, boldTag, buildEntriesCountString(detail)))
+	if momentum, ok := ComputeSignupMomentum(detail); ok {
+		sb.WriteString(fmt.Sprintf("%vRegistrations Trending%v: %v\n", boldTag,
+			boldTag, momentum))
+	}
 	sb.WriteString(fmt.Sprintf("%vDescription%v: %s\n", boldTag, boldTag,
 		detail.Description))
 
 	return sb.String()
 }
 
+// BuildEventSummary formats a one line summary of an event's format, entry
+// fee, and registration status
+func BuildEventSummary(detail *EventDetail) string {
+	var parts []string
+
+	if detail.EventFormat != "" {
+		parts = append(parts, detail.EventFormat)
+	}
+	if detail.EntryFeeSummary != "" {
+		parts = append(parts, fmt.Sprintf("Entry Fee: %v", detail.EntryFeeSummary))
+	}
+	if detail.IsRegistrationOpen {
+		parts = append(parts, "Registration open")
+	} else {
+		parts = append(parts, "Registration closed")
+	}
+
+	return strings.Join(parts, " | ")
+}
+
 // buildEntriesCountString formats a pretty printed string describing the count
 // of entries in each section
 func buildEntriesCountString(detail *EventDetail) string {
@@ -5,11 +5,18 @@
 package bcc
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestGetEventDetail(t *testing.T) {
-	detail, err := GetEventDetail(1312)
+	detail, err := GetEventDetail(context.Background(), 1312)
 	if err != nil {
 		t.Fatalf("GetEventDetail returned error: %v", err)
 	}
@@ -42,3 +49,247 @@ func TestGetEventDetail(t *testing.T) {
 		}
 	}
 }
+
+func TestGetEventDetailsWithLookup(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[int64]int)
+	inFlight, maxInFlight := 0, 0
+
+	lookup := func(_ context.Context, eventId int64) (EventDetail, error) {
+		mu.Lock()
+		calls[eventId]++
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if eventId == 13 {
+			return EventDetail{}, errors.New("bad event")
+		}
+		return EventDetail{EventID: int(eventId)}, nil
+	}
+
+	ids := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 1, 2, 13}
+	details := getEventDetailsWithLookup(context.Background(), ids, lookup)
+
+	if len(details) != 10 {
+		t.Fatalf("expected 10 details, got %v", len(details))
+	}
+	if _, ok := details[13]; ok {
+		t.Errorf("expected failed event to be omitted")
+	}
+	for id, n := range calls {
+		if n != 1 {
+			t.Errorf("event %v fetched %v times; want 1", id, n)
+		}
+	}
+	if maxInFlight > eventDetailPrefetchConcurrency {
+		t.Errorf("max concurrent fetches %v exceeds %v", maxInFlight,
+			eventDetailPrefetchConcurrency)
+	}
+}
+
+func TestBuildEventSummary(t *testing.T) {
+	detail := &EventDetail{
+		EventFormat:        "5-SS",
+		EntryFeeSummary:    "$40",
+		IsRegistrationOpen: true,
+	}
+	got := BuildEventSummary(detail)
+	want := "5-SS | Entry Fee: $40 | Registration open"
+	if got != want {
+		t.Errorf("BuildEventSummary() = %q; want %q", got, want)
+	}
+
+	got = BuildEventSummary(&EventDetail{})
+	want = "Registration closed"
+	if got != want {
+		t.Errorf("BuildEventSummary() = %q; want %q", got, want)
+	}
+}
+
+func TestEventDetailTTL(t *testing.T) {
+	now := time.Date(2026, time.May, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		detail EventDetail
+		want   time.Duration
+	}{
+		{"in progress",
+			EventDetail{StartDate: now.Add(-time.Hour), EndDate: now}, time.Minute},
+		{"imminent",
+			EventDetail{StartDate: now.Add(day), EndDate: now.Add(day)}, time.Minute},
+		{"recently changed",
+			EventDetail{StartDate: now.Add(30 * day), EndDate: now.Add(30 * day),
+				LastChangeDate: now.Add(-10 * time.Minute)}, time.Minute},
+		{"registration open",
+			EventDetail{StartDate: now.Add(30 * day), EndDate: now.Add(30 * day),
+				IsRegistrationOpen: true}, 5 * time.Minute},
+		{"completed",
+			EventDetail{StartDate: now.Add(-7 * day), EndDate: now.Add(-7 * day)},
+			6 * time.Hour},
+		{"far future",
+			EventDetail{StartDate: now.Add(30 * day), EndDate: now.Add(30 * day)},
+			12 * time.Hour},
+		{"upcoming",
+			EventDetail{StartDate: now.Add(7 * day), EndDate: now.Add(7 * day)},
+			30 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := eventDetailTTL(&tt.detail, now); got != tt.want {
+			t.Errorf("%v: eventDetailTTL() = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetEventDetailApiFailure(t *testing.T) {
+	const eventHTML = `<html><head>
+<meta property="og:description" content="A 4 round Swiss in 2 sections.">
+</head><body><h1>Fall Swiss</h1>
+<dl>
+<dt>Dates:</dt><dd>10/14/2026 - 11/4/2026</dd>
+<dt>Sections:</dt><dd>Open, U1800</dd>
+<dt>Time Control:</dt><dd>G/90 d5</dd>
+</dl>
+<table><tr><th>Entry Fee</th><td>$40</td></tr></table>
+</body></html>`
+	const entriesHTML = `<html><body><table id="members"><tbody>
+<tr><td>1</td><td>Alice Smith</td><td>1800</td><td>12345678</td></tr>
+<tr><td>2</td><td>Bob Jones</td><td>unrated</td><td>23456789</td></tr>
+</tbody></table></body></html>`
+
+	apiUp := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/event/42", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		if !apiUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"eventId":42,"title":"Fall Swiss (API)"}`)
+	})
+	mux.HandleFunc("/events/42", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, eventHTML)
+	})
+	mux.HandleFunc("/tournament/entries/42", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, entriesHTML)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	getWebDocClient()
+	eventDetailClientOnce.Do(func() {})
+	origApi, origWeb := apiBaseURL, webBaseURL
+	origDocClient, origDetailClient := webDocClient, eventDetailClient
+	defer func() {
+		apiBaseURL, webBaseURL = origApi, origWeb
+		webDocClient, eventDetailClient = origDocClient, origDetailClient
+	}()
+	apiBaseURL, webBaseURL = srv.URL, srv.URL
+	webDocClient = http.DefaultClient
+	eventDetailClient = http.DefaultClient
+
+	detail, err := GetEventDetail(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetEventDetail(context.Background(), ) err = %v", err)
+	}
+	if detail.EventID != 42 || detail.Title != "Fall Swiss" {
+		t.Errorf("EventID, Title = %v, %q; want 42, Fall Swiss",
+			detail.EventID, detail.Title)
+	}
+	if detail.Description != "A 4 round Swiss in 2 sections." {
+		t.Errorf("Description = %q", detail.Description)
+	}
+	if len(detail.Sections) != 2 || detail.Sections[1] != "U1800" {
+		t.Errorf("Sections = %v; want [Open U1800]", detail.Sections)
+	}
+	if detail.TimeControl != "G/90 d5" || detail.EntryFeeSummary != "$40" {
+		t.Errorf("TimeControl, EntryFeeSummary = %q, %q", detail.TimeControl,
+			detail.EntryFeeSummary)
+	}
+	if detail.StartDate.Month() != time.October || detail.StartDate.Day() != 14 {
+		t.Errorf("StartDate = %v; want 10/14/2026", detail.StartDate)
+	}
+	if detail.NumEntries != 2 || detail.Entries[0].LastName != "Smith" ||
+		detail.Entries[0].PrimaryRating != "1800" ||
+		detail.Entries[1].PrimaryRating != "" {
+		t.Errorf("unexpected entries: %+v", detail.Entries)
+	}
+
+	// the api is preferred whenever it responds
+	apiUp = true
+	detail, err = GetEventDetail(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetEventDetail(context.Background(), ) err = %v", err)
+	}
+	if detail.Title != "Fall Swiss (API)" {
+		t.Errorf("Title = %q; want the api's title", detail.Title)
+	}
+}
+
+func TestEventDetailMemo(t *testing.T) {
+	fetches := 0
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	memo := &eventDetailMemo{
+		fetch: func(_ context.Context, eventId int64) (EventDetail, error) {
+			fetches++
+			if eventId < 0 {
+				return EventDetail{}, errors.New("boom")
+			}
+			return EventDetail{EventID: fetches,
+				Sections: []string{"Open"}}, nil
+		},
+		now: func() time.Time { return now },
+		ttl: time.Minute,
+	}
+
+	expect := func(eventId int64, wantID int, wantFetches int) {
+		t.Helper()
+		detail, err := memo.get(context.Background(), eventId)
+		if err != nil {
+			t.Fatalf("get(%v) err = %v", eventId, err)
+		}
+		if detail.EventID != wantID {
+			t.Fatalf("get(%v) = event %v; want %v", eventId, detail.EventID,
+				wantID)
+		}
+		if fetches != wantFetches {
+			t.Fatalf("fetches = %v; want %v", fetches, wantFetches)
+		}
+		// callers own their copy
+		detail.Sections[0] = "mutated"
+	}
+
+	expect(1312, 1, 1)
+	now = now.Add(30 * time.Second)
+	expect(1312, 1, 1)
+	expect(1313, 2, 2)
+	now = now.Add(time.Minute)
+	expect(1312, 3, 3)
+
+	for i := 0; i < 2; i++ {
+		if _, err := memo.get(context.Background(), -1); err == nil {
+			t.Fatalf("get() with failing fetch succeeded; want error")
+		}
+	}
+	if fetches != 5 {
+		t.Errorf("fetches = %v; want failures not memoized", fetches)
+	}
+	if detail, _ := memo.get(context.Background(), 1312); detail.Sections[0] != "Open" {
+		t.Errorf("memoized detail was modified by a caller: %+v", detail)
+	}
+}
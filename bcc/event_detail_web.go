@@ -0,0 +1,139 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// getEventDetailViaWeb builds an EventDetail by scraping the public event
+// page and the event's entries page. Only the event page is required; when
+// the entries page cannot be fetched the detail is returned without entries.
+func getEventDetailViaWeb(ctx context.Context,
+	eventId int64) (EventDetail, error) {
+
+	var wg sync.WaitGroup
+	var eventDoc, entriesDoc *goquery.Document
+	var errEvent, errEntries error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		eventDoc, errEvent = fetchDoc(ctx, eventPageURL(eventId))
+	}()
+	go func() {
+		defer wg.Done()
+		entriesDoc, errEntries = fetchDoc(ctx, entriesPageURL(eventId))
+	}()
+	wg.Wait()
+
+	if errEvent != nil {
+		return EventDetail{}, fmt.Errorf("unable to fetch event page: %w",
+			errEvent)
+	}
+	detail := EventDetail{EventID: int(eventId)}
+	if err := parseEventPage(eventDoc, &detail); err != nil {
+		return EventDetail{}, fmt.Errorf("unable to parse event page: %w", err)
+	}
+
+	if errEntries == nil {
+		var t Tournament
+		if err := parsePlayers(entriesDoc, &t); err == nil {
+			for _, p := range t.Players {
+				detail.Entries = append(detail.Entries, playerToEntry(p))
+			}
+			detail.NumEntries = len(detail.Entries)
+		}
+	}
+
+	return detail, nil
+}
+
+// parseEventPage extracts the event's title, description, and labeled
+// details (e.g. "Time Control") from the public event page. Labeled details
+// are read from either definition lists or two column table rows.
+func parseEventPage(doc *goquery.Document, detail *EventDetail) error {
+	detail.Title = strings.TrimSpace(doc.Find("h1").First().Text())
+	if detail.Title == "" {
+		detail.Title, _ = doc.Find(`meta[property="og:title"]`).Attr("content")
+	}
+	if detail.Title == "" {
+		return fmt.Errorf("no event title found")
+	}
+	detail.Description, _ = doc.Find(`meta[property="og:description"]`).
+		Attr("content")
+
+	doc.Find("dt").Each(func(_ int, dt *goquery.Selection) {
+		setEventPageField(detail, dt.Text(), dt.NextFiltered("dd").Text())
+	})
+	doc.Find("tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("th, td")
+		if cells.Length() != 2 {
+			return
+		}
+		setEventPageField(detail, cells.Eq(0).Text(), cells.Eq(1).Text())
+	})
+
+	return nil
+}
+
+// setEventPageField stores the value of a labeled event page detail in the
+// corresponding EventDetail field. Unknown labels are ignored.
+func setEventPageField(detail *EventDetail, label string, value string) {
+	label = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(label), ":"))
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return
+	}
+
+	switch label {
+	case "date", "dates":
+		detail.DateDisplay = value
+		detail.StartDate, _ = internal.ParseDateOrZero(
+			strings.TrimSpace(strings.Split(value, " - ")[0]))
+	case "sections", "section":
+		detail.SectionDisplay = value
+		detail.Sections = nil
+		for _, sec := range strings.Split(value, ",") {
+			if sec = strings.TrimSpace(sec); sec != "" {
+				detail.Sections = append(detail.Sections, sec)
+			}
+		}
+	case "entry fee", "entry fees":
+		detail.EntryFeeSummary = value
+	case "prizes", "prize fund":
+		detail.PrizeSummary = value
+	case "format", "event format":
+		detail.EventFormat = value
+	case "time control":
+		detail.TimeControl = value
+	case "registration", "registration time":
+		detail.RegistrationTime = value
+	case "rounds", "round times":
+		detail.RoundTimes = value
+	}
+}
+
+// playerToEntry is the inverse of entryToPlayer for the fields scraped from
+// the entries page.
+func playerToEntry(p Player) Entry {
+	entry := Entry{
+		FirstName:     p.FirstName,
+		LastName:      p.LastName,
+		UscfID:        p.UscfID,
+		SectionName:   p.SectionName,
+		PairingNumber: p.PairingNumber,
+	}
+	if p.PrimaryRating != 0 {
+		entry.PrimaryRating = displayRating(p)
+	}
+
+	return entry
+}
@@ -0,0 +1,234 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// EventState classifies where an event is relative to its scheduled dates.
+type EventState int
+
+const (
+	EventNotStarted EventState = iota
+	EventInProgress
+	EventCompleted
+)
+
+func (s EventState) String() string {
+	if s == EventNotStarted {
+		return "not started"
+	} else if s == EventInProgress {
+		return "in progress"
+	} else if s == EventCompleted {
+		return "completed"
+	} // else
+
+	return "?"
+}
+
+// EventStatus reports whether an event has not yet started, is in progress,
+// or has completed. An event whose dates are unknown is assumed to be in
+// progress.
+func EventStatus(detail *EventDetail) EventState {
+	return eventStatusAt(detail, internal.Now())
+}
+
+func eventStatusAt(detail *EventDetail, now time.Time) EventState {
+	start, end := eventDateRange(detail)
+	if start.IsZero() {
+		return EventInProgress
+	}
+	if now.Before(start) {
+		return EventNotStarted
+	}
+	// the end date may be reported as midnight of the last day; treat the
+	// event as running through the end of that day
+	endDay := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0,
+		end.Location()).AddDate(0, 0, 1)
+	if !now.Before(endDay) {
+		return EventCompleted
+	}
+
+	return EventInProgress
+}
+
+// eventDateRange returns the first and last dates of an event, preferring
+// StartDate/EndDate and falling back to the individual event Dates.
+func eventDateRange(detail *EventDetail) (time.Time, time.Time) {
+	var first, last time.Time
+	for _, d := range detail.Dates {
+		t, err := internal.ParseDateOrZero(d)
+		if err != nil || t.IsZero() {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if last.IsZero() || t.After(last) {
+			last = t
+		}
+	}
+
+	start, end := detail.StartDate, detail.EndDate
+	if start.IsZero() {
+		start = first
+	}
+	if end.IsZero() {
+		end = last
+	}
+	if end.IsZero() || end.Before(start) {
+		end = start
+	}
+
+	return start, end
+}
+
+// BuildEventStatusNote returns a short note explaining an event's pairings
+// or standings in light of its state. Events in progress need no note.
+func BuildEventStatusNote(detail *EventDetail, state EventState) string {
+	switch state {
+	case EventNotStarted:
+		return fmt.Sprintf("%v has not started yet; round 1 pairings will be posted closer to the start.\n\n",
+			detail.Title)
+	case EventCompleted:
+		if detail.UscfTid != 0 {
+			return fmt.Sprintf("%v is over; here are the final results from USCF:\n\n",
+				detail.Title)
+		}
+		return fmt.Sprintf("%v is over; final results will be available once the club files the event with USCF.\n\n",
+			detail.Title)
+	default:
+		return ""
+	}
+}
+
+// EventStatusOutput returns a note describing whether an event has started
+// and, for completed events filed with USCF, the final crosstables as
+// fetched by client. Both are empty when the event's status cannot be
+// determined.
+func EventStatusOutput(ctx context.Context, client *uscfutils.Client,
+	eventID int64) (string, string) {
+
+	detail, err := GetEventDetail(ctx, eventID)
+	if err != nil {
+		return "", ""
+	}
+	state := EventStatus(&detail)
+	if state == EventCompleted && detail.UscfTid != 0 {
+		t, err := client.GetCrossTables(ctx,
+			uschess.EventID(strconv.Itoa(detail.UscfTid)))
+		if err == nil {
+			return BuildEventStatusNote(&detail, state),
+				uscfutils.BuildAllCrossTablesOutput(t,
+					uscfutils.CrossTableOptions{})
+		}
+		return "", ""
+	}
+
+	return BuildEventStatusNote(&detail, state), ""
+}
+
+// RoundPairingsOutput returns round's games, reconstructed from the US Chess
+// crosstable of eventID as fetched by client, when the club has filed the
+// event. ok is false when it has not, or when the event or its crosstable
+// cannot be fetched, in which case err says why.
+func RoundPairingsOutput(ctx context.Context, client *uscfutils.Client,
+	eventID int64, section string, round int) (string, bool, error) {
+
+	detail, err := GetEventDetail(ctx, eventID)
+	if err != nil {
+		return "", false, err
+	}
+	if detail.UscfTid == 0 {
+		return "", false, nil
+	}
+	t, err := client.GetCrossTables(ctx,
+		uschess.EventID(strconv.Itoa(detail.UscfTid)))
+	if err != nil {
+		return "", false, fmt.Errorf("crosstables %v: %w", detail.UscfTid, err)
+	}
+
+	return uscfutils.BuildRoundPairingsOutput(t, section, round), true, nil
+}
+
+var (
+	numRoundsRe = regexp.MustCompile(`(?i)\b(\d+)[\s-]*(?:rounds?|rds?|rnds?|ss)\b`)
+	// a numbered round within a schedule, e.g. "Rd 3: 7pm"
+	roundTimeRe = regexp.MustCompile(`(?i)\b(?:rounds?|rds?|rnds?)\.?\s*(\d+)\b`)
+)
+
+// numRoundsFromDetail returns the number of rounds advertised for an event,
+// e.g. "4 round Swiss" or "4-SS", or 0 when it cannot be determined. When
+// none is advertised the highest round numbered in the event's round times
+// (e.g. "Rd 1 10am, Rd 2 1pm") is used.
+func numRoundsFromDetail(detail *EventDetail) int {
+	for _, text := range []string{detail.EventFormat, detail.Title,
+		detail.Description} {
+
+		m := numRoundsRe.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		if n, err := strconv.Atoi(m[1]); err == nil && n > 0 {
+			return n
+		}
+	}
+
+	numRounds := 0
+	for _, m := range roundTimeRe.FindAllStringSubmatch(detail.RoundTimes, -1) {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			numRounds = max(numRounds, n)
+		}
+	}
+
+	return numRounds
+}
+
+// pairingHasResult reports whether a result has been posted for a pairing.
+// Byes need no result.
+func pairingHasResult(p Pairing) bool {
+	if p.IsByePairing {
+		return true
+	}
+	if p.WhitePoints != nil && p.BlackPoints != nil {
+		return true
+	}
+
+	return p.WhiteResult != nil && *p.WhiteResult != "" &&
+		p.BlackResult != nil && *p.BlackResult != ""
+}
+
+// TournamentOver reports whether no further updates are expected for an
+// event: either it has completed, or every board of its final round has a
+// result.
+func TournamentOver(detail *EventDetail, t *Tournament) bool {
+	return tournamentOverAt(detail, t, internal.Now())
+}
+
+func tournamentOverAt(detail *EventDetail, t *Tournament, now time.Time) bool {
+	if eventStatusAt(detail, now) == EventCompleted {
+		return true
+	}
+	numRounds := numRoundsFromDetail(detail)
+	if numRounds == 0 || t.IsPredicted() || len(t.CurrentPairings) == 0 {
+		return false
+	}
+	for _, p := range t.CurrentPairings {
+		if p.RoundNumber < numRounds || !pairingHasResult(p) {
+			return false
+		}
+	}
+
+	return true
+}
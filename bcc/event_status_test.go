@@ -0,0 +1,118 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventStatusAt(t *testing.T) {
+	start := time.Date(2026, time.May, 2, 10, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.May, 3, 0, 0, 0, 0, time.UTC)
+	detail := &EventDetail{StartDate: start, EndDate: end}
+
+	tests := []struct {
+		now  time.Time
+		want EventState
+	}{
+		{start.Add(-time.Hour), EventNotStarted},
+		{start, EventInProgress},
+		{end.Add(20 * time.Hour), EventInProgress},
+		{end.AddDate(0, 0, 1), EventCompleted},
+	}
+	for _, tt := range tests {
+		if got := eventStatusAt(detail, tt.now); got != tt.want {
+			t.Errorf("eventStatusAt(%v) = %v; want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestEventStatusAtFallsBackToDates(t *testing.T) {
+	detail := &EventDetail{Dates: []string{"2026-05-09", "2026-05-02"}}
+
+	if got := eventStatusAt(detail, time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)); got != EventNotStarted {
+		t.Errorf("before first date = %v; want %v", got, EventNotStarted)
+	}
+	if got := eventStatusAt(detail, time.Date(2026, time.May, 5, 0, 0, 0, 0, time.UTC)); got != EventInProgress {
+		t.Errorf("between dates = %v; want %v", got, EventInProgress)
+	}
+	if got := eventStatusAt(detail, time.Date(2026, time.May, 10, 0, 0, 0, 0, time.UTC)); got != EventCompleted {
+		t.Errorf("after last date = %v; want %v", got, EventCompleted)
+	}
+	if got := eventStatusAt(&EventDetail{}, time.Now()); got != EventInProgress {
+		t.Errorf("unknown dates = %v; want %v", got, EventInProgress)
+	}
+}
+
+func TestBuildEventStatusNote(t *testing.T) {
+	detail := &EventDetail{Title: "Tuesday Night Swiss"}
+	if note := BuildEventStatusNote(detail, EventInProgress); note != "" {
+		t.Errorf("in progress note = %q; want empty", note)
+	}
+	if note := BuildEventStatusNote(detail, EventNotStarted); !strings.Contains(note, "not started") {
+		t.Errorf("not started note = %q", note)
+	}
+	if note := BuildEventStatusNote(detail, EventCompleted); !strings.Contains(note, "files the event") {
+		t.Errorf("unfiled completed note = %q", note)
+	}
+	detail.UscfTid = 202605021234
+	if note := BuildEventStatusNote(detail, EventCompleted); !strings.Contains(note, "final results from USCF") {
+		t.Errorf("filed completed note = %q", note)
+	}
+}
+
+func TestNumRoundsFromDetail(t *testing.T) {
+	tests := []struct {
+		detail EventDetail
+		want   int
+	}{
+		{EventDetail{EventFormat: "4-SS"}, 4},
+		{EventDetail{Title: "Tuesday Night 5 Round Swiss"}, 5},
+		{EventDetail{Description: "A 3 rd event, G/90;d5"}, 3},
+		{EventDetail{Description: "Swiss system, G/60"}, 0},
+		{EventDetail{RoundTimes: "Rd 1 10:00am, Rd 2 1:30pm, Rd. 3 4:30pm"}, 3},
+		{EventDetail{EventFormat: "4-SS", RoundTimes: "Round 1: 7pm"}, 4},
+	}
+	for _, tt := range tests {
+		if got := numRoundsFromDetail(&tt.detail); got != tt.want {
+			t.Errorf("numRoundsFromDetail(%+v) = %v; want %v", tt.detail, got,
+				tt.want)
+		}
+	}
+}
+
+func TestTournamentOverAt(t *testing.T) {
+	start := time.Date(2026, time.May, 2, 10, 0, 0, 0, time.UTC)
+	detail := &EventDetail{StartDate: start, EndDate: start, EventFormat: "3-SS"}
+	now := start.Add(4 * time.Hour)
+	one, zero := "1", "0"
+	pts := 1.0
+	decided := Pairing{RoundNumber: 3, WhiteResult: &one, BlackResult: &zero}
+	bye := Pairing{RoundNumber: 3, IsByePairing: true, WhitePoints: &pts}
+	pending := Pairing{RoundNumber: 3}
+
+	tests := []struct {
+		name     string
+		pairings []Pairing
+		now      time.Time
+		want     bool
+	}{
+		{"final round done", []Pairing{decided, bye}, now, true},
+		{"final round pending", []Pairing{decided, pending}, now, false},
+		{"earlier round", []Pairing{{RoundNumber: 2, WhiteResult: &one,
+			BlackResult: &zero}}, now, false},
+		{"no pairings", nil, now, false},
+		{"event over", []Pairing{pending}, start.AddDate(0, 0, 2), true},
+	}
+	for _, tt := range tests {
+		tourney := &Tournament{CurrentPairings: tt.pairings}
+		if got := tournamentOverAt(detail, tourney, tt.now); got != tt.want {
+			t.Errorf("%v: tournamentOverAt() = %v; want %v", tt.name, got,
+				tt.want)
+		}
+	}
+}
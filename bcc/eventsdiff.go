@@ -0,0 +1,198 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// eventsSnapshotKey is the key under which CheckEventChanges stores the
+// events list it last saw.
+const eventsSnapshotKey = "bcc:events-snapshot"
+
+// SnapshotStore persists small pieces of state, such as the events list
+// CheckEventChanges last saw, under keys of their own. *s3cache.Cache
+// satisfies it.
+type SnapshotStore interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, data []byte) error
+}
+
+// RescheduledEvent is an event whose date changed between two events lists.
+type RescheduledEvent struct {
+	Prev Event
+	Curr Event
+}
+
+// EventsDiff holds the differences between two events lists.
+type EventsDiff struct {
+	Added []Event
+	// Removed lists the events no longer listed. Events also drop off the
+	// calendar once they are over; see DropEnded.
+	Removed     []Event
+	Rescheduled []RescheduledEvent
+}
+
+// Empty reports whether the events lists were the same.
+func (d EventsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 &&
+		len(d.Rescheduled) == 0
+}
+
+// DropEnded returns a copy of d without the removed events which ended
+// before now's date, so that only cancelled events remain.
+func (d EventsDiff) DropEnded(now time.Time) EventsDiff {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0,
+		now.Location())
+	result := d
+	result.Removed = nil
+	for _, ev := range d.Removed {
+		end := ev.EndDate
+		if end.IsZero() || end.Before(ev.Date) {
+			end = ev.Date
+		}
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0,
+			now.Location())
+		if !end.Before(today) {
+			result.Removed = append(result.Removed, ev)
+		}
+	}
+
+	return result
+}
+
+// DiffEvents compares two events lists by EventID, returning the events
+// added to curr, those removed from prev, and those whose date changed.
+// Each list is ordered by date.
+func DiffEvents(prev []Event, curr []Event) EventsDiff {
+	prevByID := make(map[int]Event, len(prev))
+	for _, ev := range prev {
+		prevByID[ev.EventID] = ev
+	}
+	currByID := make(map[int]Event, len(curr))
+	for _, ev := range curr {
+		currByID[ev.EventID] = ev
+	}
+
+	var diff EventsDiff
+	for _, ev := range curr {
+		old, ok := prevByID[ev.EventID]
+		if !ok {
+			diff.Added = append(diff.Added, ev)
+		} else if !old.Date.Equal(ev.Date) || !old.EndDate.Equal(ev.EndDate) {
+			diff.Rescheduled = append(diff.Rescheduled,
+				RescheduledEvent{Prev: old, Curr: ev})
+		}
+	}
+	for _, ev := range prev {
+		if _, ok := currByID[ev.EventID]; !ok {
+			diff.Removed = append(diff.Removed, ev)
+		}
+	}
+
+	byDate := func(events []Event) {
+		sort.SliceStable(events, func(i, j int) bool {
+			if !events[i].Date.Equal(events[j].Date) {
+				return events[i].Date.Before(events[j].Date)
+			}
+			return events[i].EventID < events[j].EventID
+		})
+	}
+	byDate(diff.Added)
+	byDate(diff.Removed)
+	sort.SliceStable(diff.Rescheduled, func(i, j int) bool {
+		a, b := diff.Rescheduled[i].Curr, diff.Rescheduled[j].Curr
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.EventID < b.EventID
+	})
+
+	return diff
+}
+
+// CheckEventChanges compares the current events list with the one saved by
+// the previous check, saves the current list in its place, and returns the
+// events added, cancelled, or rescheduled since. Events which simply ended
+// are not reported as cancelled. first is set, and no changes are
+// reported, when there was no previous list to compare with. Nor are changes
+// reported when the current list can't be saved, so that the next check
+// reports them once rather than every check reporting them again.
+func CheckEventChanges(ctx context.Context, store SnapshotStore,
+	now time.Time) (diff EventsDiff, first bool, err error) {
+
+	curr, err := RefreshEvents(ctx)
+	if err != nil {
+		return EventsDiff{}, false, err
+	}
+
+	return checkEventChanges(store, curr, now)
+}
+
+func checkEventChanges(store SnapshotStore, curr []Event,
+	now time.Time) (EventsDiff, bool, error) {
+
+	var prev []Event
+	data, ok := store.Get(eventsSnapshotKey)
+	if ok {
+		var err error
+		prev, err = decodeEvents(bytes.NewReader(data))
+		if err != nil {
+			log.Printf("bcc: events snapshot: discarding unreadable snapshot: %v",
+				err)
+			ok = false
+		}
+	}
+
+	data, err := json.Marshal(curr)
+	if err != nil {
+		return EventsDiff{}, false,
+			fmt.Errorf("unable to save bcc events snapshot: %w", err)
+	}
+	if err := store.Put(eventsSnapshotKey, data); err != nil {
+		return EventsDiff{}, false,
+			fmt.Errorf("unable to save bcc events snapshot: %w", err)
+	}
+	if !ok {
+		return EventsDiff{}, true, nil
+	}
+
+	return DiffEvents(prev, curr).DropEnded(now), false, nil
+}
+
+// BuildEventsDiffOutput formats event changes as an announcement, linking
+// each event to its page on the club's website.
+func BuildEventsDiffOutput(diff EventsDiff) string {
+	var sb strings.Builder
+	link := func(ev Event) string {
+		return fmt.Sprintf("[%v](https://boylstonchess.org/events/%d)",
+			ev.Title, ev.EventID)
+	}
+	date := func(ev Event) string {
+		return ev.Date.Format("Mon Jan 2")
+	}
+
+	for _, ev := range diff.Added {
+		sb.WriteString(fmt.Sprintf("New event: %v on %v\n", link(ev),
+			date(ev)))
+	}
+	for _, r := range diff.Rescheduled {
+		sb.WriteString(fmt.Sprintf("Rescheduled: %v moved from %v to %v\n",
+			link(r.Curr), date(r.Prev), date(r.Curr)))
+	}
+	for _, ev := range diff.Removed {
+		sb.WriteString(fmt.Sprintf("Cancelled: %v on %v\n", ev.Title,
+			date(ev)))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memSnapshotStore is an in-memory SnapshotStore
+type memSnapshotStore map[string][]byte
+
+func (s memSnapshotStore) Get(key string) ([]byte, bool) {
+	data, ok := s[key]
+	return data, ok
+}
+
+func (s memSnapshotStore) Put(key string, data []byte) error {
+	s[key] = data
+	return nil
+}
+
+// failingSnapshotStore is a memSnapshotStore whose Put fails while fail is
+// set
+type failingSnapshotStore struct {
+	memSnapshotStore
+	fail bool
+}
+
+func (s *failingSnapshotStore) Put(key string, data []byte) error {
+	if s.fail {
+		return errors.New("put failed")
+	}
+	return s.memSnapshotStore.Put(key, data)
+}
+
+func TestDiffEvents(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC)
+	}
+	prev := []Event{
+		{EventID: 1, Title: "Thursday Night Swiss", Date: day(5)},
+		{EventID: 2, Title: "Saturday Quads", Date: day(7)},
+		{EventID: 3, Title: "Sunday Blitz", Date: day(8)},
+		{EventID: 4, Title: "Past Event", Date: day(1)},
+	}
+	curr := []Event{
+		{EventID: 5, Title: "Spring Open", Date: day(21),
+			EndDate: day(22)},
+		{EventID: 1, Title: "Thursday Night Swiss", Date: day(5)},
+		{EventID: 3, Title: "Sunday Blitz", Date: day(15)},
+		{EventID: 6, Title: "Monday Rapid", Date: day(9)},
+	}
+
+	ids := func(events []Event) []int {
+		result := make([]int, 0, len(events))
+		for _, ev := range events {
+			result = append(result, ev.EventID)
+		}
+		return result
+	}
+	diff := DiffEvents(prev, curr)
+	if got := ids(diff.Added); !reflect.DeepEqual(got, []int{6, 5}) {
+		t.Errorf("added = %v; want [6 5]", got)
+	}
+	if got := ids(diff.Removed); !reflect.DeepEqual(got, []int{4, 2}) {
+		t.Errorf("removed = %v; want [4 2]", got)
+	}
+	if len(diff.Rescheduled) != 1 || diff.Rescheduled[0].Curr.EventID != 3 ||
+		!diff.Rescheduled[0].Prev.Date.Equal(day(8)) {
+
+		t.Errorf("rescheduled = %+v; want event 3 from Mar 8", diff.Rescheduled)
+	}
+
+	// event 4 ended before Mar 3 and so was not cancelled
+	diff = diff.DropEnded(day(3).Add(10 * time.Hour))
+	if got := ids(diff.Removed); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("cancelled = %v; want [2]", got)
+	}
+
+	output := BuildEventsDiffOutput(diff)
+	for _, want := range []string{
+		"New event: [Monday Rapid](https://boylstonchess.org/events/6) on Mon Mar 9",
+		"Rescheduled: [Sunday Blitz](https://boylstonchess.org/events/3) moved from Sun Mar 8 to Sun Mar 15",
+		"Cancelled: Saturday Quads on Sat Mar 7",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+
+	if diff := DiffEvents(curr, curr); !diff.Empty() {
+		t.Errorf("DiffEvents(curr, curr) = %+v; want no changes", diff)
+	}
+}
+
+func TestCheckEventChanges(t *testing.T) {
+	now := time.Date(2026, time.March, 3, 12, 0, 0, 0, time.UTC)
+	before := []Event{
+		{EventID: 1, Title: "Thursday Night Swiss",
+			Date: time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	after := append(before, Event{EventID: 2, Title: "Saturday Quads",
+		Date: time.Date(2026, time.March, 7, 0, 0, 0, 0, time.UTC)})
+
+	// the first check has nothing to compare with and announces nothing
+	store := memSnapshotStore{}
+	diff, first, err := checkEventChanges(store, before, now)
+	if err != nil || !first || !diff.Empty() {
+		t.Fatalf("first check = %+v, %v, %v; want no changes on first run",
+			diff, first, err)
+	}
+
+	diff, first, err = checkEventChanges(store, after, now)
+	if err != nil || first {
+		t.Fatalf("second check = %v, %v; want a comparison", first, err)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].EventID != 2 ||
+		!diff.Added[0].Date.Equal(after[1].Date) {
+
+		t.Errorf("added = %+v; want Saturday Quads", diff.Added)
+	}
+	if len(diff.Removed) != 0 || len(diff.Rescheduled) != 0 {
+		t.Errorf("unexpected changes %+v", diff)
+	}
+
+	// changes are not reported while the snapshot can't be saved, and are
+	// reported by the first check which saves it
+	failing := &failingSnapshotStore{memSnapshotStore: memSnapshotStore{}}
+	if _, _, err = checkEventChanges(failing, before, now); err != nil {
+		t.Fatalf("check with working store err = %v", err)
+	}
+	failing.fail = true
+	for range 2 {
+		diff, _, err = checkEventChanges(failing, after, now)
+		if err == nil || !diff.Empty() {
+			t.Errorf("check with failing store = %+v, %v; want an error",
+				diff, err)
+		}
+	}
+	failing.fail = false
+	diff, _, err = checkEventChanges(failing, after, now)
+	if err != nil || len(diff.Added) != 1 {
+		t.Errorf("check after recovery = %+v, %v; want Saturday Quads", diff,
+			err)
+	}
+	diff, _, err = checkEventChanges(failing, after, now)
+	if err != nil || !diff.Empty() {
+		t.Errorf("repeat check = %+v, %v; want no changes", diff, err)
+	}
+
+	// an unreadable snapshot is replaced rather than announced against
+	store[eventsSnapshotKey] = []byte("not json")
+	diff, first, err = checkEventChanges(store, after, now)
+	if err != nil || !first || !diff.Empty() {
+		t.Errorf("check after corrupt snapshot = %+v, %v, %v", diff, first,
+			err)
+	}
+}
@@ -0,0 +1,127 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// ExpectedScore is an approximation of the points a player can expect to
+// score against a section's field.
+type ExpectedScore struct {
+	Rating  int
+	Section string
+	Rounds  int
+	// FieldSize is the number of rated opponents in the section
+	FieldSize int
+	// Opponents are the ratings of the opponents assumed to be faced, one
+	// per round
+	Opponents []int
+	Points    float64
+}
+
+// EstimateEventScore approximates the points the player with the given USCF
+// id can expect to score over an event's rounds. A swiss is approximated by
+// pairing the player against the rounds opponents nearest the median of the
+// section's rated field. When the player is entered, their entry's rating
+// and section are used unless rating or section is given; rounds defaults
+// to the event's advertised number of rounds. Unrated entries are left out
+// of the field.
+func EstimateEventScore(detail *EventDetail, uscfID int, rating int,
+	section string, rounds int) (ExpectedScore, error) {
+
+	for _, entry := range detail.Entries {
+		if uscfID <= 0 || entry.UscfID != uscfID {
+			continue
+		}
+		if rating <= 0 {
+			rating = strRatingToInt(entry.PrimaryRating)
+		}
+		if section == "" {
+			section = entry.SectionName
+		}
+	}
+	if rating <= 0 {
+		return ExpectedScore{}, fmt.Errorf("player %v is unrated", uscfID)
+	}
+	if rounds <= 0 {
+		rounds = numRoundsFromDetail(detail)
+	}
+	if rounds <= 0 {
+		return ExpectedScore{}, fmt.Errorf("unable to determine the number of rounds of event %v",
+			detail.EventID)
+	}
+
+	var field []int
+	for _, entry := range detail.Entries {
+		if entry.UscfID == uscfID && uscfID > 0 {
+			continue
+		}
+		if !SectionMatches(entry.SectionName, section) {
+			continue
+		}
+		if r := strRatingToInt(entry.PrimaryRating); r > 0 {
+			field = append(field, r)
+		}
+	}
+	if len(field) == 0 {
+		return ExpectedScore{}, fmt.Errorf("no rated opponents found")
+	}
+
+	return estimateScore(rating, field, rounds, section), nil
+}
+
+// estimateScore sums the player's expected score against the rounds
+// opponents nearest the field's median. Opponents are repeated when the
+// field is smaller than the number of rounds.
+func estimateScore(rating int, field []int, rounds int,
+	section string) ExpectedScore {
+
+	sorted := append([]int(nil), field...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	start := 0
+	if rounds < len(sorted) {
+		start = (len(sorted) - rounds) / 2
+	}
+
+	est := ExpectedScore{
+		Rating:    rating,
+		Section:   section,
+		Rounds:    rounds,
+		FieldSize: len(field),
+	}
+	for i := 0; i < rounds; i++ {
+		opp := sorted[(start+i)%len(sorted)]
+		est.Opponents = append(est.Opponents, opp)
+		est.Points += internal.ExpectedScore(float64(rating), float64(opp))
+	}
+
+	return est
+}
+
+// BuildExpectedScoreOutput formats an expected score estimate.
+func BuildExpectedScoreOutput(est ExpectedScore) string {
+	section := est.Section
+	if section == "" {
+		section = "All"
+	}
+	opps := make([]string, 0, len(est.Opponents))
+	for _, opp := range est.Opponents {
+		opps = append(opps, fmt.Sprintf("%v", opp))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Expected score for a %v rated player in the %v section: %.1f / %v\n",
+		est.Rating, section, est.Points, est.Rounds))
+	sb.WriteString(fmt.Sprintf("Assumed opponents (median of %v rated entries): %v\n",
+		est.FieldSize, strings.Join(opps, ", ")))
+	sb.WriteString("This is an approximation; actual swiss pairings depend on results.\n")
+
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEstimateEventScore(t *testing.T) {
+	detail := &EventDetail{EventID: 42, EventFormat: "3-SS", Entries: []Entry{
+		{UscfID: 1, SectionName: "U1800", PrimaryRating: "1500"},
+		{UscfID: 2, SectionName: "U1800", PrimaryRating: "1600"},
+		{UscfID: 3, SectionName: "U1800", PrimaryRating: "1400"},
+		{UscfID: 4, SectionName: "U1800", PrimaryRating: "1300"},
+		{UscfID: 5, SectionName: "U1800", PrimaryRating: "1200"},
+		{UscfID: 6, SectionName: "U1800", PrimaryRating: "Unrated"},
+		{UscfID: 7, SectionName: "Open", PrimaryRating: "2100"},
+	}}
+
+	est, err := EstimateEventScore(detail, 1, 0, "", 0)
+	if err != nil {
+		t.Fatalf("EstimateEventScore() err = %v", err)
+	}
+	if est.Rating != 1500 || est.Section != "U1800" || est.Rounds != 3 ||
+		est.FieldSize != 4 {
+		t.Errorf("unexpected estimate: %+v", est)
+	}
+	if !slices.Equal(est.Opponents, []int{1600, 1400, 1300}) {
+		t.Errorf("Opponents = %v; want [1600 1400 1300]", est.Opponents)
+	}
+	// 0.360 + 0.640 + 0.760
+	if math.Abs(est.Points-1.7597) > 0.001 {
+		t.Errorf("Points = %v; want ~1.76", est.Points)
+	}
+	output := BuildExpectedScoreOutput(est)
+	for _, want := range []string{"in the U1800 section: 1.8 / 3",
+		"approximation"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	// a small field is faced repeatedly
+	est, err = EstimateEventScore(detail, 99, 2000, "Open", 2)
+	if err != nil {
+		t.Fatalf("EstimateEventScore() err = %v", err)
+	}
+	if !slices.Equal(est.Opponents, []int{2100, 2100}) {
+		t.Errorf("Opponents = %v; want [2100 2100]", est.Opponents)
+	}
+
+	if _, err := EstimateEventScore(detail, 6, 0, "", 0); err == nil {
+		t.Errorf("EstimateEventScore() of an unrated player succeeded")
+	}
+}
@@ -0,0 +1,80 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultFeaturedBoards is the number of boards FeaturedBoards selects when
+// not told otherwise.
+const DefaultFeaturedBoards = 3
+
+// FeaturedBoards returns up to n boards of the current round, across all
+// sections, with the highest combined rating of their two players; unrated
+// players count as 0. Byes are never featured. When pairings span several
+// rounds (predicted round robins) only the earliest round is considered.
+// Fewer than n boards are returned when fewer are paired.
+func FeaturedBoards(t *Tournament, n int) []Pairing {
+	round := 0
+	for _, p := range t.CurrentPairings {
+		if !p.IsByePairing && (round == 0 || p.RoundNumber < round) {
+			round = p.RoundNumber
+		}
+	}
+
+	var boards []Pairing
+	for _, p := range t.CurrentPairings {
+		if !p.IsByePairing && p.RoundNumber == round {
+			boards = append(boards, p)
+		}
+	}
+	combined := func(p Pairing) int {
+		return p.WhitePlayer.PrimaryRating + p.BlackPlayer.PrimaryRating
+	}
+	sort.SliceStable(boards, func(i, j int) bool {
+		if ci, cj := combined(boards[i]), combined(boards[j]); ci != cj {
+			return ci > cj
+		}
+		if boards[i].Section != boards[j].Section {
+			return SectionSorter{boards[i].Section,
+				boards[j].Section}.Less(0, 1)
+		}
+		return boards[i].BoardNumber < boards[j].BoardNumber
+	})
+
+	return boards[:min(max(n, 0), len(boards))]
+}
+
+// BuildFeaturedBoardsOutput formats boards selected by FeaturedBoards one
+// per line, e.g. "Open Board 1: Alice Smith (2105) vs Bob Jones (2010)",
+// for a broadcast post. See BuildBoardLinksOutput for their game links.
+func BuildFeaturedBoardsOutput(boards []Pairing) string {
+	if len(boards) == 0 {
+		return "No boards paired yet\n"
+	}
+
+	var sb strings.Builder
+	if len(boards) == 1 {
+		sb.WriteString(fmt.Sprintf("Round %v top board:\n",
+			boards[0].RoundNumber))
+	} else {
+		sb.WriteString(fmt.Sprintf("Round %v top %v boards:\n",
+			boards[0].RoundNumber, len(boards)))
+	}
+	for _, p := range boards {
+		board := fmt.Sprintf("Board %v", p.BoardNumber)
+		if p.Section != "" {
+			board = fmt.Sprintf("%v %v", p.Section, board)
+		}
+		sb.WriteString(fmt.Sprintf("  %v: %v (%v) vs %v (%v)\n", board,
+			p.WhitePlayer.DisplayName, displayRating(p.WhitePlayer),
+			p.BlackPlayer.DisplayName, displayRating(p.BlackPlayer)))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,70 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFeaturedBoards(t *testing.T) {
+	player := func(name string, rating int) Player {
+		return Player{DisplayName: name, PrimaryRating: rating}
+	}
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			{RoundNumber: 3, BoardNumber: 1, Section: "U1800",
+				WhitePlayer: player("Carol C", 1750),
+				BlackPlayer: player("Dan D", 1700)},
+			{RoundNumber: 3, BoardNumber: 1, Section: "Open",
+				WhitePlayer: player("Alice A", 2200),
+				BlackPlayer: player("Bob B", 2100),
+				GameLink:    "https://lichess.org/broadcast/b1"},
+			{RoundNumber: 3, BoardNumber: 2, Section: "Open",
+				WhitePlayer: player("Erin E", 1900),
+				BlackPlayer: player("Frank F", 0)},
+			{RoundNumber: 3, BoardNumber: 3, Section: "Open",
+				WhitePlayer: player("Gina G", 2000),
+				BlackPlayer: player("Hal H", 1950)},
+			{RoundNumber: 3, Section: "Open", IsByePairing: true,
+				WhitePlayer: player("Ivan I", 2400)},
+		},
+	}
+
+	boards := FeaturedBoards(tourney, 3)
+	var got []string
+	for _, p := range boards {
+		got = append(got, p.WhitePlayer.DisplayName)
+	}
+	if want := "Alice A,Gina G,Carol C"; strings.Join(got, ",") != want {
+		t.Errorf("FeaturedBoards() = %v; want %v", got, want)
+	}
+
+	output := BuildFeaturedBoardsOutput(boards)
+	for _, want := range []string{
+		"Round 3 top 3 boards:\n",
+		"  Open Board 1: Alice A (2200) vs Bob B (2100)\n",
+		"  U1800 Board 1: Carol C (1750) vs Dan D (1700)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+	if links := BuildBoardLinksOutput(boards); links !=
+		"Live games:\n  Open bd 1: https://lichess.org/broadcast/b1\n" {
+
+		t.Errorf("BuildBoardLinksOutput() = %q", links)
+	}
+
+	// fewer boards than requested
+	if boards := FeaturedBoards(tourney, 10); len(boards) != 4 {
+		t.Errorf("FeaturedBoards(10) returned %v boards; want 4", len(boards))
+	}
+	if output := BuildFeaturedBoardsOutput(FeaturedBoards(&Tournament{},
+		3)); output != "No boards paired yet\n" {
+
+		t.Errorf("BuildFeaturedBoardsOutput(none) = %q", output)
+	}
+}
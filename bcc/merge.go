@@ -0,0 +1,128 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// Player fields which mergeTournaments may patch from the website
+const (
+	FieldSectionName   = "SectionName"
+	FieldPrimaryRating = "PrimaryRating"
+	FieldUscfID        = "UscfID"
+)
+
+// mergeTournaments patches the preferred api tournament with data from the
+// web tournament. Stale api pairings are replaced wholesale, while missing
+// player fields (section names, ratings, USCF ids) are filled in individually
+// with their provenance recorded.
+func mergeTournaments(api, web *Tournament) *Tournament {
+	api.baseSource = api.source
+	if len(api.CurrentPairings) == 0 && len(web.CurrentPairings) > 0 {
+		// api has not yet picked up the posted pairings
+		api.CurrentPairings = web.CurrentPairings
+		api.dataAge = web.dataAge
+		api.source = SourceBoth
+	} else if len(api.CurrentPairings) > 0 &&
+		len(web.CurrentPairings) > 0 &&
+		api.CurrentPairings[0].RoundNumber <
+			web.CurrentPairings[0].RoundNumber {
+
+		// api is returning stale pairing data, so prefer the web response
+		api.CurrentPairings = web.CurrentPairings
+		api.dataAge = web.dataAge
+		api.source = SourceBoth
+	}
+
+	for idx := range api.Players {
+		p := &api.Players[idx]
+		wp := findMatchingPlayer(web.Players, p)
+		if wp == nil {
+			continue
+		}
+		if p.SectionName == "" && wp.SectionName != "" {
+			p.SectionName = wp.SectionName
+			p.setFieldSource(FieldSectionName, SourceWebsite)
+		}
+		if p.PrimaryRating == 0 && wp.PrimaryRating != 0 {
+			p.PrimaryRating = wp.PrimaryRating
+			p.RatingReported = wp.RatingReported
+			p.setFieldSource(FieldPrimaryRating, SourceWebsite)
+		}
+		if p.UscfID == 0 && wp.UscfID != 0 {
+			p.UscfID = wp.UscfID
+			p.setFieldSource(FieldUscfID, SourceWebsite)
+		}
+		if len(p.fieldSources) > 0 {
+			api.source = SourceBoth
+		}
+	}
+
+	// pairings without a section inherit the (possibly patched) section of
+	// their players
+	for idx := range api.CurrentPairings {
+		pair := &api.CurrentPairings[idx]
+		if pair.Section != "" {
+			continue
+		}
+		if p := findMatchingPlayer(api.Players, &pair.WhitePlayer); p != nil {
+			pair.Section = p.SectionName
+		}
+	}
+
+	return api
+}
+
+// findMatchingPlayer returns the player in players corresponding to p,
+// matching by USCF id when both are known and by name otherwise. An exact
+// (normalized) name is preferred over one which matches only as a nickname,
+// e.g. "Mike Brown" for "Michael Brown".
+func findMatchingPlayer(players []Player, p *Player) *Player {
+	name := internal.NormalizeName(p.DisplayName)
+	var nickname *Player
+	for idx := range players {
+		cand := &players[idx]
+		if p.UscfID != 0 && cand.UscfID != 0 {
+			if p.UscfID == cand.UscfID {
+				return cand
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+		if internal.NormalizeName(cand.DisplayName) == name {
+			return cand
+		}
+		if nickname == nil && internal.SamePlayer(uscfIdToString(cand.UscfID),
+			cand.DisplayName, uscfIdToString(p.UscfID), name) {
+
+			nickname = cand
+		}
+	}
+
+	return nickname
+}
+
+func (p *Player) setFieldSource(field string, src Source) {
+	if p.fieldSources == nil {
+		p.fieldSources = make(map[string]Source)
+	}
+	p.fieldSources[field] = src
+}
+
+// FieldSource returns where the given Player field's value came from
+func (t Tournament) FieldSource(p *Player, field string) Source {
+	if src, ok := p.fieldSources[field]; ok {
+		return src
+	}
+	if t.source == SourceBoth {
+		// fields the merge did not patch kept their pre-merge source
+		return t.baseSource
+	}
+
+	return t.source
+}
@@ -0,0 +1,146 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"testing"
+)
+
+// countMissing returns the number of player fields mergeTournaments can patch
+// which are unset in t
+func countMissing(t *Tournament) int {
+	missing := 0
+	for _, p := range t.Players {
+		if p.SectionName == "" {
+			missing++
+		}
+		if p.PrimaryRating == 0 {
+			missing++
+		}
+		if p.UscfID == 0 {
+			missing++
+		}
+	}
+	return missing
+}
+
+func TestMergeTournaments(t *testing.T) {
+	// api has current scores and USCF ids, but lacks section names and one
+	// rating
+	api := &Tournament{
+		source: SourceAPI,
+		Players: []Player{
+			{DisplayName: "Alice Smith", UscfID: 111, PrimaryRating: 1800, CurrentScore: 2},
+			{DisplayName: "Bob Jones", UscfID: 222, PrimaryRating: 0, CurrentScore: 1},
+			{DisplayName: "Carol White", UscfID: 0, PrimaryRating: 1500, SectionName: "U1800"},
+		},
+		CurrentPairings: []Pairing{
+			{RoundNumber: 3, BoardNumber: 1,
+				WhitePlayer: Player{DisplayName: "Alice Smith", UscfID: 111},
+				BlackPlayer: Player{DisplayName: "Bob Jones", UscfID: 222}},
+		},
+	}
+	// web has section names and ratings, but lacks one USCF id
+	web := &Tournament{
+		source: SourceWebsite,
+		Players: []Player{
+			{DisplayName: "Alice Smith", UscfID: 111, PrimaryRating: 1800, SectionName: "Open"},
+			{DisplayName: "Bob Jones", UscfID: 0, PrimaryRating: 1750, SectionName: "Open"},
+			{DisplayName: "Carol White", UscfID: 333, PrimaryRating: 1500, SectionName: "U1800"},
+		},
+		CurrentPairings: []Pairing{
+			{RoundNumber: 3, BoardNumber: 1, Section: "Open"},
+		},
+	}
+
+	apiMissing, webMissing := countMissing(api), countMissing(web)
+	merged := mergeTournaments(api, web)
+	mergedMissing := countMissing(merged)
+	if mergedMissing >= apiMissing || mergedMissing >= webMissing {
+		t.Fatalf("merged result missing %v fields; api missing %v; web missing %v",
+			mergedMissing, apiMissing, webMissing)
+	}
+	if mergedMissing != 0 {
+		t.Errorf("expected merged result to be complete; missing %v fields",
+			mergedMissing)
+	}
+
+	alice, bob, carol := &merged.Players[0], &merged.Players[1], &merged.Players[2]
+	if alice.SectionName != "Open" || alice.CurrentScore != 2 {
+		t.Errorf("alice = %+v", *alice)
+	}
+	if bob.PrimaryRating != 1750 || bob.UscfID != 222 {
+		t.Errorf("bob = %+v", *bob)
+	}
+	if carol.UscfID != 333 {
+		t.Errorf("carol UscfID = %v; want 333", carol.UscfID)
+	}
+
+	// provenance
+	if src := merged.FieldSource(bob, FieldPrimaryRating); src != SourceWebsite {
+		t.Errorf("bob rating source = %v; want %v", src, SourceWebsite)
+	}
+	if src := merged.FieldSource(bob, FieldUscfID); src != SourceAPI {
+		t.Errorf("bob uscfid source = %v; want %v", src, SourceAPI)
+	}
+	if src := merged.FieldSource(carol, FieldUscfID); src != SourceWebsite {
+		t.Errorf("carol uscfid source = %v; want %v", src, SourceWebsite)
+	}
+	if merged.source != SourceBoth {
+		t.Errorf("merged source = %v; want %v", merged.source, SourceBoth)
+	}
+
+	// pairings inherit the patched section
+	if sec := merged.CurrentPairings[0].Section; sec != "Open" {
+		t.Errorf("pairing section = %q; want Open", sec)
+	}
+}
+
+func TestMergeTournamentsStalePairings(t *testing.T) {
+	api := &Tournament{
+		source:          SourceAPI,
+		Players:         []Player{{DisplayName: "Alice Smith", UscfID: 111}},
+		CurrentPairings: []Pairing{{RoundNumber: 2, Section: "Open"}},
+	}
+	web := &Tournament{
+		source:          SourceWebsite,
+		Players:         []Player{{DisplayName: "Alice Smith", UscfID: 111}},
+		CurrentPairings: []Pairing{{RoundNumber: 3, Section: "Open"}},
+	}
+
+	merged := mergeTournaments(api, web)
+	if merged.CurrentPairings[0].RoundNumber != 3 {
+		t.Errorf("expected web pairings to replace stale api pairings")
+	}
+	if merged.source != SourceBoth {
+		t.Errorf("merged source = %v; want %v", merged.source, SourceBoth)
+	}
+}
+
+func TestFindMatchingPlayer(t *testing.T) {
+	players := []Player{
+		{DisplayName: "Michael Brown", UscfID: 111},
+		{DisplayName: "Mike Green"},
+		{DisplayName: "Michael Green"},
+	}
+
+	if got := findMatchingPlayer(players, &Player{DisplayName: "M Brown",
+		UscfID: 111}); got != &players[0] {
+		t.Errorf("id match = %+v", got)
+	}
+	if got := findMatchingPlayer(players,
+		&Player{DisplayName: "Mike Brown"}); got != &players[0] {
+		t.Errorf("nickname match = %+v", got)
+	}
+	// an exact name wins over an earlier nickname
+	if got := findMatchingPlayer(players,
+		&Player{DisplayName: "MICHAEL GREEN"}); got != &players[2] {
+		t.Errorf("exact match = %+v", got)
+	}
+	if got := findMatchingPlayer(players,
+		&Player{DisplayName: "Mark Brown"}); got != nil {
+		t.Errorf("mismatch = %+v", got)
+	}
+}
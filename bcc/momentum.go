@@ -0,0 +1,97 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+const (
+	// momentumRecentWindow is the window reported as recent signups
+	momentumRecentWindow = 48 * time.Hour
+	// momentumRateWindow is the window the signup rate is measured over; it
+	// is long enough to smooth over the burst of signups when registration
+	// opens but short enough to reflect current interest
+	momentumRateWindow = 7 * 24 * time.Hour
+	// momentumMinEntries is the fewest dated entries worth reporting on
+	momentumMinEntries = 3
+)
+
+// SignupMomentum describes how quickly an upcoming event is gaining entries.
+type SignupMomentum struct {
+	// Recent is the number of entries registered in the last 48 hours.
+	Recent int
+	// PerDay is the average number of entries registered per day over the
+	// past week, or since the first entry when that is more recent.
+	PerDay float64
+	// Projected is the expected number of entries by the event's start
+	// should the current rate continue.
+	Projected int
+}
+
+// ComputeSignupMomentum measures the registration rate of an upcoming event
+// from its entries' registration dates and projects its final number of
+// entries. ok is false for events which have started or which have too few
+// dated entries to measure.
+func ComputeSignupMomentum(detail *EventDetail) (SignupMomentum, bool) {
+	return signupMomentumAt(detail, internal.Now())
+}
+
+func signupMomentumAt(detail *EventDetail,
+	now time.Time) (SignupMomentum, bool) {
+
+	start, _ := eventDateRange(detail)
+	if start.IsZero() || eventStatusAt(detail, now) != EventNotStarted {
+		return SignupMomentum{}, false
+	}
+
+	var dated []time.Time
+	for _, entry := range detail.Entries {
+		if !entry.RegistrationDate.IsZero() &&
+			!entry.RegistrationDate.After(now) {
+			dated = append(dated, entry.RegistrationDate)
+		}
+	}
+	if len(dated) < momentumMinEntries {
+		return SignupMomentum{}, false
+	}
+
+	var momentum SignupMomentum
+	first := now
+	inRateWindow := 0
+	for _, registered := range dated {
+		if registered.Before(first) {
+			first = registered
+		}
+		age := now.Sub(registered)
+		if age < momentumRecentWindow {
+			momentum.Recent++
+		}
+		if age < momentumRateWindow {
+			inRateWindow++
+		}
+	}
+	// a day at minimum so that a burst of same day signups is not
+	// extrapolated as an hourly rate
+	span := min(max(now.Sub(first), 24*time.Hour), momentumRateWindow)
+	momentum.PerDay = float64(inRateWindow) / span.Hours() * 24
+
+	remaining := max(start.Sub(now), 0)
+	momentum.Projected = len(detail.Entries) +
+		int(math.Round(momentum.PerDay*remaining.Hours()/24))
+
+	return momentum, true
+}
+
+// String describes the momentum, e.g. "5 in last 48h (1.4/day; on pace for
+// about 24 entries)".
+func (m SignupMomentum) String() string {
+	return fmt.Sprintf("%v in last 48h (%.1f/day; on pace for about %v entries)",
+		m.Recent, m.PerDay, m.Projected)
+}
@@ -0,0 +1,59 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSignupMomentum(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days float64) Entry {
+		return Entry{RegistrationDate: now.Add(-time.Duration(days * 24 *
+			float64(time.Hour)))}
+	}
+	detail := &EventDetail{
+		StartDate: now.AddDate(0, 0, 5),
+		Entries: []Entry{
+			// a cluster when registration opened, outside the rate window
+			daysAgo(20), daysAgo(20), daysAgo(20), daysAgo(20),
+			daysAgo(6), daysAgo(4), daysAgo(3), daysAgo(1.5), daysAgo(0.5),
+			// without a registration date
+			{},
+		},
+	}
+
+	got, ok := signupMomentumAt(detail, now)
+	if !ok {
+		t.Fatalf("signupMomentumAt() not ok")
+	}
+	// 5 signups over the past week is ~0.71/day, or ~4 more in 5 days
+	if got.Recent != 2 || got.Projected != 14 ||
+		got.String() != "2 in last 48h (0.7/day; on pace for about 14 entries)" {
+		t.Errorf("signupMomentumAt() = %+v (%v)", got, got)
+	}
+
+	// a burst of same day signups is not extrapolated as an hourly rate
+	burst := &EventDetail{
+		StartDate: now.AddDate(0, 0, 2),
+		Entries:   []Entry{daysAgo(0.1), daysAgo(0.1), daysAgo(0.1)},
+	}
+	if got, ok := signupMomentumAt(burst, now); !ok || got.PerDay != 3 ||
+		got.Projected != 9 {
+		t.Errorf("signupMomentumAt() of a burst = %+v, %v", got, ok)
+	}
+
+	// too few dated entries or already started
+	sparse := &EventDetail{StartDate: now.AddDate(0, 0, 5),
+		Entries: []Entry{daysAgo(1), {}, {}}}
+	if _, ok := signupMomentumAt(sparse, now); ok {
+		t.Errorf("signupMomentumAt() of sparse entries is ok")
+	}
+	detail.StartDate = now.AddDate(0, 0, -1)
+	if _, ok := signupMomentumAt(detail, now); ok {
+		t.Errorf("signupMomentumAt() of a started event is ok")
+	}
+}
@@ -0,0 +1,133 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// normalizeQueryName converts a player name as typed by a user, either
+// "First Last" or "Last, First", into the same form as Player.DisplayName.
+func normalizeQueryName(name string) string {
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = first + " " + last
+	}
+	return internal.NormalizeName(name)
+}
+
+// nameMatches reports whether every word of query is a case insensitive
+// prefix of a distinct word in displayName, e.g. "Jo Sm" matches
+// "John Smith".
+func nameMatches(query string, displayName string) bool {
+	queryWords := strings.Fields(strings.ToLower(query))
+	nameWords := strings.Fields(strings.ToLower(displayName))
+	if len(queryWords) == 0 {
+		return false
+	}
+	used := make([]bool, len(nameWords))
+	for _, qw := range queryWords {
+		found := false
+		for idx, nw := range nameWords {
+			if !used[idx] && strings.HasPrefix(nw, qw) {
+				used[idx] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findPlayerPairings returns the pairings of the players whose names match
+// name, keyed by display name. Exact matches take precedence over prefix
+// matches so that e.g. "John Smith" is not ambiguous with "John Smithers".
+// When a player has several pairings (predicted round robins) only their
+// earliest round is kept.
+func findPlayerPairings(t *Tournament, name string) map[string]Pairing {
+	query := normalizeQueryName(name)
+	exact := make(map[string]Pairing)
+	fuzzy := make(map[string]Pairing)
+	add := func(matches map[string]Pairing, player string, p Pairing) {
+		if prev, ok := matches[player]; !ok || p.RoundNumber < prev.RoundNumber {
+			matches[player] = p
+		}
+	}
+	for _, p := range t.CurrentPairings {
+		for _, player := range []Player{p.WhitePlayer, p.BlackPlayer} {
+			if player.DisplayName == "" || player.DisplayName == "BYE" {
+				continue
+			}
+			if strings.EqualFold(player.DisplayName, query) {
+				add(exact, player.DisplayName, p)
+			} else if nameMatches(query, player.DisplayName) {
+				add(fuzzy, player.DisplayName, p)
+			}
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+
+	return fuzzy
+}
+
+// opponentSummary formats a player's opponent as "Smith (1800)".
+func opponentSummary(opp Player) string {
+	lastName := opp.LastName
+	if lastName == "" {
+		lastName = opp.DisplayName
+	}
+	if opp.PrimaryRating <= 0 {
+		return fmt.Sprintf("%v (unrated)", lastName)
+	}
+	return fmt.Sprintf("%v (%v)", lastName, opp.PrimaryRating)
+}
+
+// BuildMyGameOutput describes the current board, color, and opponent of the
+// player matching name, e.g. "You're on Board 3 with White vs. Smith (1800)".
+// When name matches several players the user is asked to be more specific.
+func BuildMyGameOutput(t *Tournament, name string) string {
+	matches := findPlayerPairings(t, name)
+	if len(matches) == 0 {
+		return fmt.Sprintf("No current pairing found for '%v'.", name)
+	}
+	if len(matches) > 1 {
+		players := make([]string, 0, len(matches))
+		for player := range matches {
+			players = append(players, player)
+		}
+		sort.Strings(players)
+		return fmt.Sprintf("Multiple players match '%v': %v. Please provide a more specific name.",
+			name, strings.Join(players, ", "))
+	}
+
+	var sb strings.Builder
+	for player, p := range matches {
+		color, opp := "White", p.BlackPlayer
+		if p.BlackPlayer.DisplayName == player {
+			color, opp = "Black", p.WhitePlayer
+		}
+		if p.IsByePairing || opp.DisplayName == "" || opp.DisplayName == "BYE" {
+			sb.WriteString(fmt.Sprintf("You have a bye in round %v.",
+				p.RoundNumber))
+		} else {
+			sb.WriteString(fmt.Sprintf("You're on Board %v with %v vs. %v",
+				p.BoardNumber, color, opponentSummary(opp)))
+		}
+	}
+	if t.IsPredicted() {
+		sb.WriteString("\n* Pairings are not yet posted; this is my prediction.")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,59 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMyGameOutput(t *testing.T) {
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			{
+				WhitePlayer: Player{DisplayName: "John Smith", LastName: "Smith",
+					PrimaryRating: 1850},
+				BlackPlayer: Player{DisplayName: "John Doe", LastName: "Doe",
+					PrimaryRating: 1700},
+				RoundNumber: 2,
+				BoardNumber: 1,
+			},
+			{
+				WhitePlayer: Player{DisplayName: "Jane Smithers",
+					LastName: "Smithers"},
+				BlackPlayer: Player{DisplayName: "Mary O'Brien",
+					LastName: "O'Brien", PrimaryRating: 1500},
+				RoundNumber: 2,
+				BoardNumber: 2,
+			},
+			{
+				WhitePlayer:  Player{DisplayName: "Pat Lee", LastName: "Lee"},
+				BlackPlayer:  Player{DisplayName: "BYE"},
+				RoundNumber:  2,
+				IsByePairing: true,
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Doe, John", "You're on Board 1 with Black vs. Smith (1850)"},
+		{"john smith", "You're on Board 1 with White vs. Doe (1700)"},
+		{"o'brien", "You're on Board 2 with Black vs. Smithers (unrated)"},
+		{"Smithers, J", "You're on Board 2 with White vs. O'Brien (1500)"},
+		{"Pat Lee", "You have a bye in round 2."},
+		{"Smith", "Multiple players match 'Smith': Jane Smithers, John Smith."},
+		{"Carlsen", "No current pairing found for 'Carlsen'."},
+	}
+	for _, tc := range tests {
+		got := BuildMyGameOutput(tourney, tc.name)
+		if !strings.HasPrefix(got, tc.want) {
+			t.Errorf("BuildMyGameOutput(%q) = %q; want prefix %q", tc.name,
+				got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrNoPostedPairings is returned by PredictNextRound when there are no
+// posted pairings to follow on from.
+var ErrNoPostedPairings = errors.New("no posted pairings to predict the next round from")
+
+// swissPlayer is a player's record within a section as known from the posted
+// pairings, used to predict the section's next round.
+type swissPlayer struct {
+	player    Player
+	score     float64
+	opponents map[string]bool
+	colors    []color
+	hadBye    bool
+}
+
+// NextRoundPrediction holds the pairings predicted by PredictNextRound.
+type NextRoundPrediction struct {
+	// Tournament holds the predicted pairings, each player's score being
+	// their score going into the predicted round.
+	*Tournament
+	// KnownRounds lists the rounds whose pairings the prediction was based
+	// on; opponents and colors from any other round are not known.
+	KnownRounds []int
+	// InProgress is set when not every result of the latest known round was
+	// posted, in which case scores as of that round's start were used.
+	InProgress bool
+}
+
+// PredictNextRound predicts the pairings of the round following the latest
+// posted round of a swiss, section by section, in the manner of US Chess
+// rules: players are paired within score groups, top half against bottom
+// half, avoiding rematches by transposing within the group or, failing
+// that, floating its lowest players down. A rematch is repeated only when no
+// lower group remains to float into. Colors go to the player due them.
+// A bye goes to the lowest ranked player who has not yet had one. Only the
+// rounds held in t.CurrentPairings are known, so a rematch with an opponent
+// from an earlier round is not avoided, and bye requests are not considered.
+func PredictNextRound(t *Tournament) (*NextRoundPrediction, error) {
+	if t.IsPredicted() || len(t.CurrentPairings) == 0 {
+		return nil, ErrNoPostedPairings
+	}
+
+	latest := maxPairingRound(t.CurrentPairings)
+	rounds := make(map[int]bool)
+	sections := make(map[string]map[string]*swissPlayer)
+	inProgress := false
+	for _, p := range t.CurrentPairings {
+		rounds[p.RoundNumber] = true
+		if p.RoundNumber == latest && !p.IsByePairing && !resultPosted(p) {
+			inProgress = true
+		}
+		players, ok := sections[p.Section]
+		if !ok {
+			players = make(map[string]*swissPlayer)
+			sections[p.Section] = players
+		}
+		if p.IsByePairing {
+			recordSwissGame(players, p, p.ByePlayer(), latest).hadBye = true
+			continue
+		}
+		wsp := recordSwissGame(players, p, p.WhitePlayer, latest)
+		bsp := recordSwissGame(players, p, p.BlackPlayer, latest)
+		wsp.opponents[swissPlayerKey(p.BlackPlayer)] = true
+		bsp.opponents[swissPlayerKey(p.WhitePlayer)] = true
+		wsp.colors = append(wsp.colors, white)
+		bsp.colors = append(bsp.colors, black)
+	}
+
+	var sectionNames []string
+	for sec := range sections {
+		sectionNames = append(sectionNames, sec)
+	}
+	sort.Sort(SectionSorter(sectionNames))
+
+	prediction := &NextRoundPrediction{
+		Tournament: &Tournament{
+			Players:     t.Players,
+			isPredicted: true,
+			source:      t.source,
+			dataAge:     t.dataAge,
+		},
+		InProgress: inProgress,
+	}
+	for round := range rounds {
+		prediction.KnownRounds = append(prediction.KnownRounds, round)
+	}
+	sort.Ints(prediction.KnownRounds)
+
+	boardNum := 1
+	for _, sec := range sectionNames {
+		players := make([]*swissPlayer, 0, len(sections[sec]))
+		for _, sp := range sections[sec] {
+			players = append(players, sp)
+		}
+		prediction.CurrentPairings = append(prediction.CurrentPairings,
+			predictSwissSection(sec, players, latest+1, &boardNum)...)
+	}
+
+	return prediction, nil
+}
+
+// recordSwissGame returns the record of player in players, adding it if
+// needed, and takes their score from their pairing in the latest round.
+func recordSwissGame(players map[string]*swissPlayer, p Pairing,
+	player Player, latest int) *swissPlayer {
+
+	key := swissPlayerKey(player)
+	sp, ok := players[key]
+	if !ok {
+		sp = &swissPlayer{player: player, opponents: make(map[string]bool)}
+		players[key] = sp
+	}
+	if p.RoundNumber == latest {
+		sp.player = player
+		sp.score = max(player.CurrentScoreAG, player.CurrentScore)
+	}
+
+	return sp
+}
+
+// swissPlayerKey identifies a player within a section by USCF id when known,
+// or by name otherwise.
+func swissPlayerKey(player Player) string {
+	if player.UscfID > 0 {
+		return fmt.Sprintf("%v", player.UscfID)
+	}
+
+	return strings.ToLower(strings.TrimSpace(player.DisplayName))
+}
+
+// resultPosted reports whether a game's result is known, either as parsed
+// from the website or as points reported by the API.
+func resultPosted(p Pairing) bool {
+	if p.WhiteOutcome == ResultPending || p.BlackOutcome == ResultPending {
+		return false
+	}
+
+	return p.WhiteOutcome != ResultNone || p.WhitePoints != nil ||
+		p.BlackPoints != nil
+}
+
+// predictSwissSection pairs one section's players for round.
+func predictSwissSection(sec string, players []*swissPlayer, round int,
+	boardNum *int) []Pairing {
+
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.player.PrimaryRating != b.player.PrimaryRating {
+			return a.player.PrimaryRating > b.player.PrimaryRating
+		}
+		return swissPlayerKey(a.player) < swissPlayerKey(b.player)
+	})
+
+	var bye *swissPlayer
+	if len(players)%2 == 1 {
+		byeIdx := len(players) - 1
+		for idx := len(players) - 1; idx >= 0; idx-- {
+			if !players[idx].hadBye {
+				byeIdx = idx
+				break
+			}
+		}
+		bye = players[byeIdx]
+		players = append(players[:byeIdx:byeIdx], players[byeIdx+1:]...)
+	}
+
+	// split into score groups, highest first
+	var groups [][]*swissPlayer
+	for idx, sp := range players {
+		if idx == 0 || sp.score != players[idx-1].score {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], sp)
+	}
+
+	var pairs [][2]*swissPlayer
+	var floaters []*swissPlayer
+	for idx, group := range groups {
+		group = append(append([]*swissPlayer(nil), floaters...), group...)
+		floaters = nil
+		last := idx == len(groups)-1
+		if len(group)%2 == 1 && !last {
+			floaters = group[len(group)-1:]
+			group = group[:len(group)-1]
+		}
+		// with no lower group to float into, the last group's unavoidable
+		// rematches are kept
+		groupPairs, rematches := pairScoreGroup(group)
+		for rematches > 0 && !last && len(group) >= 2 {
+			// float the lowest two down to the next group
+			floaters = append(append([]*swissPlayer(nil),
+				group[len(group)-2:]...), floaters...)
+			group = group[:len(group)-2]
+			groupPairs, rematches = pairScoreGroup(group)
+		}
+		pairs = append(pairs, groupPairs...)
+	}
+
+	pairings := make([]Pairing, 0, len(pairs)+1)
+	for _, pair := range pairs {
+		w, b := assignSwissColors(pair[0], pair[1])
+		pairings = append(pairings, Pairing{
+			WhitePlayer: swissRoundPlayer(w),
+			BlackPlayer: swissRoundPlayer(b),
+			Section:     sec,
+			RoundNumber: round,
+			BoardNumber: *boardNum,
+		})
+		(*boardNum)++
+	}
+	if bye != nil {
+		points := 1.0
+		pairings = append(pairings, Pairing{
+			WhitePlayer:  swissRoundPlayer(bye),
+			Section:      sec,
+			RoundNumber:  round,
+			IsByePairing: true,
+			WhitePoints:  &points,
+		})
+	}
+
+	return pairings
+}
+
+// pairScoreGroup pairs an even group of players ordered by rank, the top
+// half against the bottom half, transposing the bottom half as little as
+// possible to avoid rematches; see avoidRematches. rematches is the number
+// of repeat pairings the group could not avoid.
+func pairScoreGroup(group []*swissPlayer) (pairs [][2]*swissPlayer,
+	rematches int) {
+
+	half := len(group) / 2
+	top, bottom := group[:half], group[half:]
+	opps, rematches := avoidRematches(half, func(i, j int) bool {
+		return top[i].opponents[swissPlayerKey(bottom[j].player)]
+	})
+	for i, j := range opps {
+		pairs = append(pairs, [2]*swissPlayer{top[i], bottom[j]})
+	}
+
+	return pairs, rematches
+}
+
+// assignSwissColors returns the pair as white and black: white goes to the
+// player who has had black more often, then to the player who had black
+// most recently where their histories differ, then to the higher ranked
+// player, given first.
+func assignSwissColors(higher, lower *swissPlayer) (*swissPlayer,
+	*swissPlayer) {
+
+	if hb, lb := colorBalance(higher), colorBalance(lower); hb != lb {
+		if hb < lb {
+			return higher, lower
+		}
+		return lower, higher
+	}
+	for idx := 1; idx <= min(len(higher.colors), len(lower.colors)); idx++ {
+		hc := higher.colors[len(higher.colors)-idx]
+		lc := lower.colors[len(lower.colors)-idx]
+		if hc == lc {
+			continue
+		}
+		if hc == black {
+			return higher, lower
+		}
+		return lower, higher
+	}
+
+	return higher, lower
+}
+
+// colorBalance returns how many more times a player has had white than
+// black.
+func colorBalance(sp *swissPlayer) int {
+	balance := 0
+	for _, c := range sp.colors {
+		if c == white {
+			balance++
+		} else {
+			balance--
+		}
+	}
+
+	return balance
+}
+
+// swissRoundPlayer returns sp's player as paired in the predicted round,
+// whose current score is their score going into it.
+func swissRoundPlayer(sp *swissPlayer) Player {
+	player := sp.player
+	player.CurrentScore = sp.score
+	player.CurrentScoreAG = sp.score
+
+	return player
+}
+
+// BuildNextRoundNote explains what a next round prediction was based on, to
+// accompany its pairings.
+func BuildNextRoundNote(prediction *NextRoundPrediction) string {
+	rounds := make([]string, 0, len(prediction.KnownRounds))
+	for _, r := range prediction.KnownRounds {
+		rounds = append(rounds, fmt.Sprintf("%v", r))
+	}
+	label := "round"
+	if len(rounds) > 1 {
+		label = "rounds"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("* This is a prediction, not the director's pairings. Rematches and colors are judged only from %v %v; withdrawals and bye requests are not known.\n",
+		label, strings.Join(rounds, ", ")))
+	if prediction.InProgress {
+		sb.WriteString(fmt.Sprintf("* Round %v is still in progress, so scores as of its start were used.\n",
+			prediction.KnownRounds[len(prediction.KnownRounds)-1]))
+	}
+
+	return sb.String()
+}
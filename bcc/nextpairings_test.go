@@ -0,0 +1,130 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPredictNextRoundAvoidsRematches(t *testing.T) {
+	player := func(name string, id int, rating int, score float64) Player {
+		return Player{DisplayName: name, UscfID: id, PrimaryRating: rating,
+			CurrentScoreAG: score}
+	}
+	half, one, zero := 0.5, 1.0, 0.0
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			// round 1: both boards drawn
+			{RoundNumber: 1, BoardNumber: 1, Section: "Open",
+				WhitePlayer: player("Alice A", 1, 2000, 0.5),
+				BlackPlayer: player("Bob B", 2, 1900, 0.5),
+				WhitePoints: &half, BlackPoints: &half},
+			{RoundNumber: 1, BoardNumber: 2, Section: "Open",
+				WhitePlayer: player("Carol C", 3, 1800, 0.5),
+				BlackPlayer: player("Dan D", 4, 1700, 0.5),
+				WhitePoints: &half, BlackPoints: &half},
+			// round 2: Alice and Bob win
+			{RoundNumber: 2, BoardNumber: 1, Section: "Open",
+				WhitePlayer: player("Alice A", 1, 2000, 1.5),
+				BlackPlayer: player("Carol C", 3, 1800, 0.5),
+				WhitePoints: &one, BlackPoints: &zero},
+			{RoundNumber: 2, BoardNumber: 2, Section: "Open",
+				WhitePlayer: player("Dan D", 4, 1700, 0.5),
+				BlackPlayer: player("Bob B", 2, 1900, 1.5),
+				WhitePoints: &zero, BlackPoints: &one},
+		},
+	}
+
+	prediction, err := PredictNextRound(tourney)
+	if err != nil {
+		t.Fatalf("PredictNextRound() err = %v", err)
+	}
+	if prediction.InProgress {
+		t.Errorf("InProgress = true; want false with every result posted")
+	}
+	// Alice and Bob lead but already met, so both float down; Alice's
+	// natural opponent Carol is also a rematch, so Alice meets Dan instead
+	var got []string
+	for _, p := range prediction.CurrentPairings {
+		if p.RoundNumber != 3 {
+			t.Errorf("predicted round = %v; want 3", p.RoundNumber)
+		}
+		got = append(got, p.WhitePlayer.DisplayName+" - "+
+			p.BlackPlayer.DisplayName)
+	}
+	// Alice has had white twice, so Dan gets white
+	want := "Dan D - Alice A,Bob B - Carol C"
+	if strings.Join(got, ",") != want {
+		t.Errorf("predicted pairings = %v; want %v", got, want)
+	}
+
+	output := BuildNextRoundNote(prediction) +
+		BuildPairingsOutput(prediction.Tournament, false, "", 0)
+	for _, want := range []string{"This is a prediction",
+		"from rounds 1, 2;", "predicted round 3 pairings"} {
+
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+
+	// an unfinished round is flagged
+	tourney.CurrentPairings[3].WhitePoints = nil
+	tourney.CurrentPairings[3].BlackPoints = nil
+	if prediction, err = PredictNextRound(tourney); err != nil ||
+		!prediction.InProgress {
+
+		t.Errorf("PredictNextRound() with a pending result = %+v, %v; want InProgress",
+			prediction, err)
+	}
+
+	if _, err := PredictNextRound(&Tournament{}); !errors.Is(err,
+		ErrNoPostedPairings) {
+
+		t.Errorf("PredictNextRound(empty) err = %v; want ErrNoPostedPairings",
+			err)
+	}
+}
+
+func TestPredictNextRoundBlackSideBye(t *testing.T) {
+	one, zero := 1.0, 0.0
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			{RoundNumber: 1, BoardNumber: 1, Section: "Open",
+				WhitePlayer: Player{DisplayName: "Alice A", UscfID: 1,
+					PrimaryRating: 2000, CurrentScoreAG: 1},
+				BlackPlayer: Player{DisplayName: "Bob B", UscfID: 2,
+					PrimaryRating: 1900, CurrentScoreAG: 0},
+				WhitePoints: &one, BlackPoints: &zero},
+			// the website may list a bye with its recipient on black
+			{RoundNumber: 1, Section: "Open", IsByePairing: true,
+				WhitePlayer: Player{DisplayName: "BYE"},
+				BlackPlayer: Player{DisplayName: "Carol C", UscfID: 3,
+					PrimaryRating: 1800, CurrentScoreAG: 1},
+				BlackPoints: &one},
+		},
+	}
+
+	prediction, err := PredictNextRound(tourney)
+	if err != nil {
+		t.Fatalf("PredictNextRound() err = %v", err)
+	}
+	var got []string
+	for _, p := range prediction.CurrentPairings {
+		if p.IsByePairing {
+			got = append(got, p.ByePlayer().DisplayName+" bye")
+			continue
+		}
+		got = append(got, p.WhitePlayer.DisplayName+" - "+
+			p.BlackPlayer.DisplayName)
+	}
+	// Carol already had a bye, so it goes to Bob instead
+	want := "Carol C - Alice A,Bob B bye"
+	if strings.Join(got, ",") != want {
+		t.Errorf("predicted pairings = %v; want %v", got, want)
+	}
+}
@@ -13,8 +13,14 @@ import (
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 )
 
-// buildPairingsOutput formats pairings into grouped, aligned string output
-func BuildPairingsOutput(t *Tournament) string {
+// buildPairingsOutput formats pairings into grouped, aligned string output.
+// When verbose is set each player's USCF id is included to ease manual result
+// entry. A nonempty section restricts the output to matching sections. A
+// positive width bounds the length of each line, truncating long names with
+// an ellipsis.
+func BuildPairingsOutput(t *Tournament, verbose bool, section string,
+	width int) string {
+
 	// Group pairings by section
 	sections := make(map[string][]Pairing)
 	for _, p := range t.CurrentPairings {
@@ -29,90 +35,228 @@ func BuildPairingsOutput(t *Tournament) string {
 	sort.Sort(SectionSorter(sectionNames))
 	var sb strings.Builder
 
-	sb.WriteString("* Please note that pairings are tentative and subject to change before the start of the round.\n\n")
+	if disclaimer := internal.GetConfig().PairingsDisclaimer; disclaimer != "" {
+		sb.WriteString(internal.WrapText("* "+disclaimer, width) + "\n\n")
+	}
 
+	numRounds := len(pairingsByRound(t.CurrentPairings))
 	if len(t.CurrentPairings) > 0 {
-		if t.IsPredicted() {
-			sb.WriteString(fmt.Sprintf("Round %v pairings are not yet posted, but here are my predicted round %v pairings:\n\n",
+		var intro string
+		if t.IsPredicted() && numRounds > 1 {
+			intro = fmt.Sprintf("Pairings are not yet posted, but here are my predicted pairings for all %v rounds of this round robin:",
+				numRounds)
+		} else if t.IsPredicted() {
+			intro = fmt.Sprintf("Round %v pairings are not yet posted, but here are my predicted round %v pairings:",
 				t.CurrentPairings[0].RoundNumber,
-				t.CurrentPairings[0].RoundNumber))
+				t.CurrentPairings[0].RoundNumber)
 		} else {
-			sb.WriteString(fmt.Sprintf("Posted Round %v Pairings (via %v):\n\n",
-				t.CurrentPairings[0].RoundNumber, t.source.String()))
+			intro = fmt.Sprintf("Posted Round %v Pairings (via %v):",
+				t.CurrentPairings[0].RoundNumber, t.source.String())
 		}
+		sb.WriteString(internal.WrapText(intro, width) + "\n\n")
 	} else {
-		sb.WriteString("No pairings posted nor predicted")
+		sb.WriteString("No pairings posted nor predicted\n")
 		log.Printf("bcc: pairings: empty pairings")
 	}
 
 	for _, sec := range sectionNames {
+		if !SectionMatches(sec, section) {
+			continue
+		}
 		list := sections[sec]
-		// Sort by board number
-		sort.Slice(list, func(i, j int) bool {
-			// 0 means bye
-			return list[i].BoardNumber != 0 &&
-				list[i].BoardNumber < list[j].BoardNumber
-		})
 
-		type row struct{ board, white, black string }
-		var rows []row
-		for _, p := range list {
-			wRating := "unrated"
-			if p.WhitePlayer.PrimaryRating != 0 {
-				wRating = fmt.Sprintf("%v", p.WhitePlayer.PrimaryRating)
-			}
-			bRating := "unrated"
-			if p.BlackPlayer.PrimaryRating != 0 {
-				bRating = fmt.Sprintf("%v", p.BlackPlayer.PrimaryRating)
-			}
-			var w, b, bl string
-			w = fmt.Sprintf("%s(%v %v)", p.WhitePlayer.DisplayName,
-				wRating,
-				internal.ScoreToString(p.WhitePlayer.CurrentScore))
-			if p.IsByePairing {
-				b = "n/a"
-				if p.WhitePoints != nil && *p.WhitePoints == 1.0 {
-					bl = "BYE(1)"
-				} else {
-					bl = "BYE(½)"
-				}
-			} else {
-				b = fmt.Sprintf("%d.", p.BoardNumber)
-				bl = fmt.Sprintf("%s(%v %v)", p.BlackPlayer.DisplayName,
-					bRating, internal.ScoreToString(p.BlackPlayer.CurrentScore))
+		// Write section header and table
+		if len(sectionNames) > 1 {
+			sb.WriteString(internal.TruncateToWidth(
+				internal.DisplaySectionName(sec), width) + "\n")
+		}
+		rounds := pairingsByRound(list)
+		for _, round := range rounds {
+			if len(rounds) > 1 {
+				sb.WriteString(fmt.Sprintf("Round %v\n", round[0].RoundNumber))
 			}
-			rows = append(rows, row{board: b, white: w, black: bl})
+			writePairingsTable(&sb, round, verbose, width)
+			sb.WriteString("\n")
 		}
+	}
 
-		// Compute column widths
-		maxB, maxW, maxBl := len("Board"), len("White"), len("Black")
-		for _, r := range rows {
-			if l := len(r.board); l > maxB {
-				maxB = l
-			}
-			if l := len(r.white); l > maxW {
-				maxW = l
-			}
-			if l := len(r.black); l > maxBl {
-				maxBl = l
-			}
+	sb.WriteString(SourceFooter(t))
+	sb.WriteString(DataAgeFooter(t))
+
+	return sb.String()
+}
+
+// BuildGameLinksOutput lists the live game links of the boards that have
+// one, e.g. "  Open bd 1: https://...". Only sections matching section
+// are included. It returns an empty string when no board has a link.
+func BuildGameLinksOutput(t *Tournament, section string) string {
+	links := make([]Pairing, 0)
+	for _, p := range t.CurrentPairings {
+		if p.GameLink == "" || p.IsByePairing ||
+			!SectionMatches(p.Section, section) {
+			continue
+		}
+		links = append(links, p)
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].Section != links[j].Section {
+			return SectionSorter{links[i].Section,
+				links[j].Section}.Less(0, 1)
 		}
+		return links[i].BoardNumber < links[j].BoardNumber
+	})
 
-		// Write section header and table
-		if len(sectionNames) > 1 {
-			if sec == "" {
-				sec = "UNNAMED"
+	return BuildBoardLinksOutput(links)
+}
+
+// BuildBoardLinksOutput lists the live game links of boards in the given
+// order, skipping those without one. It returns an empty string when no
+// board has a link.
+func BuildBoardLinksOutput(boards []Pairing) string {
+	var sb strings.Builder
+	for _, p := range boards {
+		if p.GameLink == "" || p.IsByePairing {
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("Live games:\n")
+		}
+		board := fmt.Sprintf("bd %v", p.BoardNumber)
+		if p.Section != "" {
+			board = fmt.Sprintf("%v %v", p.Section, board)
+		}
+		sb.WriteString(fmt.Sprintf("  %v: %v\n", board, p.GameLink))
+	}
+
+	return sb.String()
+}
+
+// pairingsByRound splits a section's pairings by round, in round order. Each
+// round is sorted by board number with byes last.
+func pairingsByRound(list []Pairing) [][]Pairing {
+	byRound := make(map[int][]Pairing)
+	var roundNums []int
+	for _, p := range list {
+		if _, ok := byRound[p.RoundNumber]; !ok {
+			roundNums = append(roundNums, p.RoundNumber)
+		}
+		byRound[p.RoundNumber] = append(byRound[p.RoundNumber], p)
+	}
+	sort.Ints(roundNums)
+
+	rounds := make([][]Pairing, 0, len(roundNums))
+	for _, r := range roundNums {
+		list := byRound[r]
+		// Sort by board number, with byes (board 0) last
+		sort.SliceStable(list, func(i, j int) bool {
+			if (list[i].BoardNumber == 0) != (list[j].BoardNumber == 0) {
+				return list[j].BoardNumber == 0
 			}
-			sb.WriteString(fmt.Sprintf("%s Section\n", sec))
+			return list[i].BoardNumber < list[j].BoardNumber
+		})
+		rounds = append(rounds, list)
+	}
+
+	return rounds
+}
+
+// writePairingsTable writes one round of a section's pairings as an aligned
+// table no wider than width, if positive.
+func writePairingsTable(sb *strings.Builder, list []Pairing, verbose bool,
+	width int) {
+
+	type row struct{ board, white, whiteId, black, blackId string }
+	var rows []row
+	byeLabels := internal.GetConfig().ByeLabels
+	for _, p := range list {
+		white := p.WhitePlayer
+		if p.IsByePairing {
+			white = p.ByePlayer()
+		}
+		wRating := displayRating(white)
+		bRating := displayRating(p.BlackPlayer)
+		var w, b, bl, wId, blId string
+		wId = uscfIdToString(white.UscfID)
+		w = fmt.Sprintf("%s(%v %v)", white.DisplayName, wRating,
+			internal.ScoreToString(white.CurrentScore))
+		if p.IsByePairing {
+			b = "n/a"
+			bl = byeLabels.Label(p.ByePoints())
+		} else {
+			b = fmt.Sprintf("%d.", p.BoardNumber)
+			bl = fmt.Sprintf("%s(%v %v)", p.BlackPlayer.DisplayName,
+				bRating, internal.ScoreToString(p.BlackPlayer.CurrentScore))
+			blId = uscfIdToString(p.BlackPlayer.UscfID)
+		}
+		rows = append(rows, row{board: b, white: w, whiteId: wId, black: bl,
+			blackId: blId})
+	}
+
+	// Compute column widths
+	maxB, maxW, maxBl := len("Board"), len("White"), len("Black")
+	maxWId, maxBlId := len("USCF ID"), len("USCF ID")
+	for _, r := range rows {
+		if l := internal.DisplayWidth(r.whiteId); l > maxWId {
+			maxWId = l
+		}
+		if l := internal.DisplayWidth(r.blackId); l > maxBlId {
+			maxBlId = l
+		}
+		if l := internal.DisplayWidth(r.board); l > maxB {
+			maxB = l
+		}
+		if l := internal.DisplayWidth(r.white); l > maxW {
+			maxW = l
+		}
+		if l := internal.DisplayWidth(r.black); l > maxBl {
+			maxBl = l
+		}
+	}
+
+	if verbose {
+		// shrink the player columns first to fit within width
+		widths := internal.FitColumns([]int{maxB, maxW, maxWId, maxBl,
+			maxBlId}, 2, width, 1, 3)
+		maxB, maxW, maxWId, maxBl, maxBlId = widths[0], widths[1], widths[2],
+			widths[3], widths[4]
+		writeTableLine(sb, width, fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s",
+			maxB, "Board", maxW, "White", maxWId, "USCF ID", maxBl, "Black",
+			maxBlId, "USCF ID"))
+		for _, r := range rows {
+			writeTableLine(sb, width, fmt.Sprintf("%-*s  %-*s  %-*s  %-*s  %-*s",
+				maxB, r.board, maxW, internal.TruncateToWidth(r.white, maxW),
+				maxWId, r.whiteId, maxBl, internal.TruncateToWidth(r.black,
+					maxBl), maxBlId, r.blackId))
 		}
-		sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s\n", maxB, "Board", maxW,
-			"White", maxBl, "Black"))
+	} else {
+		// shrink the player columns first to fit within width
+		widths := internal.FitColumns([]int{maxB, maxW, maxBl}, 2, width, 1, 2)
+		maxB, maxW, maxBl = widths[0], widths[1], widths[2]
+		writeTableLine(sb, width, fmt.Sprintf("%-*s  %-*s  %-*s", maxB,
+			"Board", maxW, "White", maxBl, "Black"))
 		for _, r := range rows {
-			sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s\n", maxB, r.board,
-				maxW, r.white, maxBl, r.black))
+			writeTableLine(sb, width, fmt.Sprintf("%-*s  %-*s  %-*s", maxB,
+				r.board, maxW, internal.TruncateToWidth(r.white, maxW), maxBl,
+				internal.TruncateToWidth(r.black, maxBl)))
 		}
-		sb.WriteString("\n")
 	}
+}
 
-	return sb.String()
+// maxPairingRound returns the highest round number among pairings.
+func maxPairingRound(pairings []Pairing) int {
+	maxRound := 0
+	for _, p := range pairings {
+		maxRound = max(maxRound, p.RoundNumber)
+	}
+	return maxRound
+}
+
+func uscfIdToString(uscfId int) string {
+	if uscfId == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%v", uscfId)
 }
@@ -0,0 +1,293 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+func TestBuildPairingsOutputVerboseIncludesUscfIds(t *testing.T) {
+	byePoints := 1.0
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			{
+				RoundNumber: 2,
+				BoardNumber: 1,
+				Section:     "Open",
+				WhitePlayer: Player{DisplayName: "Alice A", UscfID: 12345678, PrimaryRating: 1800},
+				BlackPlayer: Player{DisplayName: "Bob B", UscfID: 87654321, PrimaryRating: 1700},
+			},
+			{
+				RoundNumber:  2,
+				Section:      "Open",
+				IsByePairing: true,
+				WhitePoints:  &byePoints,
+				WhitePlayer:  Player{DisplayName: "Carol C", UscfID: 11223344},
+			},
+		},
+	}
+
+	verbose := BuildPairingsOutput(tourney, true, "", 0)
+	for _, id := range []string{"12345678", "87654321", "11223344"} {
+		if !strings.Contains(verbose, id) {
+			t.Errorf("verbose output missing USCF id %v:\n%v", id, verbose)
+		}
+	}
+	if !strings.Contains(verbose, "USCF ID") {
+		t.Errorf("verbose output missing USCF ID header:\n%v", verbose)
+	}
+
+	terse := BuildPairingsOutput(tourney, false, "", 0)
+	for _, id := range []string{"12345678", "87654321", "11223344", "USCF ID"} {
+		if strings.Contains(terse, id) {
+			t.Errorf("default output unexpectedly contains %v:\n%v", id, terse)
+		}
+	}
+}
+
+func TestBuildGameLinksOutput(t *testing.T) {
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			{RoundNumber: 3, BoardNumber: 2, Section: "Open",
+				GameLink: "https://lichess.org/broadcast/b2"},
+			{RoundNumber: 3, BoardNumber: 1, Section: "Open",
+				GameLink: "https://lichess.org/broadcast/b1"},
+			{RoundNumber: 3, BoardNumber: 3, Section: "Open"},
+			{RoundNumber: 3, BoardNumber: 1, Section: "U1600",
+				GameLink: "https://lichess.org/broadcast/u1"},
+		},
+	}
+
+	want := "Live games:\n" +
+		"  Open bd 1: https://lichess.org/broadcast/b1\n" +
+		"  Open bd 2: https://lichess.org/broadcast/b2\n" +
+		"  U1600 bd 1: https://lichess.org/broadcast/u1\n"
+	if got := BuildGameLinksOutput(tourney, ""); got != want {
+		t.Errorf("BuildGameLinksOutput() =\n%v\nwant\n%v", got, want)
+	}
+
+	want = "Live games:\n" +
+		"  U1600 bd 1: https://lichess.org/broadcast/u1\n"
+	if got := BuildGameLinksOutput(tourney, "u1600"); got != want {
+		t.Errorf("BuildGameLinksOutput(u1600) =\n%v\nwant\n%v", got, want)
+	}
+
+	tourney.CurrentPairings = tourney.CurrentPairings[2:3]
+	if got := BuildGameLinksOutput(tourney, ""); got != "" {
+		t.Errorf("BuildGameLinksOutput() without links = %q; want empty", got)
+	}
+}
+
+func TestBuildTablesOutputAccentedNames(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "José Peña", UscfID: 12345678, PrimaryRating: 1800,
+				SectionName: "Open", CurrentScoreAG: 1},
+			{DisplayName: "Al Bee", UscfID: 87654321, PrimaryRating: 1700,
+				SectionName: "Open", CurrentScoreAG: 0},
+		},
+		CurrentPairings: []Pairing{
+			{
+				RoundNumber: 1,
+				BoardNumber: 1,
+				Section:     "Open",
+				WhitePlayer: Player{DisplayName: "José Peña", UscfID: 12345678},
+				BlackPlayer: Player{DisplayName: "Al Bee", UscfID: 87654321},
+			},
+			{
+				RoundNumber: 1,
+				BoardNumber: 2,
+				Section:     "Open",
+				WhitePlayer: Player{DisplayName: "Cy Dee", UscfID: 11223344},
+				BlackPlayer: Player{DisplayName: "Zoë Ñúñez", UscfID: 44332211},
+			},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+
+	// columns are sized by characters rather than bytes, so the widest
+	// (accented) name is followed by exactly the 2 space column separator
+	for name, tc := range map[string]struct {
+		output string
+		want   string
+	}{
+		"pairings": {BuildPairingsOutput(tourney, false, "", 0),
+			"José Peña(unrated 0)  Al Bee"},
+		"verbose": {BuildPairingsOutput(tourney, true, "", 0),
+			"Zoë Ñúñez(unrated 0)  44332211"},
+		"standings": {BuildStandingsOutput(tourney, "", 0), "José Peña  1"},
+		"entries":   {BuildEntriesOutput(tourney, false), "José Peña  1800"},
+	} {
+		if !strings.Contains(tc.output, tc.want) {
+			t.Errorf("%v output missing %q:\n%v", name, tc.want, tc.output)
+		}
+	}
+}
+
+func TestBuildPairingsOutputSourceAndDisclaimer(t *testing.T) {
+	newTourney := func(src Source) *Tournament {
+		return &Tournament{
+			source: src,
+			CurrentPairings: []Pairing{{
+				RoundNumber: 1,
+				BoardNumber: 1,
+				Section:     "Open",
+				WhitePlayer: Player{DisplayName: "Alice A"},
+				BlackPlayer: Player{DisplayName: "Bob B"},
+			}},
+		}
+	}
+
+	for src, want := range map[Source]string{
+		SourceAPI:     "(source: api)\n",
+		SourceWebsite: "(source: website (scraped))\n",
+		SourceBoth:    "(source: api & website (scraped))\n",
+	} {
+		tourney := newTourney(src)
+		if tourney.Source() != src {
+			t.Errorf("Source() = %v; want %v", tourney.Source(), src)
+		}
+		output := BuildPairingsOutput(tourney, false, "", 0)
+		if !strings.HasSuffix(output, want) {
+			t.Errorf("%v output does not end with %q:\n%v", src, want, output)
+		}
+		if !strings.HasPrefix(output, "* "+internal.DefaultPairingsDisclaimer) {
+			t.Errorf("%v output missing default disclaimer:\n%v", src, output)
+		}
+	}
+
+	internal.SetenvForTest(t, internal.PairingsDisclaimerEnv, "Pairings are final at 7pm.")
+	output := BuildPairingsOutput(newTourney(SourceAPI), false, "", 0)
+	if !strings.HasPrefix(output, "* Pairings are final at 7pm.\n") {
+		t.Errorf("output missing custom disclaimer:\n%v", output)
+	}
+
+	internal.SetenvForTest(t, internal.PairingsDisclaimerEnv, "none")
+	output = BuildPairingsOutput(newTourney(SourceAPI), false, "", 0)
+	if strings.HasPrefix(output, "*") {
+		t.Errorf("output unexpectedly has a disclaimer:\n%v", output)
+	}
+}
+
+func TestBuildPairingsOutputByes(t *testing.T) {
+	full, half, zero := 1.0, 0.5, 0.0
+	bye := func(name string, points *float64) Pairing {
+		return Pairing{
+			RoundNumber:  3,
+			Section:      "Open",
+			IsByePairing: true,
+			WhitePoints:  points,
+			WhitePlayer:  Player{DisplayName: name},
+		}
+	}
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{
+			bye("Full Bye", &full),
+			bye("Half Bye", &half),
+			bye("Zero Bye", &zero),
+			bye("Pending Bye", nil),
+			// the website may list the bye on the white side of the board
+			{
+				RoundNumber:  3,
+				Section:      "Open",
+				IsByePairing: true,
+				BlackPoints:  &full,
+				WhitePlayer:  Player{DisplayName: "BYE"},
+				BlackPlayer:  Player{DisplayName: "Listed Black"},
+			},
+		},
+	}
+
+	labelOf := func(output string, name string) string {
+		for _, line := range strings.Split(output, "\n") {
+			if strings.Contains(line, name+"(") {
+				fields := strings.Fields(line)
+				return fields[len(fields)-1]
+			}
+		}
+		return ""
+	}
+	output := BuildPairingsOutput(tourney, false, "", 0)
+	for name, want := range map[string]string{
+		"Full Bye":     "BYE(1)",
+		"Half Bye":     "BYE(½)",
+		"Zero Bye":     "BYE(0)",
+		"Pending Bye":  "BYE",
+		"Listed Black": "BYE(1)",
+	} {
+		if got := labelOf(output, name); got != want {
+			t.Errorf("%v labeled %q; want %q:\n%v", name, got, want, output)
+		}
+	}
+
+	internal.SetenvForTest(t, internal.ByeLabelsEnv, "full=1-BYE, half=H-BYE,zero=U-BYE,bogus")
+	output = BuildPairingsOutput(tourney, false, "", 0)
+	for name, want := range map[string]string{
+		"Full Bye":    "1-BYE",
+		"Half Bye":    "H-BYE",
+		"Zero Bye":    "U-BYE",
+		"Pending Bye": "BYE",
+	} {
+		if got := labelOf(output, name); got != want {
+			t.Errorf("%v labeled %q with %v; want %q:\n%v", name, got,
+				internal.ByeLabelsEnv, want, output)
+		}
+	}
+}
+
+func TestBuildPairingsOutputGroupsRounds(t *testing.T) {
+	player := func(name string) Player {
+		return Player{DisplayName: name, PrimaryRating: 1500}
+	}
+	pairing := func(section string, round int, board int, white string,
+		black string) Pairing {
+
+		p := Pairing{Section: section, RoundNumber: round,
+			BoardNumber: board, WhitePlayer: player(white),
+			BlackPlayer: player(black)}
+		if board == 0 {
+			p.IsByePairing = true
+			p.BlackPlayer = Player{}
+		}
+		return p
+	}
+	// a two-round round robin per section, listed out of order
+	tourney := &Tournament{isPredicted: true, CurrentPairings: []Pairing{
+		pairing("Quad 2", 2, 1, "Eve", "Fay"),
+		pairing("Quad 1", 2, 0, "Ann", ""),
+		pairing("Quad 1", 2, 1, "Cat", "Bea"),
+		pairing("Quad 1", 1, 0, "Cat", ""),
+		pairing("Quad 1", 1, 1, "Ann", "Bea"),
+		pairing("Quad 2", 1, 1, "Fay", "Eve"),
+	}}
+
+	output := BuildPairingsOutput(tourney, false, "", 0)
+	if !strings.Contains(output, "all 2 rounds") {
+		t.Errorf("output missing the round robin intro:\n%v", output)
+	}
+	// each section lists its rounds in order, boards before byes
+	prev := -1
+	for _, want := range []string{"Quad 1", "Round 1\n", "Ann(", "Cat(",
+		"Round 2\n", "Cat(", "Ann(", "Quad 2", "Round 1\n", "Fay(",
+		"Round 2\n", "Eve("} {
+
+		idx := strings.Index(output[prev+1:], want)
+		if idx < 0 {
+			t.Fatalf("output missing %q after offset %d:\n%v", want, prev,
+				output)
+		}
+		prev += idx + 1
+	}
+
+	// a single round has no round headings
+	tourney.CurrentPairings = tourney.CurrentPairings[3:5]
+	if output := BuildPairingsOutput(tourney, false, "", 0); strings.Contains(
+		output, "Round 1\n") {
+		t.Errorf("single round output has a round heading:\n%v", output)
+	}
+}
@@ -0,0 +1,77 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByePlan lists the rounds of an event in which a player could still take a
+// half-point bye without disturbing pairings which have already been set.
+type ByePlan struct {
+	NumRounds int
+	// PairedThrough is the last round whose pairings have been posted, or 0
+	// when none have.
+	PairedThrough int
+	// Available are the rounds after PairedThrough, in ascending order.
+	Available  []int
+	RoundRobin bool
+}
+
+// PlanByes determines which future rounds of an event a player could take a
+// half-point bye in. Rounds whose pairings have already been posted are
+// excluded; predicted pairings do not count as posted. The number of rounds
+// comes from the event's format, title, description, or round times; when it
+// cannot be determined Available is empty.
+func PlanByes(detail *EventDetail, t *Tournament) ByePlan {
+	plan := ByePlan{
+		NumRounds:  numRoundsFromDetail(detail),
+		RoundRobin: isRoundRobin(detail),
+	}
+	if t != nil && !t.IsPredicted() {
+		plan.PairedThrough = maxPairingRound(t.CurrentPairings)
+	}
+	for r := plan.PairedThrough + 1; r <= plan.NumRounds; r++ {
+		plan.Available = append(plan.Available, r)
+	}
+
+	return plan
+}
+
+// BuildByePlanOutput formats a bye plan for a player. The output is
+// informational only and says so; it does not request a bye.
+func BuildByePlanOutput(detail *EventDetail, plan ByePlan) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%v\n", detail.Title))
+
+	if plan.NumRounds == 0 {
+		sb.WriteString("Unable to determine the number of rounds for this event.\n")
+	} else {
+		posted := "no pairings posted yet"
+		if plan.PairedThrough > 0 {
+			posted = fmt.Sprintf("round %d pairings posted", plan.PairedThrough)
+		}
+		sb.WriteString(fmt.Sprintf("Rounds: %d (%v)\n", plan.NumRounds, posted))
+		if len(plan.Available) == 0 {
+			sb.WriteString("No future rounds remain in which to take a bye.\n")
+		} else {
+			rounds := make([]string, 0, len(plan.Available))
+			for _, r := range plan.Available {
+				rounds = append(rounds, strconv.Itoa(r))
+			}
+			sb.WriteString(fmt.Sprintf("A half-point bye could still be taken in round(s): %v\n",
+				strings.Join(rounds, ", ")))
+		}
+		if plan.RoundRobin {
+			sb.WriteString("This is a round robin; every round's opponent is fixed, so a bye forgoes that game.\n")
+		}
+	}
+
+	sb.WriteString("\nThis is informational only and does not request a bye. Ask the tournament director to request one; bye limits (e.g. in the final round) are at their discretion.\n")
+
+	return sb.String()
+}
@@ -0,0 +1,60 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPlanByes(t *testing.T) {
+	detail := &EventDetail{Title: "Tuesday Night Swiss", EventFormat: "5-SS"}
+
+	// nothing posted yet
+	plan := PlanByes(detail, nil)
+	if plan.NumRounds != 5 || !slices.Equal(plan.Available, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("PlanByes(nil) = %+v", plan)
+	}
+
+	// round 2 posted
+	tourney := &Tournament{CurrentPairings: []Pairing{{RoundNumber: 2}}}
+	plan = PlanByes(detail, tourney)
+	if plan.PairedThrough != 2 || !slices.Equal(plan.Available, []int{3, 4, 5}) {
+		t.Errorf("PlanByes(round 2) = %+v", plan)
+	}
+	output := BuildByePlanOutput(detail, plan)
+	for _, want := range []string{
+		"Rounds: 5 (round 2 pairings posted)",
+		"round(s): 3, 4, 5",
+		"does not request a bye",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	// predicted pairings have not been set
+	tourney.isPredicted = true
+	if plan = PlanByes(detail, tourney); plan.PairedThrough != 0 {
+		t.Errorf("PlanByes(predicted) = %+v", plan)
+	}
+
+	// final round posted
+	tourney = &Tournament{CurrentPairings: []Pairing{{RoundNumber: 5}}}
+	plan = PlanByes(detail, tourney)
+	if len(plan.Available) != 0 || !strings.Contains(BuildByePlanOutput(detail,
+		plan), "No future rounds") {
+		t.Errorf("PlanByes(round 5) = %+v", plan)
+	}
+
+	// unknown number of rounds
+	plan = PlanByes(&EventDetail{Title: "Mystery Open"}, nil)
+	if output := BuildByePlanOutput(detail, plan); plan.NumRounds != 0 ||
+		!strings.Contains(output, "Unable to determine") ||
+		!strings.Contains(output, "does not request a bye") {
+		t.Errorf("unexpected output for unknown rounds:\n%s", output)
+	}
+}
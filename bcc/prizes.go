@@ -0,0 +1,375 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// "$1,000" or "$500.00"
+	prizeAmountRe = regexp.MustCompile(`\$\s*(\d[\d,]*(?:\.\d+)?)`)
+	// "b/20", "b/20 paid entries", or "based on 20 entries"
+	prizeBasedOnRe    = regexp.MustCompile(`(?i)\b(?:b/\s*|based\s+on\s+)(\d+)`)
+	prizeGuaranteedRe = regexp.MustCompile(`(?i)\b(?:guaranteed|gtd)\b`)
+	// "1st", "2nd", "3rd", "4th"
+	prizePlaceRe = regexp.MustCompile(`(?i)\b(\d+)(?:st|nd|rd|th)\b`)
+	// "Open: 1st $100, 2nd $50"
+	prizeSectionLabelRe = regexp.MustCompile(`^\s*([^:$]+?)\s*:(.*)$`)
+)
+
+// PrizeFund is a prize fund as advertised in an event's prize summary.
+type PrizeFund struct {
+	Amount float64
+	// BasedOn is the number of entries the prize fund is based on, or 0
+	// when it does not depend on entries.
+	BasedOn    int
+	Guaranteed bool
+}
+
+// PrizeResolution is the prize fund an event can expect given its entries.
+type PrizeResolution struct {
+	Fund PrizeFund
+	// Entries is the number of entries the resolution is based on, or 0
+	// when the number of entries is unknown.
+	Entries int
+	// Expected is the prize fund pro-rated by Entries out of Fund.BasedOn.
+	// It is only meaningful when Entries is known.
+	Expected float64
+	// ThresholdMet is set when the event has at least Fund.BasedOn entries,
+	// so the full prize fund is paid.
+	ThresholdMet bool
+}
+
+// parsePrizeSummary extracts the prize fund and its based-on entry count from
+// a free form prize summary such as "$1,000 b/30" or "Prize fund $500
+// (based on 20 entries)". The amount nearest before the based-on condition is
+// taken as the fund; otherwise the first amount is used. ok is false when
+// the summary contains no amount.
+func parsePrizeSummary(summary string) (fund PrizeFund, ok bool) {
+	amounts := prizeAmountRe.FindAllStringSubmatchIndex(summary, -1)
+	if len(amounts) == 0 {
+		return PrizeFund{}, false
+	}
+
+	chosen := amounts[0]
+	if loc := prizeBasedOnRe.FindStringSubmatchIndex(summary); loc != nil {
+		fund.BasedOn, _ = strconv.Atoi(summary[loc[2]:loc[3]])
+		for _, a := range amounts {
+			if a[0] < loc[0] {
+				chosen = a
+			}
+		}
+	}
+	amount := strings.ReplaceAll(summary[chosen[2]:chosen[3]], ",", "")
+	var err error
+	fund.Amount, err = strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return PrizeFund{}, false
+	}
+	fund.Guaranteed = prizeGuaranteedRe.MatchString(summary)
+
+	return fund, true
+}
+
+// ResolvePrizeFund computes the prize fund an event can expect from its
+// prize summary and number of entries. A prize fund based on N entries is
+// pro-rated by NumEntries/N until the event reaches N entries. ok is false
+// when the prize summary has no amount or the fund does not depend on
+// entries, e.g. because it is guaranteed.
+func ResolvePrizeFund(detail *EventDetail) (res PrizeResolution, ok bool) {
+	fund, ok := parsePrizeSummary(detail.PrizeSummary)
+	if !ok || fund.BasedOn <= 0 || fund.Guaranteed {
+		return PrizeResolution{}, false
+	}
+
+	res.Fund = fund
+	if detail.NumEntries <= 0 {
+		return res, true
+	}
+	res.Entries = detail.NumEntries
+	res.ThresholdMet = res.Entries >= fund.BasedOn
+	res.Expected = fund.Amount
+	if !res.ThresholdMet {
+		res.Expected = math.Floor(fund.Amount * float64(res.Entries) /
+			float64(fund.BasedOn))
+	}
+
+	return res, true
+}
+
+// String describes the expected prize fund, e.g. "$600 (12 of 20 entries;
+// pro-rated from $1,000)", or the conditional fund when the number of entries
+// is unknown.
+func (res PrizeResolution) String() string {
+	if res.Entries == 0 {
+		return fmt.Sprintf("%v if at least %d entries",
+			formatDollars(res.Fund.Amount), res.Fund.BasedOn)
+	}
+	if res.ThresholdMet {
+		return fmt.Sprintf("%v (%d of %d entries; full prize fund)",
+			formatDollars(res.Expected), res.Entries, res.Fund.BasedOn)
+	}
+	return fmt.Sprintf("%v (%d of %d entries; pro-rated from %v)",
+		formatDollars(res.Expected), res.Entries, res.Fund.BasedOn,
+		formatDollars(res.Fund.Amount))
+}
+
+// formatDollars formats a dollar amount with thousands separators, e.g.
+// "$1,250".
+func formatDollars(amount float64) string {
+	digits := strconv.FormatInt(int64(math.Round(amount)), 10)
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(d)
+	}
+
+	return "$" + sb.String()
+}
+
+// Prize is a place or class prize as advertised in an event's prize
+// summary, e.g. "2nd $100" or "1st U1600 $50".
+type Prize struct {
+	// Section is the section the prize is awarded in, or empty when the
+	// prize is awarded in every section.
+	Section string
+	// Place is the prize's place within its section, or within its class
+	// for a class prize.
+	Place int
+	// Class is the lowest rating ineligible for a class prize, e.g. 1600
+	// for "U1600", or 0 for a place prize.
+	Class  int
+	Amount float64
+}
+
+// ParsePrizes extracts the place and class prizes from an event's prize
+// summary, e.g. "1st $150, 2nd $100, U1600 $50". Prizes following a section
+// label such as "U1800: 1st $100" are awarded only in that section. Items
+// without a place or class, such as the total prize fund, are ignored.
+func ParsePrizes(detail *EventDetail) []Prize {
+	sectionNames := make(map[string]string)
+	for _, sec := range ParseSections(detail) {
+		sectionNames[strings.ToLower(sec.Name)] = sec.Name
+	}
+
+	var prizes []Prize
+	for _, part := range strings.FieldsFunc(detail.PrizeSummary,
+		func(r rune) bool { return r == ';' || r == '\n' }) {
+
+		section := ""
+		if m := prizeSectionLabelRe.FindStringSubmatch(part); m != nil {
+			if name, ok := sectionNames[strings.ToLower(m[1])]; ok {
+				section = name
+				part = m[2]
+			}
+		}
+		for _, item := range splitPrizeItems(part) {
+			if prize, ok := parsePrize(item); ok {
+				prize.Section = section
+				prizes = append(prizes, prize)
+			}
+		}
+	}
+
+	return prizes
+}
+
+// splitPrizeItems splits a list of prizes on its commas, other than those
+// separating the thousands of an amount such as "$1,000".
+func splitPrizeItems(s string) []string {
+	var items []string
+	start := 0
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] != ',' {
+			continue
+		}
+		if idx > 0 && isDigit(s[idx-1]) && idx+1 < len(s) &&
+			isDigit(s[idx+1]) {
+			continue
+		}
+		items = append(items, s[start:idx])
+		start = idx + 1
+	}
+
+	return append(items, s[start:])
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// parsePrize parses a single prize such as "2nd $100" or "1st U1600 $50".
+// A class prize without a place is taken as 1st in its class.
+func parsePrize(item string) (Prize, bool) {
+	amount := prizeAmountRe.FindStringSubmatch(item)
+	if amount == nil {
+		return Prize{}, false
+	}
+	var prize Prize
+	var err error
+	prize.Amount, err = strconv.ParseFloat(strings.ReplaceAll(amount[1], ",",
+		""), 64)
+	if err != nil {
+		return Prize{}, false
+	}
+	if m := prizePlaceRe.FindStringSubmatch(item); m != nil {
+		prize.Place, _ = strconv.Atoi(m[1])
+	}
+	if m := sectionCapRe.FindStringSubmatch(item); m != nil {
+		prize.Class, _ = strconv.Atoi(m[1])
+		if prize.Place == 0 {
+			prize.Place = 1
+		}
+	}
+	if prize.Place <= 0 {
+		return Prize{}, false
+	}
+
+	return prize, true
+}
+
+// PrizeAward is the prize a player is in line for.
+type PrizeAward struct {
+	// Place and Class are those of the best prize the player shares in.
+	Place int
+	Class int
+	// Tied is the number of players sharing the pooled prizes, or 1.
+	Tied   int
+	Amount float64
+	// Pool is the total of the prizes split among the tied players.
+	Pool float64
+}
+
+// String describes an award, e.g. "1st $150", "1st U1600 $50", or "tied for
+// 2nd: $75 (share of $150)".
+func (a PrizeAward) String() string {
+	label := ordinal(a.Place)
+	if a.Class > 0 {
+		label = fmt.Sprintf("%v U%v", label, a.Class)
+	}
+	if a.Tied <= 1 {
+		return fmt.Sprintf("%v %v", label, formatPrizeDollars(a.Amount))
+	}
+
+	return fmt.Sprintf("tied for %v: %v (share of %v)", label,
+		formatPrizeDollars(a.Amount), formatPrizeDollars(a.Pool))
+}
+
+// awardPrizes determines the prizes each of a section's players, ordered by
+// standings, is in line for. Players with equal scores pool the prizes for
+// the places they occupy and split them evenly. Class prizes go to the top
+// players rated under the class's cap who did not win a place prize;
+// unrated players are not eligible for class prizes. The result is indexed
+// like players; players without a prize have a nil entry.
+func awardPrizes(players []*Player, prizes []Prize) []*PrizeAward {
+	awards := make([]*PrizeAward, len(players))
+	placePrizes := make(map[int]float64)
+	classPrizes := make(map[int]map[int]float64)
+	for _, prize := range prizes {
+		if prize.Class == 0 {
+			placePrizes[prize.Place] += prize.Amount
+			continue
+		}
+		if classPrizes[prize.Class] == nil {
+			classPrizes[prize.Class] = make(map[int]float64)
+		}
+		classPrizes[prize.Class][prize.Place] += prize.Amount
+	}
+
+	all := make([]int, len(players))
+	for idx := range players {
+		all[idx] = idx
+	}
+	splitPrizes(players, all, placePrizes, 0, awards)
+
+	var classes []int
+	for class := range classPrizes {
+		classes = append(classes, class)
+	}
+	// award the higher classes first
+	sort.Sort(sort.Reverse(sort.IntSlice(classes)))
+	for _, class := range classes {
+		var eligible []int
+		for idx, p := range players {
+			if awards[idx] == nil && p.PrimaryRating > 0 &&
+				p.PrimaryRating < class {
+				eligible = append(eligible, idx)
+			}
+		}
+		splitPrizes(players, eligible, classPrizes[class], class, awards)
+	}
+
+	return awards
+}
+
+// splitPrizes awards prizes by place among the players at the given
+// indexes, which are ordered by standings. Each group of players with equal
+// scores shares the prizes for the places it occupies.
+func splitPrizes(players []*Player, indexes []int, prizes map[int]float64,
+	class int, awards []*PrizeAward) {
+
+	place := 1
+	for start := 0; start < len(indexes); {
+		end := start + 1
+		for end < len(indexes) && players[indexes[end]].CurrentScoreAG ==
+			players[indexes[start]].CurrentScoreAG {
+			end++
+		}
+		tied := end - start
+		pool := 0.0
+		for p := place; p < place+tied; p++ {
+			pool += prizes[p]
+		}
+		if pool > 0 {
+			for _, idx := range indexes[start:end] {
+				awards[idx] = &PrizeAward{
+					Place:  place,
+					Class:  class,
+					Tied:   tied,
+					Amount: pool / float64(tied),
+					Pool:   pool,
+				}
+			}
+		}
+		place += tied
+		start = end
+	}
+}
+
+// formatPrizeDollars formats a prize, including cents only when a split
+// leaves a fraction of a dollar, e.g. "$150" or "$16.67".
+func formatPrizeDollars(amount float64) string {
+	cents := math.Round(amount * 100)
+	if math.Mod(cents, 100) == 0 {
+		return formatDollars(amount)
+	}
+
+	return fmt.Sprintf("%v.%02d", formatDollars(math.Floor(cents/100)),
+		int64(math.Mod(cents, 100)))
+}
+
+// ordinal returns n with its English ordinal suffix, e.g. "1st" or "12th".
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,151 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePrizeSummary(t *testing.T) {
+	tests := []struct {
+		summary string
+		want    PrizeFund
+		ok      bool
+	}{
+		{"$1,000 b/30", PrizeFund{Amount: 1000, BasedOn: 30}, true},
+		{"Prize fund $500 (based on 20 entries)",
+			PrizeFund{Amount: 500, BasedOn: 20}, true},
+		{"1st $150, 2nd $100; $400 total b/20 paid entries",
+			PrizeFund{Amount: 400, BasedOn: 20}, true},
+		{"$300 guaranteed", PrizeFund{Amount: 300, Guaranteed: true}, true},
+		{"Trophies to top 3", PrizeFund{}, false},
+	}
+	for _, tc := range tests {
+		got, ok := parsePrizeSummary(tc.summary)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("parsePrizeSummary(%q) = %+v, %v; want %+v, %v",
+				tc.summary, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestResolvePrizeFund(t *testing.T) {
+	tests := []struct {
+		summary string
+		entries int
+		want    string
+		ok      bool
+	}{
+		{"$1,000 b/20", 12, "$600 (12 of 20 entries; pro-rated from $1,000)", true},
+		{"$1,000 b/20", 25, "$1,000 (25 of 20 entries; full prize fund)", true},
+		{"$1,000 b/20", 0, "$1,000 if at least 20 entries", true},
+		{"$1,000 guaranteed", 12, "", false},
+		{"$1,000", 12, "", false},
+	}
+	for _, tc := range tests {
+		detail := &EventDetail{PrizeSummary: tc.summary, NumEntries: tc.entries}
+		res, ok := ResolvePrizeFund(detail)
+		if ok != tc.ok || (ok && res.String() != tc.want) {
+			t.Errorf("ResolvePrizeFund(%q, %d) = %q, %v; want %q, %v",
+				tc.summary, tc.entries, res, ok, tc.want, tc.ok)
+		}
+		if tc.ok && res.ThresholdMet != (tc.entries >= 20) {
+			t.Errorf("ResolvePrizeFund(%q, %d).ThresholdMet = %v",
+				tc.summary, tc.entries, res.ThresholdMet)
+		}
+	}
+
+	output := BuildEventOutput(&EventDetail{PrizeSummary: "$500 b/25",
+		NumEntries: 10}, "", false, false)
+	if !strings.Contains(output, "Expected Prize Fund: $200 (10 of 25 entries; pro-rated from $500)\n") {
+		t.Errorf("unexpected event output:\n%s", output)
+	}
+}
+
+func TestParsePrizes(t *testing.T) {
+	detail := &EventDetail{
+		Sections: []string{"Open", "U1800"},
+		PrizeSummary: "$400 total b/20; Open: 1st $150, 2nd $100, " +
+			"U2000 $50; U1800: 1st $60, 2nd $40",
+	}
+	got := ParsePrizes(detail)
+	want := []Prize{
+		{Section: "Open", Place: 1, Amount: 150},
+		{Section: "Open", Place: 2, Amount: 100},
+		{Section: "Open", Place: 1, Class: 2000, Amount: 50},
+		{Section: "U1800", Place: 1, Amount: 60},
+		{Section: "U1800", Place: 2, Amount: 40},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParsePrizes() = %+v; want %+v", got, want)
+	}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Errorf("ParsePrizes()[%d] = %+v; want %+v", idx, got[idx],
+				want[idx])
+		}
+	}
+}
+
+func TestParsePrizesThousands(t *testing.T) {
+	detail := &EventDetail{
+		PrizeSummary: "$1,750 guaranteed; 1st $1,000, 2nd $500,U1800 $250",
+	}
+	got := ParsePrizes(detail)
+	want := []Prize{
+		{Place: 1, Amount: 1000},
+		{Place: 2, Amount: 500},
+		{Place: 1, Class: 1800, Amount: 250},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePrizes() = %+v; want %+v", got, want)
+	}
+}
+
+func TestBuildStandingsOutputWithPrizes(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", UscfID: 1, PrimaryRating: 2100,
+				CurrentScoreAG: 3},
+			{DisplayName: "Bob Jones", UscfID: 2, PrimaryRating: 1950,
+				CurrentScoreAG: 2.5},
+			{DisplayName: "Carol White", UscfID: 3, PrimaryRating: 1900,
+				CurrentScoreAG: 2.5},
+			{DisplayName: "Dan Brown", UscfID: 4, PrimaryRating: 1700,
+				CurrentScoreAG: 2},
+			{DisplayName: "Eve Black", UscfID: 5, CurrentScoreAG: 2},
+			{DisplayName: "Frank Green", UscfID: 6, PrimaryRating: 1500,
+				CurrentScoreAG: 1},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+	prizes := ParsePrizes(&EventDetail{
+		PrizeSummary: "1st $150, 2nd $100, 3rd $50, U1800 $30"})
+
+	output := BuildStandingsOutputWithPrizes(tourney, prizes, "", 0)
+	for _, want := range []string{
+		"Prize",
+		"Alice Smith  3      1st $150",
+		"tied for 2nd: $75 (share of $150)",
+		"Dan Brown    2      1st U1800 $30",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		// the unrated player is not eligible for the class prize
+		if (strings.Contains(line, "Eve Black") ||
+			strings.Contains(line, "Frank Green")) &&
+			strings.Contains(line, "$") {
+			t.Errorf("unexpected prize: %q", line)
+		}
+	}
+	if strings.Contains(BuildStandingsOutput(tourney, "", 0), "Prize") {
+		t.Errorf("plain standings include prizes")
+	}
+}
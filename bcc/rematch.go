@@ -0,0 +1,79 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+// maxRematchSearchSteps bounds each search for a pairing with at most a
+// given number of rematches so that a pathological group cannot stall a
+// prediction.
+const maxRematchSearchSteps = 100000
+
+// avoidRematches pairs each of n top players, ordered by rank, with one of
+// n bottom players, also ordered by rank. Ideally top player i meets bottom
+// player i. When that would be a rematch per met, the nearest valid
+// alternative is found by swapping down the bottom half's rating list: each
+// top player in turn takes their ideal opponent if still free, otherwise the
+// highest ranked free one. E.g. should 1 vs 4 of 1-4, 2-5, 3-6 be a
+// rematch, 4 and 5 are swapped to give 1-5, 2-4, 3-6. Higher ranked top
+// players thus keep their ideal opponents where possible.
+//
+// opps[i] is the bottom player top player i meets. rematches is the number
+// of repeat pairings which could not be avoided: 0 whenever some pairing
+// avoids them all, otherwise the fewest possible, with the pairing nearest
+// to ideal among those which force that many.
+func avoidRematches(n int, met func(top, bottom int) bool) (opps []int,
+	rematches int) {
+
+	opps = make([]int, n)
+	used := make([]bool, n)
+	var steps int
+	var search func(i int, allowed int) bool
+	search = func(i int, allowed int) bool {
+		if i == n {
+			return true
+		}
+		steps++
+		if steps > maxRematchSearchSteps {
+			return false
+		}
+		for k := -1; k < n; k++ {
+			// the ideal opponent first, then the rest by rank
+			j := k
+			if k < 0 {
+				j = i
+			} else if k == i {
+				continue
+			}
+			if used[j] {
+				continue
+			}
+			remaining := allowed
+			if met(i, j) {
+				if remaining == 0 {
+					continue
+				}
+				remaining--
+			}
+			used[j], opps[i] = true, j
+			if search(i+1, remaining) {
+				return true
+			}
+			used[j] = false
+		}
+		return false
+	}
+
+	for rematches = 0; rematches < n; rematches++ {
+		steps = 0
+		if search(0, rematches) {
+			return opps, rematches
+		}
+	}
+	// every pairing repeats; keep the ideal one
+	for i := range opps {
+		opps[i] = i
+	}
+
+	return opps, n
+}
@@ -0,0 +1,65 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAvoidRematches(t *testing.T) {
+	tests := []struct {
+		name      string
+		n         int
+		met       [][2]int // top, bottom pairs which already played
+		want      []int
+		rematches int
+	}{
+		{name: "no prior games", n: 3, want: []int{0, 1, 2}},
+		{name: "none", n: 0, want: []int{}},
+		{name: "ideal pairing is new", n: 3, met: [][2]int{{0, 1}, {2, 0}},
+			want: []int{0, 1, 2}},
+		{name: "swap with next lower", n: 3, met: [][2]int{{0, 0}},
+			want: []int{1, 0, 2}},
+		{name: "swap further down", n: 3, met: [][2]int{{0, 0}, {0, 1}},
+			want: []int{2, 1, 0}},
+		{name: "lower board rematch", n: 3, met: [][2]int{{2, 2}},
+			want: []int{0, 2, 1}},
+		{name: "last board rematch swaps upward", n: 2,
+			met: [][2]int{{1, 1}}, want: []int{1, 0}},
+		{name: "later constraint forces earlier swap", n: 3,
+			met: [][2]int{{1, 1}, {1, 2}}, want: []int{1, 0, 2}},
+		{name: "top player has met everyone", n: 2,
+			met: [][2]int{{1, 0}, {1, 1}}, want: []int{0, 1}, rematches: 1},
+		{name: "bottom player has met everyone", n: 3,
+			met: [][2]int{{0, 0}, {1, 0}, {2, 0}}, want: []int{0, 1, 2},
+			rematches: 1},
+		{name: "forced repeat placed nearest ideal", n: 3,
+			met: [][2]int{{0, 0}, {1, 0}, {2, 0}, {0, 1}}, want: []int{0, 1, 2},
+			rematches: 1},
+		{name: "single forced repeat", n: 1, met: [][2]int{{0, 0}},
+			want: []int{0}, rematches: 1},
+		{name: "every pairing repeats", n: 2,
+			met:  [][2]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}},
+			want: []int{0, 1}, rematches: 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			met := make(map[[2]int]bool)
+			for _, pair := range tc.met {
+				met[pair] = true
+			}
+			opps, rematches := avoidRematches(tc.n, func(i, j int) bool {
+				return met[[2]int{i, j}]
+			})
+			if !reflect.DeepEqual(opps, tc.want) ||
+				rematches != tc.rematches {
+
+				t.Errorf("avoidRematches() = %v, %v; want %v, %v", opps,
+					rematches, tc.want, tc.rematches)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// GameResult is the outcome of a pairing for one side of the board.
+type GameResult int
+
+const (
+	// ResultNone marks the empty side of a bye pairing, and pairings whose
+	// results were not parsed from the website.
+	ResultNone GameResult = iota
+	// ResultPending marks a player whose result has not been posted yet.
+	ResultPending
+	ResultWin
+	ResultLoss
+	ResultDraw
+	ResultBye
+	ResultForfeitWin
+	ResultForfeitLoss
+)
+
+func (r GameResult) String() string {
+	switch r {
+	case ResultPending:
+		return "pending"
+	case ResultWin:
+		return "win"
+	case ResultLoss:
+		return "loss"
+	case ResultDraw:
+		return "draw"
+	case ResultBye:
+		return "bye"
+	case ResultForfeitWin:
+		return "forfeit win"
+	case ResultForfeitLoss:
+		return "forfeit loss"
+	default:
+		return "none"
+	}
+}
+
+// parseSideResult types one side's posted result from the pairings page,
+// e.g. "1", "½", "0", "1F" or "+" (forfeit win), "0F" or "-" (forfeit
+// loss), returning the points it is worth. isBye is set when the side is the
+// player receiving a bye, whose result is the bye's value. ok is false when
+// no result has been posted or it cannot be understood.
+func parseSideResult(res string, isBye bool) (result GameResult,
+	points float64, ok bool) {
+
+	res = strings.ToUpper(strings.TrimSpace(res))
+	forfeit := false
+	switch {
+	case res == "":
+		return ResultPending, 0, false
+	case res == "+":
+		res, forfeit = "1", true
+	case res == "-":
+		res, forfeit = "0", true
+	case strings.HasSuffix(res, "F") || strings.HasSuffix(res, "X"):
+		res, forfeit = strings.TrimSpace(res[:len(res)-1]), true
+	}
+
+	if strings.Contains(res, "½") {
+		points = 0.5
+	} else if parsed, err := strconv.ParseFloat(res, 64); err == nil {
+		points = parsed
+	} else {
+		return ResultPending, 0, false
+	}
+
+	switch {
+	case isBye:
+		return ResultBye, points, true
+	case points == 1 && forfeit:
+		return ResultForfeitWin, points, true
+	case points == 1:
+		return ResultWin, points, true
+	case points == 0 && forfeit:
+		return ResultForfeitLoss, points, true
+	case points == 0:
+		return ResultLoss, points, true
+	case points == 0.5 && !forfeit:
+		return ResultDraw, points, true
+	}
+
+	return ResultPending, 0, false
+}
+
+// ByePlayer returns the player receiving a bye. The website may list a bye
+// on either side of the board; the other side is then "BYE".
+func (p Pairing) ByePlayer() Player {
+	if p.WhitePlayer.DisplayName == "BYE" && p.BlackPlayer.DisplayName != "" {
+		return p.BlackPlayer
+	}
+
+	return p.WhitePlayer
+}
+
+// ByePoints returns the points awarded for a bye, as reported for whichever
+// side of the board received it. ok is false when they are not known, e.g.
+// because the bye's result has not been posted.
+func (p Pairing) ByePoints() (points float64, ok bool) {
+	if p.WhitePoints != nil {
+		return *p.WhitePoints, true
+	}
+	if p.BlackPoints != nil {
+		return *p.BlackPoints, true
+	}
+
+	return 0, false
+}
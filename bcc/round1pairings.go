@@ -7,6 +7,7 @@ package bcc
 import (
 	"context"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,6 +21,7 @@ import (
 const (
 	round1PairingCorrectionConcurrency = 8
 	round1PairingCorrectionTimeout     = 30 * time.Second
+	defaultRequestedByePoints          = 0.5
 )
 
 type uschessRatingProfileLookup func(context.Context,
@@ -30,6 +32,36 @@ type section struct {
 	Pairings []Pairing
 }
 
+// PairingOptions adjusts how round 1 pairings are predicted. The zero value
+// predicts pairings as the club pairs a swiss.
+type PairingOptions struct {
+	// MinRating, when positive, pairs the players of each section rated
+	// below it (including unrated players) among themselves, after and
+	// apart from the rest of the section, rather than by rating alongside
+	// them. Should both groups have an odd number of players, the lowest
+	// rated player at or above MinRating is paired into the lower group.
+	MinRating int
+	// MinSectionSize, when positive, is the fewest players a section needs
+	// to be paired on its own. Smaller sections are left out of the
+	// prediction, or merged as MergeSmallSections directs, and reported as
+	// SmallSections.
+	MinSectionSize int
+	// MergeSmallSections pairs the players of a section smaller than
+	// MinSectionSize with the section listed before it, or after it when it
+	// is listed first, instead of leaving them out.
+	MergeSmallSections bool
+}
+
+// SmallSection is a section predicted round 1 pairings did not pair on its
+// own because it had fewer than PairingOptions.MinSectionSize players.
+type SmallSection struct {
+	Name    string
+	Players int
+	// MergedInto is the section its players were paired with, or "" when
+	// they were left out.
+	MergedInto string
+}
+
 type color int
 
 const (
@@ -37,19 +69,42 @@ const (
 	black
 )
 
-func predictRound1Pairings(entries []Entry) []Pairing {
-	sections := buildSections(entries)
+// predictRound1Pairings predicts round 1 pairings for entries, awarding
+// requestedByePoints to each player who requested a round 1 bye.
+func predictRound1Pairings(entries []Entry,
+	requestedByePoints float64) []Pairing {
+
+	return predictRound1PairingsWithOptions(entries, requestedByePoints,
+		PairingOptions{})
+}
+
+func predictRound1PairingsWithOptions(entries []Entry,
+	requestedByePoints float64, opts PairingOptions) []Pairing {
+
+	pairings, _ := predictRound1PairingsWithReport(entries, requestedByePoints,
+		opts)
+	return pairings
+}
+
+// predictRound1PairingsWithReport predicts round 1 pairings as
+// predictRound1PairingsWithOptions does and also returns the sections which
+// were too small to pair on their own.
+func predictRound1PairingsWithReport(entries []Entry,
+	requestedByePoints float64, opts PairingOptions) ([]Pairing,
+	[]SmallSection) {
+
+	sections, small := buildSections(entries, requestedByePoints, opts)
 
 	pairings := make([]Pairing, 0)
 	for _, sec := range sections {
 		pairings = append(pairings, sec.Pairings...)
 	}
 
-	return pairings
+	return pairings, small
 }
 
-func correctRound1PairingEntries(entries []Entry) []Entry {
-	ctx, cancel := context.WithTimeout(context.Background(),
+func correctRound1PairingEntries(ctx context.Context, entries []Entry) []Entry {
+	ctx, cancel := context.WithTimeout(ctx,
 		round1PairingCorrectionTimeout)
 	defer cancel()
 
@@ -167,7 +222,30 @@ func isUsableSupplementRating(rating string) bool {
 	return rating != "" && rating != "<unrated>" && strRatingToInt(rating) > 0
 }
 
-func buildSections(entries []Entry) map[string]section {
+var (
+	// "full point bye", "1-pt byes"
+	fullPointByeRe = regexp.MustCompile(`\b(?:full|1)[\s-]*(?:point|pt)\s+byes?\b`)
+	// "zero point bye", "0-pt byes"
+	zeroPointByeRe = regexp.MustCompile(`\b(?:zero|0)[\s-]*(?:point|pt)\s+byes?\b`)
+)
+
+// requestedByePointsFromDetail derives the value of a requested bye from the
+// event's description, defaulting to a half point bye.
+func requestedByePointsFromDetail(detail *EventDetail) float64 {
+	desc := strings.ToLower(detail.Description)
+	if fullPointByeRe.MatchString(desc) {
+		return 1.0
+	}
+	if zeroPointByeRe.MatchString(desc) {
+		return 0.0
+	}
+
+	return defaultRequestedByePoints
+}
+
+func buildSections(entries []Entry, requestedByePoints float64,
+	opts PairingOptions) (map[string]section, []SmallSection) {
+
 	sections := make(map[string]section)
 
 	for _, entry := range entries {
@@ -189,18 +267,69 @@ func buildSections(entries []Entry) map[string]section {
 		sectionNames = append(sectionNames, sec)
 	}
 	sort.Sort(SectionSorter(sectionNames))
+	sectionNames, small := gateSmallSections(sections, sectionNames, opts)
 
 	boardNum := 1
 	for _, key := range sectionNames {
 		sec := sections[key]
-		buildPairingsInSection(&sec, &boardNum)
+		buildPairingsInSection(&sec, &boardNum, requestedByePoints, opts)
 		sections[key] = sec
 	}
 
-	return sections
+	return sections, small
 }
 
-func buildPairingsInSection(sec *section, boardNum *int) {
+// gateSmallSections removes the sections of names, in listed order, with
+// fewer than opts.MinSectionSize players from sections, merging their
+// players into a neighboring section when opts.MergeSmallSections is set. It
+// returns the names of the remaining sections and those removed.
+func gateSmallSections(sections map[string]section, names []string,
+	opts PairingOptions) ([]string, []SmallSection) {
+
+	if opts.MinSectionSize <= 0 {
+		return names, nil
+	}
+
+	kept := make([]string, 0, len(names))
+	var small []SmallSection
+	for idx, name := range names {
+		sec := sections[name]
+		if len(sec.Players) >= opts.MinSectionSize {
+			kept = append(kept, name)
+			continue
+		}
+
+		gated := SmallSection{Name: name, Players: len(sec.Players)}
+		if opts.MergeSmallSections {
+			// merged players play up where possible; a following section
+			// is still to be gated itself, so joining it may save both
+			if len(kept) > 0 {
+				gated.MergedInto = kept[len(kept)-1]
+			} else if idx+1 < len(names) {
+				gated.MergedInto = names[idx+1]
+			}
+		}
+		if gated.MergedInto != "" {
+			into := sections[gated.MergedInto]
+			for _, entry := range sec.Players {
+				// renumbered within the section they join
+				entry.SectionName = gated.MergedInto
+				entry.PairingNumber = 0
+				into.Players = append(into.Players, entry)
+			}
+			sections[gated.MergedInto] = into
+		}
+		delete(sections, name)
+		small = append(small, gated)
+	}
+
+	return kept, small
+}
+
+func buildPairingsInSection(sec *section, boardNum *int,
+	requestedByePoints float64, opts PairingOptions) {
+
+	assignPairingNumbers(sec.Players)
 	sec.Pairings = make([]Pairing, 0)
 	requestedByes := make([]Entry, 0)
 	var oddBye *Entry
@@ -213,9 +342,9 @@ func buildPairingsInSection(sec *section, boardNum *int) {
 			remainingPlayers = append(remainingPlayers, entry)
 		}
 	}
-	sort.Slice(remainingPlayers, func(i, j int) bool {
-		return strRatingToInt(remainingPlayers[i].PrimaryRating) >
-			strRatingToInt(remainingPlayers[j].PrimaryRating)
+	sort.SliceStable(remainingPlayers, func(i, j int) bool {
+		return remainingPlayers[i].PairingNumber <
+			remainingPlayers[j].PairingNumber
 	})
 	if len(remainingPlayers)%2 == 1 {
 		last := remainingPlayers[len(remainingPlayers)-1]
@@ -223,32 +352,113 @@ func buildPairingsInSection(sec *section, boardNum *int) {
 		remainingPlayers = remainingPlayers[:len(remainingPlayers)-1]
 	}
 
-	// build pairings from the remaining even set of players
-	// highest rated player gets white against (n/2)-th highest
-	// rated player. 2nd highest rated player gets black against
-	// (n/2 + 1)-th highest rated player. & so on.
 	lastTopColor := black
+	topGroup, lowerGroup := splitPairingGroups(remainingPlayers,
+		opts.MinRating)
+	for _, group := range [][]Entry{topGroup, lowerGroup} {
+		sec.Pairings = append(sec.Pairings, pairGroup(group, boardNum,
+			&lastTopColor)...)
+	}
+	for _, p := range requestedByes {
+		sec.Pairings = append(sec.Pairings, buildOneBye(p, requestedByePoints))
+	}
+	if oddBye != nil {
+		sec.Pairings = append(sec.Pairings, buildOneBye(*oddBye, 1.0))
+	}
+}
+
+// splitPairingGroups splits an even set of players ordered by pairing number
+// into those rated at least minRating and those rated below it, moving the
+// last of the former into the latter when both are odd so that each group can
+// be paired within itself. All players are in the 1st group when minRating is
+// not positive.
+func splitPairingGroups(players []Entry, minRating int) ([]Entry, []Entry) {
+	if minRating <= 0 {
+		return players, nil
+	}
+
+	top := make([]Entry, 0, len(players))
+	lower := make([]Entry, 0)
+	for _, entry := range players {
+		if strRatingToInt(entry.PrimaryRating) >= minRating {
+			top = append(top, entry)
+		} else {
+			lower = append(lower, entry)
+		}
+	}
+	if len(top)%2 == 1 {
+		lower = append([]Entry{top[len(top)-1]}, lower...)
+		top = top[:len(top)-1]
+	}
+
+	return top, lower
+}
+
+// pairGroup pairs an even set of players ordered by pairing number: the
+// highest rated player gets white against the (n/2)-th highest rated player,
+// the 2nd highest rated player gets black against the (n/2 + 1)-th highest
+// rated player, & so on.
+func pairGroup(players []Entry, boardNum *int,
+	lastTopColor *color) []Pairing {
+
+	pairings := make([]Pairing, 0, len(players)/2)
+	remainingPlayers := append([]Entry(nil), players...)
 	for len(remainingPlayers) >= 2 {
 		n := len(remainingPlayers)
 		top := remainingPlayers[0]
 		opp := remainingPlayers[n/2]
-		if lastTopColor == black {
-			lastTopColor = white
-			sec.Pairings = append(sec.Pairings, buildOnePairing(top, opp,
-				boardNum))
+		if *lastTopColor == black {
+			*lastTopColor = white
+			pairings = append(pairings, buildOnePairing(top, opp, boardNum))
 		} else {
-			lastTopColor = black
-			sec.Pairings = append(sec.Pairings, buildOnePairing(opp, top,
-				boardNum))
+			*lastTopColor = black
+			pairings = append(pairings, buildOnePairing(opp, top, boardNum))
 		}
 		remainingPlayers = removeIndex(remainingPlayers, n/2)
 		remainingPlayers = removeIndex(remainingPlayers, 0)
 	}
-	for _, p := range requestedByes {
-		sec.Pairings = append(sec.Pairings, buildOneBye(p, 0.5))
+
+	return pairings
+}
+
+// assignPairingNumbers preserves any pairing numbers the club has already
+// assigned to entries and numbers the remaining entries in descending rating
+// order using the lowest numbers not already taken. Entries with equal ratings
+// are ordered by USCF id and then by name so that predictions do not depend on
+// the order in which entries were returned.
+func assignPairingNumbers(entries []Entry) {
+	taken := make(map[int]bool)
+	unnumbered := make([]int, 0)
+	for idx, entry := range entries {
+		if entry.PairingNumber > 0 {
+			taken[entry.PairingNumber] = true
+		} else {
+			unnumbered = append(unnumbered, idx)
+		}
 	}
-	if oddBye != nil {
-		sec.Pairings = append(sec.Pairings, buildOneBye(*oddBye, 1.0))
+	sort.SliceStable(unnumbered, func(i, j int) bool {
+		a, b := entries[unnumbered[i]], entries[unnumbered[j]]
+		aRating := strRatingToInt(a.PrimaryRating)
+		bRating := strRatingToInt(b.PrimaryRating)
+		if aRating != bRating {
+			return aRating > bRating
+		}
+		if a.UscfID != b.UscfID {
+			return a.UscfID < b.UscfID
+		}
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		return a.FirstName < b.FirstName
+	})
+
+	next := 1
+	for _, idx := range unnumbered {
+		for taken[next] {
+			next++
+		}
+		entries[idx].PairingNumber = next
+		taken[next] = true
 	}
 }
 
@@ -280,31 +490,8 @@ func buildOneBye(w Entry, points float64) Pairing {
 }
 
 func round1ByeRequested(req string) bool {
-	s := strings.TrimSpace(req)
-	if s == "" {
-		return false
-	}
-	// If input is just a number, e.g., "1"
-	numOnly := regexp.MustCompile(`^\d+$`)
-	if numOnly.MatchString(s) {
-		if n, err := strconv.Atoi(s); err == nil && n == 1 {
-			return true
-		}
-	}
-
-	// Look for patterns like "round 1,5" or "rnds 1&4"
-	sl := strings.ToLower(s)
-	listRe := regexp.MustCompile(`(?i)\b(?:round|rnd|rounds|rnds)\b[\s:]*((?:\d+(?:\s*[,&;/]\s*\d+)*))`)
-	if matches := listRe.FindStringSubmatch(sl); matches != nil {
-		nums := regexp.MustCompile(`\d+`).FindAllString(matches[1], -1)
-		for _, m := range nums {
-			if n, err := strconv.Atoi(m); err == nil && n == 1 {
-				return true
-			}
-		}
-	}
-
-	return false
+	rounds, _ := parseByeRequest(req)
+	return slices.Contains(rounds, 1)
 }
 
 func removeIndex(s []Entry, i int) []Entry {
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -109,3 +110,237 @@ func TestCorrectRound1PairingEntriesWithLookupAllowsProvisionalSupplement(t *tes
 		t.Fatalf("strRatingToInt(%q) = %d; want %d", corrected[0].PrimaryRating, got, want)
 	}
 }
+
+func TestPredictRound1PairingsFullPointRequestedBye(t *testing.T) {
+	detail := &EventDetail{
+		Description: "4 round Swiss. Full-point byes available in any round if requested at registration.",
+		Entries: []Entry{
+			{FirstName: "A", LastName: "One", PrimaryRating: "1800", SectionName: "Open"},
+			{FirstName: "B", LastName: "Two", PrimaryRating: "1700", SectionName: "Open"},
+			{FirstName: "C", LastName: "Three", PrimaryRating: "1600", SectionName: "Open", ByeRequests: "round 1"},
+		},
+	}
+	points := requestedByePointsFromDetail(detail)
+	if points != 1.0 {
+		t.Fatalf("requestedByePointsFromDetail() = %v; want 1", points)
+	}
+
+	pairings := predictRound1Pairings(detail.Entries, points)
+	if len(pairings) != 2 {
+		t.Fatalf("expected 2 pairings, got %v", len(pairings))
+	}
+	bye := pairings[1]
+	if !bye.IsByePairing || bye.WhitePlayer.LastName != "Three" {
+		t.Fatalf("expected requested bye for Three, got %+v", bye)
+	}
+	if bye.WhitePoints == nil || *bye.WhitePoints != 1.0 {
+		t.Errorf("requested bye points = %v; want 1", bye.WhitePoints)
+	}
+}
+
+func TestRequestedByePointsFromDetail(t *testing.T) {
+	cases := map[string]float64{
+		"":                              0.5,
+		"Half point byes in rounds 1-3": 0.5,
+		"One full point bye available":  1.0,
+		"1 pt bye allowed":              1.0,
+		"Zero-point byes only":          0.0,
+	}
+	for desc, want := range cases {
+		got := requestedByePointsFromDetail(&EventDetail{Description: desc})
+		if got != want {
+			t.Errorf("requestedByePointsFromDetail(%q) = %v; want %v", desc, got,
+				want)
+		}
+	}
+}
+
+func TestPredictRound1PairingsPreservesPairingNumbers(t *testing.T) {
+	entries := []Entry{
+		{FirstName: "A", LastName: "One", PrimaryRating: "1800", SectionName: "Open", PairingNumber: 4},
+		{FirstName: "B", LastName: "Two", PrimaryRating: "1700", SectionName: "Open", PairingNumber: 1},
+		{FirstName: "C", LastName: "Three", PrimaryRating: "1600", SectionName: "Open"},
+		{FirstName: "D", LastName: "Four", PrimaryRating: "1500", SectionName: "Open"},
+	}
+
+	pairings := predictRound1Pairings(entries, defaultRequestedByePoints)
+	if len(pairings) != 2 {
+		t.Fatalf("expected 2 pairings, got %v", len(pairings))
+	}
+
+	got := make(map[string]int)
+	for _, p := range pairings {
+		got[p.WhitePlayer.LastName] = p.WhitePlayer.PairingNumber
+		got[p.BlackPlayer.LastName] = p.BlackPlayer.PairingNumber
+	}
+	want := map[string]int{"One": 4, "Two": 1, "Three": 2, "Four": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("pairing numbers = %v; want %v", got, want)
+	}
+
+	// pairing order follows the official numbering: #1 vs #3, #4 vs #2
+	if pairings[0].WhitePlayer.LastName != "Two" ||
+		pairings[0].BlackPlayer.LastName != "Four" {
+		t.Errorf("board 1 = %v vs %v; want Two vs Four",
+			pairings[0].WhitePlayer.LastName, pairings[0].BlackPlayer.LastName)
+	}
+	if pairings[1].WhitePlayer.LastName != "One" ||
+		pairings[1].BlackPlayer.LastName != "Three" {
+		t.Errorf("board 2 = %v vs %v; want One vs Three",
+			pairings[1].WhitePlayer.LastName, pairings[1].BlackPlayer.LastName)
+	}
+}
+
+func TestPredictRound1PairingsEqualRatingsDeterministic(t *testing.T) {
+	entries := []Entry{
+		{FirstName: "A", LastName: "One", UscfID: 30, PrimaryRating: "1500", SectionName: "Open"},
+		{FirstName: "B", LastName: "Two", UscfID: 10, PrimaryRating: "1500", SectionName: "Open"},
+		{FirstName: "C", LastName: "Three", UscfID: 20, PrimaryRating: "1500", SectionName: "Open"},
+		{FirstName: "D", LastName: "Four", PrimaryRating: "1500", SectionName: "Open"},
+		{FirstName: "E", LastName: "Five", PrimaryRating: "1500", SectionName: "Open"},
+		{FirstName: "F", LastName: "Six", UscfID: 40, PrimaryRating: "1400", SectionName: "Open"},
+	}
+
+	want := predictRound1Pairings(entries, defaultRequestedByePoints)
+	// unknown ids sort first and by name, then known ids ascending
+	if want[0].WhitePlayer.LastName != "Five" ||
+		want[0].BlackPlayer.LastName != "Three" {
+		t.Fatalf("board 1 = %v vs %v; want Five vs Three",
+			want[0].WhitePlayer.LastName, want[0].BlackPlayer.LastName)
+	}
+
+	for run := 0; run < 20; run++ {
+		// rotate the input so each run sees a different entry order
+		shuffled := append(append([]Entry(nil), entries[run%len(entries):]...),
+			entries[:run%len(entries)]...)
+		got := predictRound1Pairings(shuffled, defaultRequestedByePoints)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("run %v: pairings = %+v; want %+v", run, got, want)
+		}
+	}
+}
+
+func TestPredictRound1PairingsMinRating(t *testing.T) {
+	entries := []Entry{
+		{FirstName: "A", LastName: "One", PrimaryRating: "2000", SectionName: "Open"},
+		{FirstName: "B", LastName: "Two", PrimaryRating: "1900", SectionName: "Open"},
+		{FirstName: "C", LastName: "Three", PrimaryRating: "1800", SectionName: "Open"},
+		{FirstName: "D", LastName: "Four", PrimaryRating: "1100P4", SectionName: "Open"},
+		{FirstName: "E", LastName: "Five", PrimaryRating: "<unrated>", SectionName: "Open"},
+		{FirstName: "F", LastName: "Six", PrimaryRating: "900", SectionName: "Open"},
+	}
+	boards := func(pairings []Pairing) []string {
+		got := make([]string, 0, len(pairings))
+		for _, p := range pairings {
+			got = append(got, p.WhitePlayer.LastName+"-"+p.BlackPlayer.LastName)
+		}
+		return got
+	}
+
+	// the default pairs every player by rating
+	want := predictRound1Pairings(entries, defaultRequestedByePoints)
+	got := predictRound1PairingsWithOptions(entries, defaultRequestedByePoints,
+		PairingOptions{})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("zero options pairings = %v; want %v", boards(got),
+			boards(want))
+	}
+	if b := boards(got); !reflect.DeepEqual(b,
+		[]string{"One-Four", "Six-Two", "Three-Five"}) {
+
+		t.Errorf("default pairings = %v", b)
+	}
+
+	// players below 1200 are paired among themselves; Three, the lowest of
+	// an odd number of players rated 1200 or more, is paired down with them
+	got = predictRound1PairingsWithOptions(entries, defaultRequestedByePoints,
+		PairingOptions{MinRating: 1200})
+	wantBoards := []string{"One-Two", "Six-Three", "Four-Five"}
+	if b := boards(got); !reflect.DeepEqual(b, wantBoards) {
+		t.Errorf("minrating pairings = %v; want %v", b, wantBoards)
+	}
+	for idx, p := range got {
+		if p.BoardNumber != idx+1 {
+			t.Errorf("board %v numbered %v", idx+1, p.BoardNumber)
+		}
+	}
+
+	// with an odd section the lowest player below the threshold gets the bye
+	got = predictRound1PairingsWithOptions(entries[:5],
+		defaultRequestedByePoints, PairingOptions{MinRating: 1200})
+	wantBoards = []string{"One-Two", "Four-Three", "Five-"}
+	if b := boards(got); !reflect.DeepEqual(b, wantBoards) {
+		t.Errorf("odd minrating pairings = %v; want %v", b, wantBoards)
+	}
+}
+
+func TestPredictRound1PairingsMinSectionSize(t *testing.T) {
+	entries := []Entry{
+		{FirstName: "A", LastName: "One", PrimaryRating: "1700", SectionName: "U1800"},
+		{FirstName: "B", LastName: "Two", PrimaryRating: "1600", SectionName: "U1800"},
+		{FirstName: "C", LastName: "Three", PrimaryRating: "1500", SectionName: "U1800"},
+		{FirstName: "D", LastName: "Four", PrimaryRating: "1100", SectionName: "U1200",
+			PairingNumber: 1},
+	}
+	boards := func(pairings []Pairing) []string {
+		got := make([]string, 0, len(pairings))
+		for _, p := range pairings {
+			got = append(got, p.Section+":"+p.WhitePlayer.LastName+"-"+
+				p.BlackPlayer.LastName)
+		}
+		sort.Strings(got)
+		return got
+	}
+
+	// by default the lone U1200 player gets a bye
+	got, small := predictRound1PairingsWithReport(entries,
+		defaultRequestedByePoints, PairingOptions{})
+	want := []string{"U1200:Four-", "U1800:One-Two", "U1800:Three-"}
+	if b := boards(got); !reflect.DeepEqual(b, want) || small != nil {
+		t.Errorf("default pairings = %v, %v; want %v", b, small, want)
+	}
+
+	// gated, the U1200 is left out and reported
+	got, small = predictRound1PairingsWithReport(entries,
+		defaultRequestedByePoints, PairingOptions{MinSectionSize: 2})
+	want = []string{"U1800:One-Two", "U1800:Three-"}
+	wantSmall := []SmallSection{{Name: "U1200", Players: 1}}
+	if b := boards(got); !reflect.DeepEqual(b, want) ||
+		!reflect.DeepEqual(small, wantSmall) {
+
+		t.Errorf("gated pairings = %v, %v; want %v, %v", b, small, want,
+			wantSmall)
+	}
+
+	// merged, the U1200 player plays up in the U1800, renumbered by rating
+	got, small = predictRound1PairingsWithReport(entries,
+		defaultRequestedByePoints, PairingOptions{MinSectionSize: 2,
+			MergeSmallSections: true})
+	want = []string{"U1800:Four-Two", "U1800:One-Three"}
+	wantSmall[0].MergedInto = "U1800"
+	if b := boards(got); !reflect.DeepEqual(b, want) ||
+		!reflect.DeepEqual(small, wantSmall) {
+
+		t.Errorf("merged pairings = %v, %v; want %v, %v", b, small, want,
+			wantSmall)
+	}
+
+	// a small first section joins the section after it
+	entries[3].SectionName = "Open"
+	_, small = predictRound1PairingsWithReport(entries,
+		defaultRequestedByePoints, PairingOptions{MinSectionSize: 2,
+			MergeSmallSections: true})
+	wantSmall = []SmallSection{{Name: "Open", Players: 1, MergedInto: "U1800"}}
+	if !reflect.DeepEqual(small, wantSmall) {
+		t.Errorf("merged first section = %v; want %v", small, wantSmall)
+	}
+
+	tourney := &Tournament{smallSections: []SmallSection{
+		{Name: "U1200", Players: 1, MergedInto: "U1800"},
+		{Name: "U800", Players: 3}}}
+	wantNote := "Section U1200 has only 1 player; merged into U1800 for the prediction.\n" +
+		"Section U800 has only 3 players; left out of the prediction.\n"
+	if note := BuildSmallSectionsNote(tourney); note != wantNote {
+		t.Errorf("BuildSmallSectionsNote() = %q; want %q", note, wantNote)
+	}
+}
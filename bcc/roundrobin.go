@@ -0,0 +1,130 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"regexp"
+	"sort"
+)
+
+var roundRobinRe = regexp.MustCompile(`(?i)\b(?:quads?|round[\s-]*robin|rr)\b`)
+
+// isRoundRobin reports whether an event is a round robin (e.g. a quad)
+// rather than a swiss.
+func isRoundRobin(detail *EventDetail) bool {
+	return roundRobinRe.MatchString(detail.EventFormat) ||
+		roundRobinRe.MatchString(detail.Title)
+}
+
+// predictRoundRobinPairings predicts the pairings for every round of a round
+// robin. Each section is paired separately using the standard (Berger) round
+// robin tables with players numbered in descending rating order. In sections
+// with an odd number of players, the player who would face the missing
+// player sits out that round with a zero point bye.
+func predictRoundRobinPairings(entries []Entry) []Pairing {
+	secEntries := make(map[string][]Entry)
+	for _, entry := range entries {
+		secEntries[entry.SectionName] = append(secEntries[entry.SectionName],
+			entry)
+	}
+	var sectionNames []string
+	for sec := range secEntries {
+		sectionNames = append(sectionNames, sec)
+	}
+	sort.Sort(SectionSorter(sectionNames))
+
+	pairings := make([]Pairing, 0)
+	for _, sec := range sectionNames {
+		pairings = append(pairings, buildRoundRobinInSection(secEntries[sec])...)
+	}
+
+	return pairings
+}
+
+func buildRoundRobinInSection(players []Entry) []Pairing {
+	assignPairingNumbers(players)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].PairingNumber < players[j].PairingNumber
+	})
+
+	// berger tables are numbered from 1 with an even number of players; a
+	// missing player (nil) stands in for a bye
+	n := len(players)
+	if n%2 == 1 {
+		n++
+	}
+	player := func(num int) *Entry {
+		if num > len(players) {
+			return nil
+		}
+		return &players[num-1]
+	}
+
+	pairings := make([]Pairing, 0)
+	for round := 1; round < n; round++ {
+		boardNum := 1
+		for _, pair := range bergerRound(n, round) {
+			w, b := player(pair[0]), player(pair[1])
+			var p Pairing
+			if w == nil || b == nil {
+				if w == nil {
+					w = b
+				}
+				p = buildOneBye(*w, 0.0)
+			} else {
+				p = buildOnePairing(*w, *b, &boardNum)
+			}
+			p.RoundNumber = round
+			pairings = append(pairings, p)
+		}
+	}
+
+	return pairings
+}
+
+// bergerRound returns the [white, black] pairing numbers for each board of
+// the given round of an n player round robin, where n is even. On board 1
+// player n meets the player i with 2i = r+1 (modulo n-1); the remaining boards
+// pair the players either side of i moving outwards. Player n is white
+// against the top half of the field; otherwise the lower number is white when
+// the pair sums to an odd number.
+func bergerRound(n, round int) [][2]int {
+	m := n - 1
+	mod := func(v int) int {
+		v = ((v % m) + m) % m
+		if v == 0 {
+			return m
+		}
+		return v
+	}
+
+	pivot := 0
+	for i := 1; i <= m; i++ {
+		if mod(2*i) == mod(round+1) {
+			pivot = i
+			break
+		}
+	}
+
+	pairs := make([][2]int, 0, n/2)
+	if pivot <= n/2 {
+		pairs = append(pairs, [2]int{pivot, n})
+	} else {
+		pairs = append(pairs, [2]int{n, pivot})
+	}
+	for k := 1; k < n/2; k++ {
+		i, j := mod(pivot+k), mod(pivot-k)
+		if i > j {
+			i, j = j, i
+		}
+		if (i+j)%2 == 1 {
+			pairs = append(pairs, [2]int{i, j})
+		} else {
+			pairs = append(pairs, [2]int{j, i})
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,92 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsRoundRobin(t *testing.T) {
+	tests := []struct {
+		detail EventDetail
+		want   bool
+	}{
+		{EventDetail{Title: "Thursday Night Quads"}, true},
+		{EventDetail{EventFormat: "Round Robin"}, true},
+		{EventDetail{EventFormat: "4-RR"}, true},
+		{EventDetail{Title: "Tuesday Night Swiss", EventFormat: "4-SS"}, false},
+	}
+	for _, tt := range tests {
+		if got := isRoundRobin(&tt.detail); got != tt.want {
+			t.Errorf("isRoundRobin(%+v) = %v; want %v", tt.detail, got, tt.want)
+		}
+	}
+}
+
+func TestPredictRoundRobinPairingsQuad(t *testing.T) {
+	entries := []Entry{
+		{FirstName: "C", LastName: "Three", PrimaryRating: "1600", SectionName: "Quad 1"},
+		{FirstName: "A", LastName: "One", PrimaryRating: "1800", SectionName: "Quad 1"},
+		{FirstName: "D", LastName: "Four", PrimaryRating: "1500", SectionName: "Quad 1"},
+		{FirstName: "B", LastName: "Two", PrimaryRating: "1700", SectionName: "Quad 1"},
+	}
+
+	type board struct {
+		round        int
+		white, black string
+	}
+	got := make([]board, 0)
+	for _, p := range predictRoundRobinPairings(entries) {
+		got = append(got, board{p.RoundNumber, p.WhitePlayer.LastName,
+			p.BlackPlayer.LastName})
+	}
+	// standard 4 player schedule: 1-4 2-3, 4-3 1-2, 2-4 3-1
+	want := []board{
+		{1, "One", "Four"}, {1, "Two", "Three"},
+		{2, "Four", "Three"}, {2, "One", "Two"},
+		{3, "Two", "Four"}, {3, "Three", "One"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("predictRoundRobinPairings() = %v; want %v", got, want)
+	}
+}
+
+func TestPredictRoundRobinPairingsOddPlayers(t *testing.T) {
+	entries := []Entry{
+		{FirstName: "A", LastName: "One", PrimaryRating: "1800", SectionName: "Quad 1"},
+		{FirstName: "B", LastName: "Two", PrimaryRating: "1700", SectionName: "Quad 1"},
+		{FirstName: "C", LastName: "Three", PrimaryRating: "1600", SectionName: "Quad 1"},
+	}
+
+	pairings := predictRoundRobinPairings(entries)
+	byes := make(map[string]int)
+	games := make(map[string]int)
+	for _, p := range pairings {
+		if p.IsByePairing {
+			byes[p.WhitePlayer.LastName]++
+			continue
+		}
+		games[p.WhitePlayer.LastName]++
+		games[p.BlackPlayer.LastName]++
+	}
+	for _, name := range []string{"One", "Two", "Three"} {
+		if byes[name] != 1 || games[name] != 2 {
+			t.Errorf("%v: %v byes and %v games; want 1 and 2", name,
+				byes[name], games[name])
+		}
+	}
+
+	output := BuildPairingsOutput(&Tournament{CurrentPairings: pairings,
+		isPredicted: true}, false, "", 0)
+	for _, want := range []string{"all 3 rounds", "Round 1\n", "Round 3\n",
+		"BYE(0)"} {
+
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+}
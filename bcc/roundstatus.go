@@ -0,0 +1,45 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+)
+
+// IsRoundComplete reports whether every game of the current (latest posted)
+// round has a recorded result. Byes are not games and are not waited on.
+// Predicted pairings and a tournament without pairings have no round to
+// complete.
+func IsRoundComplete(t *Tournament) bool {
+	if t.IsPredicted() || len(t.CurrentPairings) == 0 {
+		return false
+	}
+
+	current := maxPairingRound(t.CurrentPairings)
+	for _, p := range t.CurrentPairings {
+		if p.RoundNumber == current && !p.IsByePairing && !resultPosted(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RoundStatusHeader introduces what is most useful to show of the current
+// round: "Round N complete — standings:" once every result is in, or
+// "Round N in progress — pairings:" while games are still being played. It
+// is empty when no pairings have been posted.
+func RoundStatusHeader(t *Tournament) string {
+	if t.IsPredicted() || len(t.CurrentPairings) == 0 {
+		return ""
+	}
+
+	round := maxPairingRound(t.CurrentPairings)
+	if IsRoundComplete(t) {
+		return fmt.Sprintf("Round %v complete — standings:", round)
+	}
+
+	return fmt.Sprintf("Round %v in progress — pairings:", round)
+}
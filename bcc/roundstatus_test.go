@@ -0,0 +1,52 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"testing"
+)
+
+func TestIsRoundComplete(t *testing.T) {
+	one := 1.0
+	tourney := &Tournament{CurrentPairings: []Pairing{
+		// an earlier round's unfinished game doesn't hold up the current one
+		{Section: "Open", RoundNumber: 2, BoardNumber: 1,
+			WhiteOutcome: ResultPending, BlackOutcome: ResultPending},
+		{Section: "Open", RoundNumber: 3, BoardNumber: 1,
+			WhiteOutcome: ResultWin, BlackOutcome: ResultLoss},
+		{Section: "U1800", RoundNumber: 3, BoardNumber: 1,
+			WhitePoints: &one},
+		{Section: "U1800", RoundNumber: 3, BoardNumber: 2,
+			WhiteOutcome: ResultPending, BlackOutcome: ResultPending},
+		{Section: "U1800", RoundNumber: 3, BoardNumber: 3, IsByePairing: true},
+	}}
+
+	if IsRoundComplete(tourney) {
+		t.Errorf("IsRoundComplete(pending board) = true")
+	}
+	if got, want := RoundStatusHeader(tourney),
+		"Round 3 in progress — pairings:"; got != want {
+		t.Errorf("RoundStatusHeader() = %q; want %q", got, want)
+	}
+
+	tourney.CurrentPairings[3].WhiteOutcome = ResultDraw
+	tourney.CurrentPairings[3].BlackOutcome = ResultDraw
+	if !IsRoundComplete(tourney) {
+		t.Errorf("IsRoundComplete(all results) = false")
+	}
+	if got, want := RoundStatusHeader(tourney),
+		"Round 3 complete — standings:"; got != want {
+		t.Errorf("RoundStatusHeader() = %q; want %q", got, want)
+	}
+
+	predicted := &Tournament{isPredicted: true,
+		CurrentPairings: tourney.CurrentPairings}
+	if IsRoundComplete(predicted) || RoundStatusHeader(predicted) != "" {
+		t.Errorf("predicted pairings reported a round status")
+	}
+	if IsRoundComplete(&Tournament{}) || RoundStatusHeader(&Tournament{}) != "" {
+		t.Errorf("empty tournament reported a round status")
+	}
+}
@@ -0,0 +1,141 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+const (
+	// suggested cutoffs are multiples of this, e.g. U1800
+	sectionCutStep = 100
+	sectionCutMax  = 3000
+)
+
+// SuggestSectionCuts suggests rating cutoffs which split the rated entries
+// of an event into numSections sections of about equal size. The cutoffs are
+// returned in descending order as multiples of 100; section 1 holds ratings
+// at or above the first cutoff, each following section holds ratings below
+// the previous cutoff and at or above its own, and the last section holds
+// ratings below the last cutoff. Unrated entries are left out of the
+// balancing so that they may be placed per the event's policy. Fewer cutoffs
+// are returned when there are too few distinct ratings to split.
+func SuggestSectionCuts(entries []Entry, numSections int) []int {
+	var ratings []int
+	for _, entry := range entries {
+		if r := strRatingToInt(entry.PrimaryRating); r > 0 {
+			ratings = append(ratings, r)
+		}
+	}
+	if numSections <= 1 || len(ratings) == 0 {
+		return nil
+	}
+
+	var cuts []int
+	prev := sectionCutMax + sectionCutStep
+	for k := 1; k < numSections; k++ {
+		target := int(math.Round(float64(k*len(ratings)) / float64(numSections)))
+		best, bestDiff := 0, math.MaxInt
+		for cut := prev - sectionCutStep; cut >= sectionCutStep; cut -= sectionCutStep {
+			if diff := abs(countAtOrAbove(ratings, cut) - target); diff < bestDiff {
+				best, bestDiff = cut, diff
+			}
+		}
+		above := countAtOrAbove(ratings, best)
+		if best == 0 || above == 0 || above == len(ratings) ||
+			(len(cuts) > 0 && above == countAtOrAbove(ratings, cuts[len(cuts)-1])) {
+			// the cut would leave a section empty
+			continue
+		}
+		cuts = append(cuts, best)
+		prev = best
+	}
+
+	return cuts
+}
+
+func countAtOrAbove(ratings []int, cut int) int {
+	count := 0
+	for _, r := range ratings {
+		if r >= cut {
+			count++
+		}
+	}
+	return count
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// BuildSectionCutsOutput formats suggested section cutoffs, in descending
+// order as returned by SuggestSectionCuts, along with the number of entries
+// each section would hold, e.g. "U1800 (1600-1799)  6".
+func BuildSectionCutsOutput(entries []Entry, cuts []int) string {
+	var ratings []int
+	unrated := 0
+	for _, entry := range entries {
+		if r := strRatingToInt(entry.PrimaryRating); r > 0 {
+			ratings = append(ratings, r)
+		} else {
+			unrated++
+		}
+	}
+	if len(ratings) == 0 {
+		return "No rated entries to split into sections.\n"
+	}
+	type row struct {
+		name  string
+		count int
+	}
+	var rows []row
+	upper := 0
+	for i := 0; i <= len(cuts); i++ {
+		lower := 0
+		if i < len(cuts) {
+			lower = cuts[i]
+		}
+		var name string
+		switch {
+		case upper == 0 && lower == 0:
+			name = "Open"
+		case upper == 0:
+			name = fmt.Sprintf("Open (%d+)", lower)
+		case lower == 0:
+			name = fmt.Sprintf("U%d", upper)
+		default:
+			name = fmt.Sprintf("U%d (%d-%d)", upper, lower, upper-1)
+		}
+		count := countAtOrAbove(ratings, lower)
+		if upper != 0 {
+			count -= countAtOrAbove(ratings, upper)
+		}
+		rows = append(rows, row{name, count})
+		upper = lower
+	}
+
+	nameWidth := len("Section")
+	for _, r := range rows {
+		nameWidth = max(nameWidth, internal.DisplayWidth(r.name))
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%-*s  %s\n", nameWidth, "Section", "Players"))
+	for _, r := range rows {
+		sb.WriteString(fmt.Sprintf("%-*s  %d\n", nameWidth, r.name, r.count))
+	}
+	if unrated > 0 {
+		sb.WriteString(fmt.Sprintf("%-*s  %d (not included above)\n", nameWidth,
+			"Unrated", unrated))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSuggestSectionCuts(t *testing.T) {
+	var entries []Entry
+	for _, r := range []int{2100, 2050, 2000, 1950, 1850, 1800, 1750, 1700,
+		1500, 1450, 1400, 1300} {
+		entries = append(entries, Entry{PrimaryRating: strconv.Itoa(r)})
+	}
+	entries = append(entries, Entry{PrimaryRating: ""},
+		Entry{PrimaryRating: "Unrated"})
+
+	cuts := SuggestSectionCuts(entries, 3)
+	if !slices.Equal(cuts, []int{1900, 1700}) {
+		t.Fatalf("SuggestSectionCuts(3) = %v; want [1900 1700]", cuts)
+	}
+	output := BuildSectionCutsOutput(entries, cuts)
+	for _, want := range []string{
+		"Open (1900+)       4\n",
+		"U1900 (1700-1899)  4\n",
+		"U1700              4\n",
+		"Unrated            2 (not included above)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	if cuts := SuggestSectionCuts(entries, 2); !slices.Equal(cuts, []int{1800}) {
+		t.Errorf("SuggestSectionCuts(2) = %v; want [1800]", cuts)
+	}
+	if cuts := SuggestSectionCuts(entries, 1); cuts != nil {
+		t.Errorf("SuggestSectionCuts(1) = %v; want none", cuts)
+	}
+
+	// more sections than distinct ratings never yields an empty section
+	few := []Entry{{PrimaryRating: "1500"}, {PrimaryRating: "1500"},
+		{PrimaryRating: "1200"}}
+	if cuts := SuggestSectionCuts(few, 4); !slices.Equal(cuts, []int{1500}) {
+		t.Errorf("SuggestSectionCuts(few, 4) = %v; want [1500]", cuts)
+	}
+}
@@ -0,0 +1,93 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// "U1800", "U-1800", or "Under 1800"
+	sectionCapRe = regexp.MustCompile(`(?i)\b(?:u-?|under\s+)(\d{3,4})\b`)
+	// "1600-1799"
+	sectionRangeRe = regexp.MustCompile(`\b(\d{3,4})\s*-\s*(\d{3,4})\b`)
+	// "2000+" or "Championship (2000+)"
+	sectionFloorRe = regexp.MustCompile(`\b(\d{3,4})\s*\+`)
+	// separators between sections in a section display, e.g. "Open, U1800
+	// & U1400"
+	sectionSepRe = regexp.MustCompile(`(?i)[,;/&]|\s+and\s+`)
+)
+
+// Section is a tournament section as advertised for an event.
+type Section struct {
+	Name string
+	// Cap is the lowest rating which is ineligible for the section, e.g.
+	// 1800 for "U1800", or 0 when the section has no rating cap (e.g. Open
+	// or Reserve).
+	Cap int
+	// Floor is the lowest rating which is eligible for the section, e.g.
+	// 2000 for "Championship (2000+)", or 0 when the section has no rating
+	// floor (e.g. "Open to all").
+	Floor int
+}
+
+// ParseSections returns the sections advertised for an event along with
+// their rating caps and floors. Sections are taken from detail.Sections when present
+// and otherwise split from detail.SectionDisplay.
+func ParseSections(detail *EventDetail) []Section {
+	names := detail.Sections
+	if len(names) == 0 {
+		names = sectionSepRe.Split(detail.SectionDisplay, -1)
+	}
+
+	var sections []Section
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		if name == "" {
+			continue
+		}
+		cap, _ := sectionRatingCap(name)
+		floor, _ := sectionRatingFloor(name)
+		sections = append(sections, Section{Name: name, Cap: cap,
+			Floor: floor})
+	}
+
+	return sections
+}
+
+// sectionRatingCap returns the lowest rating which is ineligible for a
+// section given its name, e.g. 1800 for "U1800" or "Under 1800" and 1800 for
+// "1600-1799". ok is false for sections without a numeric cap (e.g. Open).
+func sectionRatingCap(section string) (cap int, ok bool) {
+	if m := sectionCapRe.FindStringSubmatch(section); m != nil {
+		cap, _ = strconv.Atoi(m[1])
+		return cap, true
+	}
+	if m := sectionRangeRe.FindStringSubmatch(section); m != nil {
+		upper, _ := strconv.Atoi(m[2])
+		return upper + 1, true
+	}
+
+	return 0, false
+}
+
+// sectionRatingFloor returns the lowest rating which is eligible for a
+// section given its name, e.g. 2000 for "Championship (2000+)" and 1600 for
+// "1600-1799". ok is false for sections without a numeric floor (e.g. Open
+// or U1800).
+func sectionRatingFloor(section string) (floor int, ok bool) {
+	if m := sectionFloorRe.FindStringSubmatch(section); m != nil {
+		floor, _ = strconv.Atoi(m[1])
+		return floor, true
+	}
+	if m := sectionRangeRe.FindStringSubmatch(section); m != nil {
+		floor, _ = strconv.Atoi(m[1])
+		return floor, true
+	}
+
+	return 0, false
+}
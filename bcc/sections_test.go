@@ -0,0 +1,89 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSections(t *testing.T) {
+	tests := []struct {
+		detail EventDetail
+		want   []Section
+	}{
+		{
+			EventDetail{Sections: []string{"Open", "U1800", "Under 1600",
+				"Reserve"}},
+			[]Section{{"Open", 0, 0}, {"U1800", 1800, 0},
+				{"Under 1600", 1600, 0}, {"Reserve", 0, 0}},
+		},
+		{
+			EventDetail{SectionDisplay: "Championship, U2000 & U1600"},
+			[]Section{{"Championship", 0, 0}, {"U2000", 2000, 0},
+				{"U1600", 1600, 0}},
+		},
+		{
+			EventDetail{SectionDisplay: "Open / 1600-1799 and U-1400 Reserve"},
+			[]Section{{"Open", 0, 0}, {"1600-1799", 1800, 1600},
+				{"U-1400 Reserve", 1400, 0}},
+		},
+		{
+			EventDetail{SectionDisplay: "Championship (2000+), Open to all"},
+			[]Section{{"Championship (2000+)", 0, 2000},
+				{"Open to all", 0, 0}},
+		},
+		{EventDetail{}, nil},
+	}
+	for _, tc := range tests {
+		if got := ParseSections(&tc.detail); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseSections(%+v) = %+v; want %+v", tc.detail.Sections,
+				got, tc.want)
+		}
+	}
+}
+
+func TestSectionRatingCap(t *testing.T) {
+	tests := []struct {
+		section string
+		cap     int
+		ok      bool
+	}{
+		{"U1800", 1800, true},
+		{"u-1400 Reserve", 1400, true},
+		{"Under 1600", 1600, true},
+		{"1600-1799", 1800, true},
+		{"Open", 0, false},
+		{"Championship", 0, false},
+	}
+	for _, tc := range tests {
+		cap, ok := sectionRatingCap(tc.section)
+		if cap != tc.cap || ok != tc.ok {
+			t.Errorf("sectionRatingCap(%q) = %v, %v; want %v, %v", tc.section,
+				cap, ok, tc.cap, tc.ok)
+		}
+	}
+}
+
+func TestSectionRatingFloor(t *testing.T) {
+	tests := []struct {
+		section string
+		floor   int
+		ok      bool
+	}{
+		{"Championship (2000+)", 2000, true},
+		{"2000+ Championship", 2000, true},
+		{"1600-1799", 1600, true},
+		{"U1800", 0, false},
+		{"Open to all", 0, false},
+	}
+	for _, tc := range tests {
+		floor, ok := sectionRatingFloor(tc.section)
+		if floor != tc.floor || ok != tc.ok {
+			t.Errorf("sectionRatingFloor(%q) = %v, %v; want %v, %v",
+				tc.section, floor, ok, tc.floor, tc.ok)
+		}
+	}
+}
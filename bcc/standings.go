@@ -5,15 +5,51 @@
 package bcc
 
 import (
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
 )
 
-// buildStandingsOutput formats standings into grouped, aligned string output
-func BuildStandingsOutput(t *Tournament) string {
+// StandingsRow is one player's line in a section's standings. Place is
+// empty for players tied with the row above.
+type StandingsRow struct {
+	Place string
+	Name  string
+	Score string
+	// Prize is the prize the player is in line for, when prizes were
+	// requested.
+	Prize string
+	// Rating is the player's rating change over the event, e.g.
+	// "1650->1702 (+52)", when rating changes were requested and US Chess
+	// has rated the player.
+	Rating string
+}
+
+// StandingsSection holds the ordered standings rows of one section.
+type StandingsSection struct {
+	Name string
+	Rows []StandingsRow
+}
+
+// BuildStandingsSections returns the standings of each section matching
+// section, in section order. Sections left empty by withdrawals are omitted.
+func BuildStandingsSections(t *Tournament, section string) []StandingsSection {
+	return buildStandingsSections(t, section, nil, nil)
+}
+
+// buildStandingsSections returns the standings of each section matching
+// section. When prizes is non-nil each row notes the prize its player is in
+// line for, and when ratings is non-nil their rating change.
+func buildStandingsSections(t *Tournament, section string, prizes []Prize,
+	ratings map[uschess.MemberID]uscfutils.RatingChange) []StandingsSection {
+
 	secPlayers := getPlayersBySection(t)
 	// Sort section names using custom criteria
 	var sectionNames []string
@@ -22,74 +58,320 @@ func BuildStandingsOutput(t *Tournament) string {
 	}
 	// Use named sectionSorter instead of anonymous comparator
 	sort.Sort(SectionSorter(sectionNames))
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("Standings (via %v):\n\n", t.source.String()))
 
-	for sec, players := range secPlayers {
-		sort.Slice(players, func(i, j int) bool {
+	sections := make([]StandingsSection, 0, len(sectionNames))
+	for _, sec := range sectionNames {
+		players := secPlayers[sec]
+		// sections may be left empty by withdrawals
+		if len(players) == 0 || !SectionMatches(sec, section) {
+			continue
+		}
+		sort.SliceStable(players, func(i, j int) bool {
 			return players[i].PlaceNumber < players[j].PlaceNumber
 		})
 
-		type row struct{ rank, player, score string }
-		var rows []row
-		priorScore := -1.0
+		var awards []*PrizeAward
+		if prizes != nil {
+			awards = awardPrizes(players, sectionPrizes(prizes, sec))
+		}
+		var rows []StandingsRow
+		ranks := internal.CompetitionRanks(playerScores(players))
 		for idx, p := range players {
 			var rank string
-			if idx != 0 && p.CurrentScoreAG == priorScore {
-				rank = ""
-			} else {
-				rank = fmt.Sprintf("%v.", p.PlaceNumber)
-				priorScore = p.CurrentScoreAG
+			if idx == 0 || ranks[idx] != ranks[idx-1] {
+				rank = fmt.Sprintf("%v.", ranks[idx])
 			}
-			r := row{
-				rank:   rank,
-				player: p.DisplayName,
-				score:  fmt.Sprintf("%v", internal.ScoreToString(p.CurrentScoreAG)),
+			row := StandingsRow{
+				Place: rank,
+				Name:  p.DisplayName,
+				Score: fmt.Sprintf("%v", internal.ScoreToString(p.CurrentScoreAG)),
 			}
-			rows = append(rows, r)
+			if awards != nil && awards[idx] != nil {
+				row.Prize = awards[idx].String()
+			}
+			if rc, ok := ratings[uschess.MemberID(strconv.Itoa(p.UscfID))]; ok &&
+				p.UscfID > 0 {
+
+				row.Rating = formatRatingChange(rc)
+			}
+			rows = append(rows, row)
 		}
+		sections = append(sections, StandingsSection{Name: sec, Rows: rows})
+	}
+
+	return sections
+}
+
+// ErrStandingsNotStarted is returned by CheckStandingsAvailable for an event
+// which hasn't started, i.e. whose pairings are only predicted.
+var ErrStandingsNotStarted = errors.New("event has not started; standings are not available yet")
+
+// CheckStandingsAvailable reports whether a tournament has standings worth
+// showing. Predicted pairings would otherwise produce a table of zeros
+// "prior to round 1".
+func CheckStandingsAvailable(t *Tournament) error {
+	if t.IsPredicted() {
+		return ErrStandingsNotStarted
+	}
+
+	return nil
+}
+
+// BuildStandingsUnavailableOutput explains that an event's standings aren't
+// available yet and shows its predicted pairings instead.
+func BuildStandingsUnavailableOutput(t *Tournament, section string,
+	width int) string {
+
+	return internal.WrapText("This event hasn't started; showing predicted round-1 pairings, standings aren't available yet.",
+		width) + "\n\n" + BuildPairingsOutput(t, false, section, width)
+}
+
+// StandingsSourceHeader describes where standings were sourced from.
+func StandingsSourceHeader(t *Tournament) string {
+	return fmt.Sprintf("Standings (via %v):", t.source.String())
+}
+
+// buildStandingsOutput formats standings into grouped, aligned string output.
+// A nonempty section restricts the output to matching sections. A positive
+// width bounds the length of each line, truncating long names with an
+// ellipsis.
+func BuildStandingsOutput(t *Tournament, section string, width int) string {
+	return buildStandingsOutput(t, BuildStandingsSections(t, section), width)
+}
+
+// BuildStandingsOutputWithPrizes formats standings as BuildStandingsOutput
+// does, adding a column noting the prize each player is in line for given
+// the event's place and class prizes. Players tied on score split the
+// prizes for the places they occupy. It is intended for final standings.
+func BuildStandingsOutputWithPrizes(t *Tournament, prizes []Prize,
+	section string, width int) string {
+
+	if prizes == nil {
+		prizes = []Prize{}
+	}
+
+	return buildStandingsOutput(t, buildStandingsSections(t, section, prizes,
+		nil), width)
+}
+
+// BuildStandingsOutputWithRatings formats standings as BuildStandingsOutput
+// does, adding a column with each player's rating change over the event as
+// returned by uscfutils.RatingChangesByMember. Players are matched by USCF
+// id; those US Chess has not rated are left blank. It is intended for final
+// standings of an event the club has filed with US Chess.
+func BuildStandingsOutputWithRatings(t *Tournament,
+	ratings map[uschess.MemberID]uscfutils.RatingChange, section string,
+	width int) string {
+
+	if ratings == nil {
+		ratings = map[uschess.MemberID]uscfutils.RatingChange{}
+	}
+
+	return buildStandingsOutput(t, buildStandingsSections(t, section, nil,
+		ratings), width)
+}
+
+// formatRatingChange formats a player's rating change for standings, e.g.
+// "1650->1702 (+52)", or "unr->1234" for a newly rated player.
+func formatRatingChange(rc uscfutils.RatingChange) string {
+	if rc.PreRating <= 0 {
+		return fmt.Sprintf("unr->%d", rc.PostRating)
+	}
+
+	return fmt.Sprintf("%d->%d (%+d)", rc.PreRating, rc.PostRating, rc.Delta())
+}
+
+func buildStandingsOutput(t *Tournament, sections []StandingsSection,
+	width int) string {
+
+	multiSection := len(getPlayersBySection(t)) > 1
+	var sb strings.Builder
+
+	sb.WriteString(internal.WrapText(StandingsSourceHeader(t), width) + "\n\n")
+
+	for _, sec := range sections {
+		rows := sec.Rows
 
 		// Compute column widths
-		maxP, maxN, maxS := len("Place"), len("Name"), len("Score")
+		maxP, maxN, maxS, maxZ := len("Place"), len("Name"), len("Score"), 0
+		maxR := 0
 		for _, r := range rows {
-			if l := len(r.rank); l > maxP {
+			if l := internal.DisplayWidth(r.Place); l > maxP {
 				maxP = l
 			}
-			if l := len(r.player); l > maxN {
+			if l := internal.DisplayWidth(r.Name); l > maxN {
 				maxN = l
 			}
-			if l := len(r.score); l > maxS {
+			if l := internal.DisplayWidth(r.Score); l > maxS {
 				maxS = l
 			}
+			if l := internal.DisplayWidth(r.Prize); l > maxZ {
+				maxZ = max(l, len("Prize"))
+			}
+			if l := internal.DisplayWidth(r.Rating); l > maxR {
+				maxR = max(l, len("Rating"))
+			}
 		}
+		// shrink the name column first to fit within width
+		columns := []int{maxP, maxN, maxS}
+		if maxZ > 0 {
+			columns = append(columns, maxZ)
+		}
+		if maxR > 0 {
+			columns = append(columns, maxR)
+		}
+		widths := internal.FitColumns(columns, 2, width, 1)
+		maxP, maxN, maxS = widths[0], widths[1], widths[2]
 
 		// Write section header and table
-		if len(sectionNames) > 1 {
-			if sec == "" {
-				sec = "UNNAMED"
-			}
-			sb.WriteString(fmt.Sprintf("%s Section (%v players)\n", sec, len(rows)))
+		if multiSection {
+			sb.WriteString(internal.TruncateToWidth(fmt.Sprintf(
+				"%s (%v players)", internal.DisplaySectionName(sec.Name),
+				len(rows)), width) + "\n")
 		}
-		sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s\n", maxP, "Place", maxN,
-			"Name", maxS, "Score"))
+		header := fmt.Sprintf("%-*s  %-*s  %-*s", maxP, "Place", maxN, "Name",
+			maxS, "Score")
+		if maxZ > 0 && maxR > 0 {
+			header += fmt.Sprintf("  %-*s", maxZ, "Prize")
+		} else if maxZ > 0 {
+			header += "  Prize"
+		}
+		if maxR > 0 {
+			header += "  Rating"
+		}
+		writeTableLine(&sb, width, header)
 		for _, r := range rows {
-			sb.WriteString(fmt.Sprintf("%-*s  %-*s  %-*s\n", maxP, r.rank,
-				maxN, r.player, maxS, r.score))
+			line := fmt.Sprintf("%-*s  %-*s  %-*s", maxP, r.Place, maxN,
+				internal.TruncateToWidth(r.Name, maxN), maxS, r.Score)
+			if maxZ > 0 && maxR > 0 {
+				line += fmt.Sprintf("  %-*s", maxZ, r.Prize)
+			} else if r.Prize != "" {
+				line += "  " + r.Prize
+			}
+			if r.Rating != "" {
+				line += "  " + r.Rating
+			}
+			writeTableLine(&sb, width, line)
 		}
 		sb.WriteString("\n")
 	}
 
+	sb.WriteString(DataAgeFooter(t))
+
 	return sb.String()
 }
 
+// sectionPrizes returns the prizes awarded in a section: those advertised
+// for it and those advertised for every section.
+func sectionPrizes(prizes []Prize, section string) []Prize {
+	var matched []Prize
+	for _, prize := range prizes {
+		if prize.Section == "" || strings.EqualFold(prize.Section, section) {
+			matched = append(matched, prize)
+		}
+	}
+
+	return matched
+}
+
+// writeTableLine writes a line of an aligned table, truncating it to width
+// when its columns could not be narrowed enough to fit.
+func writeTableLine(sb *strings.Builder, width int, line string) {
+	sb.WriteString(internal.TruncateToWidth(line, width) + "\n")
+}
+
+// BuildStandingsCSV formats standings as CSV with one row per player, grouped
+// by section and ordered by place, for import into spreadsheets or prize
+// distribution tools. Each row ends with the player's tiebreaks as returned
+// by uscfutils.TiebreaksByMember, matched by USCF id; they are left blank for
+// players tiebreaks lists no values for, e.g. before the event is rated.
+func BuildStandingsCSV(t *Tournament,
+	tiebreaks map[uschess.MemberID]uscfutils.Tiebreaks) (string, error) {
+	secPlayers := getPlayersBySection(t)
+	var sectionNames []string
+	for sec := range secPlayers {
+		sectionNames = append(sectionNames, sec)
+	}
+	sort.Sort(SectionSorter(sectionNames))
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	err := w.Write([]string{"Section", "Place", "Name", "USCFID", "Rating",
+		"Score", "Median", "Solkoff", "Cumulative"})
+	if err != nil {
+		return "", err
+	}
+	for _, sec := range sectionNames {
+		players := secPlayers[sec]
+		sort.SliceStable(players, func(i, j int) bool {
+			return players[i].PlaceNumber < players[j].PlaceNumber
+		})
+		ranks := internal.CompetitionRanks(playerScores(players))
+		for idx, p := range players {
+			record := []string{
+				sec,
+				strconv.Itoa(ranks[idx]),
+				p.DisplayName,
+				uscfIdToString(p.UscfID),
+				strconv.Itoa(p.PrimaryRating),
+				strconv.FormatFloat(p.CurrentScoreAG, 'f', -1, 64),
+				"", "", "",
+			}
+			tb, ok := tiebreaks[uschess.MemberID(strconv.Itoa(p.UscfID))]
+			if ok && p.UscfID > 0 {
+				for i, val := range []float64{tb.Median, tb.Solkoff,
+					tb.Cumulative} {
+
+					record[len(record)-3+i] = strconv.FormatFloat(val, 'f', -1,
+						64)
+				}
+			}
+			if err := w.Write(record); err != nil {
+				return "", err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// getPlayersBySection groups players by section. Each section's players are
+// ordered by USCF id and then name regardless of the order the source listed
+// them in, and a player that appears more than once is only included once.
 func getPlayersBySection(t *Tournament) map[string][]*Player {
 	secPlayers := make(map[string][]*Player)
+	seen := make(map[string]bool)
 	for idx, _ := range t.Players {
 		player := &t.Players[idx]
+		key := playerKey(player)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
 		secPlayers[player.SectionName] = append(secPlayers[player.SectionName],
 			player)
 	}
+	for _, players := range secPlayers {
+		sort.Slice(players, func(i, j int) bool {
+			if players[i].UscfID != players[j].UscfID {
+				return players[i].UscfID < players[j].UscfID
+			}
+			return players[i].DisplayName < players[j].DisplayName
+		})
+	}
 
 	return secPlayers
 }
+
+// playerKey identifies a player by USCF id, falling back to display name for
+// players without one.
+func playerKey(p *Player) string {
+	if p.UscfID != 0 {
+		return fmt.Sprintf("%v:%v", p.SectionName, p.UscfID)
+	}
+	return fmt.Sprintf("%v:%v", p.SectionName, p.DisplayName)
+}
@@ -0,0 +1,441 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestAssignPlaceNumbersEmptySection(t *testing.T) {
+	alice := &Player{DisplayName: "Alice Smith", CurrentScoreAG: 2}
+	secPlayers := map[string][]*Player{
+		"Open":  {alice},
+		"U1800": {},
+	}
+
+	maxScore := assignPlaceNumbers(secPlayers)
+	if maxScore != 2 {
+		t.Errorf("maxScore = %v; want 2", maxScore)
+	}
+	if alice.PlaceNumber != 1 {
+		t.Errorf("PlaceNumber = %v; want 1", alice.PlaceNumber)
+	}
+}
+
+func TestBuildStandingsOutputOddSections(t *testing.T) {
+	// no players at all
+	output := BuildStandingsOutput(&Tournament{}, "", 0)
+	if !strings.HasPrefix(output, "Standings") {
+		t.Errorf("unexpected output for empty tournament:\n%s", output)
+	}
+
+	// a single player section alongside a regular one
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", SectionName: "Open", CurrentScoreAG: 1},
+			{DisplayName: "Bob Jones", SectionName: "Open", CurrentScoreAG: 0},
+			{DisplayName: "Carol White", SectionName: "U1800", CurrentScoreAG: 1},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+	output = BuildStandingsOutput(tourney, "", 0)
+	for _, want := range []string{
+		"Open Section (2 players)",
+		"U1800 Section (1 players)",
+		"Carol White",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestFixupStandingsPlaceNumbersPersist(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Bob Jones", PrimaryRating: 1500},
+			{DisplayName: "Alice Smith", PrimaryRating: 1800},
+		},
+		CurrentPairings: []Pairing{{
+			Section:     "Open",
+			BoardNumber: 1,
+			WhitePlayer: Player{DisplayName: "Alice Smith", PrimaryRating: 1800,
+				CurrentScore: 1, CurrentScoreAG: 2},
+			BlackPlayer: Player{DisplayName: "Bob Jones", PrimaryRating: 1500,
+				CurrentScore: 0, CurrentScoreAG: 0},
+		}},
+	}
+	fixupStandings(tourney)
+
+	for _, p := range tourney.Players {
+		want := 2
+		if p.DisplayName == "Alice Smith" {
+			want = 1
+		}
+		if p.PlaceNumber != want {
+			t.Errorf("%v PlaceNumber = %v; want %v", p.DisplayName,
+				p.PlaceNumber, want)
+		}
+	}
+
+	output := BuildStandingsOutput(tourney, "", 0)
+	alice := strings.Index(output, "1.     Alice Smith")
+	bob := strings.Index(output, "2.     Bob Jones")
+	if alice == -1 || bob == -1 || bob < alice {
+		t.Errorf("unexpected standings output:\n%s", output)
+	}
+	if round := tourney.CurrentPairings[0].RoundNumber; round != 3 {
+		t.Errorf("RoundNumber = %v; want 3", round)
+	}
+}
+
+func TestFixupStandingsNoPlayers(t *testing.T) {
+	tourney := &Tournament{
+		CurrentPairings: []Pairing{{
+			Section:     "Open",
+			BoardNumber: 1,
+			WhitePlayer: Player{DisplayName: "Alice Smith", CurrentScore: 2,
+				CurrentScoreAG: 2},
+			BlackPlayer: Player{DisplayName: "Bob Jones", CurrentScore: 1,
+				CurrentScoreAG: 1},
+		}},
+	}
+	fixupStandings(tourney)
+
+	if round := tourney.CurrentPairings[0].RoundNumber; round != 3 {
+		t.Errorf("RoundNumber = %v; want 3", round)
+	}
+}
+
+func TestStandingsGroupedByPlayerSections(t *testing.T) {
+	page := `<table id="members">
+<thead><tr><th>#</th><th>Name</th><th>Rating</th><th>USCF ID</th><th>Section</th></tr></thead>
+<tbody>
+<tr><td>1</td><td>ALICE SMITH</td><td>1900</td><td>10</td><td>Open</td></tr>
+<tr><td>2</td><td>BOB JONES</td><td>1500</td><td>20</td><td>U1800</td></tr>
+<tr><td>3</td><td>CAROL WHITE</td><td>1850</td><td>30</td><td>Open</td></tr>
+</tbody></table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("NewDocumentFromReader: %v", err)
+	}
+	var tourney Tournament
+	if err := parsePlayers(doc, &tourney); err != nil {
+		t.Fatalf("parsePlayers: %v", err)
+	}
+	tourney.Players[2].CurrentScore = 1
+	fixupStandingsFromPlayers(&tourney)
+
+	sections := BuildStandingsSections(&tourney, "")
+	if len(sections) != 2 || sections[0].Name != "Open" ||
+		sections[1].Name != "U1800" || len(sections[0].Rows) != 2 ||
+		sections[0].Rows[0].Name != "Carol White" ||
+		sections[1].Rows[0].Name != "Bob Jones" {
+		t.Fatalf("BuildStandingsSections() = %+v", sections)
+	}
+}
+
+func TestBuildStandingsOutputScoreFormat(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", SectionName: "Open", CurrentScoreAG: 2.5},
+			{DisplayName: "Bob Jones", SectionName: "Open", CurrentScoreAG: 1},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+
+	internal.SetenvForTest(t, internal.ScoreFormatEnv, "fraction")
+	rows := BuildStandingsSections(tourney, "")[0].Rows
+	if rows[0].Score != "2½" || rows[1].Score != "1" {
+		t.Errorf("fraction scores = %q, %q; want \"2½\", \"1\"", rows[0].Score,
+			rows[1].Score)
+	}
+	if output := BuildStandingsOutput(tourney, "", 0); !strings.Contains(
+		output, "2½") {
+
+		t.Errorf("standings output missing fraction score:\n%s", output)
+	}
+
+	internal.SetenvForTest(t, internal.ScoreFormatEnv, "decimal")
+	rows = BuildStandingsSections(tourney, "")[0].Rows
+	if rows[0].Score != "2.5" || rows[1].Score != "1.0" {
+		t.Errorf("decimal scores = %q, %q; want \"2.5\", \"1.0\"", rows[0].Score,
+			rows[1].Score)
+	}
+}
+
+func TestBuildStandingsCSV(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Jones, Bob", UscfID: 222, SectionName: "Open", CurrentScoreAG: 1.5, PrimaryRating: 1700},
+			{DisplayName: "Alice Smith", UscfID: 111, SectionName: "Open", CurrentScoreAG: 2, PrimaryRating: 1800},
+			{DisplayName: "Carol White", SectionName: "U1800", CurrentScoreAG: 0.5},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+
+	tiebreaks := map[uschess.MemberID]uscfutils.Tiebreaks{
+		"111": {Median: 1.5, Solkoff: 3, Cumulative: 3},
+	}
+	output, err := BuildStandingsCSV(tourney, tiebreaks)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := "Section,Place,Name,USCFID,Rating,Score,Median,Solkoff,Cumulative\n" +
+		"Open,1,Alice Smith,111,1800,2,1.5,3,3\n" +
+		"Open,2,\"Jones, Bob\",222,1700,1.5,,,\n" +
+		"U1800,1,Carol White,,0,0.5,,,\n"
+	if output != want {
+		t.Errorf("BuildStandingsCSV() =\n%v\nwant\n%v", output, want)
+	}
+}
+
+func TestBuildStandingsOutputWithRatings(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", UscfID: 111, SectionName: "Open", CurrentScoreAG: 2},
+			{DisplayName: "Bob Jones", UscfID: 222, SectionName: "Open", CurrentScoreAG: 1.5},
+			{DisplayName: "Carol White", UscfID: 333, SectionName: "Open", CurrentScoreAG: 1},
+			{DisplayName: "Dan Brown", SectionName: "Open", CurrentScoreAG: 0},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+	standing := func(id uschess.MemberID, pre,
+		post int32) uschess.Standings {
+
+		return uschess.Standings{MemberId: id,
+			Ratings: []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+				PreRating: pre, PostRating: post}}}
+	}
+	crossTable := &uschess.Tournament{
+		RatedEventDetail: uschess.RatedEventDetail{
+			Sections: []uschess.MinimalSection{{Name: "Open", Number: 1}},
+		},
+		SectionStandings: []uschess.StandingsOneSection{{
+			standing("111", 1800, 1812), standing("222", 0, 1450),
+			standing("333", 1600, 0),
+		}},
+	}
+
+	output := BuildStandingsOutputWithRatings(tourney,
+		uscfutils.RatingChangesByMember(crossTable), "", 0)
+	for _, want := range []string{
+		"Place  Name         Score  Rating\n",
+		"1.     Alice Smith  2      1800->1812 (+12)\n",
+		"2.     Bob Jones    1½     unr->1450\n",
+		// not yet rated, or without a USCF id
+		"3.     Carol White  1    \n",
+		"4.     Dan Brown    0    \n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestBuildStandingsOutputSectionFilter(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", SectionName: "Open", CurrentScoreAG: 1},
+			{DisplayName: "Bob Jones", SectionName: "U1800", CurrentScoreAG: 1},
+			{DisplayName: "Carol White", SectionName: "U1200", CurrentScoreAG: 0},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+
+	tests := []struct {
+		section string
+		want    []string
+		notWant []string
+	}{
+		{"", []string{"Alice Smith", "Bob Jones", "Carol White"}, nil},
+		{"u1800", []string{"Bob Jones"}, []string{"Alice Smith", "Carol White"}},
+		{"U1", []string{"Bob Jones", "Carol White"}, []string{"Alice Smith"}},
+		{"Reserve", nil, []string{"Alice Smith", "Bob Jones", "Carol White"}},
+	}
+	for _, tc := range tests {
+		output := BuildStandingsOutput(tourney, tc.section, 0)
+		for _, want := range tc.want {
+			if !strings.Contains(output, want) {
+				t.Errorf("section %q: output missing %q:\n%s", tc.section, want,
+					output)
+			}
+		}
+		for _, notWant := range tc.notWant {
+			if strings.Contains(output, notWant) {
+				t.Errorf("section %q: output unexpectedly contains %q:\n%s",
+					tc.section, notWant, output)
+			}
+		}
+	}
+}
+
+func TestBuildTablesOutputWidth(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Bartholomew Montgomery-Fitzgerald",
+				SectionName: "Championship", CurrentScoreAG: 2.5},
+			{DisplayName: "Alice Smith", SectionName: "Championship",
+				CurrentScoreAG: 1},
+			{DisplayName: "Maximiliana Worthington-Smythe",
+				SectionName: "Under 1800", CurrentScoreAG: 1.5},
+		},
+		CurrentPairings: []Pairing{
+			{
+				RoundNumber: 3,
+				BoardNumber: 1,
+				Section:     "Championship",
+				WhitePlayer: Player{DisplayName: "Bartholomew Montgomery-Fitzgerald",
+					UscfID: 12345678, PrimaryRating: 2150, CurrentScore: 2.5},
+				BlackPlayer: Player{DisplayName: "Alice Smith", UscfID: 87654321,
+					PrimaryRating: 1900, CurrentScore: 1},
+			},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+
+	for _, width := range []int{20, 40, 60} {
+		outputs := map[string]string{
+			"standings": BuildStandingsOutput(tourney, "", width),
+			"pairings":  BuildPairingsOutput(tourney, false, "", width),
+			"verbose":   BuildPairingsOutput(tourney, true, "", width),
+		}
+		for name, output := range outputs {
+			for _, line := range strings.Split(output, "\n") {
+				if l := utf8.RuneCountInString(line); l > width {
+					t.Errorf("%v line exceeds width %v (%v): %q", name, width,
+						l, line)
+				}
+			}
+		}
+	}
+
+	// the name column shrinks before anything else
+	output := BuildStandingsOutput(tourney, "Championship", 30)
+	if !strings.Contains(output, "Bartholomew Mon…") ||
+		!strings.Contains(output, "2½") {
+		t.Errorf("unexpected narrow standings:\n%v", output)
+	}
+
+	// no width leaves names intact
+	output = BuildStandingsOutput(tourney, "", 0)
+	if !strings.Contains(output, "Bartholomew Montgomery-Fitzgerald") {
+		t.Errorf("unexpected truncation:\n%v", output)
+	}
+}
+
+func TestDataAgeFooter(t *testing.T) {
+	for _, tc := range []struct {
+		age  time.Duration
+		want string
+	}{
+		{0, ""},
+		{30 * time.Second, ""},
+		{time.Minute, "(data as of 1 minute ago)\n"},
+		{2*time.Minute + 40*time.Second, "(data as of 2 minutes ago)\n"},
+		{3 * time.Hour, "(data as of 3 hours ago)\n"},
+	} {
+		if got := DataAgeFooter(&Tournament{dataAge: tc.age}); got != tc.want {
+			t.Errorf("DataAgeFooter(%v) = %q, want %q", tc.age, got, tc.want)
+		}
+	}
+
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", SectionName: "Open", CurrentScoreAG: 1},
+		},
+		dataAge: 5 * time.Minute,
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+	if output := BuildStandingsOutput(tourney, "", 0); !strings.HasSuffix(output,
+		"(data as of 5 minutes ago)\n") {
+		t.Errorf("standings missing data age:\n%s", output)
+	}
+	if output := BuildPairingsOutput(tourney, false, "", 0); !strings.HasSuffix(output,
+		"(data as of 5 minutes ago)\n") {
+		t.Errorf("pairings missing data age:\n%s", output)
+	}
+}
+
+func TestCheckStandingsAvailable(t *testing.T) {
+	predicted := &Tournament{isPredicted: true, CurrentPairings: []Pairing{
+		{WhitePlayer: Player{DisplayName: "Alice Smith"},
+			BlackPlayer: Player{DisplayName: "Bob Jones"}, Section: "Open",
+			RoundNumber: 1, BoardNumber: 1},
+	}}
+	if err := CheckStandingsAvailable(predicted); !errors.Is(err,
+		ErrStandingsNotStarted) {
+
+		t.Errorf("CheckStandingsAvailable(predicted) = %v; want %v", err,
+			ErrStandingsNotStarted)
+	}
+	output := BuildStandingsUnavailableOutput(predicted, "", 0)
+	for _, want := range []string{"standings aren't available yet",
+		"predicted round 1 pairings", "Alice Smith"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	if err := CheckStandingsAvailable(&Tournament{source: SourceAPI}); err != nil {
+		t.Errorf("CheckStandingsAvailable(posted) = %v", err)
+	}
+}
+
+func TestStandingsSharedPlaces(t *testing.T) {
+	// Bob, Carol, and Dan tie for 2nd; Eve is then 5th
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice", UscfID: 1, SectionName: "Open", CurrentScoreAG: 3},
+			{DisplayName: "Bob", UscfID: 2, SectionName: "Open", CurrentScoreAG: 2},
+			{DisplayName: "Carol", UscfID: 3, SectionName: "Open", CurrentScoreAG: 2},
+			{DisplayName: "Dan", UscfID: 4, SectionName: "Open", CurrentScoreAG: 2},
+			{DisplayName: "Eve", UscfID: 5, SectionName: "Open", CurrentScoreAG: 1},
+		},
+	}
+	assignPlaceNumbers(getPlayersBySection(tourney))
+
+	for _, p := range tourney.Players {
+		want := map[string]int{"Alice": 1, "Bob": 2, "Carol": 2, "Dan": 2,
+			"Eve": 5}[p.DisplayName]
+		if p.PlaceNumber != want {
+			t.Errorf("%v PlaceNumber = %v; want %v", p.DisplayName,
+				p.PlaceNumber, want)
+		}
+	}
+
+	sections := BuildStandingsSections(tourney, "")
+	var places []string
+	for _, r := range sections[0].Rows {
+		places = append(places, r.Place)
+	}
+	if want := []string{"1.", "2.", "", "", "5."}; !reflect.DeepEqual(places,
+		want) {
+
+		t.Errorf("standings places = %q; want %q", places, want)
+	}
+
+	output, err := BuildStandingsCSV(tourney, nil)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	for _, want := range []string{"Open,2,Bob,", "Open,2,Carol,",
+		"Open,2,Dan,", "Open,5,Eve,"} {
+
+		if !strings.Contains(output, want) {
+			t.Errorf("BuildStandingsCSV() missing %q:\n%v", want, output)
+		}
+	}
+}
@@ -5,14 +5,19 @@
 package bcc
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"log"
 	"math"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
@@ -26,6 +31,15 @@ type Tournament struct {
 
 	isPredicted bool
 	source      Source
+	// baseSource is where a merged tournament's players came from before
+	// any of their fields were patched
+	baseSource Source
+	// dataAge is how long ago the oldest of the website pages the tournament
+	// was built from was fetched, when it was served from the cache
+	dataAge time.Duration
+	// smallSections are the sections predicted pairings did not pair on
+	// their own
+	smallSections []SmallSection
 }
 
 // Player represents a participant in the tournament.
@@ -41,6 +55,7 @@ type Player struct {
 	FideID               int     `json:"fideId"`
 	FideCountry          string  `json:"fideCountry"`
 	PrimaryRating        int     `json:"primaryRating"`
+	RatingReported       bool    `json:"ratingReported,omitempty"`
 	SecondaryRating      int     `json:"secondaryRating"`
 	LiveRating           int     `json:"liveRating"`
 	LiveRatingProvo      int     `json:"liveRatingProvo"`
@@ -56,7 +71,7 @@ type Player struct {
 	PlaceNumber          int     `json:"placeNumber"`
 	SectionName          string  `json:"sectionName"`
 
-	emptyResult bool
+	fieldSources map[string]Source
 }
 
 // Pairing represents a single board pairing in the tournament.
@@ -73,22 +88,56 @@ type Pairing struct {
 	WhiteResult  *string  `json:"whiteResult"`
 	BlackResult  *string  `json:"blackResult"`
 	GameLink     string   `json:"gameLink"`
+	// WhiteOutcome and BlackOutcome type each side's result as parsed from
+	// the website's pairings page.
+	WhiteOutcome GameResult `json:"-"`
+	BlackOutcome GameResult `json:"-"`
 }
 
-func GetTournament(eventId int64) (*Tournament, error) {
+// errEmptyTournament indicates the tournament API responded successfully but
+// without any players, which happens for events it has not yet been
+// populated for. It is a soft failure; the website may still have the data.
+var errEmptyTournament = errors.New("bcc tournament API returned an empty response")
+
+// GetTournament fetches the players and current pairings for an event from
+// both the API and the website, preferring the API and patching any gaps in
+// its response with data from the website. When both sources fail, each
+// whose failure may be transient is retried once after a brief delay so that
+// a single transient failure does not drop the result.
+func GetTournament(ctx context.Context, eventId int64) (*Tournament, error) {
 	var wg sync.WaitGroup
 	var tViaApi, tViaWeb *Tournament
 	var apiErr, webErr error
-	wg.Add(2)
-	go func() {
-		defer wg.Done()
-		tViaApi, apiErr = getTournamentViaApi(eventId)
-	}()
-	go func() {
-		defer wg.Done()
-		tViaWeb, webErr = getTournamentViaWeb(eventId)
-	}()
-	wg.Wait()
+	fetch := func(viaApi, viaWeb bool) {
+		if viaApi {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tViaApi, apiErr = getTournamentViaApi(ctx, eventId)
+			}()
+		}
+		if viaWeb {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tViaWeb, webErr = getTournamentViaWeb(ctx, eventId)
+			}()
+		}
+		wg.Wait()
+	}
+	fetch(true, true)
+
+	if apiErr != nil && webErr != nil &&
+		(retryableSourceError(apiErr) || retryableSourceError(webErr)) {
+
+		log.Printf("bcc: tournament %v: retrying after api err: %v; web err: %v",
+			eventId, apiErr, webErr)
+		select {
+		case <-ctx.Done():
+		case <-time.After(fetchRetryBaseDelay):
+			fetch(retryableSourceError(apiErr), retryableSourceError(webErr))
+		}
+	}
 
 	if apiErr != nil {
 		if webErr != nil {
@@ -100,34 +149,35 @@ func GetTournament(eventId int64) (*Tournament, error) {
 		// web errored, use the api response
 		return tViaApi, nil
 	} // else both api and web were successful, prefer the api response
-	// but there are situations where it returns stale data and we should
-	// patch it from data from the web response.
-	if len(tViaApi.CurrentPairings) > 0 &&
-		len(tViaWeb.CurrentPairings) > 0 &&
-		tViaApi.CurrentPairings[0].RoundNumber <
-			tViaWeb.CurrentPairings[0].RoundNumber {
+	// but there are situations where it returns stale or incomplete data and
+	// we should patch it from data from the web response.
 
-		// api is returning stale pairing data, so prefer the web response
-		tViaApi.CurrentPairings = tViaWeb.CurrentPairings
-		tViaApi.source = SourceBoth
-	}
+	return mergeTournaments(tViaApi, tViaWeb), nil
+}
 
-	return tViaApi, nil
+// retryableSourceError reports whether a tournament source's failure may be
+// transient. An empty API response or a missing event is not.
+func retryableSourceError(err error) bool {
+	return !errors.Is(err, errEmptyTournament) &&
+		!errors.Is(err, internal.ErrNotFound) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
 }
 
 // getTournamentViaApi fetches the tournament data (players and pairings) for a
 // given eventId from the JSON API.
-func getTournamentViaApi(eventId int64) (*Tournament, error) {
-	url := fmt.Sprintf("https://beta.boylstonchess.org/api/event/%d/tournament",
-		eventId)
-	req, err := http.NewRequest("GET", url, nil)
+func getTournamentViaApi(ctx context.Context,
+	eventId int64) (*Tournament, error) {
+
+	url := tournamentURL(eventId)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return &Tournament{},
 			fmt.Errorf("unable to fetch bcc tournament (new): %w", err)
 	}
 
 	req.Header.Set("User-Agent", internal.UserAgent)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := internal.HTTPClient().Do(req)
 	if err != nil {
 		return &Tournament{},
 			fmt.Errorf("unable to fetch bcc tournament (do): %w", err)
@@ -135,13 +185,12 @@ func getTournamentViaApi(eventId int64) (*Tournament, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		detail, err := GetEventDetail(eventId)
+		detail, err := GetEventDetail(ctx, eventId)
 		if err == nil {
-			detail.Entries = correctRound1PairingEntries(detail.Entries)
-			return eventDetailToTournament(&detail), nil
+			return predictTournament(ctx, &detail), nil
 		} else {
-			err = fmt.Errorf("unable to fetch %v: http status: %v", url,
-				resp.StatusCode)
+			err = fmt.Errorf("unable to fetch %v: http status: %w", url,
+				internal.HTTPStatusError(resp.StatusCode))
 		}
 
 		return &Tournament{}, err
@@ -156,36 +205,39 @@ func getTournamentViaApi(eventId int64) (*Tournament, error) {
 	}
 
 	if len(tourney.Players) == 0 {
-		err = fmt.Errorf("bcc tournament API returned an empty response")
-		return &Tournament{}, err
+		return &Tournament{}, errEmptyTournament
 	}
+	fixupStandingsFromPlayers(tourney)
 
 	return tourney, nil
 }
 
 // getTournamentViaWeb fetches the tournament data by scraping the public website
 // pages: entries and pairings for the given eventId.
-func getTournamentViaWeb(eventId int64) (*Tournament, error) {
+func getTournamentViaWeb(ctx context.Context,
+	eventId int64) (*Tournament, error) {
+
 	// Prepare URLs
-	entriesURL := fmt.Sprintf("https://boylstonchess.org/tournament/entries/%d", eventId)
-	pairingsURL := fmt.Sprintf("https://boylstonchess.org/files/event/%d/pairings", eventId)
+	entriesURL := entriesPageURL(eventId)
+	pairingsURL := pairingsPageURL(eventId)
 
 	// Concurrent fetch
 	var wg sync.WaitGroup
 	var entriesDoc, pairingsDoc *goquery.Document
 	var errEntries, errPairings error
+	var entriesAge, pairingsAge time.Duration
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		entriesDoc, errEntries = fetchDoc(entriesURL)
+		entriesDoc, entriesAge, errEntries = fetchDocWithAge(ctx, entriesURL)
 	}()
 	go func() {
 		defer wg.Done()
-		pairingsDoc, errPairings = fetchDoc(pairingsURL)
+		pairingsDoc, pairingsAge, errPairings = fetchDocWithAge(ctx, pairingsURL)
 	}()
 	wg.Wait()
 
-	tourney := &Tournament{source: SourceWebsite}
+	tourney := &Tournament{source: SourceWebsite, dataAge: max(entriesAge, pairingsAge)}
 
 	if errEntries != nil {
 		return nil, fmt.Errorf("unable to fetch entries page: %w", errEntries)
@@ -207,27 +259,36 @@ func getTournamentViaWeb(eventId int64) (*Tournament, error) {
 }
 
 // parsePlayers extracts Player entries from the entries table in the document.
+// When the table has a Section column each player's section is taken from it
+// so that players may be grouped by section without pairings.
 func parsePlayers(doc *goquery.Document, t *Tournament) error {
 	t.Players = nil
+	sectionCol := -1
+	doc.Find("table#members thead th").Each(func(idx int, th *goquery.Selection) {
+		if strings.EqualFold(strings.TrimSpace(th.Text()), "section") {
+			sectionCol = idx
+		}
+	})
 	doc.Find("table#members tbody tr").Each(func(_ int, s *goquery.Selection) {
 		cells := s.Find("td")
 		if cells.Length() < 4 {
 			return
 		}
 		num, _ := strconv.Atoi(strings.TrimSpace(cells.Eq(0).Text()))
-		name := strings.TrimSpace(cells.Eq(1).Text())
+		name := html.UnescapeString(strings.TrimSpace(cells.Eq(1).Text()))
 		ratingStr := strings.TrimSpace(cells.Eq(2).Text())
-		rating := 0
-		if r, err := strconv.Atoi(ratingStr); err == nil {
-			rating = r
-		}
+		rating := internal.ParseRating(ratingStr)
 		uscfID, _ := strconv.Atoi(strings.TrimSpace(cells.Eq(3).Text()))
 
 		p := Player{
-			DisplayName:   internal.NormalizeName(name),
-			PairingNumber: num,
-			PrimaryRating: rating,
-			UscfID:        uscfID,
+			DisplayName:    internal.NormalizeName(name),
+			PairingNumber:  num,
+			PrimaryRating:  rating.Value,
+			RatingReported: rating.Reported,
+			UscfID:         uscfID,
+		}
+		if sectionCol >= 0 && sectionCol < cells.Length() {
+			p.SectionName = strings.TrimSpace(cells.Eq(sectionCol).Text())
 		}
 		parts := strings.Fields(p.DisplayName)
 		if len(parts) > 0 {
@@ -323,46 +384,108 @@ func fixupStandings(t *Tournament) {
 	haveAnyEmptyResult := false
 
 	for _, p := range t.CurrentPairings {
-		if p.WhitePlayer.emptyResult ||
-			(!p.IsByePairing && p.BlackPlayer.emptyResult) {
+		if p.WhiteOutcome == ResultPending || p.BlackOutcome == ResultPending {
 			haveAnyEmptyResult = true
 		}
 	}
 
+	// while the round is in progress report standings as of its start so
+	// that finished boards don't get ahead of those still playing
 	if haveAnyEmptyResult {
-		for idx, p := range t.CurrentPairings {
-			if !p.IsByePairing && !p.BlackPlayer.emptyResult {
-				p.BlackPlayer.CurrentScoreAG = p.BlackPlayer.CurrentScore
-			}
-			if !p.WhitePlayer.emptyResult {
+		for idx := range t.CurrentPairings {
+			p := &t.CurrentPairings[idx]
+			if p.WhiteOutcome != ResultPending {
 				p.WhitePlayer.CurrentScoreAG = p.WhitePlayer.CurrentScore
 			}
-			t.CurrentPairings[idx] = p
+			if p.BlackOutcome != ResultPending {
+				p.BlackPlayer.CurrentScoreAG = p.BlackPlayer.CurrentScore
+			}
 		}
 	}
 
 	updatePlayersFromPairings(t)
 
-	// compute placeorder
+	// getPlayersBySection returns pointers into t.Players so the computed
+	// place numbers persist into later standings output
+	maxScore := assignPlaceNumbers(getPlayersBySection(t))
+	if len(t.Players) == 0 {
+		// no entries to place (e.g. an empty entries page); fall back to the
+		// scores reported alongside the pairings
+		maxScore = maxPairingScore(t.CurrentPairings)
+	}
+	// best guess at round number
+	roundNumber := int(math.Round(maxScore) + 1)
+	for idx, _ := range t.CurrentPairings {
+		t.CurrentPairings[idx].RoundNumber = roundNumber
+
+	}
+}
+
+// fixupStandingsFromPlayers places the players of a tournament without
+// current pairings (e.g. between rounds) by their own scores, grouped by
+// section. The api may then report only each player's CurrentScore,
+// leaving their after-game score and place unset.
+func fixupStandingsFromPlayers(t *Tournament) {
+	if len(t.CurrentPairings) > 0 {
+		return
+	}
+	placed := false
+	for idx := range t.Players {
+		p := &t.Players[idx]
+		p.CurrentScoreAG = max(p.CurrentScoreAG, p.CurrentScore)
+		placed = placed || p.PlaceNumber > 0
+	}
+	if !placed {
+		assignPlaceNumbers(getPlayersBySection(t))
+	}
+}
+
+// assignPlaceNumbers computes each player's PlaceNumber within their section,
+// with players tied on score sharing a place, and returns the highest score
+// across all sections. Empty sections are skipped.
+func assignPlaceNumbers(secPlayers map[string][]*Player) float64 {
 	maxScore := float64(0.0)
-	secPlayers := getPlayersBySection(t)
 	for _, players := range secPlayers {
-		sort.Slice(players, func(i, j int) bool {
+		if len(players) == 0 {
+			continue
+		}
+		sort.SliceStable(players, func(i, j int) bool {
 			return players[i].CurrentScoreAG > players[j].CurrentScoreAG
 		})
 		if players[0].CurrentScoreAG > maxScore {
 			maxScore = players[0].CurrentScoreAG
 		}
-		for idx, p := range players {
-			p.PlaceNumber = idx + 1
+		for idx, rank := range internal.CompetitionRanks(playerScores(players)) {
+			players[idx].PlaceNumber = rank
 		}
 	}
-	// best guess at round number
-	roundNumber := int(math.Round(maxScore) + 1)
-	for idx, _ := range t.CurrentPairings {
-		t.CurrentPairings[idx].RoundNumber = roundNumber
 
+	return maxScore
+}
+
+// playerScores returns the after-game scores of players in order.
+func playerScores(players []*Player) []float64 {
+	scores := make([]float64, len(players))
+	for idx, p := range players {
+		scores[idx] = p.CurrentScoreAG
+	}
+
+	return scores
+}
+
+// maxPairingScore returns the highest score of any player in pairings
+func maxPairingScore(pairings []Pairing) float64 {
+	maxScore := float64(0.0)
+	for _, p := range pairings {
+		if p.WhitePlayer.CurrentScoreAG > maxScore {
+			maxScore = p.WhitePlayer.CurrentScoreAG
+		}
+		if !p.IsByePairing && p.BlackPlayer.CurrentScoreAG > maxScore {
+			maxScore = p.BlackPlayer.CurrentScoreAG
+		}
 	}
+
+	return maxScore
 }
 
 func updatePlayersFromPairings(t *Tournament) {
@@ -375,6 +498,9 @@ func updatePlayersFromPairings(t *Tournament) {
 					cp.PrimaryRating == pl.PrimaryRating {
 					pl.CurrentScore = cp.CurrentScore
 					pl.CurrentScoreAG = cp.CurrentScoreAG
+					if pl.SectionName == "" {
+						pl.SectionName = p.Section
+					}
 				}
 			}
 		}
@@ -423,44 +549,6 @@ func parsePairingRow(row *goquery.Selection, section string) (*Pairing, bool) {
 		tmp := blackRes
 		bResPtr = &tmp
 	}
-
-	// adjust CurrentScoreAG based on result when present, handling “½”
-	if wResPtr != nil {
-		res := strings.TrimSpace(*wResPtr)
-		var (
-			v  float64
-			ok bool
-		)
-		if strings.Contains(res, "½") {
-			v = 0.5
-			ok = true
-		} else if parsed, err := strconv.ParseFloat(res, 64); err == nil {
-			v = parsed
-			ok = true
-		}
-		if ok {
-			wp.CurrentScoreAG = wp.CurrentScore + v
-			wp.emptyResult = false
-		}
-	}
-	if bResPtr != nil {
-		res := strings.TrimSpace(*bResPtr)
-		var (
-			v  float64
-			ok bool
-		)
-		if strings.Contains(res, "½") {
-			v = 0.5
-			ok = true
-		} else if parsed, err := strconv.ParseFloat(res, 64); err == nil {
-			v = parsed
-			ok = true
-		}
-		if ok {
-			bp.CurrentScoreAG = bp.CurrentScore + v
-			bp.emptyResult = false
-		}
-	}
 	pair := Pairing{
 		Section:     section,
 		RoundNumber: 0,
@@ -472,23 +560,29 @@ func parsePairingRow(row *goquery.Selection, section string) (*Pairing, bool) {
 		BlackResult: bResPtr,
 	}
 
-	// Handle bye pairings
-	if bp.DisplayName == "BYE" && wp.DisplayName != "BYE" {
-		pair.IsByePairing = true
-		// same logic: “½”→0.5 or parse float
-		if strings.Contains(whiteRes, "½") {
-			pts := 0.5
-			pair.WhitePoints = &pts
-		} else if v, err := strconv.ParseFloat(whiteRes, 64); err == nil {
-			pair.WhitePoints = &v
+	// type each side's result once; a bye may be listed on either side of
+	// the board, and the empty side of a bye has no result
+	whiteBye := bp.DisplayName == "BYE" && wp.DisplayName != "BYE"
+	blackBye := wp.DisplayName == "BYE" && bp.DisplayName != "BYE"
+	pair.IsByePairing = whiteBye || blackBye
+	if !blackBye {
+		outcome, pts, ok := parseSideResult(whiteRes, whiteBye)
+		pair.WhiteOutcome = outcome
+		if ok {
+			pair.WhitePlayer.CurrentScoreAG = wp.CurrentScore + pts
+			if whiteBye {
+				pair.WhitePoints = &pts
+			}
 		}
-	} else if wp.DisplayName == "BYE" && bp.DisplayName != "BYE" {
-		pair.IsByePairing = true
-		if strings.Contains(blackRes, "½") {
-			pts := 0.5
-			pair.BlackPoints = &pts
-		} else if v, err := strconv.ParseFloat(blackRes, 64); err == nil {
-			pair.BlackPoints = &v
+	}
+	if !whiteBye {
+		outcome, pts, ok := parseSideResult(blackRes, blackBye)
+		pair.BlackOutcome = outcome
+		if ok {
+			pair.BlackPlayer.CurrentScoreAG = bp.CurrentScore + pts
+			if blackBye {
+				pair.BlackPoints = &pts
+			}
 		}
 	}
 
@@ -502,6 +596,7 @@ func parsePlayerRef(text string) Player {
 		return Player{DisplayName: "BYE"}
 	}
 
+	text = html.UnescapeString(text)
 	p := Player{}
 	// Pairing number and details
 	fields := strings.Fields(text)
@@ -530,17 +625,14 @@ func parsePlayerRef(text string) Player {
 			inside := text[parenStart+1 : parenEnd]
 			parts := strings.Fields(inside)
 			if len(parts) >= 1 {
-				if parts[0] != "unr." {
-					if r, err := strconv.Atoi(parts[0]); err == nil {
-						p.PrimaryRating = r
-					}
-				}
+				rating := internal.ParseRating(parts[0])
+				p.PrimaryRating = rating.Value
+				p.RatingReported = rating.Reported
 			}
 			if len(parts) >= 2 {
 				if score, err := strconv.ParseFloat(parts[1], 64); err == nil {
 					p.CurrentScore = score
 					p.CurrentScoreAG = score
-					p.emptyResult = true
 				}
 			}
 		}
@@ -551,3 +643,78 @@ func parsePlayerRef(text string) Player {
 func (t Tournament) IsPredicted() bool {
 	return t.isPredicted
 }
+
+// Source returns where the tournament's data was fetched from.
+func (t Tournament) Source() Source {
+	return t.source
+}
+
+// SourceFooter returns a note such as "(source: website (scraped))"
+// attributing the tournament's data to where it was fetched from.
+func SourceFooter(t *Tournament) string {
+	src := t.Source().String()
+	if t.Source() != SourceAPI {
+		src += " (scraped)"
+	}
+
+	return fmt.Sprintf("(source: %v)\n", src)
+}
+
+// DataAge returns how long ago the tournament's data was fetched when it was
+// served from the cache, or 0 when it was freshly fetched.
+func (t Tournament) DataAge() time.Duration {
+	return t.dataAge
+}
+
+// SmallSections returns the sections predicted pairings left out or merged
+// into another section for having too few players; see
+// PairingOptions.MinSectionSize.
+func (t Tournament) SmallSections() []SmallSection {
+	return t.smallSections
+}
+
+// BuildSmallSectionsNote returns a line per section the tournament's
+// predicted pairings left out or merged, e.g. "Section U1200 has only 1
+// player; merged into U1600 for the prediction.", or "" when there are none.
+func BuildSmallSectionsNote(t *Tournament) string {
+	var sb strings.Builder
+	for _, small := range t.SmallSections() {
+		players := "players"
+		if small.Players == 1 {
+			players = "player"
+		}
+		fmt.Fprintf(&sb, "Section %v has only %d %v; ", small.Name,
+			small.Players, players)
+		if small.MergedInto != "" {
+			fmt.Fprintf(&sb, "merged into %v for the prediction.\n",
+				small.MergedInto)
+		} else {
+			sb.WriteString("left out of the prediction.\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// staleDataThreshold is the age beyond which cached tournament data is
+// called out in the output
+const staleDataThreshold = time.Minute
+
+// DataAgeFooter returns a note such as "(data as of 2 minutes ago)" when the
+// tournament's data was served from the cache and is older than
+// staleDataThreshold, otherwise "".
+func DataAgeFooter(t *Tournament) string {
+	age := t.DataAge()
+	if age < staleDataThreshold {
+		return ""
+	}
+
+	n, unit := int(age/time.Minute), "minute"
+	if age >= time.Hour {
+		n, unit = int(age/time.Hour), "hour"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("(data as of %d %s ago)\n", n, unit)
+}
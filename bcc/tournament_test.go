@@ -5,13 +5,23 @@
 package bcc
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
 )
 
 // TestGetTournament tests fetching tournament data and verifies that the
 // list of players contains Andrew Hoy with the expected USCF ID.
 func TestGetTournament(t *testing.T) {
-	tourney, err := GetTournament(1358)
+	tourney, err := GetTournament(context.Background(), 1358)
 	if err != nil {
 		t.Fatalf("GetTournament returned error: %v", err)
 	}
@@ -30,3 +40,284 @@ func TestGetTournament(t *testing.T) {
 		t.Errorf("could not find player Andrew Hoy in tournament players")
 	}
 }
+
+func TestParsePlayersUnescapesNames(t *testing.T) {
+	// a doubly escaped name as occasionally seen on the club's pages
+	page := `<table id="members"><tbody>
+<tr><td>1</td><td>SEAN O&amp;#39;BRIEN</td><td>1650</td><td>12345678</td></tr>
+</tbody></table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("NewDocumentFromReader: %v", err)
+	}
+
+	var tourney Tournament
+	if err := parsePlayers(doc, &tourney); err != nil {
+		t.Fatalf("parsePlayers: %v", err)
+	}
+	if len(tourney.Players) != 1 ||
+		tourney.Players[0].DisplayName != "Sean O'Brien" {
+		t.Fatalf("players = %+v; want Sean O'Brien", tourney.Players)
+	}
+
+	p := parsePlayerRef("Sean O&#39;Brien (1650 2.0)")
+	if p.DisplayName != "Sean O'Brien" || p.LastName != "O'Brien" {
+		t.Fatalf("parsePlayerRef() = %q/%q; want Sean O'Brien",
+			p.DisplayName, p.LastName)
+	}
+}
+
+// TestGetTournamentEmptyApiResponse verifies that an empty 200 response from
+// the tournament API defers to the website's entries and pairings rather than
+// failing.
+func TestGetTournamentEmptyApiResponse(t *testing.T) {
+	const entriesHTML = `<html><body><table id="members"><tbody>
+<tr><td>1</td><td>Alice Smith</td><td>1800</td><td>12345678</td></tr>
+<tr><td>2</td><td>Bob Jones</td><td>1700</td><td>23456789</td></tr>
+</tbody></table></body></html>`
+	const pairingsHTML = `<html><body><div id="pairings">
+<h1>Open Pairings</h1>
+<table>
+<tr><td>Bd</td><td>Res</td><td>White</td><td>Res</td><td>Black</td></tr>
+<tr><td>1</td><td></td><td>Alice Smith (1800 0)</td><td></td><td>Bob Jones (1700 0)</td></tr>
+</table></div></body></html>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/event/42/tournament", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, `{"players":[],"currentPairings":[]}`)
+	})
+	mux.HandleFunc("/tournament/entries/42", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, entriesHTML)
+	})
+	mux.HandleFunc("/files/event/42/pairings", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, pairingsHTML)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	getWebDocClient()
+	origApi, origWeb, origClient := apiBaseURL, webBaseURL, webDocClient
+	defer func() {
+		apiBaseURL, webBaseURL, webDocClient = origApi, origWeb, origClient
+	}()
+	apiBaseURL, webBaseURL = srv.URL, srv.URL
+	webDocClient = httpcache.NewMemoryCachedHttpClient(time.Minute)
+
+	tourney, err := GetTournament(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetTournament(context.Background(), ) err = %v", err)
+	}
+	if len(tourney.Players) != 2 {
+		t.Fatalf("len(Players) = %v; want 2", len(tourney.Players))
+	}
+	if len(tourney.CurrentPairings) != 1 {
+		t.Fatalf("len(CurrentPairings) = %v; want 1",
+			len(tourney.CurrentPairings))
+	}
+	p := tourney.CurrentPairings[0]
+	if p.WhitePlayer.DisplayName != "Alice Smith" ||
+		p.BlackPlayer.DisplayName != "Bob Jones" {
+		t.Errorf("pairing = %v vs %v; want Alice Smith vs Bob Jones",
+			p.WhitePlayer.DisplayName, p.BlackPlayer.DisplayName)
+	}
+}
+
+func TestGetTournamentRetriesWebSource(t *testing.T) {
+	const entriesHTML = `<html><body><table id="members"><tbody>
+<tr><td>1</td><td>Alice Smith</td><td>1800</td><td>12345678</td></tr>
+<tr><td>2</td><td>Bob Jones</td><td>1700</td><td>23456789</td></tr>
+</tbody></table></body></html>`
+	const pairingsHTML = `<html><body><div id="pairings">
+<h1>Open Pairings</h1>
+<table>
+<tr><td>Bd</td><td>Res</td><td>White</td><td>Res</td><td>Black</td></tr>
+<tr><td>1</td><td></td><td>Alice Smith (1800 0)</td><td></td><td>Bob Jones (1700 0)</td></tr>
+</table></div></body></html>`
+
+	pairingsRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/event/42/tournament", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, `{"players":[],"currentPairings":[]}`)
+	})
+	mux.HandleFunc("/tournament/entries/42", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, entriesHTML)
+	})
+	mux.HandleFunc("/files/event/42/pairings", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		// the website is down for the whole of the first fetch's retries
+		pairingsRequests++
+		if pairingsRequests <= fetchMaxAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, pairingsHTML)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	getWebDocClient()
+	origApi, origWeb, origClient, origDelay := apiBaseURL, webBaseURL,
+		webDocClient, fetchRetryBaseDelay
+	defer func() {
+		apiBaseURL, webBaseURL, webDocClient, fetchRetryBaseDelay = origApi,
+			origWeb, origClient, origDelay
+	}()
+	apiBaseURL, webBaseURL = srv.URL, srv.URL
+	webDocClient = httpcache.NewMemoryCachedHttpClient(time.Minute)
+	fetchRetryBaseDelay = time.Millisecond
+
+	tourney, err := GetTournament(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("GetTournament() err = %v", err)
+	}
+	if len(tourney.Players) != 2 || len(tourney.CurrentPairings) != 1 {
+		t.Errorf("GetTournament() = %v players, %v pairings; want 2, 1",
+			len(tourney.Players), len(tourney.CurrentPairings))
+	}
+	if pairingsRequests != fetchMaxAttempts+1 {
+		t.Errorf("pairings requests = %v; want %v", pairingsRequests,
+			fetchMaxAttempts+1)
+	}
+}
+
+func TestGetTournamentScoresWithoutPairings(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/event/43/tournament", func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		fmt.Fprint(w, `{"players":[
+			{"displayName":"Bob Jones","uscfId":2,"sectionName":"Open","currentScore":1},
+			{"displayName":"Alice Smith","uscfId":1,"sectionName":"Open","currentScore":2},
+			{"displayName":"Carol White","uscfId":3,"sectionName":"U1800","currentScore":1.5},
+			{"displayName":"Dan Brown","uscfId":4,"sectionName":"U1800","currentScore":0.5}],
+			"currentPairings":[]}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	getWebDocClient()
+	origApi, origWeb, origClient := apiBaseURL, webBaseURL, webDocClient
+	defer func() {
+		apiBaseURL, webBaseURL, webDocClient = origApi, origWeb, origClient
+	}()
+	apiBaseURL, webBaseURL = srv.URL, srv.URL
+	webDocClient = httpcache.NewMemoryCachedHttpClient(time.Minute)
+
+	tourney, err := GetTournament(context.Background(), 43)
+	if err != nil {
+		t.Fatalf("GetTournament() err = %v", err)
+	}
+	if err := CheckStandingsAvailable(tourney); err != nil {
+		t.Fatalf("CheckStandingsAvailable() = %v", err)
+	}
+	sections := BuildStandingsSections(tourney, "")
+	if len(sections) != 2 {
+		t.Fatalf("BuildStandingsSections() = %+v; want 2 sections", sections)
+	}
+	for i, want := range [][]StandingsRow{
+		{{Place: "1.", Name: "Alice Smith", Score: "2"},
+			{Place: "2.", Name: "Bob Jones", Score: "1"}},
+		{{Place: "1.", Name: "Carol White", Score: "1½"},
+			{Place: "2.", Name: "Dan Brown", Score: "½"}},
+	} {
+		if !reflect.DeepEqual(sections[i].Rows, want) {
+			t.Errorf("%v standings = %+v; want %+v", sections[i].Name,
+				sections[i].Rows, want)
+		}
+	}
+}
+
+func TestParsePairingRowResults(t *testing.T) {
+	tests := []struct {
+		name         string
+		row          string
+		white, black GameResult
+		whiteAG      float64
+		blackAG      float64
+		bye          bool
+	}{
+		{"draw", `<td>1</td><td>½</td><td>1 Alice Smith (1800 1.0)</td><td>½</td><td>2 Bob Jones (1700 1.0)</td>`,
+			ResultDraw, ResultDraw, 1.5, 1.5, false},
+		{"decisive", `<td>2</td><td>0</td><td>3 Carol White (1600 1.0)</td><td>1</td><td>4 Dan Brown (1500 0.5)</td>`,
+			ResultLoss, ResultWin, 1, 1.5, false},
+		{"forfeit", `<td>3</td><td>+</td><td>5 Eve Green (1400 0)</td><td>-</td><td>6 Fred Black (1300 0)</td>`,
+			ResultForfeitWin, ResultForfeitLoss, 1, 0, false},
+		{"pending", `<td>4</td><td></td><td>7 Gail Gray (1200 2.0)</td><td></td><td>8 Hal Blue (1100 2.0)</td>`,
+			ResultPending, ResultPending, 2, 2, false},
+		{"white bye", `<td></td><td>½</td><td>9 Ida Rose (1000 1.0)</td><td></td><td>BYE</td>`,
+			ResultBye, ResultNone, 1.5, 0, true},
+		{"black bye", `<td></td><td></td><td>BYE</td><td>1</td><td>10 Jo Tan (900 1.0)</td>`,
+			ResultNone, ResultBye, 0, 2, true},
+	}
+	for _, tc := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(
+			"<table><tr>" + tc.row + "</tr></table>"))
+		if err != nil {
+			t.Fatalf("%v: %v", tc.name, err)
+		}
+		pair, ok := parsePairingRow(doc.Find("tr").First(), "Open")
+		if !ok {
+			t.Fatalf("%v: row skipped", tc.name)
+		}
+		if pair.WhiteOutcome != tc.white || pair.BlackOutcome != tc.black {
+			t.Errorf("%v: outcomes = %v, %v; want %v, %v", tc.name,
+				pair.WhiteOutcome, pair.BlackOutcome, tc.white, tc.black)
+		}
+		if pair.WhitePlayer.CurrentScoreAG != tc.whiteAG ||
+			pair.BlackPlayer.CurrentScoreAG != tc.blackAG {
+			t.Errorf("%v: scores = %v, %v; want %v, %v", tc.name,
+				pair.WhitePlayer.CurrentScoreAG,
+				pair.BlackPlayer.CurrentScoreAG, tc.whiteAG, tc.blackAG)
+		}
+		if pair.IsByePairing != tc.bye {
+			t.Errorf("%v: IsByePairing = %v; want %v", tc.name,
+				pair.IsByePairing, tc.bye)
+		}
+	}
+}
+
+func TestFixupStandingsPendingRound(t *testing.T) {
+	tourney := &Tournament{
+		Players: []Player{
+			{DisplayName: "Alice Smith", SectionName: "Open"},
+			{DisplayName: "Bob Jones", SectionName: "Open"},
+			{DisplayName: "Jo Tan", SectionName: "Open"},
+		},
+		CurrentPairings: []Pairing{
+			{
+				BoardNumber:  1,
+				WhitePlayer:  Player{DisplayName: "Alice Smith", CurrentScore: 1, CurrentScoreAG: 1},
+				BlackPlayer:  Player{DisplayName: "Bob Jones", CurrentScore: 1, CurrentScoreAG: 1},
+				WhiteOutcome: ResultPending,
+				BlackOutcome: ResultPending,
+			},
+			{
+				IsByePairing: true,
+				WhitePlayer:  Player{DisplayName: "BYE"},
+				BlackPlayer:  Player{DisplayName: "Jo Tan", CurrentScore: 1, CurrentScoreAG: 2},
+				WhiteOutcome: ResultNone,
+				BlackOutcome: ResultBye,
+			},
+		},
+	}
+	fixupStandings(tourney)
+
+	// the bye is not counted until the round's games finish
+	for _, p := range tourney.Players {
+		if p.CurrentScoreAG != 1 {
+			t.Errorf("%v CurrentScoreAG = %v; want 1", p.DisplayName,
+				p.CurrentScoreAG)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// ErrNoUscfEvent is returned by ResolveUscfTid when no US Chess rated event
+// corresponds to a BCC event, e.g. because it has not been filed yet.
+var ErrNoUscfEvent = errors.New("no matching US Chess event found")
+
+// words which appear in many event names and say nothing about which event
+// is meant
+var eventNameStopWords = map[string]bool{
+	"a": true, "and": true, "at": true, "bcc": true, "boylston": true,
+	"chess": true, "club": true, "of": true, "the": true,
+}
+
+// linkCandidate is an event which may be linked: a name along with the dates
+// it spans.
+type linkCandidate struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// MatchRatedEvent returns the US Chess rated event corresponding to the BCC
+// event titled title held on date. Events are matched by date, and among
+// several rated events on that date by the words their names share. ok is
+// false when no rated event matches, or when several match equally well.
+func MatchRatedEvent(title string, date time.Time,
+	rated []uschess.RatedEvent) (uschess.RatedEvent, bool) {
+
+	candidates := make([]linkCandidate, 0, len(rated))
+	for _, ev := range rated {
+		candidates = append(candidates, linkCandidate{
+			name:  ev.Name,
+			start: ev.StartDate.Time,
+			end:   ev.EndDate.Time,
+		})
+	}
+	idx := matchEvent(title, date, date, candidates)
+	if idx < 0 {
+		return uschess.RatedEvent{}, false
+	}
+
+	return rated[idx], true
+}
+
+// MatchBCCEvent returns the BCC event corresponding to a US Chess rated
+// event, matching as MatchRatedEvent does among the BCC events held during
+// the rated event. ok is false when no BCC event matches, or when several
+// match equally well.
+func MatchBCCEvent(rated uschess.RatedEvent, events []Event) (Event, bool) {
+	candidates := make([]linkCandidate, 0, len(events))
+	for _, ev := range events {
+		candidates = append(candidates, linkCandidate{
+			name:  ev.Title,
+			start: ev.Date,
+		})
+	}
+	idx := matchEvent(rated.Name, rated.StartDate.Time, rated.EndDate.Time,
+		candidates)
+	if idx < 0 {
+		return Event{}, false
+	}
+
+	return events[idx], true
+}
+
+// matchEvent returns the index of the candidate held during [start, end]
+// whose name best matches name, or -1 when none does. A lone candidate held
+// then matches regardless of name; otherwise the best match must share at
+// least one word with name and more than any other candidate.
+func matchEvent(name string, start time.Time, end time.Time,
+	candidates []linkCandidate) int {
+
+	if end.IsZero() || end.Before(start) {
+		end = start
+	}
+	start, end = calendarDay(start), calendarDay(end)
+	best := -1
+	bestScore := -1
+	tied := false
+	held := 0
+	for idx, c := range candidates {
+		if c.start.IsZero() {
+			continue
+		}
+		cEnd := c.end
+		if cEnd.IsZero() || cEnd.Before(c.start) {
+			cEnd = c.start
+		}
+		if end.Before(calendarDay(c.start)) || start.After(calendarDay(cEnd)) {
+			continue
+		}
+		held++
+		score := sharedNameWords(name, c.name)
+		if score == bestScore {
+			tied = true
+		} else if score > bestScore {
+			best, bestScore, tied = idx, score, false
+		}
+	}
+	if held == 1 {
+		return best
+	}
+	if best < 0 || tied || bestScore == 0 {
+		return -1
+	}
+
+	return best
+}
+
+// calendarDay returns t's date at midnight UTC so that dates may be compared
+// regardless of time of day or location.
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// sharedNameWords counts the distinct significant words two event names
+// have in common, ignoring case, punctuation, and common words such as
+// "Chess" or "Club".
+func sharedNameWords(a string, b string) int {
+	words := eventNameWords(b)
+	shared := 0
+	for w := range eventNameWords(a) {
+		if words[w] {
+			shared++
+		}
+	}
+
+	return shared
+}
+
+func eventNameWords(name string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(name),
+		func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+
+		if !eventNameStopWords[w] {
+			words[w] = true
+		}
+	}
+
+	return words
+}
+
+// ResolveUscfTid returns the US Chess event id of a BCC event. It is taken
+// from the event's detail when the club has recorded it, and otherwise found
+// by matching the event against the club's US Chess affiliate events. It
+// returns ErrNoUscfEvent when no rated event matches.
+func ResolveUscfTid(ctx context.Context, client *uschess.ClientWithResponses,
+	detail *EventDetail) (uschess.EventID, error) {
+
+	if detail.UscfTid != 0 {
+		return uschess.EventID(strconv.Itoa(detail.UscfTid)), nil
+	}
+	start, _ := eventDateRange(detail)
+	if start.IsZero() {
+		return "", ErrNoUscfEvent
+	}
+
+	rated, err := uscfutils.GetAffiliateEventsSince(ctx, client,
+		uschess.AffiliateID(internal.BccUSCFAffiliateID),
+		start.AddDate(0, 0, -1))
+	if err != nil {
+		return "", err
+	}
+	ev, ok := MatchRatedEvent(detail.Title, start, rated)
+	if !ok {
+		return "", ErrNoUscfEvent
+	}
+
+	return ev.Id, nil
+}
@@ -0,0 +1,94 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func testRatedEvent(id string, name string, start string,
+	end string) uschess.RatedEvent {
+
+	parse := func(d string) openapi_types.Date {
+		t, _ := time.Parse("2006-01-02", d)
+		return openapi_types.Date{Time: t}
+	}
+
+	return uschess.RatedEvent{Id: uschess.EventID(id), Name: name,
+		StartDate: parse(start), EndDate: parse(end)}
+}
+
+func TestMatchEvents(t *testing.T) {
+	boston, _ := time.LoadLocation("America/New_York")
+	rated := []uschess.RatedEvent{
+		testRatedEvent("202601131234", "BCC Tuesday Night Swiss #2",
+			"2026-01-13", "2026-01-13"),
+		testRatedEvent("202601171111", "Boylston Saturday Quads",
+			"2026-01-17", "2026-01-17"),
+		testRatedEvent("202601172222", "Saturday Scholastic G/30",
+			"2026-01-17", "2026-01-17"),
+		testRatedEvent("202601243333", "Winter Open", "2026-01-24",
+			"2026-01-25"),
+	}
+	events := []Event{
+		{EventID: 1, Title: "Tuesday Night Swiss",
+			Date: time.Date(2026, 1, 13, 19, 0, 0, 0, boston)},
+		{EventID: 2, Title: "Saturday Quads",
+			Date: time.Date(2026, 1, 17, 10, 0, 0, 0, boston)},
+		{EventID: 3, Title: "Scholastic Saturday",
+			Date: time.Date(2026, 1, 17, 9, 0, 0, 0, boston)},
+		{EventID: 4, Title: "BCC Winter Open (2 day)",
+			Date: time.Date(2026, 1, 24, 10, 0, 0, 0, boston)},
+		{EventID: 5, Title: "Thursday Blitz",
+			Date: time.Date(2026, 1, 15, 19, 0, 0, 0, boston)},
+	}
+
+	want := map[int]uschess.EventID{
+		1: "202601131234", // lone event on the date
+		2: "202601171111", // shares "saturday quads"
+		3: "202601172222", // shares "saturday scholastic"
+		4: "202601243333", // first day of a two day event
+		5: "",             // not rated
+	}
+	for _, ev := range events {
+		got, ok := MatchRatedEvent(ev.Title, ev.Date, rated)
+		if want[ev.EventID] == "" {
+			if ok {
+				t.Errorf("MatchRatedEvent(%q) = %v; want no match", ev.Title,
+					got.Id)
+			}
+			continue
+		}
+		if !ok || got.Id != want[ev.EventID] {
+			t.Errorf("MatchRatedEvent(%q) = %v, %v; want %v", ev.Title, got.Id,
+				ok, want[ev.EventID])
+		}
+	}
+	for eventID, tid := range want {
+		if tid == "" {
+			continue
+		}
+		for _, r := range rated {
+			if r.Id != tid {
+				continue
+			}
+			got, ok := MatchBCCEvent(r, events)
+			if !ok || got.EventID != eventID {
+				t.Errorf("MatchBCCEvent(%q) = %v, %v; want %v", r.Name,
+					got.EventID, ok, eventID)
+			}
+		}
+	}
+
+	// equally good matches are ambiguous
+	if got, ok := MatchRatedEvent("Saturday", events[1].Date,
+		rated); ok {
+		t.Errorf("MatchRatedEvent(ambiguous) = %v; want no match", got.Id)
+	}
+}
@@ -0,0 +1,121 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// BuildUSCFRosterCSV formats an event's entries as a roster for US Chess
+// tournament tools: one CSV row per entry with their USCF member id, name
+// as US Chess lists it ("LAST, FIRST"), rating, and section, grouped by
+// section and ordered by rating. Rows the director should look at before
+// filing are explained in a final Flag column, e.g. an entry without a USCF
+// id.
+//
+// official holds the entries' US Chess records as fetched by
+// uscfutils.Client.FetchRatingsOnly, or is nil to use the entries as
+// registered. With it, names and ratings are taken from US Chess, and ids
+// it does not know of, memberships expired as of now, and entry ratings
+// which differ from the official one are flagged.
+func BuildUSCFRosterCSV(detail *EventDetail,
+	official map[uschess.MemberID]*uschess.Player, now time.Time) (string,
+	error) {
+
+	type rosterRow struct {
+		id, name, section string
+		rating            int
+		flags             []string
+	}
+	rows := make([]rosterRow, 0, len(detail.Entries))
+	for _, entry := range detail.Entries {
+		var player *uschess.Player
+		if entry.UscfID > 0 {
+			player = official[uschess.MemberID(strconv.Itoa(entry.UscfID))]
+		}
+		merged := MergePlayer(entry, player)
+		row := rosterRow{
+			name: strings.ToUpper(merged.LastName + ", " +
+				merged.FirstName),
+			section: merged.Section,
+			rating:  merged.Rating,
+		}
+		if entry.UscfID <= 0 {
+			row.flags = append(row.flags, "missing USCF id")
+			rows = append(rows, row)
+			continue
+		}
+		row.id = strconv.Itoa(merged.UscfID)
+		if official == nil {
+			rows = append(rows, row)
+			continue
+		}
+
+		if !merged.Found {
+			row.flags = append(row.flags, "USCF id not found")
+			rows = append(rows, row)
+			continue
+		}
+		if merged.Rating != merged.EntryRating {
+			row.flags = append(row.flags, fmt.Sprintf("entered at %v",
+				displayEntryRating(merged.EntryRating)))
+		}
+		if merged.Expired(now) {
+			row.flags = append(row.flags, fmt.Sprintf("membership expired %v",
+				merged.Expiration.Format("2006-01-02")))
+		}
+		rows = append(rows, row)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].section != rows[j].section {
+			return SectionSorter{rows[i].section, rows[j].section}.Less(0, 1)
+		}
+		if rows[i].rating != rows[j].rating {
+			return rows[i].rating > rows[j].rating
+		}
+		return rows[i].name < rows[j].name
+	})
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	err := w.Write([]string{"USCF ID", "Name", "Rating", "Section", "Flag"})
+	if err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		rating := ""
+		if row.rating > 0 {
+			rating = strconv.Itoa(row.rating)
+		}
+		err := w.Write([]string{row.id, row.name, rating, row.section,
+			strings.Join(row.flags, "; ")})
+		if err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// displayEntryRating formats an entry's rating for a roster flag.
+func displayEntryRating(rating int) string {
+	if rating == 0 {
+		return "unrated"
+	}
+
+	return strconv.Itoa(rating)
+}
@@ -0,0 +1,69 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package bcc
+
+import (
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestBuildUSCFRosterCSV(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	detail := &EventDetail{Entries: []Entry{
+		{FirstName: "Bob", LastName: "Jones", UscfID: 12345678,
+			SectionName: "U1800", PrimaryRating: "1650"},
+		{FirstName: "Alice", LastName: "Smith", UscfID: 23456789,
+			SectionName: "Open", PrimaryRating: "2200"},
+		{FirstName: "Carol", LastName: "White", SectionName: "U1800",
+			PrimaryRating: "1700"},
+		{FirstName: "Dan", LastName: "Brown, Jr.", UscfID: 34567890,
+			SectionName: "U1800", PrimaryRating: ""},
+	}}
+
+	got, err := BuildUSCFRosterCSV(detail, nil, now)
+	if err != nil {
+		t.Fatalf("BuildUSCFRosterCSV() err = %v", err)
+	}
+	want := `USCF ID,Name,Rating,Section,Flag
+23456789,"SMITH, ALICE",2200,Open,
+,"WHITE, CAROL",1700,U1800,missing USCF id
+12345678,"JONES, BOB",1650,U1800,
+34567890,"BROWN, JR., DAN",,U1800,
+`
+	if got != want {
+		t.Errorf("BuildUSCFRosterCSV() =\n%s\nwant\n%s", got, want)
+	}
+
+	official := map[uschess.MemberID]*uschess.Player{
+		"12345678": {MemberDetail: uschess.MemberDetail{
+			FirstName: "Robert", LastName: "Jones",
+			Ratings: []uschess.MemberRating{{RatingType: uschess.RatingTypeR,
+				Rating: 1702}},
+			ExpirationDate: openapi_types.Date{Time: now.AddDate(1, 0, 0)},
+		}},
+		"23456789": {MemberDetail: uschess.MemberDetail{
+			FirstName: "Alice", LastName: "Smith",
+			Ratings: []uschess.MemberRating{{RatingType: uschess.RatingTypeR,
+				Rating: 2200}},
+			ExpirationDate: openapi_types.Date{Time: now.AddDate(0, -1, 0)},
+		}},
+	}
+	got, err = BuildUSCFRosterCSV(detail, official, now)
+	if err != nil {
+		t.Fatalf("BuildUSCFRosterCSV(official) err = %v", err)
+	}
+	want = `USCF ID,Name,Rating,Section,Flag
+23456789,"SMITH, ALICE",2200,Open,membership expired 2026-02-01
+12345678,"JONES, ROBERT",1702,U1800,entered at 1650
+,"WHITE, CAROL",1700,U1800,missing USCF id
+34567890,"BROWN, JR., DAN",,U1800,USCF id not found
+`
+	if got != want {
+		t.Errorf("BuildUSCFRosterCSV(official) =\n%s\nwant\n%s", got, want)
+	}
+}
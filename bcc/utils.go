@@ -5,14 +5,15 @@
 package bcc
 
 import (
+	"context"
 	"fmt"
 	"net/http"
-	"strconv"
-	"strings"
-	"unicode"
+	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
 )
 
 type Source int
@@ -38,13 +39,25 @@ func (s Source) String() string {
 
 // Construct an artificial Tournament from an EventDetail
 func eventDetailToTournament(eventDetail *EventDetail) *Tournament {
+	return eventDetailToTournamentWithOptions(eventDetail, PairingOptions{})
+}
+
+func eventDetailToTournamentWithOptions(eventDetail *EventDetail,
+	opts PairingOptions) *Tournament {
+
 	// Build tournament players list from event details entries
 	tourney := &Tournament{}
 	for _, entry := range eventDetail.Entries {
 		tourney.Players = append(tourney.Players, entryToPlayer(entry))
 	}
 
-	tourney.CurrentPairings = predictRound1Pairings(eventDetail.Entries)
+	if isRoundRobin(eventDetail) {
+		tourney.CurrentPairings = predictRoundRobinPairings(eventDetail.Entries)
+	} else {
+		tourney.CurrentPairings, tourney.smallSections =
+			predictRound1PairingsWithReport(eventDetail.Entries,
+				requestedByePointsFromDetail(eventDetail), opts)
+	}
 	tourney.isPredicted = true
 
 	return tourney
@@ -53,6 +66,7 @@ func eventDetailToTournament(eventDetail *EventDetail) *Tournament {
 // Construct an artificial Player from an Entry
 func entryToPlayer(entry Entry) Player {
 	displayName := fmt.Sprintf("%s %s", entry.FirstName, entry.LastName)
+	rating := internal.ParseRating(entry.PrimaryRating)
 
 	return Player{
 		FirstName:       entry.FirstName,
@@ -60,38 +74,40 @@ func entryToPlayer(entry Entry) Player {
 		NameTitle:       entry.ChessTitle,
 		DisplayName:     displayName,
 		UscfID:          entry.UscfID,
-		PrimaryRating:   strRatingToInt(entry.PrimaryRating),
+		PrimaryRating:   rating.Value,
+		RatingReported:  rating.Reported,
 		SecondaryRating: strRatingToInt(entry.SecondaryRating),
 		SectionName:     entry.SectionName,
+		PairingNumber:   entry.PairingNumber,
 	}
 }
 
-func strRatingToInt(rating string) int {
-	r := 0
-	if rating != "" {
-		rating = strings.TrimSpace(rating)
-		// handle formats like "559/24"
-		if idx := strings.Index(rating, "/"); idx != -1 {
-			rating = rating[:idx]
-		}
-		// handle provisional formats like "1654P11"
-		for idx, c := range rating {
-			if !unicode.IsDigit(c) {
-				rating = rating[:idx]
-				break
-			}
-		}
-		if v, err := strconv.Atoi(rating); err == nil {
-			r = v
-		}
+// displayRating formats a player's rating for entries and pairings output:
+// "unrated", "1650", or "1650*" for a self-reported rating.
+func displayRating(p Player) string {
+	switch {
+	case p.PrimaryRating == 0:
+		return "unrated"
+	case p.RatingReported:
+		return fmt.Sprintf("%v*", p.PrimaryRating)
+	default:
+		return fmt.Sprintf("%v", p.PrimaryRating)
 	}
+}
+
+// strRatingToInt returns the base rating of a rating string, or 0 if unrated.
+func strRatingToInt(rating string) int {
+	return internal.ParseRating(rating).Value
+}
 
-	return r
+// SectionMatches reports whether the section name matches a user supplied
+// section filter; see internal.SectionMatches.
+func SectionMatches(name, filter string) bool {
+	return internal.SectionMatches(name, filter)
 }
 
-// SectionSorter implements sort.Interface for custom section ordering
-// Order: "Open" first, then U<Number> sections descending by number, then
-// others lexicographically
+// SectionSorter implements sort.Interface for custom section ordering; see
+// internal.SectionLess.
 type SectionSorter []string
 
 func (s SectionSorter) Len() int { return len(s) }
@@ -99,54 +115,125 @@ func (s SectionSorter) Len() int { return len(s) }
 func (s SectionSorter) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
 
 func (s SectionSorter) Less(i, j int) bool {
-	a, b := s[i], s[j]
-	// "Open" or "Championship" always first
-	if a == "Open" && b != "Open" {
-		return true
-	}
-	if b == "Open" && a != "Open" {
-		return false
-	}
-	if a == "Championship" && b != "Championship" {
-		return true
-	}
-	if b == "Championship" && a != "Championship" {
-		return false
+	return internal.SectionLess(s[i], s[j])
+}
+
+// base URLs of the club's API and website; tests point these at local
+// servers
+var (
+	apiBaseURL = "https://beta.boylstonchess.org"
+	webBaseURL = "https://boylstonchess.org"
+)
+
+func eventDetailURL(eventId int64) string {
+	return fmt.Sprintf("%v/api/event/%d", apiBaseURL, eventId)
+}
+
+func tournamentURL(eventId int64) string {
+	return fmt.Sprintf("%v/api/event/%d/tournament", apiBaseURL, eventId)
+}
+
+func entriesPageURL(eventId int64) string {
+	return fmt.Sprintf("%v/tournament/entries/%d", webBaseURL, eventId)
+}
+
+func eventPageURL(eventId int64) string {
+	return fmt.Sprintf("%v/events/%d", webBaseURL, eventId)
+}
+
+func pairingsPageURL(eventId int64) string {
+	return fmt.Sprintf("%v/files/event/%d/pairings", webBaseURL, eventId)
+}
+
+// EventCacheKeys returns the http cache keys of every page fetched on behalf
+// of eventId so that stale cached copies may be cleared.
+func EventCacheKeys(eventId int64) []string {
+	return []string{
+		eventDetailURL(eventId),
+		tournamentURL(eventId),
+		entriesPageURL(eventId),
+		pairingsPageURL(eventId),
+		eventPageURL(eventId),
 	}
-	ua, ub := strings.HasPrefix(a, "U"), strings.HasPrefix(b, "U")
-	// Both U-sections: compare numeric suffix descending
-	if ua && ub {
-		ai, errA := strconv.Atoi(strings.TrimPrefix(a, "U"))
-		bi, errB := strconv.Atoi(strings.TrimPrefix(b, "U"))
-		if errA == nil && errB == nil {
-			return ai > bi
+}
+
+const (
+	webDocCacheTTL   = 2 * time.Minute
+	fetchMaxAttempts = 3
+)
+
+var (
+	webDocClientOnce sync.Once
+	webDocClient     *http.Client
+
+	fetchRetryBaseDelay = 500 * time.Millisecond
+)
+
+func getWebDocClient() *http.Client {
+	webDocClientOnce.Do(func() {
+		if webDocClient == nil {
+			webDocClient = httpcache.NewCachedHttpClient(context.Background(),
+				webDocCacheTTL)
 		}
+	})
+
+	return webDocClient
+}
+
+// doWithRetry issues req, retrying with exponential backoff when the request
+// fails outright or the server responds with a status indicating it is
+// unavailable (5xx or 429).
+func doWithRetry(client *http.Client, req *http.Request) (*http.Response, error) {
+	delay := fetchRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if err == nil && internal.StatusClass(resp.StatusCode) !=
+			internal.ErrUpstreamUnavailable {
+			return resp, nil
+		}
+		if attempt == fetchMaxAttempts {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
 	}
-	// U-sections before non-U (after Championship)
-	if ua != ub {
-		return ua
-	}
-	// Fallback lexicographical
-	return a < b
 }
 
 // fetchDoc gets the HTML document at the given URL using the configured User-Agent.
-func fetchDoc(url string) (*goquery.Document, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func fetchDoc(ctx context.Context, url string) (*goquery.Document, error) {
+	doc, _, err := fetchDocWithAge(ctx, url)
+	return doc, err
+}
+
+// fetchDocWithAge is like fetchDoc but also returns the age of the document
+// when it was served from the cache, or 0 when it was freshly fetched.
+func fetchDocWithAge(ctx context.Context,
+	url string) (*goquery.Document, time.Duration, error) {
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	req.Header.Set("User-Agent", internal.UserAgent)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := doWithRetry(getWebDocClient(), req)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d fetching %s", resp.StatusCode, url)
+		return nil, 0, fmt.Errorf("status %w fetching %s",
+			internal.HTTPStatusError(resp.StatusCode), url)
 	}
 
-	return goquery.NewDocumentFromReader(resp.Body)
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	return doc, httpcache.ResponseAge(resp), err
 }
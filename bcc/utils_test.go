@@ -5,7 +5,16 @@
 package bcc
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
 )
 
 // TestEntryToPlayer verifies that entryToPlayer correctly parses ratings.
@@ -68,3 +77,101 @@ func TestEntryToPlayer(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchDocRetriesAndCaches(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, "<html><body><p id=\"msg\">hello</p></body></html>")
+	}))
+	defer srv.Close()
+
+	getWebDocClient()
+	origClient, origDelay := webDocClient, fetchRetryBaseDelay
+	defer func() { webDocClient, fetchRetryBaseDelay = origClient, origDelay }()
+	webDocClient = httpcache.NewMemoryCachedHttpClient(time.Minute)
+	fetchRetryBaseDelay = time.Millisecond
+
+	doc, err := fetchDoc(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("fetchDoc() err = %v", err)
+	}
+	if got := doc.Find("#msg").Text(); got != "hello" {
+		t.Fatalf("msg = %q; want %q", got, "hello")
+	}
+	if requests != 2 {
+		t.Fatalf("requests = %v; want 2", requests)
+	}
+
+	if _, err := fetchDoc(context.Background(), srv.URL); err != nil {
+		t.Fatalf("fetchDoc() 2nd call err = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %v after 2nd call; want 2 (served from cache)",
+			requests)
+	}
+}
+
+func TestFetchErrorsByStatus(t *testing.T) {
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		_ *http.Request) {
+
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	getWebDocClient()
+	eventDetailClientOnce.Do(func() {})
+	origApi, origClient, origDelay := apiBaseURL, webDocClient,
+		fetchRetryBaseDelay
+	origDetailClient := eventDetailClient
+	defer func() {
+		apiBaseURL, webDocClient, fetchRetryBaseDelay = origApi, origClient,
+			origDelay
+		eventDetailClient = origDetailClient
+	}()
+	apiBaseURL = srv.URL
+	webDocClient = http.DefaultClient
+	eventDetailClient = http.DefaultClient
+	fetchRetryBaseDelay = time.Millisecond
+
+	tests := []struct {
+		status      int
+		notFound    bool
+		unavailable bool
+	}{
+		{http.StatusNotFound, true, false},
+		{http.StatusGone, true, false},
+		{http.StatusServiceUnavailable, false, true},
+		{http.StatusInternalServerError, false, true},
+		{http.StatusTooManyRequests, false, true},
+		{http.StatusForbidden, false, false},
+	}
+	for _, tc := range tests {
+		status = tc.status
+		_, docErr := fetchDoc(context.Background(), srv.URL)
+		_, apiErr := getEventDetailViaApi(context.Background(), 42)
+		for _, err := range []error{docErr, apiErr} {
+			if err == nil {
+				t.Fatalf("status %d: err = nil", tc.status)
+			}
+			if got := errors.Is(err, internal.ErrNotFound); got != tc.notFound {
+				t.Errorf("status %d: errors.Is(%v, ErrNotFound) = %v", tc.status,
+					err, got)
+			}
+			if got := errors.Is(err,
+				internal.ErrUpstreamUnavailable); got != tc.unavailable {
+
+				t.Errorf("status %d: errors.Is(%v, ErrUpstreamUnavailable) = %v",
+					tc.status, err, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// known-good club event used to exercise the BCC upstreams; the USCF
+// upstreams are exercised with ids from the active player lists
+const doctorEventID = 1358
+
+// doctorCheck exercises a single upstream data source.
+type doctorCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func handleDoctor(ctx context.Context, fs *flag.FlagSet, args []string) {
+	timeout := fs.Duration("timeout", 30*time.Second, "Timeout for each check")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+
+	// bypass the http cache so that each upstream is actually contacted
+	client, err := uschess.NewDefaultClient(
+		uschess.WithHTTPClient(http.DefaultClient),
+		uschess.WithUserAgent(internal.UserAgent),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating US Chess client: %v\n", err)
+		os.Exit(1)
+	}
+
+	tids := bcc.ActivePlayerTIds()
+	tid := tids[len(tids)-1]
+	memID := bcc.ActivePlayerMemIds()[0]
+
+	checks := []doctorCheck{
+		{"BCC events API", func(ctx context.Context) error {
+			events, err := bcc.RefreshEvents(ctx)
+			if err == nil && len(events) == 0 {
+				err = fmt.Errorf("no events returned")
+			}
+			return err
+		}},
+		{"BCC event detail API", func(ctx context.Context) error {
+			detail, err := bcc.GetEventDetail(ctx, doctorEventID)
+			if err == nil && detail.Title == "" {
+				err = fmt.Errorf("event %v has no title", doctorEventID)
+			}
+			return err
+		}},
+		{"BCC tournament", func(ctx context.Context) error {
+			t, err := bcc.GetTournament(ctx, doctorEventID)
+			if err == nil && len(t.Players) == 0 {
+				err = fmt.Errorf("event %v has no players", doctorEventID)
+			}
+			return err
+		}},
+		{"USCF rated events API", func(ctx context.Context) error {
+			events, err := client.GetAllAffiliateRatedEvents(ctx,
+				uschess.AffiliateID(internal.BccUSCFAffiliateID), nil)
+			if err == nil && len(events) == 0 {
+				err = fmt.Errorf("no events returned")
+			}
+			return err
+		}},
+		{"USCF standings API", func(ctx context.Context) error {
+			standings, err := client.GetAllRatedEventStandings(ctx, tid,
+				1 /* sectionNumber */)
+			if err == nil && len(standings) == 0 {
+				err = fmt.Errorf("no standings returned")
+			}
+			return err
+		}},
+		{"USCF member API", func(ctx context.Context) error {
+			player, err := client.GetPlayer(ctx, memID, nil)
+			if err == nil && player.LastName == "" {
+				err = fmt.Errorf("member %v has no name", memID)
+			}
+			return err
+		}},
+		{"USCF affiliate page", checkAffiliatePage},
+	}
+
+	failed := 0
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, *timeout)
+		start := time.Now()
+		err := check.run(checkCtx)
+		latency := time.Since(start).Round(time.Millisecond)
+		cancel()
+
+		if err != nil {
+			failed++
+			fmt.Printf("FAIL  %-24s %8v  %v\n", check.name, latency, err)
+		} else {
+			fmt.Printf("OK    %-24s %8v\n", check.name, latency)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%v of %v checks failed\n", failed, len(checks))
+		os.Exit(1)
+	}
+}
+
+// checkAffiliatePage fetches the club's USCF affiliate page and verifies that
+// it describes the club.
+func checkAffiliatePage(ctx context.Context) error {
+	url := fmt.Sprintf("https://www.uschess.org/msa/AffDtlMain.php?%v",
+		internal.BccUSCFAffiliateID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", internal.UserAgent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d fetching %s", resp.StatusCode, url)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(body), internal.BccUSCFAffiliateID) {
+		return fmt.Errorf("affiliate page does not mention %v",
+			internal.BccUSCFAffiliateID)
+	}
+
+	return nil
+}
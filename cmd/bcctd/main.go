@@ -7,16 +7,20 @@ package main
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
 	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
 	uschess "github.com/mikeb26/uschess-go"
 )
@@ -24,24 +28,130 @@ import (
 //go:embed help.txt
 var helpText string
 
-// cmdHandler defines the signature for command handler functions.
-type cmdHandler func(ctx context.Context, args []string)
+// cmdHandler defines the signature for command handler functions. Each
+// handler defines its flags on fs and then calls parseFlags with args.
+type cmdHandler func(ctx context.Context, fs *flag.FlagSet, args []string)
 
-// commands maps command names to their respective handler functions.
-var commands = map[string]cmdHandler{
-	"help":       handleHelp,
-	"cal":        handleCal,
-	"event":      handleEvent,
-	"pairings":   handlePairings,
-	"entries":    handleEntries,
-	"standings":  handleStandings,
-	"crosstable": handleCrossTable,
-	"history":    handleHistory,
-	"player":     handlePlayer,
-	"estrating":  handleEstRating,
+// command is a bcctd command along with what `bcctd help <command>` shows
+// beyond the flags its handler defines.
+type command struct {
+	handler cmdHandler
+	// args describes any positional arguments following the flags
+	args     string
+	summary  string
+	examples []string
 }
 
-var uschessClient *uschess.ClientWithResponses
+// commands maps command names to their respective commands. It is
+// populated by init as the help command refers back to it.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"help": {handler: handleHelp, args: "[<command>]",
+			summary:  "Show all commands, or a single command's flags and examples.",
+			examples: []string{"bcctd help", "bcctd help pairings"}},
+		"cal": {handler: handleCal,
+			summary: "Show upcoming events.",
+			examples: []string{"bcctd cal", "bcctd cal --days 30 --detailed",
+				"bcctd cal --days 60 --dow tuesday"}},
+		"event": {handler: handleEvent,
+			summary:  "Show detailed information regarding an event.",
+			examples: []string{"bcctd event --eventid 1234"}},
+		"pairings": {handler: handlePairings,
+			summary: "Show current pairings for a tournament, grouped by section.",
+			examples: []string{"bcctd pairings --eventid 1234",
+				"bcctd pairings --eventid 1234 --section u18 --width 60",
+				"bcctd pairings --eventid 1234 --round 2",
+				"bcctd pairings --eventid 1234 --minsection 4 --mergesmall"}},
+		"comparepairings": {handler: handleComparePairings,
+			summary:  "Compare an event's posted pairings with the predicted pairings.",
+			examples: []string{"bcctd comparepairings --eventid 1234"}},
+		"featured": {handler: handleFeatured,
+			summary:  "Show the current round's top boards across all sections.",
+			examples: []string{"bcctd featured --eventid 1234 --n 5"}},
+		"nextpairings": {handler: handleNextPairings,
+			summary:  "Predict the next round's pairings of a swiss in progress.",
+			examples: []string{"bcctd nextpairings --eventid 1234 --section open"}},
+		"entries": {handler: handleEntries,
+			summary: "Show a tournament's current entries, grouped by section.",
+			examples: []string{"bcctd entries --eventid 1234",
+				"bcctd entries --eventid 1234 --newcomers"}},
+		"byerequests": {handler: handleByeRequests,
+			summary:  "List the byes each entry requested, grouped by section.",
+			examples: []string{"bcctd byerequests --eventid 1234"}},
+		"planbye": {handler: handlePlanBye,
+			summary:  "List the future rounds in which a player could still take a bye.",
+			examples: []string{"bcctd planbye --eventid 1234"}},
+		"standings": {handler: handleStandings,
+			summary: "Show current standings for a tournament, grouped by section.",
+			examples: []string{"bcctd standings --eventid 1234",
+				"bcctd standings --eventid 1234 --prizes",
+				"bcctd standings --eventid 1234 --ratings",
+				"bcctd standings --eventid 1234 --csv"}},
+		"crosstable": {handler: handleCrossTable,
+			summary: "Show a tournament's US Chess cross table.",
+			examples: []string{"bcctd crosstable --uscftid 202601131234",
+				"bcctd crosstable --eventid 1234 --bystandings --style classic",
+				"bcctd crosstable --eventid 1234 --combined"}},
+		"history": {handler: handleHistory,
+			summary:  "Show recently completed tournaments of US Chess affiliates.",
+			examples: []string{"bcctd history --days 30 --links"}},
+		"recent": {handler: handleRecent,
+			summary:  "Show the section winners of an affiliate's most recent rated events.",
+			examples: []string{"bcctd recent --count 3"}},
+		"gainers": {handler: handleGainers,
+			summary:  "Show the players of a rated event who gained and lost the most rating points.",
+			examples: []string{"bcctd gainers --uscftid 202601131234"}},
+		"player": {handler: handlePlayer,
+			summary: "Show information about a player given their USCF member id.",
+			examples: []string{"bcctd player --id 12345678",
+				"bcctd player --id 12345678 --eventcount 3 --json"}},
+		"estrating": {handler: handleEstRating,
+			args:    "[<Opponent USCF member ids>]",
+			summary: "Estimate a player's new rating from their score against a list of opponents.",
+			examples: []string{"bcctd estrating --id 12345678 --score 2.5 23456789 34567890 45678901",
+				"bcctd estrating --age 12 --score 2 23456789 34567890 45678901",
+				"bcctd estrating --id 12345678 --score 1 --unrated 1200 23456789 34567890"}},
+		"target": {handler: handleTarget,
+			summary:  "Compute the minimum score needed against opponents to reach a goal rating.",
+			examples: []string{"bcctd target --id 12345678 --opp 23456789,34567890 --goal 1600"}},
+		"expect": {handler: handleExpect,
+			summary:  "Approximate the points a player can expect to score against a section's field.",
+			examples: []string{"bcctd expect --id 12345678 --eventid 1234 --section u18"}},
+		"bands": {handler: handleBands,
+			summary: "Count players in each rating band of an event or the club roster.",
+			examples: []string{"bcctd bands --eventid 1234",
+				"bcctd bands --roster --since 2026-01-01"}},
+		"sectioncuts": {handler: handleSectionCuts,
+			summary:  "Suggest rating cutoffs which split an event's entries into sections of equal size.",
+			examples: []string{"bcctd sectioncuts --eventid 1234 --sections 3"}},
+		"eligibility": {handler: handleEligibility,
+			summary:  "List entries whose rating is too high or too low for their section.",
+			examples: []string{"bcctd eligibility --eventid 1234"}},
+		"roster": {handler: handleRoster,
+			summary: "Export a tournament's entries as a roster for US Chess tournament tools.",
+			examples: []string{"bcctd roster --eventid 1234 --format uscf",
+				"bcctd roster --eventid 1234 --format uscf --reconcile --output roster.csv"}},
+		"attendance": {handler: handleAttendance,
+			summary:  "Compare the entry counts of recent club events.",
+			examples: []string{"bcctd attendance --days 180 --series"}},
+		"cache-clear": {handler: handleCacheClear,
+			summary:  "Remove the cached pages of a club event and/or USCF tournament.",
+			examples: []string{"bcctd cache-clear --eventid 1234 --uscftid 202601131234"}},
+		"config": {handler: handleConfig,
+			summary:  "Show the configuration in effect, including environment overrides.",
+			examples: []string{"bcctd config"}},
+		"doctor": {handler: handleDoctor,
+			summary:  "Check connectivity to and parsing of each upstream data source.",
+			examples: []string{"bcctd doctor --timeout 10s"}},
+		"watch": {handler: handleWatch,
+			summary:  "Follow a tournament's pairings and standings from the terminal.",
+			examples: []string{"bcctd watch --eventid 1234 --interval 1m"}},
+	}
+}
+
+var uschessClient *uscfutils.Client
 
 func main() {
 	ctx := context.Background()
@@ -56,11 +166,20 @@ func main() {
 		usage()
 		os.Exit(1)
 	}
-	cmd := os.Args[1]
-	if handler, ok := commands[cmd]; ok {
-		handler(ctx, os.Args[2:])
+	name := os.Args[1]
+	args, noCache := stripNoCache(os.Args[2:])
+	if noCache {
+		ctx = httpcache.WithCacheBypass(ctx)
+	}
+	if cmd, ok := commands[name]; ok {
+		fs := flag.NewFlagSet(name, flag.ExitOnError)
+		fs.Usage = func() {
+			help, _ := commandHelp(name)
+			fmt.Fprint(os.Stderr, help)
+		}
+		cmd.handler(ctx, fs, args)
 	} else {
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", cmd)
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", name)
 		usage()
 		os.Exit(1)
 	}
@@ -70,54 +189,138 @@ func usage() {
 	fmt.Printf("%v", helpText)
 }
 
-func handleHelp(ctx context.Context, args []string) {
-	usage()
+// stripNoCache removes the --no-cache flag, which any command accepts, from
+// args and reports whether it was present. With it the command's fetches
+// bypass the http cache, fetching fresh from the origin and updating the
+// cache.
+func stripNoCache(args []string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	noCache := false
+	for _, arg := range args {
+		switch arg {
+		case "--no-cache", "-no-cache":
+			noCache = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	return rest, noCache
 }
 
-func handleCal(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("cal", flag.ExitOnError)
-	days := fs.Int("days", 14, "Number of days to retrieve (1-60)")
+// describeOnlyKey marks the context commandHelp runs a handler with so that
+// it only defines its flags.
+type describeOnlyKey struct{}
+
+// parseFlags parses args into fs, whose flags the calling handler has just
+// defined, and reports whether the handler should go on to run the command.
+// It should not when commandHelp is only describing the command.
+func parseFlags(ctx context.Context, fs *flag.FlagSet, args []string) bool {
+	if describeOnly, _ := ctx.Value(describeOnlyKey{}).(bool); describeOnly {
+		return false
+	}
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
-	// enforce bounds
-	if *days < -60 {
-		*days = -60
-	} else if *days > 60 {
-		*days = 60
+
+	return true
+}
+
+// commandHelp returns the usage of the named command, generated from the
+// flags its handler defines, along with its summary and examples.
+func commandHelp(name string) (string, error) {
+	cmd, ok := commands[name]
+	if !ok {
+		return "", fmt.Errorf("unknown command %q", name)
 	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd.handler(context.WithValue(context.Background(), describeOnlyKey{},
+		true), fs, nil)
 
-	var start time.Time
+	usageLine := "bcctd " + name
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		usageLine += " [flags]"
+	}
+	if cmd.args != "" {
+		usageLine += " " + cmd.args
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Usage: %v\n\n%v\n", usageLine, cmd.summary))
+	if hasFlags {
+		sb.WriteString("\nFlags:\n")
+		fs.SetOutput(&sb)
+		fs.PrintDefaults()
+	}
+	if len(cmd.examples) > 0 {
+		sb.WriteString("\nExamples:\n")
+		for _, example := range cmd.examples {
+			sb.WriteString("  " + example + "\n")
+		}
+	}
 
-	now := time.Now()
-	nowDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	end := nowDate.AddDate(0, 0, *days)
+	return sb.String(), nil
+}
 
-	if nowDate.After(end) {
-		start = end
-		end = nowDate
-	} else {
-		start = nowDate
+func handleHelp(ctx context.Context, fs *flag.FlagSet, args []string) {
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if fs.NArg() == 0 {
+		usage()
+		return
+	}
+
+	help, err := commandHelp(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", fs.Arg(0))
+		usage()
+		os.Exit(1)
+	}
+	fmt.Print(help)
+}
+
+func handleCal(ctx context.Context, fs *flag.FlagSet, args []string) {
+	days := fs.Int("days", internal.DefaultDays,
+		fmt.Sprintf("Number of days to retrieve (-%v-%v)", internal.MaxDays,
+			internal.MaxDays))
+	detailed := fs.Bool("detailed", false,
+		"Include format, entry fee, and registration status for each event")
+	dow := fs.String("dow", "",
+		"Only list events on this day of the week, e.g. tuesday")
+	if !parseFlags(ctx, fs, args) {
+		return
 	}
+	*days = internal.ClampSignedDays(*days)
+	var weekday time.Weekday
+	if *dow != "" {
+		var err error
+		weekday, err = bcc.ParseWeekday(*dow)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Please provide a valid --dow: %v.\n", err)
+			fs.Usage()
+			os.Exit(1)
+		}
+	}
+
+	start, end := bcc.CalWindow(*days)
 	// Fetch events from BCC API
-	events, err := bcc.GetEvents()
+	events, err := bcc.GetEvents(ctx)
 	if err != nil {
 		log.Fatalf("Error fetching events: %v", err)
 	}
-	// Filter and group events by date
-	eventsByDate := make(map[string][]bcc.Event)
-	for _, ev := range events {
-		// truncate event date to local date for inclusive comparison
-		evDate := time.Date(ev.Date.Year(), ev.Date.Month(), ev.Date.Day(), 0, 0, 0, 0, now.Location())
-		if evDate.Before(start) || evDate.After(end) {
-			continue
-		}
-		key := ev.Date.Format("2006-01-02")
-		eventsByDate[key] = append(eventsByDate[key], ev)
+	what := "events"
+	if *dow != "" {
+		events = bcc.FilterEventsByWeekday(events, weekday)
+		what = weekday.String() + " events"
 	}
+	// Filter and group events by date
+	eventsByDate := bcc.EventsByDate(events, start, end)
 
 	if len(eventsByDate) == 0 {
-		fmt.Printf("No events found in the next %d days.\n", *days)
+		fmt.Printf("No %v found in the next %d days.\n", what, *days)
 		return
 	}
 	// Build sorted output
@@ -125,132 +328,617 @@ func handleCal(ctx context.Context, args []string) {
 	for d := range eventsByDate {
 		dates = append(dates, d)
 	}
-	if start == nowDate {
+	if *days >= 0 {
 		sort.Strings(dates)
 	} else {
 		sort.Slice(dates, func(i, j int) bool {
 			return dates[j] < dates[i]
 		})
 	}
+	var details map[int64]*bcc.EventDetail
+	if *detailed {
+		var eventIds []int64
+		for _, evs := range eventsByDate {
+			for _, ev := range evs {
+				eventIds = append(eventIds, int64(ev.EventID))
+			}
+		}
+		details = bcc.GetEventDetails(ctx, eventIds)
+	}
 	for _, d := range dates {
 		fmt.Println(d)
 		for _, ev := range eventsByDate[d] {
 			fmt.Printf("  - %s (EventID:%d)\n", ev.Title, ev.EventID)
+			if detail, ok := details[int64(ev.EventID)]; ok {
+				fmt.Printf("      %s\n", bcc.BuildEventSummary(detail))
+			}
 		}
 	}
 	fmt.Printf("\nRun '%s event --eventid <EventID>' to get details on a specific event\n",
 		os.Args[0])
 }
 
-func handleEvent(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("event", flag.ExitOnError)
+func handleEvent(ctx context.Context, fs *flag.FlagSet, args []string) {
 	eventID := fs.Int("eventid", 0, "Event ID to fetch details for")
-	if err := fs.Parse(args); err != nil {
-		os.Exit(1)
+	if !parseFlags(ctx, fs, args) {
+		return
 	}
 	if *eventID <= 0 {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
 		fs.Usage()
 		os.Exit(1)
 	}
-	detail, err := bcc.GetEventDetail(int64(*eventID))
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
 	if err != nil {
 		log.Fatalf("Error fetching event %d: %v", *eventID, err)
 	}
+	if detail.UscfTid == 0 && bcc.EventStatus(&detail) == bcc.EventCompleted {
+		// link completed events to their crosstable even when the club
+		// hasn't recorded the US Chess event id
+		tid, err := bcc.ResolveUscfTid(ctx, uschessClient.ClientWithResponses,
+			&detail)
+		if err == nil {
+			detail.UscfTid, _ = strconv.Atoi(string(tid))
+		}
+	}
 	// Print event details
 	fmt.Printf("%v", bcc.BuildEventOutput(&detail, "", true, true))
 }
 
-func handlePairings(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("pairings", flag.ExitOnError)
+func handlePairings(ctx context.Context, fs *flag.FlagSet, args []string) {
 	eventID := fs.Int("eventid", 0, "Event ID to fetch pairings for")
-	if err := fs.Parse(args); err != nil {
-		os.Exit(1)
+	verbose := fs.Bool("verbose", false, "Include each player's USCF id")
+	section := fs.String("section", "", "Only show sections matching this name")
+	width := fs.Int("width", 0,
+		"Maximum line width; long names are truncated to fit (0 for no limit)")
+	minRating := fs.Int("minrating", 0,
+		"Pair predicted round 1 players rated below this (or unrated) among themselves")
+	minSection := fs.Int("minsection", 0,
+		"Leave predicted round 1 sections with fewer players than this unpaired")
+	mergeSmall := fs.Bool("mergesmall", false,
+		"Merge sections smaller than --minsection into a neighboring section")
+	round := fs.Int("round", 0,
+		"Show this round's games from the US Chess crosstable once the event is filed")
+	if !parseFlags(ctx, fs, args) {
+		return
 	}
 	if *eventID <= 0 {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
 		fs.Usage()
 		os.Exit(1)
 	}
+	if *minRating < 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a non-negative --minrating.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *minSection < 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a non-negative --minsection.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *round < 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --round.")
+		fs.Usage()
+		os.Exit(1)
+	}
 
-	tourney, err := bcc.GetTournament(int64(*eventID))
+	if *round > 0 {
+		output, ok, err := bcc.RoundPairingsOutput(ctx, uschessClient,
+			int64(*eventID), *section, *round)
+		if err != nil {
+			log.Printf("Error fetching round %d pairings: %v", *round, err)
+		}
+		if ok {
+			fmt.Print(output)
+			return
+		}
+		fmt.Printf("Round %v pairings are only available once the event is filed with US Chess; showing current pairings instead.\n\n",
+			*round)
+	}
+
+	note, final := bcc.EventStatusOutput(ctx, uschessClient, int64(*eventID))
+	fmt.Print(note)
+	if final != "" {
+		fmt.Print(final)
+		return
+	}
+
+	tourney, err := bcc.GetTournament(ctx, int64(*eventID))
 	if err != nil {
 		log.Fatalf("Error fetching pairings for event %d: %v", *eventID, err)
 	}
-	output := bcc.BuildPairingsOutput(tourney)
+	if tourney.IsPredicted() && (*minRating > 0 || *minSection > 0) {
+		tourney, err = bcc.PredictTournamentWithOptions(ctx, int64(*eventID),
+			bcc.PairingOptions{MinRating: *minRating,
+				MinSectionSize: *minSection, MergeSmallSections: *mergeSmall})
+		if err != nil {
+			log.Fatalf("Error predicting pairings for event %d: %v", *eventID,
+				err)
+		}
+		fmt.Print(bcc.BuildSmallSectionsNote(tourney))
+	}
+	output := bcc.BuildPairingsOutput(tourney, *verbose, *section, *width)
 	fmt.Print(output)
+	fmt.Print(bcc.BuildGameLinksOutput(tourney, *section))
 }
 
-func handleEntries(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("entries", flag.ExitOnError)
-	eventID := fs.Int("eventid", 0, "Event ID to fetch pairings for")
-	if err := fs.Parse(args); err != nil {
+func handleNextPairings(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to predict the next round of")
+	section := fs.String("section", "", "Only show sections matching this name")
+	width := fs.Int("width", 0,
+		"Maximum line width; long names are truncated to fit (0 for no limit)")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
 		os.Exit(1)
 	}
+
+	tourney, err := bcc.GetTournament(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching pairings for event %d: %v", *eventID, err)
+	}
+	prediction, err := bcc.PredictNextRound(tourney)
+	if err != nil {
+		log.Fatalf("Error predicting the next round of event %d: %v", *eventID,
+			err)
+	}
+	fmt.Print(bcc.BuildNextRoundNote(prediction))
+	fmt.Println()
+	fmt.Print(bcc.BuildPairingsOutput(prediction.Tournament, false, *section,
+		*width))
+}
+
+func handleFeatured(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to feature boards of")
+	n := fs.Int("n", bcc.DefaultFeaturedBoards, "Number of boards to feature")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
 	if *eventID <= 0 {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
 		fs.Usage()
 		os.Exit(1)
 	}
+	if *n <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --n.")
+		fs.Usage()
+		os.Exit(1)
+	}
 
-	tourney, err := bcc.GetTournament(int64(*eventID))
+	tourney, err := bcc.GetTournament(ctx, int64(*eventID))
 	if err != nil {
 		log.Fatalf("Error fetching pairings for event %d: %v", *eventID, err)
 	}
-	output := bcc.BuildEntriesOutput(tourney)
-	fmt.Print(output)
+	boards := bcc.FeaturedBoards(tourney, *n)
+	fmt.Print(bcc.BuildFeaturedBoardsOutput(boards))
+	fmt.Print(bcc.BuildBoardLinksOutput(boards))
 }
 
-func handleStandings(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("standings", flag.ExitOnError)
-	eventID := fs.Int("eventid", 0, "Event ID to fetch standings for")
-	if err := fs.Parse(args); err != nil {
+func handleEntries(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to fetch pairings for")
+	newcomers := fs.Bool("newcomers", false, "Note whether each entrant has played at the club before")
+	historyDays := fs.Int("historydays", uscfutils.DefaultClubHistoryDays,
+		"Number of days of club history to consider with --newcomers")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *historyDays <= 0 || *historyDays > uscfutils.MaxClubHistoryDays {
+		fmt.Fprintf(os.Stderr, "Please provide --historydays between 1 and %v.\n",
+			uscfutils.MaxClubHistoryDays)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	tourney, err := bcc.GetTournament(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching pairings for event %d: %v", *eventID, err)
+	}
+	if !*newcomers {
+		fmt.Print(bcc.BuildEntriesOutput(tourney, false))
+		return
+	}
+	since := internal.Now().AddDate(0, 0, -*historyDays)
+	clubPlayers, err := uschessClient.GetAffiliatePlayers(ctx,
+		uschess.AffiliateID(internal.BccUSCFAffiliateID), since)
+	if err != nil {
+		log.Fatalf("Error fetching club history: %v", err)
+	}
+	fmt.Print(bcc.BuildEntriesOutputWithNewcomers(tourney, clubPlayers))
+}
+
+func handleByeRequests(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to fetch bye requests for")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", *eventID, err)
+	}
+	fmt.Print(bcc.BuildByeRequestsOutput(bcc.ByeRequestSummary(&detail)))
+}
+
+func handleEligibility(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to check section eligibility for")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", *eventID, err)
+	}
+	fmt.Print(bcc.BuildEligibilityOutput(bcc.SectionEligibilityIssues(&detail)))
+}
+
+func handleRoster(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to export entries for")
+	format := fs.String("format", "uscf", "Roster format; only uscf (CSV for US Chess tournament tools) is supported")
+	reconcile := fs.Bool("reconcile", false, "Use names and ratings from US Chess, flagging entries which differ")
+	output := fs.String("output", "", "File to write the roster to instead of standard output")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *format != "uscf" {
+		fmt.Fprintf(os.Stderr, "Unsupported --format %q; only uscf is supported.\n",
+			*format)
+		fs.Usage()
 		os.Exit(1)
 	}
+
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", *eventID, err)
+	}
+	var official map[uschess.MemberID]*uschess.Player
+	if *reconcile {
+		var memberIDs []uschess.MemberID
+		for _, entry := range detail.Entries {
+			if entry.UscfID > 0 {
+				memberIDs = append(memberIDs,
+					uschess.MemberID(strconv.Itoa(entry.UscfID)))
+			}
+		}
+		official, err = uschessClient.FetchRatingsOnly(ctx, memberIDs)
+		if err != nil {
+			log.Fatalf("Error fetching official ratings: %v", err)
+		}
+	}
+	roster, err := bcc.BuildUSCFRosterCSV(&detail, official, internal.Now())
+	if err != nil {
+		log.Fatalf("Error building roster for event %d: %v", *eventID, err)
+	}
+	if *output == "" {
+		fmt.Print(roster)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(roster), 0644); err != nil {
+		log.Fatalf("Error writing roster: %v", err)
+	}
+}
+
+func handlePlanBye(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to plan a bye for")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
 	if *eventID <= 0 {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	tourney, err := bcc.GetTournament(int64(*eventID))
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", *eventID, err)
+	}
+	// an event which has not started may not have any pairings to fetch
+	tourney, err := bcc.GetTournament(ctx, int64(*eventID))
+	if err != nil {
+		tourney = nil
+	}
+	fmt.Print(bcc.BuildByePlanOutput(&detail, bcc.PlanByes(&detail, tourney)))
+}
+
+func handleComparePairings(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to compare pairings for")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	posted, err := bcc.GetTournament(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching tournament %d: %v", *eventID, err)
+	}
+	predicted, err := bcc.PredictTournament(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error predicting pairings for event %d: %v", *eventID, err)
+	}
+	fmt.Print(bcc.BuildComparePairingsOutput(posted,
+		bcc.ComparePairings(predicted, posted)))
+}
+
+func handleStandings(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to fetch standings for")
+	csvOut := fs.Bool("csv", false, "Output standings as CSV")
+	prizes := fs.Bool("prizes", false,
+		"Note the prize each player is in line for once standings are final")
+	ratings := fs.Bool("ratings", false,
+		"Note each player's rating change once the event is rated by US Chess")
+	section := fs.String("section", "", "Only show sections matching this name")
+	width := fs.Int("width", 0,
+		"Maximum line width; long names are truncated to fit (0 for no limit)")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *csvOut {
+		tourney, err := bcc.GetTournament(ctx, int64(*eventID))
+		if err != nil {
+			log.Fatalf("Error fetching standings for event %d: %v", *eventID, err)
+		}
+		if err := bcc.CheckStandingsAvailable(tourney); err != nil {
+			log.Fatalf("Error fetching standings for event %d: %v", *eventID, err)
+		}
+		output, err := bcc.BuildStandingsCSV(tourney,
+			standingsTiebreaks(ctx, int64(*eventID)))
+		if err != nil {
+			log.Fatalf("Error formatting standings for event %d: %v", *eventID, err)
+		}
+		fmt.Print(output)
+		return
+	}
+	if *prizes && *ratings {
+		fmt.Fprintln(os.Stderr, "Please provide only one of --prizes and --ratings.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *prizes {
+		printPrizeStandings(ctx, int64(*eventID), *section, *width)
+		return
+	}
+	if *ratings {
+		printRatingStandings(ctx, int64(*eventID), *section, *width)
+		return
+	}
+
+	note, final := bcc.EventStatusOutput(ctx, uschessClient, int64(*eventID))
+	fmt.Print(note)
+	if final != "" {
+		fmt.Print(final)
+		return
+	}
+
+	tourney, err := bcc.GetTournament(ctx, int64(*eventID))
 	if err != nil {
 		log.Fatalf("Error fetching standings for event %d: %v", *eventID, err)
 	}
-	output := bcc.BuildStandingsOutput(tourney)
+	if bcc.CheckStandingsAvailable(tourney) != nil {
+		fmt.Print(bcc.BuildStandingsUnavailableOutput(tourney, *section,
+			*width))
+		return
+	}
+	output := bcc.BuildStandingsOutput(tourney, *section, *width)
 	fmt.Print(output)
 }
 
-func handleCrossTable(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("crosstable", flag.ExitOnError)
+// standingsTiebreaks returns the tiebreaks of the players of an event, as
+// computed from its US Chess crosstables, or nil when the event has not
+// been filed with US Chess or its crosstables cannot be fetched.
+func standingsTiebreaks(ctx context.Context,
+	eventID int64) map[uschess.MemberID]uscfutils.Tiebreaks {
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		log.Printf("Leaving tiebreaks blank; error fetching event %d: %v",
+			eventID, err)
+		return nil
+	}
+	if detail.UscfTid == 0 {
+		log.Printf("Leaving tiebreaks blank; %v has not been filed with US Chess",
+			detail.Title)
+		return nil
+	}
+	t, err := uschessClient.GetCrossTables(ctx,
+		uschess.EventID(strconv.Itoa(detail.UscfTid)))
+	if err != nil {
+		log.Printf("Leaving tiebreaks blank; error fetching cross tables %d: %v",
+			detail.UscfTid, err)
+		return nil
+	}
+
+	return uscfutils.TiebreaksByMember(t)
+}
+
+// printPrizeStandings prints an event's final standings annotated with the
+// prizes advertised in its prize summary.
+func printPrizeStandings(ctx context.Context, eventID int64, section string,
+	width int) {
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", eventID, err)
+	}
+	tourney, err := bcc.GetTournament(ctx, eventID)
+	if err != nil {
+		log.Fatalf("Error fetching standings for event %d: %v", eventID, err)
+	}
+	if !bcc.TournamentOver(&detail, tourney) {
+		fmt.Printf("%v is not over yet; prizes can only be assigned from final standings.\n",
+			detail.Title)
+		return
+	}
+	prizes := bcc.ParsePrizes(&detail)
+	if len(prizes) == 0 {
+		fmt.Printf("No place or class prizes found in the prize summary %q.\n",
+			detail.PrizeSummary)
+		return
+	}
+	fmt.Print(bcc.BuildStandingsOutputWithPrizes(tourney, prizes, section,
+		width))
+}
+
+// printRatingStandings prints an event's final standings annotated with each
+// player's rating change from its US Chess crosstable.
+func printRatingStandings(ctx context.Context, eventID int64, section string,
+	width int) {
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", eventID, err)
+	}
+	if detail.UscfTid == 0 {
+		fmt.Printf("%v has not been filed with US Chess; rating changes are only available once it is rated.\n",
+			detail.Title)
+		return
+	}
+	t, err := uschessClient.GetCrossTables(ctx,
+		uschess.EventID(strconv.Itoa(detail.UscfTid)))
+	if err != nil {
+		log.Fatalf("Error fetching cross tables %d: %v", detail.UscfTid, err)
+	}
+	changes := uscfutils.RatingChangesByMember(t)
+	if len(changes) == 0 {
+		fmt.Printf("%v has not been rated by US Chess yet.\n", detail.Title)
+		return
+	}
+	tourney, err := bcc.GetTournament(ctx, eventID)
+	if err != nil {
+		log.Fatalf("Error fetching standings for event %d: %v", eventID, err)
+	}
+	fmt.Print(bcc.BuildStandingsOutputWithRatings(tourney, changes, section,
+		width))
+}
+
+func handleCrossTable(ctx context.Context, fs *flag.FlagSet, args []string) {
 	tid := fs.Int("uscftid", 0, "USCF Tournament ID")
-	if err := fs.Parse(args); err != nil {
+	eventID := fs.Int("eventid", 0,
+		"BCC Event ID whose USCF tournament to show (instead of --uscftid)")
+	byStandings := fs.Bool("bystandings", false,
+		"Order entries by score and tiebreaks instead of pair number")
+	throughRound := fs.Int("throughround", 0,
+		"Reconstruct standings as of the end of this round")
+	lastRounds := fs.Int("lastrounds", 0,
+		"Only show this many of the most recent rounds (0 for all rounds)")
+	styleName := fs.String("style", "compact",
+		"Result style: compact (e.g. W8(w)) or classic (e.g. W 8)")
+	section := fs.String("section", "", "Only show sections matching this name")
+	combined := fs.Bool("combined", false,
+		"Open with a summary of the whole event and list sections Open first")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *tid <= 0 && *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --uscftid or --eventid ID.")
+		fs.Usage()
 		os.Exit(1)
 	}
-	if *tid <= 0 {
-		fmt.Fprintln(os.Stderr, "Please provide a valid --uscftid ID.")
+	style, err := uscfutils.ParseCrossTableStyle(*styleName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Please provide a valid --style: %v\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *throughRound < 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --throughround round.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *lastRounds < 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --lastrounds count.")
 		fs.Usage()
 		os.Exit(1)
 	}
 
-	t, err := uschessClient.GetTournament(ctx, uschess.EventID(strconv.Itoa(*tid)))
+	uscfTid := uschess.EventID(strconv.Itoa(*tid))
+	if *tid <= 0 {
+		detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+		if err != nil {
+			log.Fatalf("Error fetching event %d: %v", *eventID, err)
+		}
+		uscfTid, err = bcc.ResolveUscfTid(ctx,
+			uschessClient.ClientWithResponses, &detail)
+		if err != nil {
+			log.Fatalf("Error finding the USCF tournament of event %d: %v",
+				*eventID, err)
+		}
+	}
+	var t *uschess.Tournament
+	if *section != "" && !*combined {
+		// spare fetching the standings of the other sections
+		t, err = uschessClient.FetchSectionCrossTable(ctx, uscfTid, *section)
+	}
+	if t == nil {
+		t, err = uschessClient.GetCrossTables(ctx, uscfTid)
+	}
 	if err != nil {
-		log.Fatalf("Error fetching cross tables %d: %v", *tid, err)
+		log.Fatalf("Error fetching cross tables %v: %v", uscfTid, err)
 	}
 
-	for i, xt := range t.SectionStandings {
-		output, _ := uscfutils.BuildCrossTableOutput(t.Sections[i], xt,
-			len(t.SectionStandings) > 1, "")
-		fmt.Print(output)
+	opts := uscfutils.CrossTableOptions{
+		LastRounds: *lastRounds,
+		Style:      style,
+	}
+	if *byStandings {
+		opts.Order = uscfutils.OrderByStandings
+	}
+	if *throughRound > 0 {
+		fmt.Printf("Standings after round %v:\n\n", *throughRound)
+		t = uscfutils.TournamentAfterRound(t, *throughRound)
+		opts.Order = uscfutils.OrderByStandings
+	}
+	if *combined {
+		fmt.Print(uscfutils.BuildCombinedCrossTablesOutput(t, *section, opts))
+		return
 	}
+	fmt.Print(uscfutils.BuildCrossTablesOutput(t, *section, opts))
 }
 
-func handleHistory(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("history", flag.ExitOnError)
-	days := fs.Int("days", 14, "Number of days to retrieve (1-60)")
-	aid := fs.String("uscfaid", internal.BccUSCFAffiliateID, "USCF Affiliate ID")
-	if err := fs.Parse(args); err != nil {
-		os.Exit(1)
+func handleHistory(ctx context.Context, fs *flag.FlagSet, args []string) {
+	days := fs.Int("days", internal.DefaultDays,
+		fmt.Sprintf("Number of days to retrieve (1-%v)", internal.MaxDays))
+	aid := fs.String("uscfaid", internal.BccUSCFAffiliateID,
+		"Comma separated USCF Affiliate IDs")
+	links := fs.Bool("links", false,
+		"Also show each event's section count and US Chess crosstable link")
+	if !parseFlags(ctx, fs, args) {
+		return
 	}
 	if *aid == "" {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --uscfaid ID.")
@@ -258,19 +946,24 @@ func handleHistory(ctx context.Context, args []string) {
 		os.Exit(1)
 	}
 
-	// enforce bounds
-	if *days <= 0 {
-		*days = 14
-	} else if *days > 60 {
-		*days = 60
-	}
+	*days = internal.ClampDays(*days)
 
-	now := time.Now()
-	end := now.AddDate(0, 0, -*days)
+	end := internal.Now().AddDate(0, 0, -*days)
 
-	events, err := uschessClient.GetAllAffiliateRatedEvents(ctx, uschess.AffiliateID(*aid), nil)
+	aids := make([]uschess.AffiliateID, 0)
+	for _, a := range strings.Split(*aid, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			aids = append(aids, uschess.AffiliateID(a))
+		}
+	}
+	events, err := uscfutils.GetMultiAffiliateEvents(ctx,
+		uschessClient.ClientWithResponses, aids, end)
 	if err != nil {
-		log.Fatalf("Error fetching events for aid:%v: %v", *aid, err)
+		if len(events) == 0 {
+			log.Fatalf("Error fetching events for aid:%v: %v", *aid, err)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: some affiliates could not be fetched: %v\n",
+			err)
 	}
 
 	// Filter and group events by date
@@ -300,19 +993,79 @@ func handleHistory(ctx context.Context, args []string) {
 		fmt.Println(d)
 		for _, ev := range eventsByDate[d] {
 			fmt.Printf("  - %s (uscftid:%v)\n", ev.Name, ev.Id)
+			if *links {
+				fmt.Printf("    %v section(s): %v\n", ev.SectionCount,
+					uscfutils.CrossTableURL(ev.Id))
+			}
 		}
 	}
 	fmt.Printf("\nRun '%s crosstable --uscftid ID' to get results from a specific event\n",
 		os.Args[0])
 }
 
-func handlePlayer(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("player", flag.ExitOnError)
+func handleRecent(ctx context.Context, fs *flag.FlagSet, args []string) {
+	count := fs.Int("count", 5,
+		fmt.Sprintf("Number of events to show (1-%v)", uscfutils.MaxRecentEvents))
+	aid := fs.String("uscfaid", internal.BccUSCFAffiliateID,
+		"USCF Affiliate ID")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *aid == "" {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --uscfaid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	*count = min(max(*count, 1), uscfutils.MaxRecentEvents)
+
+	events, err := uscfutils.GetRecentEventWinners(ctx,
+		uschessClient.ClientWithResponses, uschess.AffiliateID(*aid), *count)
+	if err != nil {
+		log.Fatalf("Error fetching recent events for aid:%v: %v", *aid, err)
+	}
+
+	fmt.Print(uscfutils.BuildRecentEventsOutput(events))
+}
+
+func handleGainers(ctx context.Context, fs *flag.FlagSet, args []string) {
+	tid := fs.Int("uscftid", 0, "USCF Tournament ID")
+	count := fs.Int("count", uscfutils.DefaultGainersCount,
+		"Number of gainers and losers to show")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *tid <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --uscftid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *count <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --count.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	t, err := uschessClient.GetCrossTables(ctx, uschess.EventID(strconv.Itoa(*tid)))
+	if err != nil {
+		log.Fatalf("Error fetching cross tables %d: %v", *tid, err)
+	}
+	changes, notYetRated := uscfutils.RatingChanges(t)
+	fmt.Print(uscfutils.BuildGainersOutput(changes, notYetRated, *count))
+}
+
+func handlePlayer(ctx context.Context, fs *flag.FlagSet, args []string) {
 	memberID := fs.Int("id", 0, "USCF member id")
 	eventCount := fs.Int("eventcount", 3,
-		"Number of recent crosstables to retrieve (0-5)")
-	if err := fs.Parse(args); err != nil {
-		os.Exit(1)
+		fmt.Sprintf("Number of recent crosstables to retrieve (0-%v)",
+			uscfutils.MaxPlayerReportEventCount))
+	recordCount := fs.Int("recordcount", uscfutils.DefaultRecordEventCount,
+		fmt.Sprintf("Number of recent events to tally the player's record over (1-%v)",
+			uscfutils.MaxRecordEventCount))
+	asJSON := fs.Bool("json", false, "Output the report as JSON")
+	forfeits := fs.Bool("forfeits", false,
+		"Count games decided by forfeit in the record, average opposition and performance ratings")
+	if !parseFlags(ctx, fs, args) {
+		return
 	}
 	if *memberID == 0 {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --id <USCF member id>")
@@ -323,34 +1076,52 @@ func handlePlayer(ctx context.Context, args []string) {
 	// enforce bounds
 	if *eventCount < 0 {
 		*eventCount = 1
-	} else if *eventCount > 5 {
-		*eventCount = 5
+	} else if *eventCount > uscfutils.MaxPlayerReportEventCount {
+		*eventCount = uscfutils.MaxPlayerReportEventCount
 	}
+	*recordCount = min(max(*recordCount, 1), uscfutils.MaxRecordEventCount)
 
-	report, err := uscfutils.BuildPlayerReport(ctx, uschessClient,
-		uschess.MemberID(strconv.Itoa(*memberID)),
-		*eventCount)
+	opts := uscfutils.PlayerReportOptions{}
+	if *forfeits {
+		opts.Forfeits = uscfutils.IncludeForfeits
+	}
+	report, err := uscfutils.GetPlayerReportDataWithOptions(ctx,
+		uschessClient, uschess.MemberID(strconv.Itoa(*memberID)), *eventCount,
+		*recordCount, opts)
 	if err != nil {
 		log.Fatalf("Error fetching player %v: %v", *memberID, err)
 	}
 
-	fmt.Printf("%v", report)
+	if *asJSON {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("Error encoding player %v: %v", *memberID, err)
+		}
+		fmt.Printf("%s\n", out)
+		return
+	}
+	fmt.Printf("%v", uscfutils.BuildPlayerReportOutput(report, false))
 }
 
-func handleEstRating(ctx context.Context, args []string) {
-	fs := flag.NewFlagSet("estrating", flag.ExitOnError)
+func handleEstRating(ctx context.Context, fs *flag.FlagSet, args []string) {
 	score := fs.Float64("score", 0, "Score")
 	memberID := fs.Int("id", 0, "USCF member id")
-	if err := fs.Parse(args); err != nil {
-		os.Exit(1)
+	age := fs.Int("age", 0,
+		"Age of an unrated player; estimates from an age based initial rating instead of --id")
+	unratedRating := fs.Int("unrated", 0,
+		"Count unrated opponents at this rating for an approximate estimate")
+	eventID := fs.Int("eventid", 0,
+		"Count unrated opponents at the rating they entered this event with for an approximate estimate")
+	if !parseFlags(ctx, fs, args) {
+		return
 	}
 	if *score == 0.0 {
 		fmt.Fprintln(os.Stderr, "Please provide a valid --score <score>")
 		fs.Usage()
 		os.Exit(1)
 	}
-	if *memberID == 0 {
-		fmt.Fprintln(os.Stderr, "Please provide a valid --id <USCF member id>")
+	if *memberID == 0 && *age <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --id <USCF member id> or --age <age>")
 		fs.Usage()
 		os.Exit(1)
 	}
@@ -367,6 +1138,37 @@ func handleEstRating(ctx context.Context, args []string) {
 		opponentIds = append(opponentIds, uschess.MemberID(strconv.FormatInt(r, 10)))
 	}
 
+	if *memberID == 0 {
+		newRating, err := uscfutils.GetRatingEstimateForUnrated(ctx,
+			uschessClient.ClientWithResponses, *age, opponentIds, *score)
+		if err != nil {
+			log.Fatalf("Failed to estimate: %v\n", err)
+		}
+		fmt.Printf("Initial Rating (age %v): %v\n", *age,
+			uscfutils.InitialRatingForAge(*age))
+		fmt.Printf("Estimated New Rating: %v\n", newRating)
+		return
+	}
+
+	if *unratedRating > 0 || *eventID > 0 {
+		subs := uscfutils.UnratedOpponentRatings{Default: *unratedRating}
+		if *eventID > 0 {
+			detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+			if err != nil {
+				log.Fatalf("Error fetching event %v: %v", *eventID, err)
+			}
+			subs.Reported = bcc.EntryRatingsByID(&detail)
+		}
+		est, err := uscfutils.GetApproxRatingEstimate(ctx,
+			uschessClient.ClientWithResponses,
+			uschess.MemberID(strconv.Itoa(*memberID)), opponentIds, *score, subs)
+		if err != nil {
+			log.Fatalf("Failed to estimate: %v\n", err)
+		}
+		fmt.Print(uscfutils.BuildApproxRatingEstimateOutput(est))
+		return
+	}
+
 	newRating, err := uschessClient.GetRatingEstimate(ctx,
 		uschess.MemberID(strconv.Itoa(*memberID)), opponentIds, *score, uschess.RatingTypeR)
 	if err != nil {
@@ -374,3 +1176,249 @@ func handleEstRating(ctx context.Context, args []string) {
 	}
 	fmt.Printf("Estimated New Rating: %v\n", newRating.PostRating)
 }
+
+func handleTarget(ctx context.Context, fs *flag.FlagSet, args []string) {
+	memberID := fs.Int("id", 0, "USCF member id")
+	opp := fs.String("opp", "", "Comma separated opponent USCF member ids")
+	goal := fs.Int("goal", 0, "Target post-event rating")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *memberID == 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --id <USCF member id>")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *goal <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --goal <rating>")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// collect opponent USCF ids
+	opponentIds := make([]uschess.MemberID, 0)
+	for _, oppUscfId := range strings.Split(*opp, ",") {
+		oppUscfId = strings.TrimSpace(oppUscfId)
+		if oppUscfId == "" {
+			continue
+		}
+		r, err := strconv.ParseInt(oppUscfId, 10, 64)
+		if err != nil {
+			log.Fatalf("Failed to parse opponent USCF id '%v': %v\n", oppUscfId,
+				err)
+		}
+		opponentIds = append(opponentIds, uschess.MemberID(strconv.FormatInt(r, 10)))
+	}
+	if len(opponentIds) == 0 {
+		fmt.Fprintln(os.Stderr, "Please provide at least one --opp <USCF member id>")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	score, achievable, err := uscfutils.ScoreNeededForRating(ctx,
+		uschessClient.ClientWithResponses, uschess.MemberID(strconv.Itoa(*memberID)), opponentIds, *goal)
+	if err != nil {
+		log.Fatalf("Failed to estimate: %v\n", err)
+	}
+	if !achievable {
+		fmt.Printf("A rating of %v is not reachable against these opponents, even with a perfect score of %v\n",
+			*goal, internal.ScoreToString(score))
+		return
+	}
+	fmt.Printf("Minimum score needed to reach %v: %v/%v\n", *goal,
+		internal.ScoreToString(score), len(opponentIds))
+}
+
+func handleExpect(ctx context.Context, fs *flag.FlagSet, args []string) {
+	memberID := fs.Int("id", 0, "USCF member id")
+	eventID := fs.Int("eventid", 0, "Event ID whose field to score against")
+	section := fs.String("section", "", "Section to score against (default the player's section, or every section)")
+	rounds := fs.Int("rounds", 0, "Number of rounds (default the event's advertised number of rounds)")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *memberID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --id <USCF member id>")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", *eventID, err)
+	}
+	// entered players are scored at their entry's rating
+	rating := 0
+	if !slices.ContainsFunc(detail.Entries, func(e bcc.Entry) bool {
+		return e.UscfID == *memberID
+	}) {
+		ratings, err := uscfutils.RegularLiveRatings(ctx,
+			uschessClient.ClientWithResponses,
+			[]uschess.MemberID{uschess.MemberID(strconv.Itoa(*memberID))})
+		if err != nil {
+			log.Fatalf("Error fetching rating of %v: %v", *memberID, err)
+		}
+		rating = ratings[0]
+	}
+
+	est, err := bcc.EstimateEventScore(&detail, *memberID, rating, *section,
+		*rounds)
+	if err != nil {
+		log.Fatalf("Failed to estimate: %v\n", err)
+	}
+	fmt.Print(bcc.BuildExpectedScoreOutput(est))
+}
+
+func handleSectionCuts(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to fetch entries for")
+	sections := fs.Int("sections", 0, "Number of sections to split entries into (default the event's sections, or 3)")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *sections != 0 && *sections < 2 {
+		fmt.Fprintln(os.Stderr, "Please provide at least 2 --sections.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+	if err != nil {
+		log.Fatalf("Error fetching event %d: %v", *eventID, err)
+	}
+	if *sections == 0 {
+		*sections = 3
+		if advertised := len(bcc.ParseSections(&detail)); advertised >= 2 {
+			*sections = advertised
+		}
+	}
+	cuts := bcc.SuggestSectionCuts(detail.Entries, *sections)
+	fmt.Print(bcc.BuildSectionCutsOutput(detail.Entries, cuts))
+}
+
+func handleBands(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to fetch entries for")
+	roster := fs.Bool("roster", false, "Use the active member roster instead of an event's entries")
+	since := fs.String("since", "", "With --roster, count members active in club events since this date (YYYY-MM-DD) instead of the built-in roster")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	var sinceDate time.Time
+	if *since != "" {
+		var err error
+		sinceDate, err = time.Parse(time.DateOnly, *since)
+		if err != nil || !*roster {
+			fmt.Fprintln(os.Stderr, "Please provide --since as YYYY-MM-DD along with --roster.")
+			fs.Usage()
+			os.Exit(1)
+		}
+	}
+	if (*eventID <= 0) == !*roster {
+		fmt.Fprintln(os.Stderr, "Please provide either a valid --eventid ID or --roster.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var ratings []int
+	if *roster {
+		memberIDs := bcc.ActivePlayerMemIds()
+		if !sinceDate.IsZero() {
+			memberIDs = bcc.ActiveMemIdsSince(ctx, uschessClient, sinceDate)
+		}
+		var err error
+		ratings, err = uscfutils.RegularLiveRatings(ctx,
+			uschessClient.ClientWithResponses, memberIDs)
+		if err != nil {
+			log.Fatalf("Error fetching roster ratings: %v", err)
+		}
+	} else {
+		detail, err := bcc.GetEventDetail(ctx, int64(*eventID))
+		if err != nil {
+			log.Fatalf("Error fetching event %d: %v", *eventID, err)
+		}
+		ratings = bcc.EntryRatings(&detail)
+	}
+
+	fmt.Print(bcc.BuildRatingBandsOutput(ratings))
+}
+
+func handleAttendance(ctx context.Context, fs *flag.FlagSet, args []string) {
+	days := fs.Int("days", internal.DefaultAttendanceDays,
+		fmt.Sprintf("Number of past days to compare (1-%v)",
+			internal.MaxAttendanceDays))
+	bySeries := fs.Bool("series", false,
+		"Group events by series (e.g. Tuesday Night Swiss)")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	*days = internal.ClampAttendanceDays(*days)
+
+	// the window ends today, but today's events are excluded as they may not
+	// have finished
+	start, today := bcc.CalWindow(-*days)
+
+	events, err := bcc.GetEvents(ctx)
+	if err != nil {
+		log.Fatalf("Error fetching events: %v", err)
+	}
+	past := make([]bcc.Event, 0, len(events))
+	for _, ev := range events {
+		if ev.Date.Before(start) || !ev.Date.Before(today) {
+			continue
+		}
+		past = append(past, ev)
+	}
+
+	fmt.Printf("Attendance over the last %d days:\n\n", *days)
+	fmt.Print(bcc.BuildAttendanceOutput(bcc.GetAttendance(ctx, past), *bySeries))
+}
+
+func handleConfig(ctx context.Context, fs *flag.FlagSet, args []string) {
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+
+	fmt.Print(internal.GetConfig())
+}
+
+func handleCacheClear(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID whose cached pages should be cleared")
+	tid := fs.Int("uscftid", 0, "USCF Tournament ID whose cached pages should be cleared")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 && *tid <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID and/or --uscftid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var keys []string
+	if *eventID > 0 {
+		keys = append(keys, bcc.EventCacheKeys(int64(*eventID))...)
+	}
+	if *tid > 0 {
+		tidKeys, err := uscfutils.TournamentCacheKeys(ctx,
+			uschess.EventID(strconv.Itoa(*tid)))
+		if err != nil {
+			log.Fatalf("Error determining cached pages for uscftid %d: %v", *tid, err)
+		}
+		keys = append(keys, tidKeys...)
+	}
+
+	if err := httpcache.ClearCacheKeys(ctx, keys); err != nil {
+		log.Fatalf("Error clearing cache: %v", err)
+	}
+	for _, key := range keys {
+		fmt.Printf("cleared %v\n", key)
+	}
+}
@@ -0,0 +1,61 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestCommandHelp(t *testing.T) {
+	for name, cmd := range commands {
+		help, err := commandHelp(name)
+		if err != nil {
+			t.Errorf("commandHelp(%v) err = %v", name, err)
+			continue
+		}
+		if !strings.HasPrefix(help, "Usage: bcctd "+name) {
+			t.Errorf("commandHelp(%v) missing usage line:\n%v", name, help)
+		}
+		if cmd.summary == "" || len(cmd.examples) == 0 {
+			t.Errorf("command %v is missing a summary or examples", name)
+		}
+
+		// every flag an example uses must be one the handler defines
+		fs := flag.NewFlagSet(name, flag.ContinueOnError)
+		cmd.handler(context.WithValue(context.Background(), describeOnlyKey{},
+			true), fs, nil)
+		for _, example := range cmd.examples {
+			if !strings.HasPrefix(example, "bcctd "+name) {
+				t.Errorf("command %v example %q is for another command", name,
+					example)
+			}
+			for _, field := range strings.Fields(example) {
+				flagName, ok := strings.CutPrefix(field, "--")
+				if ok && fs.Lookup(flagName) == nil {
+					t.Errorf("command %v example %q uses undefined flag --%v",
+						name, example, flagName)
+				}
+			}
+		}
+	}
+
+	help, err := commandHelp("pairings")
+	if err != nil {
+		t.Fatalf("commandHelp(pairings) err = %v", err)
+	}
+	for _, want := range []string{"Flags:\n", "  -eventid int\n", "  -round int\n",
+		"Examples:\n  bcctd pairings --eventid 1234\n"} {
+
+		if !strings.Contains(help, want) {
+			t.Errorf("commandHelp(pairings) missing %q:\n%v", want, help)
+		}
+	}
+	if _, err := commandHelp("nosuchcommand"); err == nil {
+		t.Errorf("commandHelp(nosuchcommand) err = nil")
+	}
+}
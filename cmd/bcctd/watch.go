@@ -0,0 +1,100 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+)
+
+// avoid hammering the BCC website and API when watching an event
+const watchMinInterval = 15 * time.Second
+
+const clearScreen = "\033[H\033[2J"
+
+func handleWatch(ctx context.Context, fs *flag.FlagSet, args []string) {
+	eventID := fs.Int("eventid", 0, "Event ID to watch")
+	interval := fs.Duration("interval", 30*time.Second,
+		"How often to refresh pairings and standings")
+	section := fs.String("section", "", "Only show sections matching this name")
+	width := fs.Int("width", 0,
+		"Maximum line width; long names are truncated to fit (0 for no limit)")
+	if !parseFlags(ctx, fs, args) {
+		return
+	}
+	if *eventID <= 0 {
+		fmt.Fprintln(os.Stderr, "Please provide a valid --eventid ID.")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *interval < watchMinInterval {
+		fmt.Fprintf(os.Stderr, "Using the minimum interval of %v.\n",
+			watchMinInterval)
+		*interval = watchMinInterval
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		if watchOnce(ctx, int64(*eventID), *section, *width) {
+			fmt.Println("Event is over; no further updates expected.")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchOnce reprints an event's current round and reports whether the event
+// is over. While the round is in progress its pairings are shown, and once
+// every result is in, the standings; both are shown when no round's
+// pairings are posted.
+func watchOnce(ctx context.Context, eventID int64, section string,
+	width int) bool {
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching event %d: %v\n", eventID, err)
+		return false
+	}
+	tourney, err := bcc.GetTournament(ctx, eventID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching event %d: %v\n", eventID, err)
+		return false
+	}
+
+	fmt.Print(clearScreen)
+	fmt.Printf("%v (updated %v)\n\n", detail.Title,
+		time.Now().Format(time.Kitchen))
+	switch header := bcc.RoundStatusHeader(tourney); {
+	case header == "":
+		fmt.Print(bcc.BuildPairingsOutput(tourney, false, section, width))
+		fmt.Print(bcc.BuildGameLinksOutput(tourney, section))
+		fmt.Print("\n")
+		fmt.Print(bcc.BuildStandingsOutput(tourney, section, width))
+	case bcc.IsRoundComplete(tourney):
+		fmt.Printf("%v\n\n", header)
+		fmt.Print(bcc.BuildStandingsOutput(tourney, section, width))
+	default:
+		fmt.Printf("%v\n\n", header)
+		fmt.Print(bcc.BuildPairingsOutput(tourney, false, section, width))
+		fmt.Print(bcc.BuildGameLinksOutput(tourney, section))
+	}
+
+	return bcc.TournamentOver(&detail, tourney)
+}
@@ -17,7 +17,7 @@ import (
 )
 
 // this program exists just to seed the http cache for bcc members
-var uschessClient *uschess.ClientWithResponses
+var uschessClient *uscfutils.Client
 
 func main() {
 	ctx := context.Background()
@@ -27,6 +27,7 @@ func main() {
 	if err != nil {
 		return
 	}
+	defer uschessClient.Close()
 
 	for _, memId := range bcc.ActivePlayerMemIds() {
 		player, err := uschessClient.GetPlayer(ctx, memId, nil)
@@ -0,0 +1,110 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
+)
+
+// AnnounceChannelEnv names the environment variable which holds the id of
+// the discord channel that calendar changes are announced in.
+const AnnounceChannelEnv = internal.AnnounceChannelEnv
+
+// announceInterval is how often the calendar is checked for changes to
+// announce.
+const announceInterval = 30 * time.Minute
+
+// runEventAnnouncer announces calendar changes in the channel named by
+// AnnounceChannelEnv every announceInterval until ctx is done. It does
+// nothing when no channel is configured or the snapshot store is
+// unavailable.
+func runEventAnnouncer(ctx context.Context) {
+	channelID := internal.GetConfig().AnnounceChannel
+	if channelID == "" {
+		return
+	}
+	store, err := httpcache.NewS3Store(ctx)
+	if err != nil {
+		log.Printf("discordbot.announce: not announcing calendar changes: %v",
+			err)
+		return
+	}
+	log.Printf("discordbot.announce: announcing calendar changes in %v every %v",
+		channelID, announceInterval)
+
+	ticker := time.NewTicker(announceInterval)
+	defer ticker.Stop()
+	for {
+		announceEventChanges(ctx, store, channelID)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// announceEventChanges posts the calendar changes since the last check to
+// channelID. The first check only records the calendar so that every
+// listed event is not announced as new.
+func announceEventChanges(ctx context.Context,
+	store bcc.SnapshotStore, channelID string) {
+
+	diff, first, err := bcc.CheckEventChanges(ctx, store, internal.Now())
+	if err != nil {
+		log.Printf("discordbot.announce: %v", err)
+		return
+	}
+	if first {
+		log.Printf("discordbot.announce: recorded initial calendar snapshot")
+		return
+	}
+	if diff.Empty() {
+		return
+	}
+
+	for _, msg := range splitMessage(bcc.BuildEventsDiffOutput(diff)) {
+		err = threadSender(channelID, &discordgo.MessageSend{
+			Content: msg,
+			// avoid a preview embed per event link
+			Flags: discordgo.MessageFlagsSuppressEmbeds,
+		})
+		if err != nil {
+			log.Printf("discordbot.announce: failed to post to %v: %v",
+				channelID, err)
+			return
+		}
+	}
+}
+
+// splitMessage splits s on line boundaries into messages that each fit
+// within discordMsgLimit.
+func splitMessage(s string) []string {
+	var msgs []string
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		line, _ = truncateContent(line)
+		if sb.Len() > 0 &&
+			discordLen(sb.String())+discordLen(line) > discordMsgLimit {
+
+			msgs = append(msgs, sb.String())
+			sb.Reset()
+		}
+		sb.WriteString(line)
+	}
+	if sb.Len() > 0 {
+		msgs = append(msgs, sb.String())
+	}
+
+	return msgs
+}
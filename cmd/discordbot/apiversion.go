@@ -0,0 +1,30 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/api"
+)
+
+// apiVersionHandler reports the version of the JSON contract of the bot's
+// read-only endpoints, so that external consumers can check they understand
+// its responses.
+func apiVersionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(api.VersionResponse{Version: api.Version})
+	if err != nil {
+		log.Printf("discordbot.api: writing version: %v", err)
+	}
+}
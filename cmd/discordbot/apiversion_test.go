@@ -0,0 +1,31 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIVersionHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	apiVersionHandler(rec, httptest.NewRequest(http.MethodGet,
+		"/DiscordBot/api/version", nil))
+	if rec.Code != http.StatusOK ||
+		rec.Header().Get("Content-Type") != "application/json" ||
+		rec.Body.String() != "{\"version\":1}\n" {
+
+		t.Errorf("GET = %v %q %q", rec.Code, rec.Header().Get("Content-Type"),
+			rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	apiVersionHandler(rec, httptest.NewRequest(http.MethodPost,
+		"/DiscordBot/api/version", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %v; want %v", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
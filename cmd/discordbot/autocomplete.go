@@ -0,0 +1,135 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// discord discards autocomplete responses which take longer than 3s
+const autocompleteTimeout = 2500 * time.Millisecond
+
+// the most matches listed when a typed name is ambiguous
+const maxNameMatchesShown = 10
+
+// memberSearcher searches US Chess members by name. It is a variable so
+// that tests need not reach US Chess.
+var memberSearcher = func(ctx context.Context,
+	query string) ([]uschess.MemberDetail, error) {
+
+	return uschessClient.SearchMembers(ctx, query)
+}
+
+// autocompleteHandler suggests values for the option a user is typing. Only
+// the player command's name option is autocompleted; its suggestions are
+// the members matching the name typed so far, whose values are their
+// member ids.
+func autocompleteHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{},
+	}
+	data := inter.ApplicationCommandData()
+	if len(data.Options) == 0 ||
+		TdSubCommand(data.Options[0].Name) != TdPlayerCmd {
+
+		return resp
+	}
+	for _, opt := range data.Options[0].Options {
+		if opt.Focused && opt.Name == "name" {
+			resp.Data.Choices = memberChoices(ctx, opt.StringValue())
+		}
+	}
+
+	return resp
+}
+
+// memberChoices returns the members matching a partially typed name as
+// autocomplete choices.
+func memberChoices(ctx context.Context,
+	query string) []*discordgo.ApplicationCommandOptionChoice {
+
+	ctx, cancel := context.WithTimeout(ctx, autocompleteTimeout)
+	defer cancel()
+	members, err := memberSearcher(ctx, query)
+	if err != nil {
+		log.Printf("discordbot.autocomplete: searching for %q: %v", query, err)
+		return nil
+	}
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0,
+		len(members))
+	for _, m := range members {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  memberChoiceName(m),
+			Value: string(m.Id),
+		})
+	}
+
+	return choices
+}
+
+func memberChoiceName(m uschess.MemberDetail) string {
+	name := internal.NormalizeName(m.FirstName + " " + m.LastName)
+	if m.StateRep != "" {
+		return fmt.Sprintf("%v (%v, %v)", name, m.Id, m.StateRep)
+	}
+
+	return fmt.Sprintf("%v (%v)", name, m.Id)
+}
+
+// resolveMemberName returns the member id of the name option of a command.
+// A suggestion chosen from autocomplete is already a member id; a name
+// typed without choosing one is searched for and must match exactly one
+// member. Otherwise msg explains why no member id was resolved.
+func resolveMemberName(ctx context.Context,
+	name string) (memID int64, msg string) {
+
+	name = strings.TrimSpace(name)
+	if id, err := strconv.ParseInt(name, 10, 64); err == nil {
+		return id, ""
+	}
+	if len(name) < uscfutils.MinMemberSearchLen {
+		return 0, fmt.Sprintf("Please provide at least %v characters of the player's name.",
+			uscfutils.MinMemberSearchLen)
+	}
+
+	members, err := memberSearcher(ctx, name)
+	if err != nil {
+		return 0, fmt.Sprintf("Error searching for %q: %v", name, err)
+	}
+	switch len(members) {
+	case 0:
+		return 0, fmt.Sprintf("No US Chess members match %q.", name)
+	case 1:
+		id, err := strconv.ParseInt(string(members[0].Id), 10, 64)
+		if err != nil {
+			return 0, fmt.Sprintf("Invalid member id %q for %q.",
+				members[0].Id, name)
+		}
+		return id, ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%q matches several members; please choose one of the suggestions:\n",
+		name))
+	for _, m := range members[:min(len(members), maxNameMatchesShown)] {
+		sb.WriteString(fmt.Sprintf("  %v\n", memberChoiceName(m)))
+	}
+
+	return 0, sb.String()
+}
@@ -0,0 +1,80 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestAutocompleteMemberName(t *testing.T) {
+	members := []uschess.MemberDetail{
+		{Id: "12345678", FirstName: "ALICE", LastName: "SMITH", StateRep: "MA"},
+		{Id: "12345679", FirstName: "Alicia", LastName: "Smithers"},
+	}
+	var queries []string
+	origSearcher := memberSearcher
+	memberSearcher = func(ctx context.Context,
+		query string) ([]uschess.MemberDetail, error) {
+
+		queries = append(queries, query)
+		if strings.HasPrefix(query, "alice s") {
+			return members[:1], nil
+		}
+		return members, nil
+	}
+	defer func() { memberSearcher = origSearcher }()
+
+	inter := &discordgo.Interaction{
+		Type: discordgo.InteractionApplicationCommandAutocomplete,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: string(TdPlayerCmd),
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{
+							Name:    "name",
+							Type:    discordgo.ApplicationCommandOptionString,
+							Value:   "ali",
+							Focused: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resp := autocompleteHandler(context.Background(), inter)
+	if resp.Type != discordgo.InteractionApplicationCommandAutocompleteResult ||
+		len(resp.Data.Choices) != 2 {
+		t.Fatalf("autocompleteHandler() = %+v", resp)
+	}
+	if c := resp.Data.Choices[0]; c.Name != "Alice Smith (12345678, MA)" ||
+		c.Value != "12345678" {
+		t.Errorf("unexpected choice %+v", c)
+	}
+
+	// a chosen suggestion is the member id itself
+	if id, msg := resolveMemberName(context.Background(),
+		"12345679"); id != 12345679 || msg != "" {
+		t.Errorf("resolveMemberName() = %v, %q", id, msg)
+	}
+	if id, msg := resolveMemberName(context.Background(),
+		"alice smith"); id != 12345678 || msg != "" {
+		t.Errorf("resolveMemberName() = %v, %q", id, msg)
+	}
+	if id, msg := resolveMemberName(context.Background(),
+		"alic"); id != 0 || !strings.Contains(msg, "Alicia Smithers (12345679)") {
+		t.Errorf("resolveMemberName() = %v, %q", id, msg)
+	}
+	if len(queries) != 3 {
+		t.Errorf("searched %q; want 3 searches", queries)
+	}
+}
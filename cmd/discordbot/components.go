@@ -0,0 +1,343 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Message component custom ids take the form
+// <prefix>:<subcommand>:<name>=<type><value>,<name>=<type><value>...
+// so that the originating command can be rebuilt from the id alone. type is
+// a single character identifying the option type and value is query escaped.
+const (
+	customIdSep     = ":"
+	customIdArgSep  = ","
+	customIdKeyVal  = "="
+	shareCustomId   = "share"
+	pageCustomId    = "page"
+	maxCustomIdSize = 100 // discord limit
+	maxContentPages = 10
+)
+
+var optTypeTags = map[discordgo.ApplicationCommandOptionType]byte{
+	discordgo.ApplicationCommandOptionString:  's',
+	discordgo.ApplicationCommandOptionInteger: 'i',
+	discordgo.ApplicationCommandOptionBoolean: 'b',
+	discordgo.ApplicationCommandOptionNumber:  'n',
+}
+
+var componentHdlrs = map[string]CmdHandler{
+	shareCustomId: shareComponentHandler,
+	pageCustomId:  pageComponentHandler,
+}
+
+// componentHandler dispatches message component interactions (e.g. button
+// clicks) based on the prefix of the component's custom id.
+func componentHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	customId := inter.MessageComponentData().CustomID
+	prefix, _, _ := strings.Cut(customId, customIdSep)
+	hdlr, ok := componentHdlrs[prefix]
+	if !ok {
+		resp := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("unknown component '%v'", customId),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		}
+		log.Printf("discordbot.component: %v", resp.Data.Content)
+		return resp
+	}
+
+	return hdlr(ctx, inter)
+}
+
+// buildCustomId encodes a component prefix, the td subcommand and its options
+// into a component custom id. broadcast is kept only for page ids, so that
+// paging a broadcast message keeps it public; a share always broadcasts.
+func buildCustomId(prefix string, subCmd TdSubCommand,
+	opts []*discordgo.ApplicationCommandInteractionDataOption) string {
+
+	args := make([]string, 0, len(opts))
+	for _, opt := range opts {
+		tag, ok := optTypeTags[opt.Type]
+		if !ok || opt.Name == "thread" ||
+			(opt.Name == "broadcast" && prefix != pageCustomId) {
+			continue
+		}
+		args = append(args, fmt.Sprintf("%v%v%c%v", opt.Name, customIdKeyVal,
+			tag, url.QueryEscape(fmt.Sprint(opt.Value))))
+	}
+
+	return strings.Join([]string{prefix, string(subCmd),
+		strings.Join(args, customIdArgSep)}, customIdSep)
+}
+
+// parseCustomId decodes a custom id produced by buildCustomId back into a td
+// subcommand and its options.
+func parseCustomId(customId string) (TdSubCommand,
+	[]*discordgo.ApplicationCommandInteractionDataOption, error) {
+
+	parts := strings.SplitN(customId, customIdSep, 3)
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("malformed component id %q", customId)
+	}
+	subCmd := TdSubCommand(parts[1])
+	opts := make([]*discordgo.ApplicationCommandInteractionDataOption, 0)
+	if parts[2] == "" {
+		return subCmd, opts, nil
+	}
+	for _, arg := range strings.Split(parts[2], customIdArgSep) {
+		name, tagged, ok := strings.Cut(arg, customIdKeyVal)
+		if !ok || tagged == "" {
+			return "", nil, fmt.Errorf("malformed component id %q", customId)
+		}
+		val, err := url.QueryUnescape(tagged[1:])
+		if err != nil {
+			return "", nil, fmt.Errorf("malformed component id %q: %w",
+				customId, err)
+		}
+		opt := &discordgo.ApplicationCommandInteractionDataOption{Name: name}
+		switch tagged[0] {
+		case 's':
+			opt.Type = discordgo.ApplicationCommandOptionString
+			opt.Value = val
+		case 'b':
+			opt.Type = discordgo.ApplicationCommandOptionBoolean
+			opt.Value, err = strconv.ParseBool(val)
+		case 'i':
+			opt.Type = discordgo.ApplicationCommandOptionInteger
+			opt.Value, err = strconv.ParseFloat(val, 64)
+		case 'n':
+			opt.Type = discordgo.ApplicationCommandOptionNumber
+			opt.Value, err = strconv.ParseFloat(val, 64)
+		default:
+			err = fmt.Errorf("unknown option type %q", tagged[0])
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("malformed component id %q: %w",
+				customId, err)
+		}
+		opts = append(opts, opt)
+	}
+
+	return subCmd, opts, nil
+}
+
+// addShareButton attaches a "Share to channel" button to an ephemeral
+// response so the user can re-post it to the channel after the fact.
+func addShareButton(resp *discordgo.InteractionResponse, subCmd TdSubCommand,
+	inter *discordgo.Interaction) {
+
+	customId := buildCustomId(shareCustomId, subCmd, subCmdOptions(inter))
+	if len(customId) > maxCustomIdSize {
+		return
+	}
+
+	resp.Data.Components = append(resp.Data.Components, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Share to channel",
+				Style:    discordgo.SecondaryButton,
+				CustomID: customId,
+			},
+		},
+	})
+}
+
+// shareComponentHandler handles a click on a "Share to channel" button by
+// re-running the original td subcommand and posting its output publicly. As
+// for the command itself, a slow subcommand's response is deferred.
+func shareComponentHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+
+	subCmd, opts, err := parseCustomId(inter.MessageComponentData().CustomID)
+	if err != nil {
+		resp.Data.Content = fmt.Sprintf("Unable to share: %v", err)
+		log.Printf("discordbot.share: %v", resp.Data.Content)
+		return resp
+	}
+	hdlr, ok := tdSubCmdHdlrs[subCmd]
+	if !ok {
+		resp.Data.Content = fmt.Sprintf("Unable to share: unknown command '%v'",
+			subCmd)
+		log.Printf("discordbot.share: %v", resp.Data.Content)
+		return resp
+	}
+
+	opts = append(opts, &discordgo.ApplicationCommandInteractionDataOption{
+		Name:  "broadcast",
+		Type:  discordgo.ApplicationCommandOptionBoolean,
+		Value: true,
+	})
+	subInter := subCmdInteraction(inter, subCmd, opts)
+	if deferredSubCmds[subCmd] {
+		return deferResponse(subInter, hdlr)
+	}
+
+	return hdlr(ctx, subInter)
+}
+
+// subCmdOptions returns the options passed to the td subcommand of an
+// application command interaction.
+func subCmdOptions(
+	inter *discordgo.Interaction) []*discordgo.ApplicationCommandInteractionDataOption {
+
+	data := inter.ApplicationCommandData()
+	if len(data.Options) == 0 {
+		return nil
+	}
+	return data.Options[0].Options
+}
+
+// subCmdInteraction builds a synthetic td application command interaction
+// from a message component interaction so that the td subcommand handlers
+// can be reused to rebuild a response.
+func subCmdInteraction(inter *discordgo.Interaction, subCmd TdSubCommand,
+	opts []*discordgo.ApplicationCommandInteractionDataOption) *discordgo.Interaction {
+
+	cmdInter := *inter
+	cmdInter.Type = discordgo.InteractionApplicationCommand
+	cmdInter.Data = discordgo.ApplicationCommandInteractionData{
+		Name: string(TdCmd),
+		Options: []*discordgo.ApplicationCommandInteractionDataOption{
+			{
+				Name:    string(subCmd),
+				Type:    discordgo.ApplicationCommandOptionSubCommand,
+				Options: opts,
+			},
+		},
+	}
+
+	return &cmdInter
+}
+
+// paginateContent splits s on line boundaries into pages that each fit
+// within a discord message. At most maxContentPages pages are returned; the
+// 2nd return value indicates whether content beyond that was dropped.
+func paginateContent(s string) ([]string, bool) {
+	const PageLimit = 1980 // keep space for code block markdown
+
+	pages := make([]string, 0)
+	var sb strings.Builder
+	pageLen := 0
+	for _, line := range strings.SplitAfter(s, "\n") {
+		lineLen := discordLen(line)
+		if pageLen > 0 && pageLen+lineLen > PageLimit {
+			pages = append(pages, sb.String())
+			sb.Reset()
+			pageLen = 0
+			if len(pages) == maxContentPages {
+				return pages, true
+			}
+		}
+		if lineLen > PageLimit {
+			line, _ = truncateContent(line)
+			lineLen = discordLen(line)
+		}
+		sb.WriteString(line)
+		pageLen += lineLen
+	}
+	if pageLen > 0 || len(pages) == 0 {
+		pages = append(pages, sb.String())
+	}
+
+	return pages, false
+}
+
+// addPageButtons attaches Prev/Next page buttons to a paginated response.
+func addPageButtons(resp *discordgo.InteractionResponse, subCmd TdSubCommand,
+	inter *discordgo.Interaction, page int, numPages int) {
+
+	if numPages <= 1 {
+		return
+	}
+
+	pageId := func(p int) string {
+		opts := make([]*discordgo.ApplicationCommandInteractionDataOption, 0)
+		for _, opt := range subCmdOptions(inter) {
+			if opt.Name != "page" {
+				opts = append(opts, opt)
+			}
+		}
+		opts = append(opts, &discordgo.ApplicationCommandInteractionDataOption{
+			Name:  "page",
+			Type:  discordgo.ApplicationCommandOptionInteger,
+			Value: float64(p),
+		})
+		return buildCustomId(pageCustomId, subCmd, opts)
+	}
+	prevId := pageId(max(page-1, 0))
+	nextId := pageId(min(page+1, numPages-1))
+	if len(prevId) > maxCustomIdSize || len(nextId) > maxCustomIdSize {
+		return
+	}
+
+	resp.Data.Components = append(resp.Data.Components, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "Prev page",
+				Style:    discordgo.SecondaryButton,
+				CustomID: prevId,
+				Disabled: page == 0,
+			},
+			discordgo.Button{
+				Label:    fmt.Sprintf("Next page (%v/%v)", page+1, numPages),
+				Style:    discordgo.SecondaryButton,
+				CustomID: nextId,
+				Disabled: page == numPages-1,
+			},
+		},
+	})
+}
+
+// pageComponentHandler handles a click on a Prev/Next page button by
+// re-running the original td subcommand for the requested page and editing
+// the existing message in place. A slow subcommand's update is deferred.
+func pageComponentHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	subCmd, opts, err := parseCustomId(inter.MessageComponentData().CustomID)
+	hdlr, ok := tdSubCmdHdlrs[subCmd]
+	if err != nil || !ok {
+		resp := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("Unable to change page: %v",
+					inter.MessageComponentData().CustomID),
+				Flags: discordgo.MessageFlagsEphemeral,
+			},
+		}
+		log.Printf("discordbot.page: %v", resp.Data.Content)
+		return resp
+	}
+
+	subInter := subCmdInteraction(inter, subCmd, opts)
+	if deferredSubCmds[subCmd] {
+		return deferUpdate(subInter, hdlr)
+	}
+
+	resp := hdlr(ctx, subInter)
+	resp.Type = discordgo.InteractionResponseUpdateMessage
+
+	return resp
+}
@@ -0,0 +1,306 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCustomIdRoundTrip(t *testing.T) {
+	opts := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "days", Type: discordgo.ApplicationCommandOptionInteger, Value: 30.0},
+		{Name: "detailed", Type: discordgo.ApplicationCommandOptionBoolean, Value: true},
+		{Name: "broadcast", Type: discordgo.ApplicationCommandOptionBoolean, Value: false},
+	}
+
+	customId := buildCustomId(shareCustomId, TdCalCmd, opts)
+	if want := "share:cal:days=i30,detailed=btrue"; customId != want {
+		t.Fatalf("buildCustomId() = %q; want %q", customId, want)
+	}
+
+	subCmd, parsed, err := parseCustomId(customId)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if subCmd != TdCalCmd {
+		t.Fatalf("subCmd = %q; want %q", subCmd, TdCalCmd)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("expected 2 options, got %v", len(parsed))
+	}
+	if parsed[0].IntValue() != 30 {
+		t.Errorf("days = %v; want 30", parsed[0].IntValue())
+	}
+	if !parsed[1].BoolValue() {
+		t.Errorf("detailed = false; want true")
+	}
+}
+
+func TestParseCustomIdMalformed(t *testing.T) {
+	for _, id := range []string{"share", "share:cal", "share:cal:days",
+		"share:cal:days=x30", "share:cal:days=inope"} {
+		if _, _, err := parseCustomId(id); err == nil {
+			t.Errorf("parseCustomId(%q) expected error", id)
+		}
+	}
+}
+
+func TestShareComponentHandlerBroadcasts(t *testing.T) {
+	var gotBroadcast bool
+	var gotEventID int64
+	orig := tdSubCmdHdlrs[TdPairingsCmd]
+	defer func() { tdSubCmdHdlrs[TdPairingsCmd] = orig }()
+	tdSubCmdHdlrs[TdPairingsCmd] = func(ctx context.Context,
+		inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+		for _, opt := range inter.ApplicationCommandData().Options[0].Options {
+			switch opt.Name {
+			case "eventid":
+				gotEventID = opt.IntValue()
+			case "broadcast":
+				gotBroadcast = opt.BoolValue()
+			}
+		}
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Content: "ok"},
+		}
+	}
+
+	inter := &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{
+			CustomID:      "share:pairings:eventid=i1312",
+			ComponentType: discordgo.ButtonComponent,
+		},
+	}
+	resp := componentHandler(context.Background(), inter)
+	if resp.Data.Content != "ok" {
+		t.Fatalf("Content = %q; want %q", resp.Data.Content, "ok")
+	}
+	if gotEventID != 1312 || !gotBroadcast {
+		t.Errorf("eventid=%v broadcast=%v; want 1312 true", gotEventID,
+			gotBroadcast)
+	}
+}
+
+func TestCustomIdEscapesStrings(t *testing.T) {
+	opts := []*discordgo.ApplicationCommandInteractionDataOption{
+		{Name: "section", Type: discordgo.ApplicationCommandOptionString, Value: "U1800: A,B"},
+	}
+	_, parsed, err := parseCustomId(buildCustomId(pageCustomId, TdCrossTableCmd,
+		opts))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := parsed[0].StringValue(); got != "U1800: A,B" {
+		t.Fatalf("section = %q; want %q", got, "U1800: A,B")
+	}
+}
+
+func TestPaginateContent(t *testing.T) {
+	line := strings.Repeat("x", 99) + "\n"
+	pages, truncated := paginateContent(strings.Repeat(line, 50))
+	if truncated {
+		t.Fatalf("unexpected truncation")
+	}
+	if len(pages) != 3 {
+		t.Fatalf("expected 3 pages, got %v", len(pages))
+	}
+	if strings.Join(pages, "") != strings.Repeat(line, 50) {
+		t.Errorf("pages do not reassemble to the original content")
+	}
+	for i, p := range pages {
+		if len(p) > 1980 {
+			t.Errorf("page %v is %v chars; want <= 1980", i, len(p))
+		}
+	}
+
+	pages, truncated = paginateContent(strings.Repeat(line, 1000))
+	if !truncated || len(pages) != maxContentPages {
+		t.Errorf("got %v pages truncated=%v; want %v true", len(pages),
+			truncated, maxContentPages)
+	}
+
+	// emoji count twice against discord's limit; an over-long line is
+	// truncated to within a message, code block markdown included
+	emoji := strings.Repeat("🏆", 49) + "\n"
+	long := strings.Repeat("🏆", 1500) + "\n"
+	pages, _ = paginateContent(strings.Repeat(emoji, 50) + long)
+	for i, p := range pages {
+		if n := len(utf16.Encode([]rune(p))); n+len("```\n```") > 2000 {
+			t.Errorf("page %v is %v code units; want <= 1993", i, n)
+		}
+	}
+	if len(pages) != 4 {
+		t.Errorf("expected 4 pages of emoji, got %v", len(pages))
+	}
+}
+
+func TestPageComponentHandlerUpdatesMessage(t *testing.T) {
+	orig := tdSubCmdHdlrs[TdStandingsCmd]
+	defer func() { tdSubCmdHdlrs[TdStandingsCmd] = orig }()
+	tdSubCmdHdlrs[TdStandingsCmd] = func(ctx context.Context,
+		inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+		resp := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{},
+		}
+		addPageButtons(resp, TdStandingsCmd, inter, 1, 3)
+		return resp
+	}
+
+	inter := &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{
+			CustomID:      "page:standings:eventid=i1312,page=i1",
+			ComponentType: discordgo.ButtonComponent,
+		},
+	}
+	resp := componentHandler(context.Background(), inter)
+	if resp.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Fatalf("Type = %v; want %v", resp.Type,
+			discordgo.InteractionResponseUpdateMessage)
+	}
+	if len(resp.Data.Components) != 1 {
+		t.Fatalf("expected 1 component row, got %v", len(resp.Data.Components))
+	}
+	row := resp.Data.Components[0].(discordgo.ActionsRow)
+	prev := row.Components[0].(discordgo.Button)
+	next := row.Components[1].(discordgo.Button)
+	if want := "page:standings:eventid=i1312,page=i0"; prev.CustomID != want {
+		t.Errorf("prev CustomID = %q; want %q", prev.CustomID, want)
+	}
+	if want := "page:standings:eventid=i1312,page=i2"; next.CustomID != want {
+		t.Errorf("next CustomID = %q; want %q", next.CustomID, want)
+	}
+}
+
+func TestPageComponentHandlerKeepsBroadcast(t *testing.T) {
+	orig := tdSubCmdHdlrs[TdStandingsCmd]
+	defer func() { tdSubCmdHdlrs[TdStandingsCmd] = orig }()
+	tdSubCmdHdlrs[TdStandingsCmd] = func(ctx context.Context,
+		inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+		resp := &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Flags: discordgo.MessageFlagsEphemeral,
+			},
+		}
+		broadcast := false
+		for _, opt := range subCmdOptions(inter) {
+			if opt.Name == "broadcast" {
+				broadcast = opt.BoolValue()
+			}
+		}
+		addPageButtons(resp, TdStandingsCmd, inter, 1, 3)
+		if broadcast {
+			resp.Data.Flags = 0
+		} else {
+			addShareButton(resp, TdStandingsCmd, inter)
+		}
+		return resp
+	}
+
+	inter := &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{
+			CustomID:      "page:standings:eventid=i1312,broadcast=btrue,page=i1",
+			ComponentType: discordgo.ButtonComponent,
+		},
+	}
+	resp := componentHandler(context.Background(), inter)
+	if resp.Type != discordgo.InteractionResponseUpdateMessage {
+		t.Fatalf("Type = %v; want %v", resp.Type,
+			discordgo.InteractionResponseUpdateMessage)
+	}
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0 {
+		t.Errorf("paged broadcast message is ephemeral")
+	}
+	if len(resp.Data.Components) != 1 {
+		t.Fatalf("expected only the page buttons, got %v component rows",
+			len(resp.Data.Components))
+	}
+	row := resp.Data.Components[0].(discordgo.ActionsRow)
+	next := row.Components[1].(discordgo.Button)
+	want := "page:standings:eventid=i1312,broadcast=btrue,page=i2"
+	if next.CustomID != want {
+		t.Errorf("next CustomID = %q; want %q", next.CustomID, want)
+	}
+}
+
+func TestComponentHandlersDeferSlowSubCmds(t *testing.T) {
+	origEditor := responseEditor
+	origGainers := tdSubCmdHdlrs[TdGainersCmd]
+	origCrossTable := tdSubCmdHdlrs[TdCrossTableCmd]
+	defer func() {
+		responseEditor = origEditor
+		tdSubCmdHdlrs[TdGainersCmd] = origGainers
+		tdSubCmdHdlrs[TdCrossTableCmd] = origCrossTable
+	}()
+
+	var edited *discordgo.WebhookEdit
+	responseEditor = func(_ *discordgo.Interaction,
+		edit *discordgo.WebhookEdit) error {
+
+		edited = edit
+		return nil
+	}
+	report := func(ctx context.Context,
+		inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Content: "report"},
+		}
+	}
+	tdSubCmdHdlrs[TdGainersCmd] = report
+	tdSubCmdHdlrs[TdCrossTableCmd] = report
+
+	tests := []struct {
+		customId string
+		wantType discordgo.InteractionResponseType
+	}{
+		{"share:gainers:days=i30",
+			discordgo.InteractionResponseDeferredChannelMessageWithSource},
+		{"page:crosstable:eventid=i1312,page=i1",
+			discordgo.InteractionResponseDeferredMessageUpdate},
+	}
+	for _, tc := range tests {
+		edited = nil
+		inter := &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: discordgo.MessageComponentInteractionData{
+				CustomID:      tc.customId,
+				ComponentType: discordgo.ButtonComponent,
+			},
+		}
+		resp := componentHandler(context.Background(), inter)
+		if !waitDeferredHandlers(time.Second) {
+			t.Fatalf("%v: deferred handler did not finish", tc.customId)
+		}
+		if resp.Type != tc.wantType {
+			t.Errorf("%v: Type = %v; want %v", tc.customId, resp.Type,
+				tc.wantType)
+		}
+		if resp.Data != nil &&
+			resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0 {
+
+			t.Errorf("%v: deferred response is ephemeral", tc.customId)
+		}
+		if edited == nil || *edited.Content != "report" {
+			t.Errorf("%v: edited = %+v; want the handler's report",
+				tc.customId, edited)
+		}
+	}
+}
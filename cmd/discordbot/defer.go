@@ -0,0 +1,201 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discord fails an interaction which is not responded to within 3 seconds.
+// The td subcommands listed here may make enough upstream fetches to exceed
+// that, so their responses are deferred: discord is told immediately that a
+// response is coming and the original response is edited once the handler
+// finishes.
+var deferredSubCmds = map[TdSubCommand]bool{
+	TdPlayerCmd:     true,
+	TdCrossTableCmd: true,
+	TdRecentCmd:     true,
+	TdGainersCmd:    true,
+}
+
+// deferredHandlerTimeout bounds how long a deferred handler may run; discord
+// invalidates an interaction's token 15 minutes after it is received.
+const deferredHandlerTimeout = 5 * time.Minute
+
+// deferredHandlers tracks deferred handlers still running so that they may
+// deliver their responses before the bot exits.
+var deferredHandlers sync.WaitGroup
+
+// responseEditor, responseDeleter, and followupSender edit or delete an
+// interaction's original response or send a follow-up message. They are
+// variables so that tests need not reach discord.
+var (
+	responseEditor = func(inter *discordgo.Interaction,
+		edit *discordgo.WebhookEdit) error {
+
+		_, err := client.InteractionResponseEdit(inter, edit)
+		return err
+	}
+	responseDeleter = func(inter *discordgo.Interaction) error {
+		return client.InteractionResponseDelete(inter)
+	}
+	followupSender = func(inter *discordgo.Interaction,
+		params *discordgo.WebhookParams) error {
+
+		_, err := client.FollowupMessageCreate(inter, true, params)
+		return err
+	}
+)
+
+// deferResponse runs hdlr in the background and returns the deferred
+// response which acknowledges inter in the meantime. Whether the response
+// is ephemeral must be decided up front, so it is ephemeral unless the user
+// asked to broadcast it.
+func deferResponse(inter *discordgo.Interaction,
+	hdlr CmdHandler) *discordgo.InteractionResponse {
+
+	var flags discordgo.MessageFlags
+	if !broadcastRequested(inter) {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	runDeferred(inter, hdlr, func(resp *discordgo.InteractionResponse) {
+		deliverDeferredResponse(inter, flags, resp)
+	})
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: flags,
+		},
+	}
+}
+
+// deferUpdate runs hdlr in the background and returns the deferred response
+// which acknowledges a message component interaction in the meantime. The
+// message holding the component is then edited in place with the handler's
+// response.
+func deferUpdate(inter *discordgo.Interaction,
+	hdlr CmdHandler) *discordgo.InteractionResponse {
+
+	runDeferred(inter, hdlr, func(resp *discordgo.InteractionResponse) {
+		if resp.Data == nil {
+			return
+		}
+		err := responseEditor(inter, deferredEdit(resp.Data))
+		if err != nil {
+			log.Printf("discordbot.defer: failed to update message for %v: %v",
+				inter.ID, err)
+		}
+	})
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}
+}
+
+// runDeferred runs hdlr in the background and passes its response to
+// deliver.
+func runDeferred(inter *discordgo.Interaction, hdlr CmdHandler,
+	deliver func(resp *discordgo.InteractionResponse)) {
+
+	deferredHandlers.Add(1)
+	go func() {
+		defer deferredHandlers.Done()
+
+		// the request's context ends as soon as the deferred response is
+		// written
+		ctx, cancel := context.WithTimeout(context.Background(),
+			deferredHandlerTimeout)
+		defer cancel()
+		deliver(hdlr(ctx, inter))
+	}()
+}
+
+// deferredEdit returns the edit which replaces a deferred response with
+// data.
+func deferredEdit(data *discordgo.InteractionResponseData) *discordgo.WebhookEdit {
+	edit := &discordgo.WebhookEdit{Content: &data.Content}
+	if len(data.Embeds) > 0 {
+		edit.Embeds = &data.Embeds
+	}
+	if len(data.Components) > 0 {
+		edit.Components = &data.Components
+	}
+
+	return edit
+}
+
+// deliverDeferredResponse replaces the "thinking..." placeholder discord shows
+// for a deferred response with the handler's response. The placeholder's
+// visibility cannot be changed, so a private response (e.g. an error) to a
+// request which was to be broadcast replaces the placeholder with a private
+// follow-up instead. Should editing fail, the response is sent as a
+// follow-up.
+func deliverDeferredResponse(inter *discordgo.Interaction,
+	deferredFlags discordgo.MessageFlags, resp *discordgo.InteractionResponse) {
+
+	data := resp.Data
+	if data == nil {
+		data = &discordgo.InteractionResponseData{}
+	}
+	ephemeral := data.Flags&discordgo.MessageFlagsEphemeral != 0
+	if ephemeral == (deferredFlags&discordgo.MessageFlagsEphemeral != 0) {
+		err := responseEditor(inter, deferredEdit(data))
+		if err == nil {
+			return
+		}
+		log.Printf("discordbot.defer: failed to edit response to %v: %v",
+			inter.ID, err)
+	} else if err := responseDeleter(inter); err != nil {
+		log.Printf("discordbot.defer: failed to delete placeholder for %v: %v",
+			inter.ID, err)
+	}
+
+	err := followupSender(inter, &discordgo.WebhookParams{
+		Content:    data.Content,
+		Embeds:     data.Embeds,
+		Components: data.Components,
+		Flags: data.Flags & (discordgo.MessageFlagsEphemeral |
+			discordgo.MessageFlagsSuppressEmbeds),
+	})
+	if err != nil {
+		log.Printf("discordbot.defer: failed to send response to %v: %v",
+			inter.ID, err)
+	}
+}
+
+// broadcastRequested reports whether a td subcommand interaction asks for
+// its response to be broadcast to the channel.
+func broadcastRequested(inter *discordgo.Interaction) bool {
+	for _, opt := range subCmdOptions(inter) {
+		if opt.Name == "broadcast" {
+			return opt.BoolValue()
+		}
+	}
+
+	return false
+}
+
+// waitDeferredHandlers waits up to timeout for deferred handlers to deliver
+// their responses and reports whether they all did.
+func waitDeferredHandlers(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		deferredHandlers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
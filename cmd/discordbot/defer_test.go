@@ -0,0 +1,146 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestDeferResponse(t *testing.T) {
+	origEditor, origDeleter, origSender := responseEditor, responseDeleter,
+		followupSender
+	defer func() {
+		responseEditor, responseDeleter, followupSender = origEditor,
+			origDeleter, origSender
+	}()
+
+	var edited *discordgo.WebhookEdit
+	var deleted bool
+	var followup *discordgo.WebhookParams
+	var editErr error
+	responseEditor = func(_ *discordgo.Interaction,
+		edit *discordgo.WebhookEdit) error {
+
+		edited = edit
+		return editErr
+	}
+	responseDeleter = func(_ *discordgo.Interaction) error {
+		deleted = true
+		return nil
+	}
+	followupSender = func(_ *discordgo.Interaction,
+		params *discordgo.WebhookParams) error {
+
+		followup = params
+		return nil
+	}
+
+	newInter := func(broadcast bool) *discordgo.Interaction {
+		return subCmdInteraction(&discordgo.Interaction{ID: "1"}, TdPlayerCmd,
+			[]*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name:  "broadcast",
+					Type:  discordgo.ApplicationCommandOptionBoolean,
+					Value: broadcast,
+				},
+			})
+	}
+	hdlr := func(flags discordgo.MessageFlags) CmdHandler {
+		return func(ctx context.Context,
+			inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+			return &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Content: "report",
+					Flags:   flags,
+				},
+			}
+		}
+	}
+
+	tests := []struct {
+		name          string
+		broadcast     bool
+		respFlags     discordgo.MessageFlags
+		editErr       error
+		wantDeferred  discordgo.MessageFlags
+		wantEdited    bool
+		wantDeleted   bool
+		wantFollowup  bool
+		followupFlags discordgo.MessageFlags
+	}{
+		{
+			name:         "private",
+			respFlags:    discordgo.MessageFlagsEphemeral,
+			wantDeferred: discordgo.MessageFlagsEphemeral,
+			wantEdited:   true,
+		},
+		{
+			name:       "broadcast",
+			broadcast:  true,
+			wantEdited: true,
+		},
+		{
+			name:          "broadcast error",
+			broadcast:     true,
+			respFlags:     discordgo.MessageFlagsEphemeral,
+			wantDeleted:   true,
+			wantFollowup:  true,
+			followupFlags: discordgo.MessageFlagsEphemeral,
+		},
+		{
+			name:          "edit failure",
+			respFlags:     discordgo.MessageFlagsEphemeral,
+			editErr:       errors.New("unknown webhook"),
+			wantDeferred:  discordgo.MessageFlagsEphemeral,
+			wantEdited:    true,
+			wantFollowup:  true,
+			followupFlags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	for _, tc := range tests {
+		edited, deleted, followup, editErr = nil, false, nil, tc.editErr
+
+		resp := deferResponse(newInter(tc.broadcast), hdlr(tc.respFlags))
+		if !waitDeferredHandlers(time.Second) {
+			t.Fatalf("%v: deferred handler did not finish", tc.name)
+		}
+		if resp.Type !=
+			discordgo.InteractionResponseDeferredChannelMessageWithSource {
+
+			t.Errorf("%v: response type %v; want deferred", tc.name, resp.Type)
+		}
+		if resp.Data.Flags != tc.wantDeferred {
+			t.Errorf("%v: deferred flags %v; want %v", tc.name, resp.Data.Flags,
+				tc.wantDeferred)
+		}
+		if (edited != nil) != tc.wantEdited {
+			t.Errorf("%v: edited %v; want %v", tc.name, edited != nil,
+				tc.wantEdited)
+		} else if edited != nil && *edited.Content != "report" {
+			t.Errorf("%v: edited content %q; want \"report\"", tc.name,
+				*edited.Content)
+		}
+		if deleted != tc.wantDeleted {
+			t.Errorf("%v: deleted %v; want %v", tc.name, deleted,
+				tc.wantDeleted)
+		}
+		if (followup != nil) != tc.wantFollowup {
+			t.Errorf("%v: followup %v; want %v", tc.name, followup != nil,
+				tc.wantFollowup)
+		} else if followup != nil && (followup.Content != "report" ||
+			followup.Flags != tc.followupFlags) {
+
+			t.Errorf("%v: followup %q flags %v; want \"report\" flags %v",
+				tc.name, followup.Content, followup.Flags, tc.followupFlags)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
+)
+
+// entryWatchInterval is how often watched events' registration is checked.
+// It matches how long the details of an event open for registration are
+// cached.
+const entryWatchInterval = 5 * time.Minute
+
+// maxEntryWatchesPerUser bounds the events a single user may watch at once.
+const maxEntryWatchesPerUser = 5
+
+// errTooManyEntryWatches is returned by entryWatcher.add when a user already
+// watches maxEntryWatchesPerUser events.
+var errTooManyEntryWatches = errors.New("too many entry watches")
+
+// dmSender sends a direct message to a discord user. It is a variable so
+// that tests need not reach discord.
+var dmSender = func(userID string, content string) error {
+	channel, err := client.UserChannelCreate(userID)
+	if err != nil {
+		return err
+	}
+	_, err = client.ChannelMessageSend(channel.ID, content)
+	return err
+}
+
+// entryWatcher holds the registration watches users have requested via
+// /td watchentries, persisting them to store when one is available.
+type entryWatcher struct {
+	mu      sync.Mutex
+	watches []bcc.EntryWatch
+	store   bcc.SnapshotStore
+}
+
+var entryWatches = &entryWatcher{}
+
+// open starts persisting watches to store, keeping any added before it was
+// opened along with those previously saved.
+func (ew *entryWatcher) open(store bcc.SnapshotStore) {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	added := ew.watches
+	ew.watches = bcc.LoadEntryWatches(store)
+	ew.store = store
+	for _, w := range added {
+		ew.put(w)
+	}
+	ew.save()
+}
+
+// add starts w, replacing any watch of the same event by the same user.
+func (ew *entryWatcher) add(w bcc.EntryWatch) error {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	count := 0
+	for _, other := range ew.watches {
+		if other.UserID == w.UserID && other.EventID != w.EventID {
+			count++
+		}
+	}
+	if count >= maxEntryWatchesPerUser {
+		return errTooManyEntryWatches
+	}
+	ew.put(w)
+	ew.save()
+
+	return nil
+}
+
+// put adds or replaces w; the caller holds ew.mu.
+func (ew *entryWatcher) put(w bcc.EntryWatch) {
+	for idx, other := range ew.watches {
+		if other.UserID == w.UserID && other.EventID == w.EventID {
+			ew.watches[idx] = w
+			return
+		}
+	}
+	ew.watches = append(ew.watches, w)
+}
+
+// remove stops userID's watch of eventID, reporting whether there was one.
+func (ew *entryWatcher) remove(userID string, eventID int64) bool {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+
+	for idx, w := range ew.watches {
+		if w.UserID == userID && w.EventID == eventID {
+			ew.watches = append(ew.watches[:idx], ew.watches[idx+1:]...)
+			ew.save()
+			return true
+		}
+	}
+
+	return false
+}
+
+// save persists the watches when a store is open; the caller holds ew.mu.
+func (ew *entryWatcher) save() {
+	if ew.store != nil {
+		bcc.SaveEntryWatches(ew.store, ew.watches)
+	}
+}
+
+// check fetches each watched event once and direct messages users the
+// notifications due, dropping watches which are done. An event which cannot
+// be fetched is retried at the next check.
+func (ew *entryWatcher) check(ctx context.Context, now time.Time) {
+	ew.mu.Lock()
+	eventIDs := make(map[int64]bool)
+	for _, w := range ew.watches {
+		eventIDs[w.EventID] = true
+	}
+	ew.mu.Unlock()
+
+	details := make(map[int64]*bcc.EventDetail)
+	for eventID := range eventIDs {
+		detail, err := bcc.GetEventDetail(ctx, eventID)
+		if err != nil {
+			log.Printf("discordbot.watchentries: fetching event %d: %v",
+				eventID, err)
+			continue
+		}
+		details[eventID] = &detail
+	}
+
+	type dm struct{ userID, content string }
+	var dms []dm
+	ew.mu.Lock()
+	kept := ew.watches[:0]
+	for _, w := range ew.watches {
+		detail, ok := details[w.EventID]
+		if !ok {
+			kept = append(kept, w)
+			continue
+		}
+		notes, done := bcc.CheckEntryWatch(&w, detail, now)
+		if len(notes) > 0 {
+			dms = append(dms, dm{w.UserID, strings.Join(notes, "\n")})
+		}
+		if !done {
+			kept = append(kept, w)
+		}
+	}
+	ew.watches = kept
+	ew.save()
+	ew.mu.Unlock()
+
+	for _, msg := range dms {
+		if err := dmSender(msg.userID, msg.content); err != nil {
+			log.Printf("discordbot.watchentries: failed to message %v: %v",
+				msg.userID, err)
+		}
+	}
+}
+
+// runEntryWatcher checks watched events' registration every
+// entryWatchInterval until ctx is done. Watches are kept only in memory when
+// the snapshot store is unavailable.
+func runEntryWatcher(ctx context.Context) {
+	store, err := httpcache.NewS3Store(ctx)
+	if err != nil {
+		log.Printf("discordbot.watchentries: entry watches will not persist across restarts: %v",
+			err)
+	} else {
+		entryWatches.open(store)
+	}
+
+	ticker := time.NewTicker(entryWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			entryWatches.check(ctx, internal.Now())
+		}
+	}
+}
+
+// tdWatchEntriesCmdHandler handles the /td watchentries command, which
+// direct messages the user as an event's registration fills and when it
+// closes, until the event starts.
+func tdWatchEntriesCmdHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	data := inter.ApplicationCommandData()
+	var eventID, step int64
+	stop := false
+	if len(data.Options) > 0 {
+		for _, opt := range data.Options[0].Options {
+			switch opt.Name {
+			case "eventid":
+				eventID = opt.IntValue()
+			case "step":
+				step = opt.IntValue()
+			case "stop":
+				stop = opt.BoolValue()
+			}
+		}
+	}
+	if eventID <= 0 {
+		resp.Data.Content = "Please provide an event ID."
+		log.Printf("discordbot.watchentries: %v", resp.Data.Content)
+		return resp
+	}
+	userID := interactionUserID(inter)
+	if userID == "" {
+		resp.Data.Content = "Unable to determine who to notify."
+		log.Printf("discordbot.watchentries: %v", resp.Data.Content)
+		return resp
+	}
+
+	if stop {
+		if entryWatches.remove(userID, eventID) {
+			resp.Data.Content = fmt.Sprintf("No longer watching registration for event %d.",
+				eventID)
+		} else {
+			resp.Data.Content = fmt.Sprintf("You are not watching registration for event %d.",
+				eventID)
+		}
+		return resp
+	}
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		resp.Data.Content = fetchErrorContent(fmt.Sprintf("event %d", eventID),
+			err)
+		log.Printf("discordbot.watchentries: fetching event %d: %v", eventID,
+			err)
+		return resp
+	}
+	w := bcc.NewEntryWatch(&detail, userID, int(step))
+	if notes, done := bcc.CheckEntryWatch(&w, &detail,
+		internal.Now()); done {
+
+		// already closed or started; nothing to watch
+		resp.Data.Content = strings.Join(notes, "\n")
+		return resp
+	}
+	if err := entryWatches.add(w); err != nil {
+		resp.Data.Content = fmt.Sprintf("You are already watching %v events; stop one with stop: true first.",
+			maxEntryWatchesPerUser)
+		log.Printf("discordbot.watchentries: %v", resp.Data.Content)
+		return resp
+	}
+
+	resp.Data.Content = fmt.Sprintf("Watching registration for %v (currently %v entries). You'll get a direct message every %v entries and when registration closes; the watch ends when the event starts.",
+		detail.Title, w.Entries, w.Step)
+
+	return resp
+}
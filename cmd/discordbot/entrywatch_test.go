@@ -0,0 +1,115 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+)
+
+type memWatchStore map[string][]byte
+
+func (s memWatchStore) Get(key string) ([]byte, bool) {
+	data, ok := s[key]
+	return data, ok
+}
+
+func (s memWatchStore) Put(key string, data []byte) error {
+	s[key] = data
+	return nil
+}
+
+func TestEntryWatcher(t *testing.T) {
+	ew := &entryWatcher{}
+	watch := func(userID string, eventID int64) bcc.EntryWatch {
+		return bcc.EntryWatch{EventID: eventID, UserID: userID, Step: 10}
+	}
+
+	// watches added before the store opens are kept alongside saved ones
+	store := memWatchStore{}
+	bcc.SaveEntryWatches(store, []bcc.EntryWatch{watch("1", 100)})
+	if err := ew.add(watch("1", 101)); err != nil {
+		t.Fatalf("add() err = %v", err)
+	}
+	ew.open(store)
+	if len(ew.watches) != 2 || len(bcc.LoadEntryWatches(store)) != 2 {
+		t.Fatalf("watches = %+v; want 2", ew.watches)
+	}
+
+	// rewatching an event replaces the watch
+	replaced := watch("1", 100)
+	replaced.Step = 5
+	if err := ew.add(replaced); err != nil || len(ew.watches) != 2 ||
+		ew.watches[0].Step != 5 {
+
+		t.Errorf("add(replacement) = %v; watches = %+v", err, ew.watches)
+	}
+
+	for eventID := int64(102); eventID < 100+maxEntryWatchesPerUser; eventID++ {
+		if err := ew.add(watch("1", eventID)); err != nil {
+			t.Fatalf("add(%v) err = %v", eventID, err)
+		}
+	}
+	if err := ew.add(watch("1", 999)); !errors.Is(err,
+		errTooManyEntryWatches) {
+
+		t.Errorf("add(over limit) err = %v; want errTooManyEntryWatches", err)
+	}
+	if err := ew.add(watch("2", 999)); err != nil {
+		t.Errorf("add(other user) err = %v", err)
+	}
+
+	if !ew.remove("1", 101) || ew.remove("1", 101) {
+		t.Errorf("remove() did not remove exactly once")
+	}
+	if got := len(bcc.LoadEntryWatches(store)); got != len(ew.watches) {
+		t.Errorf("saved %v watches; want %v", got, len(ew.watches))
+	}
+}
+
+func TestTdWatchEntriesStop(t *testing.T) {
+	origWatches := entryWatches
+	defer func() { entryWatches = origWatches }()
+	entryWatches = &entryWatcher{}
+	if err := entryWatches.add(bcc.EntryWatch{EventID: 1312,
+		UserID: "42"}); err != nil {
+
+		t.Fatalf("add() err = %v", err)
+	}
+
+	inter := &discordgo.Interaction{
+		Type:   discordgo.InteractionApplicationCommand,
+		Member: &discordgo.Member{User: &discordgo.User{ID: "42"}},
+		Data: discordgo.ApplicationCommandInteractionData{
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name: string(TdWatchEntriesCmd),
+					Type: discordgo.ApplicationCommandOptionSubCommand,
+					Options: []*discordgo.ApplicationCommandInteractionDataOption{
+						{Name: "eventid", Type: discordgo.ApplicationCommandOptionInteger,
+							Value: float64(1312)},
+						{Name: "stop", Type: discordgo.ApplicationCommandOptionBoolean,
+							Value: true},
+					},
+				},
+			},
+		},
+	}
+	for _, want := range []string{"No longer watching", "You are not watching"} {
+		resp := tdWatchEntriesCmdHandler(context.Background(), inter)
+		if !strings.HasPrefix(resp.Data.Content, want) {
+			t.Errorf("stop content = %q; want prefix %q", resp.Data.Content,
+				want)
+		}
+		if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Errorf("stop response is not ephemeral")
+		}
+	}
+}
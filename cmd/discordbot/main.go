@@ -5,20 +5,25 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
-	uschess "github.com/mikeb26/uschess-go"
 
 	_ "embed"
 )
@@ -35,6 +40,10 @@ var botAppId string
 
 const TdCmdId = "1382811720254230578"
 
+// shutdownTimeout bounds how long in-flight interactions may take to finish
+// once the bot is asked to exit.
+const shutdownTimeout = 10 * time.Second
+
 var client *discordgo.Session
 
 type TopLevelCommand string
@@ -78,6 +87,13 @@ func interactionHandler(w http.ResponseWriter, r *http.Request) {
 	resp := &discordgo.InteractionResponse{}
 	if inter.Type == discordgo.InteractionPing {
 		resp.Type = discordgo.InteractionResponsePong
+	} else if (inter.Type == discordgo.InteractionApplicationCommand ||
+		inter.Type == discordgo.InteractionMessageComponent) &&
+		!interactionLimiter.Allow(interactionUserID(&inter)) {
+
+		log.Printf("discordbot.int: rate limited user %v",
+			interactionUserID(&inter))
+		resp = slowDownResponse()
 	} else if inter.Type == discordgo.InteractionApplicationCommand {
 		hdlr, ok :=
 			topLevelCmdHdlrs[TopLevelCommand(inter.ApplicationCommandData().Name)]
@@ -91,6 +107,10 @@ func interactionHandler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			resp = hdlr(r.Context(), &inter)
 		}
+	} else if inter.Type == discordgo.InteractionMessageComponent {
+		resp = componentHandler(r.Context(), &inter)
+	} else if inter.Type == discordgo.InteractionApplicationCommandAutocomplete {
+		resp = autocompleteHandler(r.Context(), &inter)
 	} else {
 		log.Printf("discordbot.int: unimplemented interation type %v: inter:%v",
 			inter.Type, inter)
@@ -135,16 +155,45 @@ func init() {
 //go:embed lastupdate.hash
 var lastCmdUpdateHash string
 
+// cmdRegistrationHash returns the hash of cmd which lastupdate.hash records.
+// cmd is hashed in canonical form so that the hash changes only when the
+// command does; see canonicalJSON.
+func cmdRegistrationHash(cmd *discordgo.ApplicationCommand) (string, error) {
+	cmdJson, err := canonicalJSON(cmd)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(cmdJson)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// canonicalJSON marshals v to JSON with the keys of every object sorted, so
+// that the output depends on neither the order of struct fields nor that of
+// map iteration.
+func canonicalJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// keep numbers exactly as marshaled rather than as float64
+	dec.UseNumber()
+	var generic any
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	// encoding/json writes map keys in sorted order
+	return json.Marshal(generic)
+}
+
 func shouldUpdateCmdRegistration(cmd *discordgo.ApplicationCommand) bool {
-	cmdJson, err := json.Marshal(cmd)
+	hexString, err := cmdRegistrationHash(cmd)
 	if err != nil {
 		log.Fatalf("discordbot.reg: failed to marshal cmd: %v", err)
 		return false
 	}
-	hasher := sha256.New()
-	hasher.Write(cmdJson)
-	hash := hasher.Sum(nil)
-	hexString := hex.EncodeToString(hash)
 
 	shouldUpdate := (hexString != lastCmdUpdateHash)
 
@@ -182,6 +231,19 @@ func registerSlashCommands() {
 						Description: "Number of days to retrieve (default is 14)",
 						Required:    false,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "detailed",
+						Description: "Include format, entry fee, and registration status (default is false)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "dow",
+						Description: "Only show events on this day of the week",
+						Required:    false,
+						Choices:     weekdayChoices(),
+					},
 					{
 						Type:        discordgo.ApplicationCommandOptionBoolean,
 						Name:        "broadcast",
@@ -220,12 +282,118 @@ func registerSlashCommands() {
 						Description: "Event id of the tournament (as returned by cal)",
 						Required:    true,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "section",
+						Description: "Section of the tournament to retrieve",
+						Required:    false,
+					},
 					{
 						Type:        discordgo.ApplicationCommandOptionBoolean,
 						Name:        "broadcast",
 						Description: "Share with the rest of the channel instead of	only to you (default is false)",
 						Required:    false,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "thread",
+						Description: "Post to the event's configured thread instead of the channel (default is false)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "round",
+						Description: "Show this round's games once the event is filed with US Chess",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        string(TdWatchEntriesCmd),
+				Description: "Get a direct message as an event's registration fills and when it closes",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "eventid",
+						Description: "Event id of the tournament (as returned by cal)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "step",
+						Description: "Number of entries between messages (default is 10)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "stop",
+						Description: "Stop watching the event's registration (default is false)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        string(TdFeaturedCmd),
+				Description: "Get the current round's top boards by combined rating",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "eventid",
+						Description: "Event id of the tournament (as returned by cal)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "n",
+						Description: "Number of boards to show (default is 3)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "broadcast",
+						Description: "Share with the rest of the channel instead of only to you (default is false)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        string(TdMyGameCmd),
+				Description: "Privately show your current board, color, and opponent",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "eventid",
+						Description: "Event id of the tournament (as returned by cal)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "name",
+						Description: "Your name as entered in the event (e.g. \"Doe, John\" or \"John Doe\")",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        string(TdMyByesCmd),
+				Description: "Privately show the rounds you requested byes for",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "eventid",
+						Description: "Event id of the tournament (as returned by cal)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "memid",
+						Description: "Your USCF member id",
+						Required:    true,
+					},
 				},
 			},
 			{
@@ -258,12 +426,24 @@ func registerSlashCommands() {
 						Description: "Event id of the tournament (as returned by cal)",
 						Required:    true,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "section",
+						Description: "Section of the tournament to retrieve",
+						Required:    false,
+					},
 					{
 						Type:        discordgo.ApplicationCommandOptionBoolean,
 						Name:        "broadcast",
 						Description: "Share with the rest of the channel instead of	only to you (default is false)",
 						Required:    false,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "thread",
+						Description: "Post to the event's configured thread instead of the channel (default is false)",
+						Required:    false,
+					},
 				},
 			},
 			{
@@ -283,6 +463,18 @@ func registerSlashCommands() {
 						Description: "Section of the tournament to retrieve",
 						Required:    false,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "lastrounds",
+						Description: "Only show this many of the most recent rounds (default is all rounds)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "combined",
+						Description: "Open with a summary of the whole event and list sections Open first (default is false)",
+						Required:    false,
+					},
 					{
 						Type:        discordgo.ApplicationCommandOptionBoolean,
 						Name:        "broadcast",
@@ -300,7 +492,14 @@ func registerSlashCommands() {
 						Type:        discordgo.ApplicationCommandOptionInteger,
 						Name:        "memid",
 						Description: "USCF member id of the player",
-						Required:    true,
+						Required:    false,
+					},
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "name",
+						Description:  "Name of the player; choose from the matching members as you type",
+						Required:     false,
+						Autocomplete: true,
 					},
 					{
 						Type:        discordgo.ApplicationCommandOptionBoolean,
@@ -333,6 +532,18 @@ func registerSlashCommands() {
 						Description: "Opponent USCF member ids (space and/or comma separated)",
 						Required:    true,
 					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "unrated",
+						Description: "Count unrated opponents at this rating for an approximate estimate",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "eventid",
+						Description: "Count unrated opponents at the rating they entered this event with",
+						Required:    false,
+					},
 					{
 						Type:        discordgo.ApplicationCommandOptionBoolean,
 						Name:        "broadcast",
@@ -341,6 +552,50 @@ func registerSlashCommands() {
 					},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        string(TdRecentCmd),
+				Description: "Show the section winners of the club's most recent rated events",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "Number of events to show (default is 5)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "broadcast",
+						Description: "Share with the rest of the channel instead of only to you (default is false)",
+						Required:    false,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        string(TdGainersCmd),
+				Description: "Show who gained and lost the most rating in a completed event",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "eventid",
+						Description: "Event id of the tournament (as returned by cal)",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "Number of gainers and losers to show (default is 5)",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionBoolean,
+						Name:        "broadcast",
+						Description: "Share with the rest of the channel instead of only to you (default is false)",
+						Required:    false,
+					},
+				},
+			},
 		},
 	}
 
@@ -365,7 +620,26 @@ func registerSlashCommands() {
 	}
 }
 
-var uschessClient *uschess.ClientWithResponses
+var uschessClient *uscfutils.Client
+
+// interactionLimiter throttles each user's commands and button presses so
+// that a single user cannot drive heavy upstream fetching.
+var interactionLimiter *rateLimiter
+
+// weekdayChoices returns the days of the week, Monday first, as choices of a
+// string option.
+func weekdayChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, 7)
+	for i := range 7 {
+		day := time.Weekday((int(time.Monday) + i) % 7)
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  day.String(),
+			Value: strings.ToLower(day.String()),
+		})
+	}
+
+	return choices
+}
 
 func main() {
 	go registerSlashCommands()
@@ -379,12 +653,50 @@ func main() {
 	if err != nil {
 		hostname = "localhost"
 	}
+	defer uschessClient.Close()
+	for _, line := range strings.Split(strings.TrimSpace(
+		internal.GetConfig().String()), "\n") {
+		log.Printf("discordbot.main: config %v", line)
+	}
+	limit, window := rateLimitConfig()
+	interactionLimiter = newRateLimiter(limit, window)
+	log.Printf("discordbot.main: limiting users to %v interactions per %v",
+		limit, window)
+
 	log.Printf("discordbot.main: starting server on %v:8080", hostname)
 
+	announceCtx, stopAnnouncer := context.WithCancel(context.Background())
+	defer stopAnnouncer()
+	go runEventAnnouncer(announceCtx)
+	go runEntryWatcher(announceCtx)
+
 	http.HandleFunc("/DiscordBot/Interaction", interactionHandler)
-	if err := http.ListenAndServe(":8080", nil); err != nil {
+	http.HandleFunc("/DiscordBot/api/version", apiVersionHandler)
+	srv := &http.Server{Addr: ":8080"}
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		sigCtx, stop := signal.NotifyContext(context.Background(),
+			os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-sigCtx.Done()
+
+		log.Printf("discordbot.main: shutting down")
+		ctx, cancel := context.WithTimeout(context.Background(),
+			shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("discordbot.main: Shutdown failed: %v", err)
+		}
+	}()
+	if err := srv.ListenAndServe(); err != nil &&
+		!errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("discordbot.main: Serve failed: %v", err)
 	}
+	<-shutdownDone
+	if !waitDeferredHandlers(shutdownTimeout) {
+		log.Printf("discordbot.main: exiting with deferred responses undelivered")
+	}
 
 	log.Printf("discordbot.main: exiting")
 }
@@ -0,0 +1,73 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCmdRegistrationHashStable(t *testing.T) {
+	newCmd := func(order []discordgo.Locale) *discordgo.ApplicationCommand {
+		names := make(map[discordgo.Locale]string)
+		for _, locale := range order {
+			names[locale] = "td-" + string(locale)
+		}
+		return &discordgo.ApplicationCommand{
+			Name:              "td",
+			Description:       "Tournament director commands",
+			NameLocalizations: &names,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "eventid",
+					Description: "Event id",
+					Required:    true,
+					MinValue:    new(float64),
+				},
+			},
+		}
+	}
+	locales := []discordgo.Locale{discordgo.German, discordgo.French,
+		discordgo.SpanishES, discordgo.Japanese, discordgo.EnglishUS}
+
+	want, err := cmdRegistrationHash(newCmd(locales))
+	if err != nil {
+		t.Fatalf("cmdRegistrationHash() err = %v", err)
+	}
+	// reserialize the unchanged command, building its maps in other orders
+	for i := range locales {
+		order := append(append([]discordgo.Locale(nil), locales[i:]...),
+			locales[:i]...)
+		for j := 0; j < 10; j++ {
+			got, err := cmdRegistrationHash(newCmd(order))
+			if err != nil || got != want {
+				t.Fatalf("cmdRegistrationHash() = %v, %v; want %v", got, err,
+					want)
+			}
+		}
+	}
+
+	changed := newCmd(locales)
+	changed.Options[0].Description = "Event id of the tournament"
+	if got, _ := cmdRegistrationHash(changed); got == want {
+		t.Errorf("cmdRegistrationHash() unchanged after changing the command")
+	}
+}
+
+func TestCanonicalJSON(t *testing.T) {
+	got, err := canonicalJSON(struct {
+		Zeta  int            `json:"zeta"`
+		Alpha map[string]int `json:"alpha"`
+		Big   int64          `json:"big"`
+	}{1, map[string]int{"y": 2, "x": 3}, 1 << 60})
+	if err != nil {
+		t.Fatalf("canonicalJSON() err = %v", err)
+	}
+	if want := `{"alpha":{"x":3,"y":2},"big":1152921504606846976,"zeta":1}`; string(got) != want {
+		t.Errorf("canonicalJSON() = %s; want %s", got, want)
+	}
+}
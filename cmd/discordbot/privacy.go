@@ -0,0 +1,24 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// MaskPublicIDsEnv names the environment variable which, when true, masks
+// all but the last 4 digits of USCF member ids in entries and player output
+// that is broadcast to a channel. Ephemeral output always shows full ids.
+const MaskPublicIDsEnv = internal.MaskPublicIDsEnv
+
+// maskPublicIDs reports whether USCF member ids should be masked in a
+// response, i.e. whether it is broadcast and MaskPublicIDsEnv is set.
+func maskPublicIDs(broadcast bool) bool {
+	if !broadcast {
+		return false
+	}
+
+	return internal.GetConfig().MaskPublicIDs
+}
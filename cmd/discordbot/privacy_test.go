@@ -0,0 +1,32 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"testing"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+func TestMaskPublicIDs(t *testing.T) {
+	tests := []struct {
+		env       string
+		broadcast bool
+		want      bool
+	}{
+		{"", true, false},
+		{"true", true, true},
+		{"true", false, false},
+		{"false", true, false},
+		{"bogus", true, false},
+	}
+	for _, tc := range tests {
+		internal.SetenvForTest(t, MaskPublicIDsEnv, tc.env)
+		if got := maskPublicIDs(tc.broadcast); got != tc.want {
+			t.Errorf("maskPublicIDs(%v) with %v=%q = %v; want %v",
+				tc.broadcast, MaskPublicIDsEnv, tc.env, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+const slowDownMsg = "You're sending commands too quickly; please slow down and try again shortly."
+
+// rateLimiter allows each user at most limit interactions within any
+// sliding window of the given duration.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	recent    map[string][]time.Time
+	lastSweep time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+		now:    internal.Now,
+		recent: make(map[string][]time.Time),
+	}
+}
+
+// Allow reports whether userID may issue another interaction, recording it
+// if so. Denied interactions do not count against the user.
+func (rl *rateLimiter) Allow(userID string) bool {
+	if rl.limit <= 0 {
+		return true
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	cutoff := now.Add(-rl.window)
+	if now.Sub(rl.lastSweep) >= rl.window {
+		// forget users who have gone quiet so the map doesn't grow unbounded
+		for id, times := range rl.recent {
+			if !times[len(times)-1].After(cutoff) {
+				delete(rl.recent, id)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	times := rl.recent[userID]
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+	if len(times) >= rl.limit {
+		rl.recent[userID] = times
+		return false
+	}
+	rl.recent[userID] = append(times, now)
+
+	return true
+}
+
+// rateLimitConfig returns the interaction limit and window resolved by
+// internal.GetConfig.
+func rateLimitConfig() (int, time.Duration) {
+	cfg := internal.GetConfig()
+	return cfg.RateLimit, cfg.RateWindow
+}
+
+// interactionUserID returns the id of the user who issued an interaction;
+// Member is set for interactions within a guild and User for those in DMs.
+func interactionUserID(inter *discordgo.Interaction) string {
+	if inter.Member != nil && inter.Member.User != nil {
+		return inter.Member.User.ID
+	}
+	if inter.User != nil {
+		return inter.User.ID
+	}
+
+	return ""
+}
+
+func slowDownResponse() *discordgo.InteractionResponse {
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: slowDownMsg,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
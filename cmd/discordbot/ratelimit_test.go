@@ -0,0 +1,71 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl := newRateLimiter(2, 10*time.Second)
+	rl.now = func() time.Time { return now }
+
+	steps := []struct {
+		advance time.Duration
+		user    string
+		want    bool
+	}{
+		{0, "alice", true},
+		{time.Second, "alice", true},
+		{time.Second, "alice", false},
+		// other users are limited independently
+		{0, "bob", true},
+		// denied interactions don't extend the wait
+		{7 * time.Second, "alice", false},
+		// the first interaction has left the window
+		{time.Second, "alice", true},
+		{0, "alice", false},
+		// both remaining interactions have left the window
+		{10 * time.Second, "alice", true},
+		{0, "alice", true},
+		{0, "alice", false},
+	}
+	for i, step := range steps {
+		now = now.Add(step.advance)
+		if got := rl.Allow(step.user); got != step.want {
+			t.Errorf("step %d: Allow(%q) = %v; want %v", i, step.user, got,
+				step.want)
+		}
+	}
+	if _, ok := rl.recent["bob"]; ok {
+		t.Errorf("idle user was not forgotten")
+	}
+
+	unlimited := newRateLimiter(0, time.Second)
+	for i := 0; i < 10; i++ {
+		if !unlimited.Allow("alice") {
+			t.Fatalf("Allow() with no limit = false")
+		}
+	}
+}
+
+func TestRateLimitConfig(t *testing.T) {
+	internal.SetenvForTest(t, internal.RateLimitEnv, "3")
+	internal.SetenvForTest(t, internal.RateWindowEnv, "1m")
+	if limit, window := rateLimitConfig(); limit != 3 || window != time.Minute {
+		t.Errorf("rateLimitConfig() = %v, %v; want 3, 1m", limit, window)
+	}
+
+	internal.SetenvForTest(t, internal.RateLimitEnv, "bogus")
+	internal.SetenvForTest(t, internal.RateWindowEnv, "-5s")
+	if limit, window := rateLimitConfig(); limit != internal.DefaultRateLimit ||
+		window != internal.DefaultRateWindow {
+		t.Errorf("rateLimitConfig() = %v, %v; want defaults", limit, window)
+	}
+}
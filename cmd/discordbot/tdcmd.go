@@ -7,17 +7,19 @@ package main
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 	"unicode"
+	"unicode/utf16"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
 	uschess "github.com/mikeb26/uschess-go"
 )
@@ -25,29 +27,41 @@ import (
 type TdSubCommand string
 
 const (
-	TdAboutCmd      TdSubCommand = "about"
-	TdHelpCmd       TdSubCommand = "help"
-	TdCalCmd        TdSubCommand = "cal"
-	TdEntriesCmd    TdSubCommand = "entries"
-	TdEventCmd      TdSubCommand = "event"
-	TdPairingsCmd   TdSubCommand = "pairings"
-	TdStandingsCmd  TdSubCommand = "standings"
-	TdPlayerCmd     TdSubCommand = "player"
-	TdCrossTableCmd TdSubCommand = "crosstable"
-	TdEstRatingCmd  TdSubCommand = "estrating"
+	TdAboutCmd        TdSubCommand = "about"
+	TdHelpCmd         TdSubCommand = "help"
+	TdCalCmd          TdSubCommand = "cal"
+	TdEntriesCmd      TdSubCommand = "entries"
+	TdEventCmd        TdSubCommand = "event"
+	TdPairingsCmd     TdSubCommand = "pairings"
+	TdStandingsCmd    TdSubCommand = "standings"
+	TdPlayerCmd       TdSubCommand = "player"
+	TdCrossTableCmd   TdSubCommand = "crosstable"
+	TdEstRatingCmd    TdSubCommand = "estrating"
+	TdRecentCmd       TdSubCommand = "recent"
+	TdMyGameCmd       TdSubCommand = "mygame"
+	TdMyByesCmd       TdSubCommand = "mybyes"
+	TdGainersCmd      TdSubCommand = "gainers"
+	TdFeaturedCmd     TdSubCommand = "featured"
+	TdWatchEntriesCmd TdSubCommand = "watchentries"
 )
 
 var tdSubCmdHdlrs = map[TdSubCommand]CmdHandler{
-	TdAboutCmd:      tdAboutCmdHandler,
-	TdHelpCmd:       tdHelpCmdHandler,
-	TdCalCmd:        tdCalCmdHandler,
-	TdEntriesCmd:    tdEntriesCmdHandler,
-	TdEventCmd:      tdEventCmdHandler,
-	TdPairingsCmd:   tdPairingsCmdHandler,
-	TdStandingsCmd:  tdStandingsCmdHandler,
-	TdPlayerCmd:     tdPlayerCmdHandler,
-	TdCrossTableCmd: tdCrossTableCmdHandler,
-	TdEstRatingCmd:  tdEstRatingCmdHandler,
+	TdAboutCmd:        tdAboutCmdHandler,
+	TdHelpCmd:         tdHelpCmdHandler,
+	TdCalCmd:          tdCalCmdHandler,
+	TdEntriesCmd:      tdEntriesCmdHandler,
+	TdEventCmd:        tdEventCmdHandler,
+	TdPairingsCmd:     tdPairingsCmdHandler,
+	TdStandingsCmd:    tdStandingsCmdHandler,
+	TdPlayerCmd:       tdPlayerCmdHandler,
+	TdCrossTableCmd:   tdCrossTableCmdHandler,
+	TdEstRatingCmd:    tdEstRatingCmdHandler,
+	TdRecentCmd:       tdRecentCmdHandler,
+	TdMyGameCmd:       tdMyGameCmdHandler,
+	TdMyByesCmd:       tdMyByesCmdHandler,
+	TdGainersCmd:      tdGainersCmdHandler,
+	TdFeaturedCmd:     tdFeaturedCmdHandler,
+	TdWatchEntriesCmd: tdWatchEntriesCmdHandler,
 }
 
 func tdCmdHandler(ctx context.Context,
@@ -58,7 +72,9 @@ func tdCmdHandler(ctx context.Context,
 	if len(data.Options) > 0 {
 		if subName := data.Options[0].Name; subName != "" {
 			h, ok := tdSubCmdHdlrs[TdSubCommand(subName)]
-			if ok {
+			if ok && deferredSubCmds[TdSubCommand(subName)] {
+				return deferResponse(inter, h)
+			} else if ok {
 				hdlr = h
 			}
 		}
@@ -97,10 +113,57 @@ func tdHelpCmdHandler(ctx context.Context,
 		},
 	}
 
-	resp.Data.Content, _ = truncateContent(helpText)
+	page := 0
+	for _, opt := range subCmdOptions(inter) {
+		if opt.Name == "page" {
+			page = int(opt.IntValue())
+		}
+	}
+
+	pages := helpPages()
+	page = min(max(page, 0), len(pages)-1)
+	resp.Data.Content = pages[page]
+	addPageButtons(resp, TdHelpCmd, inter, page, len(pages))
+
 	return resp
 }
 
+// helpPages splits the help text into pages that each fit within a discord
+// message. The command list's code block is closed at the end of each page
+// and reopened at the start of the next.
+func helpPages() []string {
+	const CodeBlock = "```\n"
+
+	head, rest, _ := strings.Cut(helpText, CodeBlock)
+	body, tail, _ := strings.Cut(rest, CodeBlock)
+	bodyPages, _ := paginateContent(body)
+
+	pages := make([]string, 0, len(bodyPages)+2)
+	if head != "" {
+		pages = append(pages, head)
+	}
+	for _, bodyPage := range bodyPages {
+		pages = append(pages, CodeBlock+bodyPage+CodeBlock)
+	}
+	if tail != "" {
+		pages = append(pages, tail)
+	}
+
+	// merge the short text before and after the code block into its
+	// neighbouring page where it fits
+	merged := pages[:1]
+	for _, page := range pages[1:] {
+		last := &merged[len(merged)-1]
+		if discordLen(*last)+discordLen(page) <= discordMsgLimit {
+			*last += page
+		} else {
+			merged = append(merged, page)
+		}
+	}
+
+	return merged
+}
+
 func tdCalCmdHandler(ctx context.Context,
 	inter *discordgo.Interaction) *discordgo.InteractionResponse {
 
@@ -112,50 +175,52 @@ func tdCalCmdHandler(ctx context.Context,
 	}
 
 	data := inter.ApplicationCommandData()
-	days := int64(14)  // default
+	days := int64(internal.DefaultDays)
 	broadcast := false // default
+	detailed := false  // default
+	dow := ""          // default
 	if len(data.Options) > 0 {
 		for _, opt := range data.Options[0].Options {
 			if opt.Name == "days" {
 				days = opt.IntValue()
 			} else if opt.Name == "broadcast" {
 				broadcast = opt.BoolValue()
+			} else if opt.Name == "detailed" {
+				detailed = opt.BoolValue()
+			} else if opt.Name == "dow" {
+				dow = opt.StringValue()
 			}
 		}
 	}
-	// enforce bounds
-	if days <= 0 {
-		days = 14
-	} else if days > 60 {
-		days = 60
-	}
+	days = int64(internal.ClampDays(int(days)))
 
-	now := time.Now()
-	nowDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	end := nowDate.AddDate(0, 0, int(days))
+	start, end := bcc.CalWindow(int(days))
 
 	// Fetch events from BCC API
-	events, err := bcc.GetEvents()
+	events, err := bcc.GetEvents(ctx)
 	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Error fetching events: %v", err)
-		log.Printf("discordbot.cal: %v", resp.Data.Content)
+		resp.Data.Content = fetchErrorContent("events", err)
+		log.Printf("discordbot.cal: fetching events: %v", err)
 		return resp
 	}
-
-	// Filter and group events by date
-	eventsByDate := make(map[string][]bcc.Event)
-	for _, ev := range events {
-		// truncate event date to local date for inclusive comparison
-		evDate := time.Date(ev.Date.Year(), ev.Date.Month(), ev.Date.Day(), 0, 0, 0, 0, nowDate.Location())
-		if evDate.Before(nowDate) || evDate.After(end) {
-			continue
+	what := "events"
+	if dow != "" {
+		weekday, err := bcc.ParseWeekday(dow)
+		if err != nil {
+			resp.Data.Content = fmt.Sprintf("Please provide a valid dow: %v.",
+				err)
+			return resp
 		}
-		key := ev.Date.Format("2006-01-02")
-		eventsByDate[key] = append(eventsByDate[key], ev)
+		events = bcc.FilterEventsByWeekday(events, weekday)
+		what = weekday.String() + " events"
 	}
 
+	// Filter and group events by date
+	eventsByDate := bcc.EventsByDate(events, start, end)
+
 	if len(eventsByDate) == 0 {
-		resp.Data.Content = fmt.Sprintf("No events found in the next %d days.", days)
+		resp.Data.Content = fmt.Sprintf("No %v found in the next %d days.",
+			what, days)
 		log.Printf("discordbot.cal: %v", resp.Data.Content)
 		return resp
 	}
@@ -166,11 +231,24 @@ func tdCalCmdHandler(ctx context.Context,
 		datesList = append(datesList, d)
 	}
 	sort.Strings(datesList)
+	var details map[int64]*bcc.EventDetail
+	if detailed {
+		var eventIds []int64
+		for _, evs := range eventsByDate {
+			for _, ev := range evs {
+				eventIds = append(eventIds, int64(ev.EventID))
+			}
+		}
+		details = bcc.GetEventDetails(ctx, eventIds)
+	}
 	var sb strings.Builder
 	for _, d := range datesList {
 		sb.WriteString(fmt.Sprintf("**%s**\n", d))
 		for _, ev := range eventsByDate[d] {
 			sb.WriteString(fmt.Sprintf("- %v (EventID:%v)\n", ev.Title, ev.EventID))
+			if detail, ok := details[int64(ev.EventID)]; ok {
+				sb.WriteString(fmt.Sprintf("  - %v\n", bcc.BuildEventSummary(detail)))
+			}
 		}
 	}
 	sb.WriteString("\nRun /td event <EventID> to get details on a specific event\n")
@@ -178,6 +256,8 @@ func tdCalCmdHandler(ctx context.Context,
 
 	if broadcast {
 		resp.Data.Flags = 0
+	} else {
+		addShareButton(resp, TdCalCmd, inter)
 	}
 
 	return resp
@@ -217,10 +297,11 @@ func tdEventCmdHandler(ctx context.Context,
 		return resp
 	}
 
-	detail, err := bcc.GetEventDetail(eventID)
+	detail, err := bcc.GetEventDetail(ctx, eventID)
 	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Error fetching event %d: %v", eventID, err)
-		log.Printf("discordbot.event: %v", resp.Data.Content)
+		resp.Data.Content = fetchErrorContent(fmt.Sprintf("event %d", eventID),
+			err)
+		log.Printf("discordbot.event: fetching event %d: %v", eventID, err)
 		return resp
 	}
 
@@ -233,6 +314,8 @@ func tdEventCmdHandler(ctx context.Context,
 	resp.Data.Embeds = []*discordgo.MessageEmbed{embed}
 	if broadcast {
 		resp.Data.Flags = 0
+	} else {
+		addShareButton(resp, TdEventCmd, inter)
 	}
 
 	return resp
@@ -251,6 +334,9 @@ func tdCrossTableCmdHandler(ctx context.Context,
 	data := inter.ApplicationCommandData()
 	broadcast := false // default
 	section := ""
+	page := 0
+	lastRounds := 0
+	combined := false
 	var eventID int64
 	if len(data.Options) > 0 {
 		found := false
@@ -262,6 +348,12 @@ func tdCrossTableCmdHandler(ctx context.Context,
 				broadcast = opt.BoolValue()
 			} else if opt.Name == "section" {
 				section = opt.StringValue()
+			} else if opt.Name == "page" {
+				page = int(opt.IntValue())
+			} else if opt.Name == "lastrounds" {
+				lastRounds = max(int(opt.IntValue()), 0)
+			} else if opt.Name == "combined" {
+				combined = opt.BoolValue()
 			}
 		}
 		if !found {
@@ -275,10 +367,11 @@ func tdCrossTableCmdHandler(ctx context.Context,
 		return resp
 	}
 
-	detail, err := bcc.GetEventDetail(eventID)
+	detail, err := bcc.GetEventDetail(ctx, eventID)
 	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Error fetching event %d: %v", eventID, err)
-		log.Printf("discordbot.xt: %v", resp.Data.Content)
+		resp.Data.Content = fetchErrorContent(fmt.Sprintf("event %d", eventID),
+			err)
+		log.Printf("discordbot.xt: fetching event %d: %v", eventID, err)
 		return resp
 	}
 
@@ -288,20 +381,36 @@ func tdCrossTableCmdHandler(ctx context.Context,
 		log.Printf("discordbot.xt: %v", resp.Data.Content)
 		return resp
 	}
-	t, err := uschessClient.GetTournament(ctx, uschess.EventID(strconv.FormatInt(int64(detail.UscfTid), 10)))
+	// the combined summary covers every section, not just those shown
+	fetchSection := section
+	if combined {
+		fetchSection = ""
+	}
+	t, err := fetchCrossTables(ctx,
+		uschess.EventID(strconv.FormatInt(int64(detail.UscfTid), 10)),
+		fetchSection)
 	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Error fetching crosstables for eventid %d: %v", eventID, err)
-		log.Printf("discordbot.xt: %v", resp.Data.Content)
+		resp.Data.Content = fetchErrorContent(
+			fmt.Sprintf("crosstables for eventid %d", eventID), err)
+		log.Printf("discordbot.xt: fetching crosstables for eventid %d: %v",
+			eventID, err)
 		return resp
 	}
 
 	var sb strings.Builder
+	sectionOrder := uscfutils.SectionOrder(t)
+	if combined {
+		sb.WriteString(uscfutils.BuildCrossTablesSummary(t))
+		sectionOrder = uscfutils.CombinedSectionOrder(t)
+	}
+	prefix := sb.String()
 	sectionList := ""
 	sectionCount := 0
-	for i, xt := range t.SectionStandings {
-		sectionDetail := t.Sections[i]
-		if section != "" &&
-			!strings.Contains(strings.ToLower(sectionDetail.Name), strings.ToLower(section)) {
+	lastTable := ""
+	lastPlayers := 0
+	for _, i := range sectionOrder {
+		sectionDetail, xt := t.Sections[i], t.SectionStandings[i]
+		if !bcc.SectionMatches(sectionDetail.Name, section) {
 			continue
 		}
 		if sectionList == "" {
@@ -309,20 +418,41 @@ func tdCrossTableCmdHandler(ctx context.Context,
 		} else {
 			sectionList = fmt.Sprintf("%v, %v", sectionList, sectionDetail.Name)
 		}
+		if xt == nil {
+			// the section failed to load
+			sb.WriteString(fmt.Sprintf("%v data unavailable\n\n",
+				internal.DisplaySectionName(sectionDetail.Name)))
+			continue
+		}
 		output, _ := uscfutils.BuildCrossTableOutput(sectionDetail, xt,
-			len(t.SectionStandings) > 1, "")
+			uscfutils.CrossTableOptions{
+				IncludeSectionHeader: uscfutils.MultiSection(t),
+				LastRounds:           lastRounds,
+			})
 		sb.WriteString(output)
 		sectionCount++
+		lastTable, lastPlayers = output, len(xt)
 	}
 
-	// Wrap output in code block for monospace formatting in Discord
-	content, truncated := truncateContent(sb.String())
-	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
+	pages, truncated := paginateContent(sb.String())
 	if truncated && section == "" && sectionCount > 1 {
 		resp.Data.Content = fmt.Sprintf("Too much data. Please try again and specify one of the following sections: %v", sectionList)
 		log.Printf("discordbot.xt: %v", resp.Data.Content)
 		return resp
 	}
+	if truncated && sectionCount == 1 {
+		// a lone section is cut at a row boundary rather than mid-table
+		hint := "; use lastrounds to fit more"
+		if lastRounds == 1 {
+			hint = ""
+		}
+		pages, _ = fitCrossTable(prefix, lastTable, lastPlayers, hint)
+	}
+	page = min(max(page, 0), len(pages)-1)
+
+	// Wrap output in code block for monospace formatting in Discord
+	resp.Data.Content = fmt.Sprintf("```\n%s```", pages[page])
+	addPageButtons(resp, TdCrossTableCmd, inter, page, len(pages))
 
 	if broadcast {
 		resp.Data.Flags = 0
@@ -343,7 +473,9 @@ func tdPairingsCmdHandler(ctx context.Context,
 	}
 	data := inter.ApplicationCommandData()
 	broadcast := false // default
-	var eventID int64
+	thread := false    // default
+	section := ""
+	var eventID, round int64
 	if len(data.Options) > 0 {
 		found := false
 		for _, opt := range data.Options[0].Options {
@@ -352,6 +484,12 @@ func tdPairingsCmdHandler(ctx context.Context,
 				found = true
 			} else if opt.Name == "broadcast" {
 				broadcast = opt.BoolValue()
+			} else if opt.Name == "thread" {
+				thread = opt.BoolValue()
+			} else if opt.Name == "section" {
+				section = opt.StringValue()
+			} else if opt.Name == "round" {
+				round = opt.IntValue()
 			}
 		}
 		if !found {
@@ -364,26 +502,217 @@ func tdPairingsCmdHandler(ctx context.Context,
 		log.Printf("discordbot.pairings: %v", resp.Data.Content)
 		return resp
 	}
-	tourney, err := bcc.GetTournament(eventID)
+	note, final := bcc.EventStatusOutput(ctx, uschessClient, eventID)
+	if round > 0 {
+		output, ok, err := bcc.RoundPairingsOutput(ctx, uschessClient, eventID,
+			section, int(round))
+		if err != nil {
+			log.Printf("discordbot.pairings: round %v: %v", round, err)
+		}
+		if ok {
+			note, final = "", output
+		} else {
+			note = fmt.Sprintf("Round %v pairings are only available once the event is filed with US Chess; showing current pairings instead.\n\n",
+				round) + note
+			final = ""
+		}
+	}
+	output := final
+	links := ""
+	if final == "" {
+		tourney, err := bcc.GetTournament(ctx, eventID)
+		if err != nil {
+			resp.Data.Content = fmt.Sprintf("Error fetching pairings for event %d: %v",
+				eventID, err)
+			log.Printf("discordbot.pairings: %v", resp.Data.Content)
+			return resp
+		}
+		if len(tourney.CurrentPairings) == 0 {
+			resp.Data.Content = fmt.Sprintf("No pairings found for event %d.",
+				eventID)
+			log.Printf("discordbot.pairings: %v", resp.Data.Content)
+			return resp
+		}
+		output = bcc.BuildPairingsOutput(tourney, false, section, 0)
+		links = bcc.BuildGameLinksOutput(tourney, section)
+	}
+	// Wrap output in code block for monospace formatting in Discord
+	content, _ := truncateContent(note + output)
+	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
+	// live game links go outside the code block so that they are clickable
+	if links != "" &&
+		discordLen(resp.Data.Content)+discordLen(links)+1 <= discordMsgLimit {
+		resp.Data.Content += "\n" + links
+	}
+
+	if links != "" {
+		// avoid a preview embed per live game
+		resp.Data.Flags |= discordgo.MessageFlagsSuppressEmbeds
+	}
+	if thread {
+		return postToThread(resp, eventID, inter)
+	}
+	if broadcast {
+		resp.Data.Flags &^= discordgo.MessageFlagsEphemeral
+	} else {
+		addShareButton(resp, TdPairingsCmd, inter)
+	}
+
+	return resp
+}
+
+// tdFeaturedCmdHandler handles the /td featured command listing the current
+// round's top boards by combined rating for a broadcast post
+func tdFeaturedCmdHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	data := inter.ApplicationCommandData()
+	n := int64(bcc.DefaultFeaturedBoards) // default
+	broadcast := false                    // default
+	var eventID int64
+	if len(data.Options) > 0 {
+		for _, opt := range data.Options[0].Options {
+			if opt.Name == "eventid" {
+				eventID = opt.IntValue()
+			} else if opt.Name == "n" {
+				n = opt.IntValue()
+			} else if opt.Name == "broadcast" {
+				broadcast = opt.BoolValue()
+			}
+		}
+	}
+	if eventID == 0 {
+		resp.Data.Content = "Please provide an event ID."
+		log.Printf("discordbot.featured: %v", resp.Data.Content)
+		return resp
+	}
+	n = max(n, 1)
+
+	tourney, err := bcc.GetTournament(ctx, eventID)
 	if err != nil {
 		resp.Data.Content = fmt.Sprintf("Error fetching pairings for event %d: %v",
 			eventID, err)
-		log.Printf("discordbot.pairings: %v", resp.Data.Content)
+		log.Printf("discordbot.featured: %v", resp.Data.Content)
 		return resp
 	}
 	if len(tourney.CurrentPairings) == 0 {
 		resp.Data.Content = fmt.Sprintf("No pairings found for event %d.",
 			eventID)
-		log.Printf("discordbot.pairings: %v", resp.Data.Content)
+		log.Printf("discordbot.featured: %v", resp.Data.Content)
 		return resp
 	}
+	boards := bcc.FeaturedBoards(tourney, int(n))
 	// Wrap output in code block for monospace formatting in Discord
-	content, _ := truncateContent(bcc.BuildPairingsOutput(tourney))
+	content, _ := truncateContent(bcc.BuildFeaturedBoardsOutput(boards))
 	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
+	// live game links go outside the code block so that they are clickable
+	if links := bcc.BuildBoardLinksOutput(boards); links != "" {
+		if discordLen(resp.Data.Content)+discordLen(links)+1 <= discordMsgLimit {
+			resp.Data.Content += "\n" + links
+		}
+		// avoid a preview embed per live game
+		resp.Data.Flags |= discordgo.MessageFlagsSuppressEmbeds
+	}
 
 	if broadcast {
-		resp.Data.Flags = 0
+		resp.Data.Flags &^= discordgo.MessageFlagsEphemeral
+	} else {
+		addShareButton(resp, TdFeaturedCmd, inter)
+	}
+
+	return resp
+}
+
+// tdMyGameCmdHandler handles the /td mygame command to privately tell a
+// player their current board, color, and opponent
+func tdMyGameCmdHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	data := inter.ApplicationCommandData()
+	var eventID int64
+	name := ""
+	if len(data.Options) > 0 {
+		for _, opt := range data.Options[0].Options {
+			if opt.Name == "eventid" {
+				eventID = opt.IntValue()
+			} else if opt.Name == "name" {
+				name = strings.TrimSpace(opt.StringValue())
+			}
+		}
+	}
+	if eventID == 0 || name == "" {
+		resp.Data.Content = "Please provide an event ID and your name."
+		log.Printf("discordbot.mygame: %v", resp.Data.Content)
+		return resp
+	}
+
+	tourney, err := bcc.GetTournament(ctx, eventID)
+	if err != nil {
+		resp.Data.Content = fmt.Sprintf("Error fetching pairings for event %d: %v",
+			eventID, err)
+		log.Printf("discordbot.mygame: %v", resp.Data.Content)
+		return resp
+	}
+	if len(tourney.CurrentPairings) == 0 {
+		resp.Data.Content = fmt.Sprintf("No pairings found for event %d.",
+			eventID)
+		log.Printf("discordbot.mygame: %v", resp.Data.Content)
+		return resp
 	}
+	resp.Data.Content, _ = truncateContent(bcc.BuildMyGameOutput(tourney, name))
+
+	return resp
+}
+
+// tdMyByesCmdHandler handles the /td mybyes command to privately tell a
+// player which rounds they requested byes for
+func tdMyByesCmdHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	data := inter.ApplicationCommandData()
+	var eventID, memID int64
+	if len(data.Options) > 0 {
+		for _, opt := range data.Options[0].Options {
+			if opt.Name == "eventid" {
+				eventID = opt.IntValue()
+			} else if opt.Name == "memid" {
+				memID = opt.IntValue()
+			}
+		}
+	}
+	if eventID == 0 || memID <= 0 {
+		resp.Data.Content = "Please provide an event ID and your USCF member id."
+		log.Printf("discordbot.mybyes: %v", resp.Data.Content)
+		return resp
+	}
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		resp.Data.Content = fetchErrorContent(fmt.Sprintf("event %d", eventID),
+			err)
+		log.Printf("discordbot.mybyes: fetching event %d: %v", eventID, err)
+		return resp
+	}
+	resp.Data.Content, _ = truncateContent(bcc.BuildMyByesOutput(&detail,
+		int(memID)))
 
 	return resp
 }
@@ -421,7 +750,7 @@ func tdEntriesCmdHandler(ctx context.Context,
 		log.Printf("discordbot.pairings: %v", resp.Data.Content)
 		return resp
 	}
-	tourney, err := bcc.GetTournament(eventID)
+	tourney, err := bcc.GetTournament(ctx, eventID)
 	if err != nil {
 		resp.Data.Content = fmt.Sprintf("Error fetching pairings for event %d: %v",
 			eventID, err)
@@ -435,7 +764,8 @@ func tdEntriesCmdHandler(ctx context.Context,
 		return resp
 	}
 	// Wrap output in code block for monospace formatting in Discord
-	content, _ := truncateContent(bcc.BuildEntriesOutput(tourney))
+	content, _ := truncateContent(bcc.BuildEntriesOutput(tourney,
+		maskPublicIDs(broadcast)))
 	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
 
 	if broadcast {
@@ -458,6 +788,9 @@ func tdStandingsCmdHandler(ctx context.Context,
 	}
 	data := inter.ApplicationCommandData()
 	broadcast := false // default
+	thread := false    // default
+	section := ""
+	page := 0
 	var eventID int64
 	if len(data.Options) > 0 {
 		found := false
@@ -467,6 +800,12 @@ func tdStandingsCmdHandler(ctx context.Context,
 				found = true
 			} else if opt.Name == "broadcast" {
 				broadcast = opt.BoolValue()
+			} else if opt.Name == "thread" {
+				thread = opt.BoolValue()
+			} else if opt.Name == "section" {
+				section = opt.StringValue()
+			} else if opt.Name == "page" {
+				page = int(opt.IntValue())
 			}
 		}
 		if !found {
@@ -479,25 +818,119 @@ func tdStandingsCmdHandler(ctx context.Context,
 		log.Printf("discordbot.standings: %v", resp.Data.Content)
 		return resp
 	}
-	tourney, err := bcc.GetTournament(eventID)
-	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Error fetching standings for event %d: %v",
-			eventID, err)
-		log.Printf("discordbot.standings: %v", resp.Data.Content)
-		return resp
+	note, final := bcc.EventStatusOutput(ctx, uschessClient, eventID)
+	output := final
+	var embeds []*discordgo.MessageEmbed
+	if final == "" {
+		tourney, err := bcc.GetTournament(ctx, eventID)
+		if err != nil {
+			resp.Data.Content = fmt.Sprintf("Error fetching standings for event %d: %v",
+				eventID, err)
+			log.Printf("discordbot.standings: %v", resp.Data.Content)
+			return resp
+		}
+		standingsErr := bcc.CheckStandingsAvailable(tourney)
+		if standingsErr == nil {
+			// small standings read better on mobile as embeds than as a
+			// monospace table
+			embeds = buildStandingsEmbeds(bcc.BuildStandingsSections(tourney,
+				section))
+		}
+		if standingsErr != nil {
+			output = bcc.BuildStandingsUnavailableOutput(tourney, section,
+				standingsMobileWidth)
+		} else if embeds != nil {
+			output = bcc.StandingsSourceHeader(tourney)
+			if footer := bcc.DataAgeFooter(tourney); footer != "" {
+				output += "\n" + footer
+			}
+		} else {
+			output = bcc.BuildStandingsOutput(tourney, section,
+				standingsMobileWidth)
+		}
 	}
 
-	// Wrap output in code block for monospace formatting in Discord
-	content, _ := truncateContent(bcc.BuildStandingsOutput(tourney))
-	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
+	if embeds != nil {
+		resp.Data.Content, _ = truncateContent(note + output)
+		resp.Data.Embeds = embeds
+	} else {
+		pages, _ := paginateContent(note + output)
+		page = min(max(page, 0), len(pages)-1)
+
+		// Wrap output in code block for monospace formatting in Discord
+		resp.Data.Content = fmt.Sprintf("```\n%s```", pages[page])
+		addPageButtons(resp, TdStandingsCmd, inter, page, len(pages))
+	}
 
+	if thread {
+		return postToThread(resp, eventID, inter)
+	}
 	if broadcast {
 		resp.Data.Flags = 0
+	} else {
+		addShareButton(resp, TdStandingsCmd, inter)
 	}
 
 	return resp
 }
 
+// standings with more players than this (or more sections than a message may
+// hold embeds) fall back to a code block table
+const (
+	standingsEmbedMaxPlayers  = 20
+	standingsEmbedMaxSections = 10 // discord limit
+	// code block tables wider than this wrap on most phone screens
+	standingsMobileWidth = 40
+)
+
+// buildStandingsEmbeds renders standings as one embed per section with
+// Place, Name, and Score fields. It returns nil when the standings are too
+// large to read well as embeds.
+func buildStandingsEmbeds(
+	sections []bcc.StandingsSection) []*discordgo.MessageEmbed {
+
+	numPlayers := 0
+	for _, sec := range sections {
+		numPlayers += len(sec.Rows)
+	}
+	if numPlayers == 0 || numPlayers > standingsEmbedMaxPlayers ||
+		len(sections) > standingsEmbedMaxSections {
+		return nil
+	}
+
+	embeds := make([]*discordgo.MessageEmbed, 0, len(sections))
+	for _, sec := range sections {
+		places := make([]string, 0, len(sec.Rows))
+		names := make([]string, 0, len(sec.Rows))
+		scores := make([]string, 0, len(sec.Rows))
+		for _, r := range sec.Rows {
+			place := r.Place
+			if place == "" {
+				// keep tied rows aligned; discord trims blank lines
+				place = "\u200b"
+			}
+			places = append(places, place)
+			names = append(names, r.Name)
+			scores = append(scores, r.Score)
+		}
+		title := "Standings"
+		if sec.Name != "" {
+			title = fmt.Sprintf("%s (%v players)",
+				internal.DisplaySectionName(sec.Name), len(sec.Rows))
+		}
+		embeds = append(embeds, &discordgo.MessageEmbed{
+			Title: title,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "Place", Value: strings.Join(places, "\n"), Inline: true},
+				{Name: "Name", Value: strings.Join(names, "\n"), Inline: true},
+				{Name: "Score", Value: strings.Join(scores, "\n"), Inline: true},
+			},
+		})
+	}
+
+	return embeds
+}
+
 // tdPlayerCmdHandler handles the /td player command to display information
 // regarding a specific player
 func tdPlayerCmdHandler(ctx context.Context,
@@ -512,33 +945,42 @@ func tdPlayerCmdHandler(ctx context.Context,
 	data := inter.ApplicationCommandData()
 	broadcast := false // default
 	var memID int64
+	name := ""
 	if len(data.Options) > 0 {
-		found := false
 		for _, opt := range data.Options[0].Options {
 			if opt.Name == "memid" {
 				memID = opt.IntValue()
-				found = true
+			} else if opt.Name == "name" {
+				name = opt.StringValue()
 			} else if opt.Name == "broadcast" {
 				broadcast = opt.BoolValue()
 			}
 		}
-		if !found {
-			resp.Data.Content = "Please provide a USCF member ID."
+	}
+	if memID == 0 && name != "" {
+		var msg string
+		memID, msg = resolveMemberName(ctx, name)
+		if msg != "" {
+			resp.Data.Content = msg
 			log.Printf("discordbot.player: %v", resp.Data.Content)
 			return resp
 		}
-	} else {
-		resp.Data.Content = "Please provide a USCF member ID."
+	}
+	if memID == 0 {
+		resp.Data.Content = "Please provide a USCF member ID or name."
 		log.Printf("discordbot.player: %v", resp.Data.Content)
 		return resp
 	}
 
-	report, err := uscfutils.BuildPlayerReport(ctx, uschessClient,
-		uschess.MemberID(strconv.FormatInt(memID, 10)), 3 /* eventCount */)
+	report, err := uscfutils.BuildPlayerReport(ctx,
+		uschessClient,
+		uschess.MemberID(strconv.FormatInt(memID, 10)), 3, /* eventCount */
+		uscfutils.DefaultRecordEventCount, maskPublicIDs(broadcast))
 	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Error fetching player %v report: %v",
-			memID, err)
-		log.Printf("discordbot.player: %v", resp.Data.Content)
+		resp.Data.Content = fetchErrorContent(fmt.Sprintf("player %v", memID),
+			err)
+		log.Printf("discordbot.player: fetching player %v report: %v", memID,
+			err)
 		return resp
 	}
 
@@ -553,17 +995,184 @@ func tdPlayerCmdHandler(ctx context.Context,
 	return resp
 }
 
+func tdRecentCmdHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	data := inter.ApplicationCommandData()
+	count := int64(5)  // default
+	broadcast := false // default
+	if len(data.Options) > 0 {
+		for _, opt := range data.Options[0].Options {
+			if opt.Name == "count" {
+				count = opt.IntValue()
+			} else if opt.Name == "broadcast" {
+				broadcast = opt.BoolValue()
+			}
+		}
+	}
+	count = min(max(count, 1), uscfutils.MaxRecentEvents)
+
+	events, err := uscfutils.GetRecentEventWinners(ctx,
+		uschessClient.ClientWithResponses,
+		uschess.AffiliateID(internal.BccUSCFAffiliateID), int(count))
+	if err != nil {
+		resp.Data.Content = fmt.Sprintf("Error fetching recent events: %v", err)
+		log.Printf("discordbot.recent: %v", resp.Data.Content)
+		return resp
+	}
+
+	// Wrap output in code block for monospace formatting in Discord
+	content, _ := truncateContent(uscfutils.BuildRecentEventsOutput(events))
+	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
+
+	if broadcast {
+		resp.Data.Flags = 0
+	} else {
+		addShareButton(resp, TdRecentCmd, inter)
+	}
+
+	return resp
+}
+
+// tdGainersCmdHandler handles the /td gainers command listing the players
+// who gained and lost the most rating in a completed event
+func tdGainersCmdHandler(ctx context.Context,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}
+	data := inter.ApplicationCommandData()
+	count := int64(uscfutils.DefaultGainersCount) // default
+	broadcast := false                            // default
+	var eventID int64
+	if len(data.Options) > 0 {
+		for _, opt := range data.Options[0].Options {
+			if opt.Name == "eventid" {
+				eventID = opt.IntValue()
+			} else if opt.Name == "count" {
+				count = opt.IntValue()
+			} else if opt.Name == "broadcast" {
+				broadcast = opt.BoolValue()
+			}
+		}
+	}
+	if eventID == 0 {
+		resp.Data.Content = "Please provide an event ID."
+		log.Printf("discordbot.gainers: %v", resp.Data.Content)
+		return resp
+	}
+	count = max(count, 1)
+
+	detail, err := bcc.GetEventDetail(ctx, eventID)
+	if err != nil {
+		resp.Data.Content = fetchErrorContent(fmt.Sprintf("event %d", eventID),
+			err)
+		log.Printf("discordbot.gainers: fetching event %d: %v", eventID, err)
+		return resp
+	}
+	if detail.UscfTid == 0 {
+		resp.Data.Content = fmt.Sprintf("The club has not yet filed event %v with USCF; please try again once the club files it.",
+			eventID)
+		log.Printf("discordbot.gainers: %v", resp.Data.Content)
+		return resp
+	}
+	t, err := uschessClient.GetCrossTables(ctx,
+		uschess.EventID(strconv.Itoa(detail.UscfTid)))
+	if err != nil {
+		resp.Data.Content = fetchErrorContent(
+			fmt.Sprintf("crosstables for eventid %d", eventID), err)
+		log.Printf("discordbot.gainers: fetching crosstables for eventid %d: %v",
+			eventID, err)
+		return resp
+	}
+
+	changes, notYetRated := uscfutils.RatingChanges(t)
+	// Wrap output in code block for monospace formatting in Discord
+	content, _ := truncateContent(uscfutils.BuildGainersOutput(changes,
+		notYetRated, int(count)))
+	resp.Data.Content = fmt.Sprintf("```\n%s```", content)
+
+	if broadcast {
+		resp.Data.Flags = 0
+	} else {
+		addShareButton(resp, TdGainersCmd, inter)
+	}
+
+	return resp
+}
+
+// fetchCrossTables retrieves an event's crosstables. With a section filter
+// only the matching section's standings are fetched when exactly one section
+// matches; otherwise, or should that fail, every section is fetched.
+func fetchCrossTables(ctx context.Context, eventID uschess.EventID,
+	section string) (*uschess.Tournament, error) {
+
+	if section != "" {
+		t, err := uschessClient.FetchSectionCrossTable(ctx, eventID, section)
+		if err == nil {
+			return t, nil
+		}
+		log.Printf("discordbot.xt: fetching all sections: %v", err)
+	}
+
+	return uschessClient.GetCrossTables(ctx, eventID)
+}
+
+// fetchErrorContent describes a failure to fetch what (e.g. "event 1312")
+// to the user, telling an id which does not exist apart from a service
+// which is down and worth retrying later.
+func fetchErrorContent(what string, err error) string {
+	switch {
+	case errors.Is(err, internal.ErrNotFound):
+		return fmt.Sprintf("Could not find %v; please check the id and try again.",
+			what)
+	case errors.Is(err, internal.ErrUpstreamUnavailable):
+		return fmt.Sprintf("Error fetching %v: the service is temporarily unavailable; please try again later.",
+			what)
+	}
+
+	return fmt.Sprintf("Error fetching %v: %v", what, err)
+}
+
 // https://discord.com/developers/docs/resources/channel#start-thread-in-forum-or-media-channel-forum-and-media-thread-message-params-object
-// limits messages to 2k characters
+// limits messages to 2k characters, as counted by discordLen
 func truncateContent(s string) (string, bool) {
-	truncated := false
 	const MsgLimit = 1988 // keep space for newlines and markdown
-	runes := []rune(s)
-	if len(runes) > MsgLimit {
-		s = fmt.Sprintf("%v...", string(runes[:MsgLimit]))
-		truncated = true
+	if discordLen(s) <= MsgLimit {
+		return s, false
 	}
-	return s, truncated
+
+	n := 0
+	for idx, r := range s {
+		// never split a surrogate pair
+		if n+utf16.RuneLen(r) > MsgLimit {
+			s = s[:idx]
+			break
+		}
+		n += utf16.RuneLen(r)
+	}
+	return s + "...", true
+}
+
+// discordLen returns the length of s as discord counts it against its
+// message limits: in UTF-16 code units, so that an emoji or other
+// character outside the Basic Multilingual Plane counts as 2.
+func discordLen(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
 }
 
 func parseMemIDList(s string) ([]uschess.MemberID, error) {
@@ -604,7 +1213,7 @@ func tdEstRatingCmdHandler(ctx context.Context,
 	data := inter.ApplicationCommandData()
 	broadcast := false // default
 	var score float64
-	var memID int64
+	var memID, unratedRating, eventID int64
 	opponentsText := ""
 
 	if len(data.Options) > 0 {
@@ -618,6 +1227,10 @@ func tdEstRatingCmdHandler(ctx context.Context,
 				opponentsText = opt.StringValue()
 			case "broadcast":
 				broadcast = opt.BoolValue()
+			case "unrated":
+				unratedRating = opt.IntValue()
+			case "eventid":
+				eventID = opt.IntValue()
 			}
 		}
 	}
@@ -645,16 +1258,41 @@ func tdEstRatingCmdHandler(ctx context.Context,
 		return resp
 	}
 
-	newRating, err := uschessClient.GetRatingEstimate(ctx,
-		uschess.MemberID(strconv.FormatInt(memID, 10)), opponentIDs, score, uschess.RatingTypeR)
-	if err != nil {
-		resp.Data.Content = fmt.Sprintf("Failed to estimate rating: %v", err)
-		log.Printf("discordbot.estrating: %v", resp.Data.Content)
-		return resp
+	if unratedRating > 0 || eventID > 0 {
+		subs := uscfutils.UnratedOpponentRatings{Default: int(unratedRating)}
+		if eventID > 0 {
+			detail, err := bcc.GetEventDetail(ctx, eventID)
+			if err != nil {
+				resp.Data.Content = fetchErrorContent(
+					fmt.Sprintf("event %d", eventID), err)
+				log.Printf("discordbot.estrating: %v", resp.Data.Content)
+				return resp
+			}
+			subs.Reported = bcc.EntryRatingsByID(&detail)
+		}
+		est, err := uscfutils.GetApproxRatingEstimate(ctx,
+			uschessClient.ClientWithResponses,
+			uschess.MemberID(strconv.FormatInt(memID, 10)), opponentIDs, score,
+			subs)
+		if err != nil {
+			resp.Data.Content = fmt.Sprintf("Failed to estimate rating: %v", err)
+			log.Printf("discordbot.estrating: %v", resp.Data.Content)
+			return resp
+		}
+		resp.Data.Content = uscfutils.BuildApproxRatingEstimateOutput(est)
+	} else {
+		newRating, err := uschessClient.GetRatingEstimate(ctx,
+			uschess.MemberID(strconv.FormatInt(memID, 10)), opponentIDs, score,
+			uschess.RatingTypeR)
+		if err != nil {
+			resp.Data.Content = fmt.Sprintf("Failed to estimate rating: %v", err)
+			log.Printf("discordbot.estrating: %v", resp.Data.Content)
+			return resp
+		}
+		resp.Data.Content = fmt.Sprintf("Estimated New Rating: %v",
+			newRating.PostRating)
 	}
 
-	resp.Data.Content = fmt.Sprintf("Estimated New Rating: %v", newRating.PostRating)
-
 	if broadcast {
 		resp.Data.Flags = 0
 	}
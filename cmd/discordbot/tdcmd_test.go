@@ -6,10 +6,17 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/bcc"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 )
 
 func TestTdCalCmdHandler(t *testing.T) {
@@ -94,3 +101,150 @@ func TestParseMemIDList(t *testing.T) {
 		t.Fatalf("expected 3 ids, got %v", len(ids))
 	}
 }
+
+func TestTruncateContent(t *testing.T) {
+	utf16Len := func(s string) int { return len(utf16.Encode([]rune(s))) }
+
+	for _, s := range []string{
+		strings.Repeat("x", 1988),
+		strings.Repeat("é", 1988), // 2 bytes but 1 code unit each
+		strings.Repeat("♞", 1000), // 3 bytes but 1 code unit each
+	} {
+		if got, truncated := truncateContent(s); truncated || got != s {
+			t.Errorf("truncateContent(%q...) truncated", s[:3])
+		}
+	}
+
+	// 1000 emoji are 1000 runes but 2000 code units; an odd limit must not
+	// split a surrogate pair
+	for _, s := range []string{
+		strings.Repeat("♟️", 1000), // a pawn and its variation selector
+		strings.Repeat("😀", 1000),
+		"x" + strings.Repeat("😀", 1000),
+	} {
+		got, truncated := truncateContent(s)
+		if !truncated || !utf8.ValidString(got) ||
+			!strings.HasSuffix(got, "...") {
+
+			t.Errorf("truncateContent(%q...) = %q..., %v", s[:4], got[:4],
+				truncated)
+		}
+		if n := utf16Len(got); n > 1988+3 || n < 1988+3-1 {
+			t.Errorf("truncateContent(%q...) is %v code units", s[:4], n)
+		}
+		if discordLen(got) != utf16Len(got) {
+			t.Errorf("discordLen() = %v; want %v", discordLen(got),
+				utf16Len(got))
+		}
+	}
+}
+
+func TestFetchErrorContent(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("unable to fetch bcc event detail (http): %w",
+			internal.HTTPStatusError(404)),
+			"Could not find event 1312; please check the id and try again."},
+		{fmt.Errorf("unable to fetch bcc event detail (http): %w",
+			internal.HTTPStatusError(503)),
+			"Error fetching event 1312: the service is temporarily unavailable; please try again later."},
+		{errors.New("unable to parse bcc event detail"),
+			"Error fetching event 1312: unable to parse bcc event detail"},
+	}
+	for _, tc := range tests {
+		if got := fetchErrorContent("event 1312", tc.err); got != tc.want {
+			t.Errorf("fetchErrorContent(%v) = %q; want %q", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestBuildStandingsEmbeds(t *testing.T) {
+	sections := []bcc.StandingsSection{
+		{Name: "Open", Rows: []bcc.StandingsRow{
+			{Place: "1.", Name: "Alice Able", Score: "3"},
+			{Place: "2.", Name: "Bob Baker", Score: "2½"},
+			{Place: "", Name: "Cal Cole", Score: "2½"},
+		}},
+		{Name: "U1600", Rows: []bcc.StandingsRow{
+			{Place: "1.", Name: "Dee Dunn", Score: "2"},
+		}},
+	}
+
+	embeds := buildStandingsEmbeds(sections)
+	if len(embeds) != 2 {
+		t.Fatalf("len(embeds) = %v; want 2", len(embeds))
+	}
+	if embeds[0].Title != "Open Section (3 players)" {
+		t.Errorf("title = %q; want Open Section (3 players)", embeds[0].Title)
+	}
+	fields := embeds[0].Fields
+	if len(fields) != 3 || fields[0].Name != "Place" ||
+		fields[1].Name != "Name" || fields[2].Name != "Score" {
+		t.Fatalf("fields = %+v; want Place, Name, Score", fields)
+	}
+	if got := strings.Split(fields[0].Value, "\n"); len(got) != 3 ||
+		got[2] != "\u200b" {
+		t.Errorf("places = %q; want tied place kept as a blank line", got)
+	}
+	if fields[1].Value != "Alice Able\nBob Baker\nCal Cole" {
+		t.Errorf("names = %q", fields[1].Value)
+	}
+
+	large := bcc.StandingsSection{Name: "Open"}
+	for i := 0; i <= standingsEmbedMaxPlayers; i++ {
+		large.Rows = append(large.Rows, bcc.StandingsRow{Name: "Player"})
+	}
+	if embeds := buildStandingsEmbeds([]bcc.StandingsSection{large}); embeds != nil {
+		t.Errorf("large standings rendered as %v embeds; want code block",
+			len(embeds))
+	}
+	if embeds := buildStandingsEmbeds(nil); embeds != nil {
+		t.Errorf("empty standings rendered as embeds; want code block")
+	}
+}
+
+func TestTdHelpCmdHandlerPages(t *testing.T) {
+	var all strings.Builder
+	numPages := 1
+	for page := 0; page < numPages; page++ {
+		inter := subCmdInteraction(&discordgo.Interaction{}, TdHelpCmd,
+			[]*discordgo.ApplicationCommandInteractionDataOption{
+				{
+					Name:  "page",
+					Type:  discordgo.ApplicationCommandOptionInteger,
+					Value: float64(page),
+				},
+			})
+		resp := tdHelpCmdHandler(context.Background(), inter)
+		content := resp.Data.Content
+		if discordLen(content) > discordMsgLimit {
+			t.Errorf("page %v is %v long; want at most %v", page,
+				discordLen(content), discordMsgLimit)
+		}
+		if strings.Count(content, "```")%2 != 0 {
+			t.Errorf("page %v leaves a code block open:\n%v", page, content)
+		}
+		if page == 0 && len(resp.Data.Components) == 1 {
+			row := resp.Data.Components[0].(discordgo.ActionsRow)
+			next := row.Components[1].(discordgo.Button)
+			fmt.Sscanf(next.Label, "Next page (1/%d)", &numPages)
+		}
+		all.WriteString(content)
+	}
+	if numPages < 2 {
+		t.Fatalf("help has %v page(s); want it paginated", numPages)
+	}
+
+	for subCmd := range tdSubCmdHdlrs {
+		if want := "/td " + string(subCmd); !strings.Contains(all.String(),
+			want) {
+
+			t.Errorf("help pages missing %q", want)
+		}
+	}
+	if !strings.Contains(all.String(), "Share to channel") {
+		t.Errorf("help pages missing the closing notes")
+	}
+}
@@ -0,0 +1,112 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+// discord's limit on the length of a message
+const discordMsgLimit = 2000
+
+// threadSender posts a message to a discord channel or thread. It is a
+// variable so that tests need not reach discord.
+var threadSender = func(channelID string, msg *discordgo.MessageSend) error {
+	_, err := client.ChannelMessageSendComplex(channelID, msg)
+	return err
+}
+
+// parseThreadMap parses a comma separated list of key=threadID pairs as held
+// by internal.EventThreadsEnv and internal.GuildThreadsEnv. Malformed pairs
+// are logged and skipped.
+func parseThreadMap(envName string, val string) map[string]string {
+	threads := make(map[string]string)
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, threadID, ok := strings.Cut(pair, "=")
+		key, threadID = strings.TrimSpace(key), strings.TrimSpace(threadID)
+		if _, err := strconv.ParseUint(threadID, 10, 64); !ok || key == "" ||
+			err != nil {
+
+			log.Printf("discordbot.thread: ignoring invalid %v entry %q",
+				envName, pair)
+			continue
+		}
+		threads[key] = threadID
+	}
+
+	return threads
+}
+
+// threadFor returns the id of the thread configured for an event, falling
+// back to the one configured for the guild, or "" when neither is.
+func threadFor(eventID int64, guildID string) string {
+	events := parseThreadMap(internal.EventThreadsEnv,
+		internal.GetConfig().EventThreads)
+	if threadID, ok := events[strconv.FormatInt(eventID, 10)]; ok {
+		return threadID
+	}
+	if guildID == "" {
+		return ""
+	}
+
+	return parseThreadMap(internal.GuildThreadsEnv,
+		internal.GetConfig().GuildThreads)[guildID]
+}
+
+// postToThread sends a command's response to the thread configured for the
+// event and privately confirms doing so. When no thread is configured, or
+// the bot is unable to post to it (e.g. for lack of permission), the
+// response is broadcast to the channel instead with a note explaining why.
+func postToThread(resp *discordgo.InteractionResponse, eventID int64,
+	inter *discordgo.Interaction) *discordgo.InteractionResponse {
+
+	resp.Data.Flags &^= discordgo.MessageFlagsEphemeral
+
+	threadID := threadFor(eventID, inter.GuildID)
+	if threadID == "" {
+		return withNote(resp, "No thread is configured for this event; posting here instead.")
+	}
+	err := threadSender(threadID, &discordgo.MessageSend{
+		Content:    resp.Data.Content,
+		Embeds:     resp.Data.Embeds,
+		Components: resp.Data.Components,
+		Flags:      resp.Data.Flags,
+	})
+	if err != nil {
+		log.Printf("discordbot.thread: failed to post event %v to thread %v: %v",
+			eventID, threadID, err)
+		return withNote(resp, "Unable to post to the event's thread (the bot may lack permission there); posting here instead.")
+	}
+
+	return &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("Posted to <#%v>.", threadID),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	}
+}
+
+// withNote appends a note to a response's content when it fits within a
+// discord message.
+func withNote(resp *discordgo.InteractionResponse,
+	note string) *discordgo.InteractionResponse {
+
+	if discordLen(resp.Data.Content)+discordLen(note)+1 <= discordMsgLimit {
+		resp.Data.Content += "\n" + note
+	}
+
+	return resp
+}
@@ -0,0 +1,94 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+)
+
+func TestThreadFor(t *testing.T) {
+	internal.SetenvForTest(t, internal.EventThreadsEnv, "1312=111, bogus, 1313=abc")
+	internal.SetenvForTest(t, internal.GuildThreadsEnv, "900=222")
+
+	tests := []struct {
+		eventID int64
+		guildID string
+		want    string
+	}{
+		{1312, "900", "111"},
+		{1313, "900", "222"},
+		{1314, "901", ""},
+		{1314, "", ""},
+	}
+	for _, tc := range tests {
+		if got := threadFor(tc.eventID, tc.guildID); got != tc.want {
+			t.Errorf("threadFor(%v, %q) = %q; want %q", tc.eventID, tc.guildID,
+				got, tc.want)
+		}
+	}
+}
+
+func TestPostToThread(t *testing.T) {
+	internal.SetenvForTest(t, internal.EventThreadsEnv, "1312=111")
+	internal.SetenvForTest(t, internal.GuildThreadsEnv, "")
+	origSender := threadSender
+	defer func() { threadSender = origSender }()
+
+	newResp := func() *discordgo.InteractionResponse {
+		return &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: "pairings",
+				Flags: discordgo.MessageFlagsEphemeral |
+					discordgo.MessageFlagsSuppressEmbeds,
+			},
+		}
+	}
+	inter := &discordgo.Interaction{GuildID: "900"}
+
+	var sentTo string
+	var sent *discordgo.MessageSend
+	threadSender = func(channelID string, msg *discordgo.MessageSend) error {
+		sentTo, sent = channelID, msg
+		return nil
+	}
+	resp := postToThread(newResp(), 1312, inter)
+	if sentTo != "111" || sent.Content != "pairings" ||
+		sent.Flags != discordgo.MessageFlagsSuppressEmbeds {
+		t.Errorf("sent %+v to %q; want pairings to 111", sent, sentTo)
+	}
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral == 0 ||
+		!strings.Contains(resp.Data.Content, "<#111>") {
+		t.Errorf("unexpected confirmation: %+v", resp.Data)
+	}
+
+	// lacking permission to post to the thread falls back to the channel
+	threadSender = func(string, *discordgo.MessageSend) error {
+		return errors.New("HTTP 403 Forbidden, Missing Permissions")
+	}
+	resp = postToThread(newResp(), 1312, inter)
+	if resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0 ||
+		!strings.HasPrefix(resp.Data.Content, "pairings\n") ||
+		!strings.Contains(resp.Data.Content, "Unable to post") {
+		t.Errorf("unexpected fallback: %+v", resp.Data)
+	}
+
+	// as does an event without a thread
+	sentTo = ""
+	threadSender = func(channelID string, msg *discordgo.MessageSend) error {
+		sentTo = channelID
+		return nil
+	}
+	resp = postToThread(newResp(), 1313, inter)
+	if sentTo != "" || resp.Data.Flags&discordgo.MessageFlagsEphemeral != 0 ||
+		!strings.Contains(resp.Data.Content, "No thread is configured") {
+		t.Errorf("unexpected response without a thread: %+v", resp.Data)
+	}
+}
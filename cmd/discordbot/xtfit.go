@@ -0,0 +1,78 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// fitCrossTable paginates prefix followed by a single section's crosstable,
+// as rendered by uscfutils.BuildCrossTableOutput for numPlayers players. When
+// the whole table would not fit within maxContentPages pages, trailing
+// player rows are dropped rather than cutting the table mid-row, and a note
+// stating how many players are shown, followed by hint, takes their place.
+// The rows kept are unchanged, so the table remains aligned. The 2nd return
+// value indicates whether any rows were dropped.
+func fitCrossTable(prefix string, table string, numPlayers int,
+	hint string) ([]string, bool) {
+
+	pages, truncated := paginateContent(prefix + table)
+	if !truncated {
+		return pages, false
+	}
+
+	lines := strings.SplitAfter(table, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	end := len(lines)
+	if end > 0 && lines[end-1] == "\n" {
+		end--
+	}
+	footnote := ""
+	if end > 0 && strings.HasPrefix(lines[end-1], "*") {
+		footnote = lines[end-1]
+		end--
+	}
+	start := end - numPlayers
+	if start < 0 {
+		// not a crosstable of numPlayers players; fall back to plain pages
+		return pages, true
+	}
+	header := strings.Join(lines[:start], "")
+	rows := lines[start:end]
+
+	build := func(shown int) string {
+		var sb strings.Builder
+		sb.WriteString(prefix)
+		sb.WriteString(header)
+		forfeitShown := false
+		for _, row := range rows[:shown] {
+			sb.WriteString(row)
+			forfeitShown = forfeitShown || strings.Contains(row, "*")
+		}
+		if forfeitShown {
+			sb.WriteString(footnote)
+		}
+		sb.WriteString(fmt.Sprintf("(showing first %d of %d players%s)\n",
+			shown, numPlayers, hint))
+		return sb.String()
+	}
+
+	// find the most rows which fit; fitting is monotonic in the row count
+	lo, hi := 0, len(rows)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if _, over := paginateContent(build(mid)); over {
+			hi = mid - 1
+		} else {
+			lo = mid
+		}
+	}
+	pages, _ = paginateContent(build(lo))
+
+	return pages, true
+}
@@ -0,0 +1,82 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	"github.com/mikeb26/boylstonchessclub-tdbot/uscfutils"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestFitCrossTable(t *testing.T) {
+	const numPlayers = 400
+	standings := make(uschess.StandingsOneSection, 0, numPlayers)
+	for i := int32(1); i <= numPlayers; i++ {
+		outcome := uschess.PlayerOutcomeWin
+		if i == 1 {
+			outcome = uschess.PlayerOutcomeWinForfeit
+		}
+		rounds := make([]uschess.StandingsRound, 0, 5)
+		for r := int32(1); r <= 5; r++ {
+			rounds = append(rounds, uschess.StandingsRound{Outcome: outcome,
+				OpponentOrdinal: (i+r-1)%numPlayers + 1})
+		}
+		standings = append(standings, uschess.Standings{Ordinal: i,
+			FirstName: "Player", LastName: fmt.Sprintf("Number%d", i),
+			MemberId: uschess.MemberID(fmt.Sprint(i)), Score: 5,
+			RoundOutcomes: rounds})
+	}
+	table, _ := uscfutils.BuildCrossTableOutput(
+		uschess.MinimalSection{Name: "Open"}, standings,
+		uscfutils.CrossTableOptions{IncludeSectionHeader: true})
+
+	pages, truncated := fitCrossTable("", table, numPlayers, "; hint")
+	if !truncated {
+		t.Fatalf("fitCrossTable() of %d players was not truncated",
+			numPlayers)
+	}
+	if len(pages) != maxContentPages {
+		t.Errorf("fitCrossTable() returned %d pages; want %d", len(pages),
+			maxContentPages)
+	}
+	lines := strings.Split(strings.TrimSuffix(strings.Join(pages, ""), "\n"),
+		"\n")
+	if lines[0] != "Open Section" || !strings.HasPrefix(lines[1], "No") {
+		t.Fatalf("fitCrossTable() lost the table header: %q, %q", lines[0],
+			lines[1])
+	}
+	note := lines[len(lines)-1]
+	shown := len(lines) - 4 // section, header, footnote and note lines
+	if want := fmt.Sprintf("(showing first %d of %d players; hint)", shown,
+		numPlayers); note != want {
+		t.Errorf("note = %q; want %q", note, want)
+	}
+	if lines[len(lines)-2] != "* indicates game was decided by forfeit" {
+		t.Errorf("footnote = %q", lines[len(lines)-2])
+	}
+
+	// every row kept is whole and aligned with the header
+	width := internal.DisplayWidth(lines[1])
+	for i, row := range lines[2 : 2+shown] {
+		if !strings.HasPrefix(row, fmt.Sprintf("%d.", i+1)) ||
+			internal.DisplayWidth(row) != width {
+			t.Errorf("row %d = %q is not a whole aligned row", i+1, row)
+		}
+	}
+
+	// a table which fits is paginated as is
+	short, _ := uscfutils.BuildCrossTableOutput(
+		uschess.MinimalSection{Name: "Open"}, standings[:3],
+		uscfutils.CrossTableOptions{})
+	pages, truncated = fitCrossTable("Summary\n", short, 3, "")
+	if truncated || len(pages) != 1 || pages[0] != "Summary\n"+short {
+		t.Errorf("fitCrossTable() of a short table = %q, %v", pages,
+			truncated)
+	}
+}
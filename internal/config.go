@@ -0,0 +1,387 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// CacheReadOnlyEnv names the environment variable which, when true,
+	// makes the S3-backed cache read-only: cached responses are still
+	// served but new responses are neither stored nor evict them.
+	CacheReadOnlyEnv = "TDBOT_CACHE_READ_ONLY"
+	// CacheReadPreviousEnv names the environment variable which, when
+	// true, makes the S3-backed cache fall back to entries stored under
+	// the previous key version, easing the rollout of a version bump.
+	CacheReadPreviousEnv = "TDBOT_CACHE_READ_PREVIOUS"
+	// CrossTablesConcurrencyEnv names the environment variable which
+	// overrides the number of crosstables fetched concurrently when
+	// gathering a set of recent events (default
+	// DefaultCrossTablesConcurrency, at most MaxCrossTablesConcurrency).
+	CrossTablesConcurrencyEnv     = "TDBOT_CROSSTABLES_CONCURRENCY"
+	DefaultCrossTablesConcurrency = 4
+	MaxCrossTablesConcurrency     = 16
+	// MaskPublicIDsEnv names the environment variable which, when true,
+	// masks all but the last 4 digits of USCF member ids in discord output
+	// that is broadcast to a channel.
+	MaskPublicIDsEnv = "TDBOT_MASK_PUBLIC_IDS"
+	// RateLimitEnv names the environment variable which overrides
+	// DefaultRateLimit; a value of 0 disables rate limiting.
+	RateLimitEnv     = "TDBOT_RATE_LIMIT"
+	DefaultRateLimit = 5
+	// RateWindowEnv names the environment variable which overrides
+	// DefaultRateWindow; its value is parsed with time.ParseDuration (e.g.
+	// "1m").
+	RateWindowEnv     = "TDBOT_RATE_WINDOW"
+	DefaultRateWindow = 30 * time.Second
+	// EventThreadsEnv names the environment variable which maps event ids
+	// to the discord thread their results are posted to, e.g.
+	// "1312=1400000000000000001,1313=1400000000000000002".
+	EventThreadsEnv = "TDBOT_EVENT_THREADS"
+	// GuildThreadsEnv names the environment variable which maps guild ids
+	// to the discord thread results are posted to for events without a
+	// thread of their own.
+	GuildThreadsEnv = "TDBOT_GUILD_THREADS"
+	// PairingsDisclaimerEnv names the environment variable which overrides
+	// DefaultPairingsDisclaimer, the note heading pairings output; a value
+	// of "none" omits it.
+	PairingsDisclaimerEnv     = "TDBOT_PAIRINGS_DISCLAIMER"
+	DefaultPairingsDisclaimer = "Please note that pairings are tentative and subject to change before the start of the round."
+	// ByeLabelsEnv names the environment variable which overrides how byes
+	// are labeled in pairings, e.g. "full=BYE,half=½ BYE,zero=NO GAME".
+	// Labels it does not give keep their DefaultByeLabels value.
+	ByeLabelsEnv = "TDBOT_BYE_LABELS"
+	// AnnounceChannelEnv names the environment variable which holds the id
+	// of the discord channel that calendar changes (new, cancelled, and
+	// rescheduled events) are announced in. Nothing is announced when it
+	// is unset.
+	AnnounceChannelEnv = "TDBOT_ANNOUNCE_CHANNEL"
+	// ScoreFormatEnv names the environment variable which selects how
+	// scores are shown: "fraction" (the default, e.g. "2½") or "decimal"
+	// (e.g. "2.5").
+	ScoreFormatEnv = "TDBOT_SCORE_FORMAT"
+)
+
+// ByeLabels holds the labels shown in pairings in place of the opponent of
+// a player receiving a full, half, or zero point bye, or a bye whose points
+// are not known yet.
+type ByeLabels struct {
+	Full    string
+	Half    string
+	Zero    string
+	Unknown string
+}
+
+// DefaultByeLabels are the bye labels used unless ByeLabelsEnv overrides
+// them.
+var DefaultByeLabels = ByeLabels{
+	Full:    "BYE(1)",
+	Half:    "BYE(½)",
+	Zero:    "BYE(0)",
+	Unknown: "BYE",
+}
+
+// Label returns the label of a bye worth points, or of a bye whose points
+// are not known when known is false.
+func (l ByeLabels) Label(points float64, known bool) string {
+	switch {
+	case !known:
+		return l.Unknown
+	case points >= 1:
+		return l.Full
+	case points > 0:
+		return l.Half
+	default:
+		return l.Zero
+	}
+}
+
+// String formats the labels as ByeLabelsEnv expects them.
+func (l ByeLabels) String() string {
+	return fmt.Sprintf("full=%v,half=%v,zero=%v,unknown=%v", l.Full, l.Half,
+		l.Zero, l.Unknown)
+}
+
+// environment variables holding AWS credentials; only whether they are set
+// is ever reported
+var awsSecretEnvs = []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY",
+	"AWS_SESSION_TOKEN"}
+
+// Config is the configuration of the bot and bcctd as resolved from the
+// environment and built-in defaults.
+type Config struct {
+	UserAgent              string
+	AffiliateID            string
+	CacheBucket            string
+	HTTPTimeout            time.Duration
+	CrawlDelay             time.Duration
+	CacheReadOnly          bool
+	CacheReadPrevious      bool
+	CrossTablesConcurrency int
+	MaskPublicIDs          bool
+	RateLimit              int
+	RateWindow             time.Duration
+	EventThreads           string
+	GuildThreads           string
+	PairingsDisclaimer     string
+	ByeLabels              ByeLabels
+	AnnounceChannel        string
+	ScoreFormat            ScoreStyle
+	AWSProfile             string
+	AWSRegion              string
+	// AWSCredentials lists which of the AWS credential environment
+	// variables are set; their values are never read.
+	AWSCredentials map[string]bool
+}
+
+var (
+	configMu     sync.Mutex
+	configLoaded bool
+	config       Config
+)
+
+// GetConfig returns the configuration in effect, loading it on first use.
+func GetConfig() Config {
+	configMu.Lock()
+	defer configMu.Unlock()
+	if !configLoaded {
+		config = LoadConfig()
+		configLoaded = true
+	}
+
+	return config
+}
+
+// ReloadConfig re-resolves the configuration in effect from the environment,
+// e.g. after a test changes it, and returns it.
+func ReloadConfig() Config {
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = LoadConfig()
+	configLoaded = true
+
+	return config
+}
+
+// SetenvForTest sets an environment variable for the rest of a test as
+// t.Setenv does, reloading the configuration both now and once the variable
+// is restored.
+func SetenvForTest(t interface {
+	Setenv(key, value string)
+	Cleanup(f func())
+}, name string, val string) {
+
+	// cleanups run last-registered first, so registering this one ahead of
+	// t.Setenv's reloads only after the variable is restored
+	t.Cleanup(func() { ReloadConfig() })
+	t.Setenv(name, val)
+	ReloadConfig()
+}
+
+// LoadConfig resolves the configuration from the environment. Invalid
+// values are logged and replaced by their defaults. Everything else reads
+// the configuration through GetConfig rather than from the environment, so
+// that each setting is parsed, and any warning about it logged, once.
+func LoadConfig() Config {
+	cfg := Config{
+		UserAgent:              UserAgent,
+		AffiliateID:            BccUSCFAffiliateID,
+		CacheBucket:            WebCacheBucket,
+		HTTPTimeout:            durationEnv(HTTPTimeoutEnv, DefaultHTTPTimeout),
+		CrawlDelay:             durationEnv(CrawlDelayEnv, DefaultCrawlDelay),
+		CacheReadOnly:          boolEnv(CacheReadOnlyEnv, false),
+		CacheReadPrevious:      boolEnv(CacheReadPreviousEnv, false),
+		CrossTablesConcurrency: crossTablesConcurrency(),
+		MaskPublicIDs:          boolEnv(MaskPublicIDsEnv, false),
+		RateLimit:              intEnv(RateLimitEnv, DefaultRateLimit, 0),
+		RateWindow:             durationEnv(RateWindowEnv, DefaultRateWindow),
+		EventThreads:           os.Getenv(EventThreadsEnv),
+		GuildThreads:           os.Getenv(GuildThreadsEnv),
+		PairingsDisclaimer:     pairingsDisclaimer(),
+		ByeLabels:              pairingByeLabels(),
+		AnnounceChannel:        strings.TrimSpace(os.Getenv(AnnounceChannelEnv)),
+		ScoreFormat:            scoreFormat(),
+		AWSProfile:             os.Getenv("AWS_PROFILE"),
+		AWSRegion:              os.Getenv("AWS_REGION"),
+		AWSCredentials:         make(map[string]bool),
+	}
+	for _, name := range awsSecretEnvs {
+		val, ok := os.LookupEnv(name)
+		cfg.AWSCredentials[name] = ok && val != ""
+	}
+
+	return cfg
+}
+
+// String formats the configuration one setting per line. Secrets are
+// reported only as set or unset.
+func (cfg Config) String() string {
+	orUnset := func(val string) string {
+		if val == "" {
+			return "(unset)"
+		}
+		return val
+	}
+
+	var sb strings.Builder
+	for _, setting := range [][2]string{
+		{"user agent", cfg.UserAgent},
+		{"affiliate id", cfg.AffiliateID},
+		{"cache bucket", cfg.CacheBucket},
+		{HTTPTimeoutEnv, cfg.HTTPTimeout.String()},
+		{CrawlDelayEnv, cfg.CrawlDelay.String()},
+		{CacheReadOnlyEnv, strconv.FormatBool(cfg.CacheReadOnly)},
+		{CacheReadPreviousEnv, strconv.FormatBool(cfg.CacheReadPrevious)},
+		{CrossTablesConcurrencyEnv, strconv.Itoa(cfg.CrossTablesConcurrency)},
+		{MaskPublicIDsEnv, strconv.FormatBool(cfg.MaskPublicIDs)},
+		{RateLimitEnv, strconv.Itoa(cfg.RateLimit)},
+		{RateWindowEnv, cfg.RateWindow.String()},
+		{EventThreadsEnv, orUnset(cfg.EventThreads)},
+		{GuildThreadsEnv, orUnset(cfg.GuildThreads)},
+		{PairingsDisclaimerEnv, strconv.Quote(cfg.PairingsDisclaimer)},
+		{ByeLabelsEnv, cfg.ByeLabels.String()},
+		{AnnounceChannelEnv, orUnset(cfg.AnnounceChannel)},
+		{ScoreFormatEnv, cfg.ScoreFormat.String()},
+		{"AWS_PROFILE", orUnset(cfg.AWSProfile)},
+		{"AWS_REGION", orUnset(cfg.AWSRegion)},
+	} {
+		sb.WriteString(fmt.Sprintf("%-30s %v\n", setting[0]+":", setting[1]))
+	}
+	for _, name := range awsSecretEnvs {
+		val := "(unset)"
+		if cfg.AWSCredentials[name] {
+			val = "(set, redacted)"
+		}
+		sb.WriteString(fmt.Sprintf("%-30s %v\n", name+":", val))
+	}
+
+	return sb.String()
+}
+
+// crossTablesConcurrency returns the maximum number of concurrent crosstable
+// fetches, honoring CrossTablesConcurrencyEnv when it holds a valid positive
+// count.
+func crossTablesConcurrency() int {
+	n := intEnv(CrossTablesConcurrencyEnv, DefaultCrossTablesConcurrency, 1)
+	return min(n, MaxCrossTablesConcurrency)
+}
+
+// pairingsDisclaimer returns the note heading pairings output, honoring
+// PairingsDisclaimerEnv when it is set. It returns "" when the note is to be
+// omitted.
+func pairingsDisclaimer() string {
+	val := strings.TrimSpace(os.Getenv(PairingsDisclaimerEnv))
+	if val == "" {
+		return DefaultPairingsDisclaimer
+	} else if strings.EqualFold(val, "none") {
+		return ""
+	}
+
+	return val
+}
+
+// pairingByeLabels returns the labels of byes in pairings, honoring the
+// labels ByeLabelsEnv gives. Malformed entries are logged and ignored.
+func pairingByeLabels() ByeLabels {
+	labels := DefaultByeLabels
+	val := os.Getenv(ByeLabelsEnv)
+	for _, pair := range strings.Split(val, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, label, ok := strings.Cut(pair, "=")
+		key, label = strings.ToLower(strings.TrimSpace(key)),
+			strings.TrimSpace(label)
+		var dest *string
+		switch key {
+		case "full":
+			dest = &labels.Full
+		case "half":
+			dest = &labels.Half
+		case "zero":
+			dest = &labels.Zero
+		case "unknown":
+			dest = &labels.Unknown
+		}
+		if !ok || dest == nil || label == "" {
+			log.Printf("internal: ignoring invalid %v entry %q", ByeLabelsEnv,
+				pair)
+			continue
+		}
+		*dest = label
+	}
+
+	return labels
+}
+
+// scoreFormat returns the style scores are shown in, honoring
+// ScoreFormatEnv when it names a valid style.
+func scoreFormat() ScoreStyle {
+	val := strings.TrimSpace(os.Getenv(ScoreFormatEnv))
+	if val == "" {
+		return ScoreStyleFraction
+	}
+	style, ok := ParseScoreStyle(val)
+	if !ok {
+		log.Printf("internal: ignoring invalid %v=%q; using %v",
+			ScoreFormatEnv, val, ScoreStyleFraction)
+		return ScoreStyleFraction
+	}
+
+	return style
+}
+
+func boolEnv(name string, def bool) bool {
+	val, ok := os.LookupEnv(name)
+	if !ok || val == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Printf("internal: ignoring invalid %v=%q; using %v", name, val, def)
+		return def
+	}
+
+	return b
+}
+
+// intEnv returns the integer value of an environment variable, or def when
+// it is unset, malformed, or less than minVal.
+func intEnv(name string, def int, minVal int) int {
+	val, ok := os.LookupEnv(name)
+	if !ok || val == "" {
+		return def
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < minVal {
+		log.Printf("internal: ignoring invalid %v=%q; using %v", name, val, def)
+		return def
+	}
+
+	return n
+}
+
+// durationEnv returns the positive duration held by an environment
+// variable, or def when it is unset or invalid.
+func durationEnv(name string, def time.Duration) time.Duration {
+	val, ok := os.LookupEnv(name)
+	if !ok || val == "" {
+		return def
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		log.Printf("internal: ignoring invalid %v=%q; using %v", name, val, def)
+		return def
+	}
+
+	return d
+}
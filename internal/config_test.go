@@ -0,0 +1,60 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	t.Setenv(HTTPTimeoutEnv, "45s")
+	t.Setenv(CacheReadOnlyEnv, "true")
+	t.Setenv(CacheReadPreviousEnv, "true")
+	t.Setenv(CrossTablesConcurrencyEnv, "8")
+	t.Setenv(MaskPublicIDsEnv, "1")
+	t.Setenv(RateLimitEnv, "0")
+	t.Setenv(RateWindowEnv, "bogus")
+	t.Setenv(EventThreadsEnv, "1312=111")
+	t.Setenv(GuildThreadsEnv, "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "not-a-real-secret")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+
+	cfg := LoadConfig()
+	if cfg.HTTPTimeout != 45*time.Second || !cfg.CacheReadOnly ||
+		!cfg.CacheReadPrevious ||
+		cfg.CrossTablesConcurrency != 8 || !cfg.MaskPublicIDs ||
+		cfg.RateLimit != 0 || cfg.RateWindow != DefaultRateWindow ||
+		cfg.EventThreads != "1312=111" || cfg.GuildThreads != "" {
+		t.Fatalf("LoadConfig() = %+v", cfg)
+	}
+	if !cfg.AWSCredentials["AWS_SECRET_ACCESS_KEY"] ||
+		cfg.AWSCredentials["AWS_ACCESS_KEY_ID"] {
+		t.Errorf("AWSCredentials = %v", cfg.AWSCredentials)
+	}
+
+	output := cfg.String()
+	if strings.Contains(output, "not-a-real-secret") {
+		t.Errorf("output reveals a secret:\n%s", output)
+	}
+	for _, want := range []string{
+		"TDBOT_HTTP_TIMEOUT:            45s\n",
+		"TDBOT_CROSSTABLES_CONCURRENCY: 8\n",
+		"TDBOT_GUILD_THREADS:           (unset)\n",
+		"AWS_SECRET_ACCESS_KEY:         (set, redacted)\n",
+		"AWS_ACCESS_KEY_ID:             (unset)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	t.Setenv(CrossTablesConcurrencyEnv, "1000")
+	if cfg := LoadConfig(); cfg.CrossTablesConcurrency != MaxCrossTablesConcurrency {
+		t.Errorf("CrossTablesConcurrency = %v; want %v",
+			cfg.CrossTablesConcurrency, MaxCrossTablesConcurrency)
+	}
+}
@@ -9,3 +9,15 @@ const (
 	BccUSCFAffiliateID = "A5000408"
 	WebCacheBucket     = "bopmatic-boylstonchessclub-tdbot-prod-webcache"
 )
+
+// bounds on the number of days of events cal and history retrieve
+const (
+	DefaultDays = 14
+	MaxDays     = 60
+)
+
+// bounds on the number of past days of events attendance compares
+const (
+	DefaultAttendanceDays = 90
+	MaxAttendanceDays     = 365
+)
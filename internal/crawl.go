@@ -0,0 +1,111 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// CrawlDelayEnv names the environment variable which overrides
+	// DefaultCrawlDelay; its value is parsed with time.ParseDuration (e.g.
+	// "500ms").
+	CrawlDelayEnv = "TDBOT_CRAWL_DELAY"
+	// DefaultCrawlDelay is the minimum time between the starts of
+	// consecutive requests to the same host by ScrapeClient, so that bursts
+	// of scraping (e.g. a player report needing several profile pages) don't
+	// peg uschess.org.
+	DefaultCrawlDelay = 2 * time.Second
+)
+
+var (
+	scrapeClientOnce sync.Once
+	scrapeClient     *http.Client
+)
+
+// ScrapeClient returns the shared uncached http.Client for fetching HTML
+// pages, e.g. uschess.org's MSA pages. It behaves as HTTPClient except that
+// consecutive requests to the same host are spaced at least the configured
+// crawl delay (see CrawlDelayEnv) apart. JSON APIs, which are meant to be
+// called programmatically, should use HTTPClient instead.
+func ScrapeClient() *http.Client {
+	scrapeClientOnce.Do(func() {
+		cfg := GetConfig()
+		scrapeClient = newScrapeClient(cfg.HTTPTimeout, cfg.CrawlDelay,
+			http.DefaultTransport)
+	})
+
+	return scrapeClient
+}
+
+func newScrapeClient(timeout time.Duration, delay time.Duration,
+	transport http.RoundTripper) *http.Client {
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &throttledTransport{
+			throttle:  newHostThrottle(delay),
+			wrappedRT: transport,
+		},
+	}
+}
+
+// hostThrottle spaces the requests made to each host at least delay apart.
+type hostThrottle struct {
+	delay time.Duration
+
+	mu sync.Mutex
+	// next holds the earliest time the next request to each host may start
+	next map[string]time.Time
+}
+
+func newHostThrottle(delay time.Duration) *hostThrottle {
+	return &hostThrottle{delay: delay, next: make(map[string]time.Time)}
+}
+
+// wait blocks until a request to host may start, or ctx is done. Each caller
+// reserves its own slot so that concurrent requests are spaced out too.
+func (h *hostThrottle) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	now := Now()
+	start := now
+	if next, ok := h.next[host]; ok && next.After(now) {
+		start = next
+	}
+	h.next[host] = start.Add(h.delay)
+	h.mu.Unlock()
+
+	if !start.After(now) {
+		return nil
+	}
+	timer := time.NewTimer(start.Sub(now))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// throttledTransport waits for its host's turn before each request.
+type throttledTransport struct {
+	throttle  *hostThrottle
+	wrappedRT http.RoundTripper
+}
+
+// RoundTrip waits until req's host may be requested again, then sends req.
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response,
+	error) {
+
+	if err := t.throttle.wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+
+	return t.wrappedRT.RoundTrip(req)
+}
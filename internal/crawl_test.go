@@ -0,0 +1,91 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCrawlDelay(t *testing.T) {
+	t.Setenv(CrawlDelayEnv, "")
+	if got := LoadConfig().CrawlDelay; got != DefaultCrawlDelay {
+		t.Errorf("CrawlDelay = %v; want %v", got, DefaultCrawlDelay)
+	}
+	t.Setenv(CrawlDelayEnv, "500ms")
+	if got := LoadConfig().CrawlDelay; got != 500*time.Millisecond {
+		t.Errorf("CrawlDelay = %v; want 500ms", got)
+	}
+}
+
+func TestScrapeClientSpacesRequests(t *testing.T) {
+	const delay = 100 * time.Millisecond
+	var mu sync.Mutex
+	var starts []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+	}))
+	defer other.Close()
+
+	client := newScrapeClient(DefaultHTTPTimeout, delay, http.DefaultTransport)
+	get := func(url string) {
+		resp, err := client.Get(url)
+		if err != nil {
+			t.Errorf("Get(%v) err = %v", url, err)
+			return
+		}
+		resp.Body.Close()
+	}
+
+	// concurrent requests to one host are spaced too
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get(srv.URL)
+		}()
+	}
+	wg.Wait()
+	if len(starts) != 3 {
+		t.Fatalf("server saw %v requests; want 3", len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		// allow for timer granularity
+		if gap := starts[i].Sub(starts[i-1]); gap < delay-10*time.Millisecond {
+			t.Errorf("request %v followed the previous after %v; want >= %v",
+				i, gap, delay)
+		}
+	}
+
+	// other hosts aren't held up
+	begin := time.Now()
+	get(other.URL)
+	if elapsed := time.Since(begin); elapsed >= delay {
+		t.Errorf("request to another host took %v", elapsed)
+	}
+
+	// a waiting request gives up with its context
+	ctx, cancel := context.WithTimeout(context.Background(),
+		10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do(canceled) err = %v; want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,74 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+var (
+	// ErrNotFound is matched by errors reporting that the requested
+	// event, member, or page does not exist, e.g. an invalid id.
+	ErrNotFound = errors.New("not found")
+	// ErrUpstreamUnavailable is matched by errors reporting that the club
+	// website or US Chess is down or overloaded, so that the request may
+	// succeed if tried again later.
+	ErrUpstreamUnavailable = errors.New("upstream service unavailable")
+)
+
+// StatusClass returns ErrNotFound or ErrUpstreamUnavailable according to an
+// unexpected HTTP status, or nil when the status is neither.
+func StatusClass(status int) error {
+	switch {
+	case status == http.StatusNotFound || status == http.StatusGone:
+		return ErrNotFound
+	case status == http.StatusTooManyRequests ||
+		status >= http.StatusInternalServerError:
+		return ErrUpstreamUnavailable
+	}
+
+	return nil
+}
+
+// HTTPStatusError is an unexpected HTTP response status. It formats as the
+// bare status code and matches its StatusClass with errors.Is.
+type HTTPStatusError int
+
+func (e HTTPStatusError) Error() string {
+	return strconv.Itoa(int(e))
+}
+
+func (e HTTPStatusError) Unwrap() error {
+	return StatusClass(int(e))
+}
+
+// statusClassError is an error which also matches the StatusClass of the
+// HTTP status it was caused by.
+type statusClassError struct {
+	err   error
+	class error
+}
+
+func (e statusClassError) Error() string {
+	return e.err.Error()
+}
+
+func (e statusClassError) Unwrap() []error {
+	return []error{e.err, e.class}
+}
+
+// WithStatus returns err, caused by an unexpected HTTP status, such that it
+// also matches the status's StatusClass with errors.Is. Its message is
+// unchanged.
+func WithStatus(err error, status int) error {
+	class := StatusClass(status)
+	if err == nil || class == nil {
+		return err
+	}
+
+	return statusClassError{err: err, class: class}
+}
@@ -0,0 +1,32 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStatusErrors(t *testing.T) {
+	err := fmt.Errorf("unable to fetch bcc events (http): %w",
+		HTTPStatusError(404))
+	if err.Error() != "unable to fetch bcc events (http): 404" ||
+		!errors.Is(err, ErrNotFound) || errors.Is(err, ErrUpstreamUnavailable) {
+
+		t.Errorf("404 error = %v; want a not found error", err)
+	}
+
+	base := errors.New("GetRatedEvent: unexpected response status 502")
+	err = WithStatus(base, 502)
+	if err.Error() != base.Error() || !errors.Is(err, base) ||
+		!errors.Is(err, ErrUpstreamUnavailable) {
+
+		t.Errorf("502 error = %v; want an unavailable error", err)
+	}
+	if err := WithStatus(base, 400); err != base {
+		t.Errorf("WithStatus(400) = %v; want the error unchanged", err)
+	}
+}
@@ -20,16 +20,47 @@ import (
 // If cache initialization fails, it falls back to an in-memory cache instead of no cache.
 // It also enforces a client-side TTL by rewriting origin cache headers.
 func NewCachedHttpClient(ctx context.Context, maxAge time.Duration) *http.Client {
+	return NewCachedHttpClientWithTTL(ctx,
+		func(resp *http.Response) time.Duration {
+			return maxAge
+		})
+}
+
+// NewCachedHttpClientWithTTL is like NewCachedHttpClient but computes the TTL
+// of each origin response with maxAge, allowing the TTL to vary with the
+// response's contents. maxAge may consume resp.Body so long as it replaces it
+// with an equivalent reader.
+func NewCachedHttpClientWithTTL(ctx context.Context,
+	maxAge func(resp *http.Response) time.Duration) *http.Client {
+
 	// Initialize S3-backed cache
 	cache := s3cache.New(ctx, internal.WebCacheBucket, false, true)
+	cfg := internal.GetConfig()
+	cache.SetReadOnly(cfg.CacheReadOnly)
+	cache.SetReadPreviousVersion(cfg.CacheReadPrevious)
 
 	err := cache.Init()
 
 	if err != nil {
 		log.Printf("httpcache: warning failed to init S3 cache: %v; falling back to uncached http", err)
-		return http.DefaultClient
+		return internal.HTTPClient()
 	}
 
+	return newCachedHttpClient(cache, maxAge)
+}
+
+// NewMemoryCachedHttpClient returns an http.Client that caches responses in
+// memory for maxAge.
+func NewMemoryCachedHttpClient(maxAge time.Duration) *http.Client {
+	return newCachedHttpClient(httpcache.NewMemoryCache(),
+		func(resp *http.Response) time.Duration {
+			return maxAge
+		})
+}
+
+func newCachedHttpClient(cache httpcache.Cache,
+	maxAge func(resp *http.Response) time.Duration) *http.Client {
+
 	hc := httpcache.NewTransport(cache)
 	// we have to inject our own header overrides here in order to override
 	// server responses that might indicate caching shouldn't be done
@@ -40,13 +71,89 @@ func NewCachedHttpClient(ctx context.Context, maxAge time.Duration) *http.Client
 			resp.Header.Del("Pragma")
 			resp.Header.Del("Expires")
 			resp.Header.Del("Cache-Control")
-			// Enforce the provided TTL
-			resp.Header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge/time.Second)))
+			// Never cache transient server errors (5xx or 429) so that
+			// retries reach the origin
+			class := internal.StatusClass(resp.StatusCode)
+			if class == internal.ErrUpstreamUnavailable {
+				resp.Header.Set("Cache-Control", "no-store")
+				return nil
+			}
+			// Record when the origin was fetched so that cached copies
+			// can report their age
+			resp.Header.Set(FetchedAtHeader,
+				internal.Now().UTC().Format(http.TimeFormat))
+			// Enforce the provided TTL, except that lookups of ids which
+			// don't exist are only remembered briefly in case they are
+			// created
+			ttl := NegativeCacheTTL
+			if class != internal.ErrNotFound {
+				ttl = maxAge(resp)
+			}
+			resp.Header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(ttl/time.Second)))
 			return nil
 		},
 	}
 
-	return &http.Client{Transport: hc}
+	// requests asking to bypass the cache are marked before reaching it
+	return &http.Client{
+		Transport: &HeaderOverrideTransport{
+			wrappedRT: hc,
+			Request:   bypassCache,
+		},
+		Timeout: internal.GetConfig().HTTPTimeout,
+	}
+}
+
+// BypassCacheHeader, when set on a request made through a cached client,
+// skips the cached response for that request only: the response is fetched
+// from the origin and replaces the cached copy, leaving the rest of the
+// cache intact. The header itself is not sent to the origin.
+const BypassCacheHeader = "X-TDBot-Bypass-Cache"
+
+// bypassCacheKey marks a context whose requests bypass the cache.
+type bypassCacheKey struct{}
+
+// WithCacheBypass returns a copy of ctx whose requests through cached clients
+// bypass the cache as if they carried BypassCacheHeader, for callers which
+// don't build their requests themselves.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// bypassCache turns a request for bypassing the cache into one httpcache
+// honors: with "Cache-Control: no-cache" it fetches from the origin without
+// consulting its cached copy, then stores the response as usual.
+func bypassCache(req *http.Request) {
+	bypass, _ := req.Context().Value(bypassCacheKey{}).(bool)
+	if !bypass && req.Header.Get(BypassCacheHeader) == "" {
+		return
+	}
+	req.Header.Del(BypassCacheHeader)
+	req.Header.Set("Cache-Control", "no-cache")
+}
+
+// NegativeCacheTTL is how long a not found (404 or 410) response is cached,
+// so that repeated lookups of a bad id are answered without reaching the
+// origin each time, whatever TTL the client otherwise applies.
+const NegativeCacheTTL = 5 * time.Minute
+
+// FetchedAtHeader is set on each origin response to the time it was fetched
+// and is stored along with the response in the cache.
+const FetchedAtHeader = "X-Fetched-At"
+
+// ResponseAge returns how long ago a response served from the cache was
+// originally fetched. It returns 0 for responses which did not come from the
+// cache or whose fetch time is unknown.
+func ResponseAge(resp *http.Response) time.Duration {
+	if resp.Header.Get(httpcache.XFromCache) != "1" {
+		return 0
+	}
+	fetchedAt, err := http.ParseTime(resp.Header.Get(FetchedAtHeader))
+	if err != nil {
+		return 0
+	}
+
+	return max(internal.Now().Sub(fetchedAt), 0)
 }
 
 type HeaderOverrideTransport struct {
@@ -77,3 +184,54 @@ func (t *HeaderOverrideTransport) RoundTrip(req *http.Request) (*http.Response,
 	}
 	return resp, nil
 }
+
+// CloseIdleConnections closes the idle connections of the underlying
+// transport, so that http.Client.CloseIdleConnections reaches it through
+// this decorator.
+func (t *HeaderOverrideTransport) CloseIdleConnections() {
+	closeIdleConnections(t.wrappedRT)
+}
+
+// closeIdleConnections closes the idle connections of rt, if it has any,
+// looking through an httpcache.Transport, which does not do so itself.
+func closeIdleConnections(rt http.RoundTripper) {
+	if hc, ok := rt.(*httpcache.Transport); ok {
+		rt = hc.Transport
+		if rt == nil {
+			rt = http.DefaultTransport
+		}
+	}
+	if closer, ok := rt.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// NewS3Store returns the S3 bucket backing the http cache, for keeping other
+// small state such as snapshots under keys of its own. The store is writable
+// even when internal.CacheReadOnlyEnv freezes cached responses, as its state
+// must advance for the bot to work; callers should check the errors of Put.
+func NewS3Store(ctx context.Context) (*s3cache.Cache, error) {
+	cache := s3cache.New(ctx, internal.WebCacheBucket, false, true)
+	if err := cache.Init(); err != nil {
+		return nil, fmt.Errorf("httpcache: unable to open S3 store: %w", err)
+	}
+
+	return cache, nil
+}
+
+// ClearCacheKeys removes the cached responses for each of the given cache
+// keys (request URLs) from the S3-backed cache. It does so even when
+// internal.CacheReadOnlyEnv freezes the cache, as clearing is an explicit
+// request.
+func ClearCacheKeys(ctx context.Context, keys []string) error {
+	cache := s3cache.New(ctx, internal.WebCacheBucket, false, true)
+	if err := cache.Init(); err != nil {
+		return fmt.Errorf("httpcache: unable to clear cache: %w", err)
+	}
+
+	for _, key := range keys {
+		cache.Delete(key)
+	}
+
+	return nil
+}
@@ -10,9 +10,11 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gregjones/httpcache"
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 )
 
@@ -20,7 +22,7 @@ func TestHttpClient(t *testing.T) {
 	ctx := context.Background()
 	client := NewCachedHttpClient(ctx, 5*time.Minute)
 
-	if client == http.DefaultClient {
+	if client == internal.HTTPClient() {
 		t.Skip("Skipping test because http client is uncached")
 	}
 	id := 12912297
@@ -53,3 +55,191 @@ func TestHttpClient(t *testing.T) {
 		resp.Body.Close()
 	}
 }
+
+func TestResponseAge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		w.Write([]byte("pairings"))
+	}))
+	defer srv.Close()
+	client := NewMemoryCachedHttpClient(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("Get() err = %v", err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.Header.Get(FetchedAtHeader) == "" {
+			t.Errorf("fetch %d: missing %v header", i, FetchedAtHeader)
+		}
+		if i == 0 && ResponseAge(resp) != 0 {
+			t.Errorf("ResponseAge() = %v for an origin response",
+				ResponseAge(resp))
+		}
+		if i == 1 && resp.Header.Get("X-From-Cache") != "1" {
+			t.Errorf("response not cached")
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("X-From-Cache", "1")
+	resp.Header.Set(FetchedAtHeader,
+		time.Now().Add(-5*time.Minute).UTC().Format(http.TimeFormat))
+	if age := ResponseAge(resp); age < 5*time.Minute || age > 6*time.Minute {
+		t.Errorf("ResponseAge() = %v, want ~5m", age)
+	}
+}
+
+func TestNegativeCaching(t *testing.T) {
+	status := http.StatusNotFound
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+	client := NewMemoryCachedHttpClient(24 * time.Hour)
+
+	get := func(path string) *http.Response {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("Get(%v) err = %v", path, err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp
+	}
+
+	// a second lookup of a bad id is served from the cache, briefly
+	get("/members/0")
+	resp := get("/members/0")
+	if requests != 1 || resp.StatusCode != http.StatusNotFound ||
+		resp.Header.Get("X-From-Cache") != "1" {
+
+		t.Errorf("2nd 404 lookup: %d requests, status %d, from cache %q; want 1 request from cache",
+			requests, resp.StatusCode, resp.Header.Get("X-From-Cache"))
+	}
+	want := fmt.Sprintf("public, max-age=%d", int(NegativeCacheTTL/time.Second))
+	if got := resp.Header.Get("Cache-Control"); got != want {
+		t.Errorf("Cache-Control = %q; want %q", got, want)
+	}
+
+	// transient failures are never cached
+	for _, status = range []int{http.StatusServiceUnavailable,
+		http.StatusTooManyRequests} {
+
+		requests = 0
+		path := fmt.Sprintf("/members/%d", status)
+		get(path)
+		get(path)
+		if requests != 2 {
+			t.Errorf("status %d: %d requests; want 2 (not cached)", status,
+				requests)
+		}
+	}
+}
+
+func TestCacheReadOnly(t *testing.T) {
+	for val, want := range map[string]bool{
+		"":      false,
+		"true":  true,
+		"1":     true,
+		"false": false,
+		"bogus": false,
+	} {
+		t.Setenv(internal.CacheReadOnlyEnv, val)
+		if got := internal.LoadConfig().CacheReadOnly; got != want {
+			t.Errorf("CacheReadOnly with %q = %v; want %v", val, got, want)
+		}
+	}
+}
+
+func TestBypassCache(t *testing.T) {
+	version := 1
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		requests++
+		if r.Header.Get(BypassCacheHeader) != "" {
+			t.Errorf("%v sent to the origin", BypassCacheHeader)
+		}
+		fmt.Fprintf(w, "pairings v%d", version)
+	}))
+	defer srv.Close()
+	client := NewMemoryCachedHttpClient(time.Hour)
+
+	get := func(ctx context.Context, bypass bool) (string, bool) {
+		req, err := http.NewRequestWithContext(ctx, "GET", srv.URL, nil)
+		if err != nil {
+			t.Fatalf("NewRequest() err = %v", err)
+		}
+		if bypass {
+			req.Header.Set(BypassCacheHeader, "1")
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() err = %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return string(data), resp.Header.Get("X-From-Cache") == "1"
+	}
+
+	ctx := context.Background()
+	get(ctx, false)
+	version = 2
+	if body, cached := get(ctx, false); body != "pairings v1" || !cached {
+		t.Fatalf("2nd fetch = %q, cached %v; want cached v1", body, cached)
+	}
+
+	// bypassing re-fetches from the origin...
+	if body, cached := get(ctx, true); body != "pairings v2" || cached {
+		t.Errorf("bypass fetch = %q, cached %v; want fresh v2", body, cached)
+	}
+	// ...and repopulates the cache for later fetches
+	if body, cached := get(ctx, false); body != "pairings v2" || !cached ||
+		requests != 2 {
+
+		t.Errorf("fetch after bypass = %q, cached %v, %d requests; want cached v2 after 2 requests",
+			body, cached, requests)
+	}
+
+	version = 3
+	if body, cached := get(WithCacheBypass(ctx), false); body !=
+		"pairings v3" || cached || requests != 3 {
+
+		t.Errorf("context bypass fetch = %q, cached %v, %d requests; want fresh v3",
+			body, cached, requests)
+	}
+}
+
+// closeCountingTransport counts calls to CloseIdleConnections
+type closeCountingTransport struct {
+	http.RoundTripper
+	closes int
+}
+
+func (t *closeCountingTransport) CloseIdleConnections() {
+	t.closes++
+}
+
+func TestCloseIdleConnections(t *testing.T) {
+	origin := &closeCountingTransport{}
+	hc := httpcache.NewTransport(httpcache.NewMemoryCache())
+	hc.Transport = &HeaderOverrideTransport{wrappedRT: origin}
+	client := &http.Client{
+		Transport: &HeaderOverrideTransport{wrappedRT: hc},
+	}
+
+	client.CloseIdleConnections()
+	if origin.closes != 1 {
+		t.Errorf("origin transport closed %v times; want 1", origin.closes)
+	}
+}
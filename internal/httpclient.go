@@ -0,0 +1,40 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// HTTPTimeoutEnv names the environment variable which overrides
+	// DefaultHTTPTimeout; its value is parsed with time.ParseDuration (e.g.
+	// "45s").
+	HTTPTimeoutEnv     = "TDBOT_HTTP_TIMEOUT"
+	DefaultHTTPTimeout = 30 * time.Second
+)
+
+var (
+	httpClientOnce sync.Once
+	httpClient     *http.Client
+)
+
+// HTTPClient returns the shared uncached http.Client. Unlike
+// http.DefaultClient it bounds each request by the configured timeout (see
+// HTTPTimeoutEnv) so that a hung upstream cannot block its caller
+// indefinitely.
+func HTTPClient() *http.Client {
+	httpClientOnce.Do(func() {
+		httpClient = newHTTPClient(GetConfig().HTTPTimeout)
+	})
+
+	return httpClient
+}
+
+func newHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
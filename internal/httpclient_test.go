@@ -0,0 +1,65 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPTimeout(t *testing.T) {
+	tests := []struct {
+		val  string
+		want time.Duration
+	}{
+		{"", DefaultHTTPTimeout},
+		{"45s", 45 * time.Second},
+		{"500ms", 500 * time.Millisecond},
+		{"bogus", DefaultHTTPTimeout},
+		{"-5s", DefaultHTTPTimeout},
+		{"0", DefaultHTTPTimeout},
+	}
+	for _, tc := range tests {
+		t.Setenv(HTTPTimeoutEnv, tc.val)
+		if got := LoadConfig().HTTPTimeout; got != tc.want {
+			t.Errorf("HTTPTimeout with %q = %v; want %v", tc.val, got,
+				tc.want)
+		}
+	}
+}
+
+func TestHTTPClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := newHTTPClient(50 * time.Millisecond)
+	start := time.Now()
+	resp, err := client.Get(srv.URL)
+	if err == nil {
+		resp.Body.Close()
+		t.Fatalf("Get() against a hung server succeeded; want timeout")
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("Get() err = %v; want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Get() took %v; want it bounded by the client timeout",
+			elapsed)
+	}
+}
@@ -0,0 +1,152 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"strings"
+	"unicode"
+)
+
+// nicknameGroups lists given names along with the nicknames commonly used in
+// their place. A name may appear in more than one group, e.g. "pat".
+var nicknameGroups = [][]string{
+	{"alexander", "alex", "sasha"},
+	{"andrew", "andy", "drew"},
+	{"anthony", "tony"},
+	{"benjamin", "ben", "benny"},
+	{"charles", "charlie", "chuck"},
+	{"christopher", "chris"},
+	{"daniel", "dan", "danny"},
+	{"david", "dave"},
+	{"edward", "ed", "eddie", "ted"},
+	{"elizabeth", "liz", "beth", "betsy"},
+	{"eugene", "gene"},
+	{"frederick", "fred"},
+	{"gregory", "greg"},
+	{"jacob", "jake"},
+	{"james", "jim", "jimmy", "jamie"},
+	{"jennifer", "jen", "jenny"},
+	{"john", "jack", "johnny"},
+	{"jonathan", "jon"},
+	{"joseph", "joe", "joey"},
+	{"joshua", "josh"},
+	{"katherine", "catherine", "kate", "katie", "kathy"},
+	{"kenneth", "ken"},
+	{"lawrence", "larry"},
+	{"matthew", "matt"},
+	{"michael", "mike", "mikey", "mick"},
+	{"nicholas", "nick"},
+	{"patricia", "pat", "patty"},
+	{"patrick", "pat"},
+	{"peter", "pete"},
+	{"philip", "phil"},
+	{"richard", "rich", "rick", "dick"},
+	{"robert", "rob", "bob", "bobby"},
+	{"ronald", "ron"},
+	{"samuel", "sam"},
+	{"stephen", "steven", "steve"},
+	{"thomas", "tom", "tommy"},
+	{"timothy", "tim"},
+	{"william", "will", "bill", "billy", "liam"},
+	{"zachary", "zach", "zack"},
+}
+
+// nicknames maps each name of nicknameGroups to the indices of the groups it
+// appears in.
+var nicknames = func() map[string][]int {
+	m := make(map[string][]int)
+	for idx, group := range nicknameGroups {
+		for _, name := range group {
+			m[name] = append(m[name], idx)
+		}
+	}
+	return m
+}()
+
+// nameSuffixes are generational suffixes which are not part of a last name.
+var nameSuffixes = map[string]bool{
+	"jr": true, "sr": true, "ii": true, "iii": true, "iv": true,
+}
+
+// splitName returns the lower cased first and last names of a full name
+// given as either "First [Middle] Last" or "Last, First [Middle]", ignoring
+// punctuation and generational suffixes such as "Jr.".
+func splitName(name string) (first, last string) {
+	if l, f, ok := strings.Cut(name, ","); ok {
+		// "Brown, Jr., Mike" names Mike Brown as "Brown Jr., Mike" does
+		if suffix, ff, ok := strings.Cut(f, ","); ok &&
+			nameSuffixes[nameWord(suffix)] {
+
+			f = ff
+		}
+		name = f + " " + l
+	}
+	var words []string
+	for _, w := range strings.Fields(name) {
+		if w = nameWord(w); w != "" && !nameSuffixes[w] {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return "", ""
+	}
+
+	return words[0], words[len(words)-1]
+}
+
+// nameWord lower cases a word of a name, dropping any character other than
+// a letter, digit, apostrophe or hyphen, e.g. "Jr." becomes "jr".
+func nameWord(w string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '-' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, strings.TrimSpace(w))
+}
+
+// firstNamesMatch reports whether two lower cased first names are the same
+// or nicknames of one another, e.g. "mike" and "michael".
+func firstNamesMatch(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, ga := range nicknames[a] {
+		for _, gb := range nicknames[b] {
+			if ga == gb {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// NamesMatch reports whether two full names likely refer to the same person:
+// their last names are the same and their first names are the same or
+// nicknames of one another, e.g. "Mike Brown" and "BROWN, MICHAEL".
+// Case, punctuation, middle names and suffixes such as "Jr." are ignored.
+func NamesMatch(a, b string) bool {
+	firstA, lastA := splitName(a)
+	firstB, lastB := splitName(b)
+	if lastA == "" || lastB == "" || lastA != lastB {
+		return false
+	}
+
+	return firstNamesMatch(firstA, firstB)
+}
+
+// SamePlayer reports whether two player records, e.g. a club entry and a US
+// Chess member, refer to the same person. They are matched by USCF id when
+// both have one, and by NamesMatch otherwise. An id of "" or "0" is missing.
+func SamePlayer(idA, nameA, idB, nameB string) bool {
+	knownA := idA != "" && idA != "0"
+	knownB := idB != "" && idB != "0"
+	if knownA && knownB {
+		return idA == idB
+	}
+
+	return NamesMatch(nameA, nameB)
+}
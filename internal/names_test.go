@@ -0,0 +1,61 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"testing"
+)
+
+func TestNamesMatch(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		// exact names, ignoring case, punctuation and middle names
+		{"Mike Brown", "mike brown", true},
+		{"Mary Anne O'Brien", "MARY O'BRIEN", true},
+		{"BROWN, MIKE", "Mike Brown", true},
+		{"Brown, Jr., Mike", "Mike Brown Jr.", true},
+		// nicknames
+		{"Mike Brown", "Michael Brown", true},
+		{"BROWN, MICHAEL", "Mike Brown", true},
+		{"Bob Smith", "Robert Smith", true},
+		{"Pat Jones", "Patricia Jones", true},
+		{"Pat Jones", "Patrick Jones", true},
+		{"Patricia Jones", "Patrick Jones", false},
+		// different people
+		{"Mike Brown", "Michael Green", false},
+		{"Mike Brown", "Mark Brown", false},
+		{"Brown", "Mike Brown", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := NamesMatch(tt.a, tt.b); got != tt.want {
+			t.Errorf("NamesMatch(%q, %q) = %v; want %v", tt.a, tt.b, got,
+				tt.want)
+		}
+	}
+}
+
+func TestSamePlayer(t *testing.T) {
+	tests := []struct {
+		idA, nameA, idB, nameB string
+		want                   bool
+	}{
+		// ids decide when both are known, whatever the names
+		{"12345678", "Mike Brown", "12345678", "M. Brown", true},
+		{"12345678", "Mike Brown", "87654321", "Mike Brown", false},
+		// names decide when either id is missing
+		{"12345678", "Mike Brown", "", "Michael Brown", true},
+		{"0", "Mike Brown", "12345678", "Mike Brown", true},
+		{"", "Mike Brown", "", "Mark Brown", false},
+	}
+	for _, tt := range tests {
+		if got := SamePlayer(tt.idA, tt.nameA, tt.idB, tt.nameB); got != tt.want {
+			t.Errorf("SamePlayer(%q, %q, %q, %q) = %v; want %v", tt.idA,
+				tt.nameA, tt.idB, tt.nameB, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Rating is a rating parsed from one of the string formats used by the club
+// and USCF websites.
+type Rating struct {
+	Value            int  // base rating; 0 when unrated
+	ProvisionalGames int  // games the rating is based on; 0 when established
+	Unrated          bool // no usable rating
+	Reported         bool // self-reported rather than official, e.g. "1234*"
+}
+
+// ParseRating parses a rating string such as "1234", "559/24", "1234P10",
+// "unrated", "unr." or "<unrated>". A trailing "*" or a "reported" note marks
+// a self-reported rating. Any text it does not recognize is treated as
+// unrated.
+func ParseRating(s string) Rating {
+	s = strings.ToLower(strings.TrimSpace(s))
+	reported := strings.HasSuffix(s, "*") || strings.Contains(s, "reported")
+	s = strings.TrimRight(s, "* ")
+	s = strings.Trim(s, "()")
+
+	digits := leadingDigits(s)
+	if digits == "" {
+		return Rating{Unrated: true}
+	}
+	value, err := strconv.Atoi(digits)
+	if err != nil || value <= 0 {
+		return Rating{Unrated: true}
+	}
+
+	r := Rating{Value: value, Reported: reported}
+	// provisional ratings are written as either "559/24" or "1654P11"
+	rest := strings.TrimSpace(s[len(digits):])
+	if rest, ok := strings.CutPrefix(rest, "/"); ok {
+		rest = strings.TrimSpace(rest)
+		r.ProvisionalGames, _ = strconv.Atoi(leadingDigits(rest))
+	} else if rest, ok := strings.CutPrefix(rest, "p"); ok {
+		r.ProvisionalGames, _ = strconv.Atoi(leadingDigits(rest))
+	}
+
+	return r
+}
+
+func leadingDigits(s string) string {
+	for idx, c := range s {
+		if !unicode.IsDigit(c) {
+			return s[:idx]
+		}
+	}
+	return s
+}
+
+// ExpectedScore returns the Elo winning expectancy of a player rated rating
+// against an opponent rated oppRating.
+func ExpectedScore(rating float64, oppRating float64) float64 {
+	return 1.0 / (1.0 + math.Pow(10.0, (oppRating-rating)/400.0))
+}
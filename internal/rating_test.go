@@ -0,0 +1,52 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import "testing"
+
+func TestParseRating(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Rating
+	}{
+		{"1234", Rating{Value: 1234}},
+		{" 1234 ", Rating{Value: 1234}},
+		{"(1234)", Rating{Value: 1234}},
+		{"559/24", Rating{Value: 559, ProvisionalGames: 24}},
+		{"559 / 24", Rating{Value: 559, ProvisionalGames: 24}},
+		{"1654P11", Rating{Value: 1654, ProvisionalGames: 11}},
+		{"1654p11", Rating{Value: 1654, ProvisionalGames: 11}},
+		{"1654P", Rating{Value: 1654}},
+		{"1800*", Rating{Value: 1800, Reported: true}},
+		{"1800 (reported)", Rating{Value: 1800, Reported: true}},
+		{"unrated*", Rating{Unrated: true}},
+		{"100", Rating{Value: 100}},
+		{"0", Rating{Unrated: true}},
+		{"", Rating{Unrated: true}},
+		{"unrated", Rating{Unrated: true}},
+		{"Unrated", Rating{Unrated: true}},
+		{"unr.", Rating{Unrated: true}},
+		{"<unrated>", Rating{Unrated: true}},
+		{"n/a", Rating{Unrated: true}},
+	}
+	for _, tt := range tests {
+		if got := ParseRating(tt.in); got != tt.want {
+			t.Errorf("ParseRating(%q) = %+v; want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpectedScore(t *testing.T) {
+	if got := ExpectedScore(1500, 1500); got != 0.5 {
+		t.Errorf("ExpectedScore(equal) = %v; want 0.5", got)
+	}
+	// 400 points is 10 to 1 odds
+	if got := ExpectedScore(1900, 1500); got < 0.9090 || got > 0.9091 {
+		t.Errorf("ExpectedScore(+400) = %v; want 10/11", got)
+	}
+	if got := ExpectedScore(1500, 1900) + ExpectedScore(1900, 1500); got != 1 {
+		t.Errorf("expectancies sum to %v; want 1", got)
+	}
+}
@@ -6,7 +6,9 @@ package internal
 
 import (
 	"fmt"
+	"log"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -14,6 +16,10 @@ import (
 	"github.com/araddon/dateparse"
 )
 
+// Now returns the current time. Tests may replace it to pin the current
+// time when checking date windows.
+var Now = time.Now
+
 // ParseDateOrZero returns a parsed time or zero if input is empty or "null".
 func ParseDateOrZero(s string) (time.Time, error) {
 	if s == "" || s == "null" {
@@ -22,9 +28,176 @@ func ParseDateOrZero(s string) (time.Time, error) {
 	return dateparse.ParseAny(s)
 }
 
-// ScoreToString returns a score as an integer or integer plus ½ if applicable.
-// Assumes score is always an integer or integer plus 0.5.
+// ParseDateLenient is like ParseDateOrZero but treats a date which cannot be
+// parsed as unset, logging it along with the name of the field it came from,
+// so that one malformed date does not fail decoding of an entire response.
+func ParseDateLenient(s string, field string) time.Time {
+	t, err := ParseDateOrZero(s)
+	if err != nil {
+		log.Printf("internal: ignoring unparseable %v %q: %v", field, s, err)
+		return time.Time{}
+	}
+
+	return t
+}
+
+// SectionMatches reports whether the section name matches a user supplied
+// section filter. Matching is a case insensitive substring match so that
+// e.g. "u18" selects "U1800"; an empty filter matches every section.
+func SectionMatches(name, filter string) bool {
+	return filter == "" ||
+		strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+// SectionLess reports whether section a is listed before section b:
+// "Open" first, then "Championship", then U<Number> sections descending by
+// number, then others lexicographically.
+func SectionLess(a, b string) bool {
+	// "Open" or "Championship" always first
+	if a == "Open" && b != "Open" {
+		return true
+	}
+	if b == "Open" && a != "Open" {
+		return false
+	}
+	if a == "Championship" && b != "Championship" {
+		return true
+	}
+	if b == "Championship" && a != "Championship" {
+		return false
+	}
+	ua, ub := strings.HasPrefix(a, "U"), strings.HasPrefix(b, "U")
+	// Both U-sections: compare numeric suffix descending
+	if ua && ub {
+		ai, errA := strconv.Atoi(strings.TrimPrefix(a, "U"))
+		bi, errB := strconv.Atoi(strings.TrimPrefix(b, "U"))
+		if errA == nil && errB == nil {
+			return ai > bi
+		}
+	}
+	// U-sections before non-U (after Championship)
+	if ua != ub {
+		return ua
+	}
+	// Fallback lexicographical
+	return a < b
+}
+
+// DisplaySectionName labels a section for display with the word "Section"
+// appearing once after its name, e.g. "Open Section" whether the raw name is
+// "Open", "Open Section", or "Section Open". Unnamed sections are labeled
+// "UNNAMED Section".
+func DisplaySectionName(name string) string {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+	if strings.HasPrefix(lower, "section ") {
+		name = strings.TrimSpace(name[len("section "):])
+	} else if strings.HasSuffix(lower, " section") {
+		name = strings.TrimSpace(name[:len(name)-len(" section")])
+	} else if lower == "section" {
+		name = ""
+	}
+	if name == "" {
+		name = "UNNAMED"
+	}
+
+	return name + " Section"
+}
+
+// ClampDays bounds a requested number of days to [1, MaxDays], substituting
+// DefaultDays for requests that are zero or negative.
+func ClampDays(requested int) int {
+	if requested <= 0 {
+		return DefaultDays
+	} else if requested > MaxDays {
+		return MaxDays
+	}
+	return requested
+}
+
+// ClampAttendanceDays is like ClampDays but bounds the number of past days
+// attendance compares to [1, MaxAttendanceDays], substituting
+// DefaultAttendanceDays for requests that are zero or negative.
+func ClampAttendanceDays(requested int) int {
+	if requested <= 0 {
+		return DefaultAttendanceDays
+	} else if requested > MaxAttendanceDays {
+		return MaxAttendanceDays
+	}
+	return requested
+}
+
+// ClampSignedDays bounds a requested number of days to [-MaxDays, MaxDays]
+// for callers where a negative number of days looks back in time.
+func ClampSignedDays(requested int) int {
+	if requested < -MaxDays {
+		return -MaxDays
+	} else if requested > MaxDays {
+		return MaxDays
+	}
+	return requested
+}
+
+// CompetitionRanks returns the places of players whose scores are listed
+// best first, using standard competition ranking: players with equal scores
+// share the place of the first of them and the places after skip
+// accordingly, e.g. 1, 2, 2, 2, 5.
+func CompetitionRanks(scores []float64) []int {
+	ranks := make([]int, len(scores))
+	for idx, score := range scores {
+		if idx > 0 && score == scores[idx-1] {
+			ranks[idx] = ranks[idx-1]
+		} else {
+			ranks[idx] = idx + 1
+		}
+	}
+
+	return ranks
+}
+
+// ScoreStyle selects how scores are formatted.
+type ScoreStyle int
+
+const (
+	// ScoreStyleFraction shows half points as "½", e.g. "2½".
+	ScoreStyleFraction ScoreStyle = iota
+	// ScoreStyleDecimal shows scores with one decimal place, e.g. "2.5".
+	ScoreStyleDecimal
+)
+
+// ParseScoreStyle parses "fraction" or "decimal", ignoring case.
+func ParseScoreStyle(s string) (ScoreStyle, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "fraction":
+		return ScoreStyleFraction, true
+	case "decimal":
+		return ScoreStyleDecimal, true
+	}
+
+	return ScoreStyleFraction, false
+}
+
+func (s ScoreStyle) String() string {
+	if s == ScoreStyleDecimal {
+		return "decimal"
+	}
+	return "fraction"
+}
+
+// ScoreToString formats a score in the configured style (see
+// ScoreFormatEnv). Every score shown to users (standings, pairings,
+// crosstables) goes through it so that they are formatted alike.
 func ScoreToString(score float64) string {
+	return FormatScore(score, GetConfig().ScoreFormat)
+}
+
+// FormatScore formats a score in the given style. In the fraction style a
+// score is shown as an integer or integer plus ½ if applicable; it assumes
+// score is always an integer or integer plus 0.5.
+func FormatScore(score float64, style ScoreStyle) string {
+	if style == ScoreStyleDecimal {
+		return fmt.Sprintf("%.1f", score)
+	}
 	intPart, frac := math.Modf(score)
 	// Integer score
 	if frac == 0 {
@@ -52,14 +225,37 @@ func NormalizeName(s string) string {
 	if len(parts) > 1 {
 		last = parts[len(parts)-1]
 	}
-	firstLower := strings.ToLower(first)
-	lastLower := strings.ToLower(last)
-	fn := []rune(firstLower)
-	ln := []rune(lastLower)
-	firstTitle := string(unicode.ToUpper(fn[0])) + string(fn[1:])
-	lastTitle := string(unicode.ToUpper(ln[0])) + string(ln[1:])
+	firstTitle := titleWord(first)
+	lastTitle := titleWord(last)
 	if firstTitle == lastTitle {
 		return firstTitle
 	}
 	return firstTitle + " " + lastTitle
 }
+
+// titleWord lower cases a word except for its first letter and any letter
+// following an apostrophe or hyphen, e.g. "o'BRIEN" becomes "O'Brien".
+func titleWord(s string) string {
+	rs := []rune(strings.ToLower(s))
+	for idx := range rs {
+		if idx == 0 || rs[idx-1] == '\'' || rs[idx-1] == '-' {
+			rs[idx] = unicode.ToUpper(rs[idx])
+		}
+	}
+	return string(rs)
+}
+
+// maskedIDDigits is the number of trailing digits MaskMemberID leaves visible
+const maskedIDDigits = 4
+
+// MaskMemberID hides all but the last 4 digits of a USCF member id, e.g.
+// "****5678" for "12345678", for output which is shared publicly. Ids of 4
+// or fewer digits are returned unchanged.
+func MaskMemberID(id string) string {
+	if len(id) <= maskedIDDigits {
+		return id
+	}
+
+	return strings.Repeat("*", len(id)-maskedIDDigits) +
+		id[len(id)-maskedIDDigits:]
+}
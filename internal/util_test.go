@@ -0,0 +1,158 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClampDays(t *testing.T) {
+	tests := []struct {
+		requested, want int
+	}{
+		{-100, DefaultDays},
+		{-1, DefaultDays},
+		{0, DefaultDays},
+		{1, 1},
+		{30, 30},
+		{MaxDays, MaxDays},
+		{MaxDays + 1, MaxDays},
+	}
+	for _, tt := range tests {
+		if got := ClampDays(tt.requested); got != tt.want {
+			t.Errorf("ClampDays(%v) = %v; want %v", tt.requested, got, tt.want)
+		}
+	}
+}
+
+func TestClampAttendanceDays(t *testing.T) {
+	tests := []struct {
+		requested, want int
+	}{
+		{-1, DefaultAttendanceDays},
+		{0, DefaultAttendanceDays},
+		{1, 1},
+		{180, 180},
+		{MaxAttendanceDays, MaxAttendanceDays},
+		{MaxAttendanceDays + 1, MaxAttendanceDays},
+	}
+	for _, tt := range tests {
+		if got := ClampAttendanceDays(tt.requested); got != tt.want {
+			t.Errorf("ClampAttendanceDays(%v) = %v; want %v", tt.requested,
+				got, tt.want)
+		}
+	}
+}
+
+func TestClampSignedDays(t *testing.T) {
+	tests := []struct {
+		requested, want int
+	}{
+		{-MaxDays - 1, -MaxDays},
+		{-MaxDays, -MaxDays},
+		{-7, -7},
+		{0, 0},
+		{7, 7},
+		{MaxDays, MaxDays},
+		{MaxDays + 1, MaxDays},
+	}
+	for _, tt := range tests {
+		if got := ClampSignedDays(tt.requested); got != tt.want {
+			t.Errorf("ClampSignedDays(%v) = %v; want %v", tt.requested, got,
+				tt.want)
+		}
+	}
+}
+
+func TestMaskMemberID(t *testing.T) {
+	tests := []struct {
+		id, want string
+	}{
+		{"12345678", "****5678"},
+		{"1234567", "***4567"},
+		{"1234", "1234"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := MaskMemberID(tt.id); got != tt.want {
+			t.Errorf("MaskMemberID(%q) = %q; want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateLenient(t *testing.T) {
+	want := time.Date(2026, time.March, 14, 10, 0, 0, 0, time.UTC)
+	if got := ParseDateLenient("2026-03-14T10:00:00", "Date"); !got.Equal(want) {
+		t.Errorf("ParseDateLenient() = %v; want %v", got, want)
+	}
+	for _, s := range []string{"", "null", "not a date", "2026-13-45",
+		"14th of March"} {
+
+		if got := ParseDateLenient(s, "Date"); !got.IsZero() {
+			t.Errorf("ParseDateLenient(%q) = %v; want zero", s, got)
+		}
+	}
+}
+
+func TestDisplaySectionName(t *testing.T) {
+	for in, want := range map[string]string{
+		"Open":          "Open Section",
+		"U1800":         "U1800 Section",
+		"Section Open":  "Open Section",
+		"open section":  "open Section",
+		" Reserve ":     "Reserve Section",
+		"":              "UNNAMED Section",
+		"Section":       "UNNAMED Section",
+		"Sectional U10": "Sectional U10 Section",
+	} {
+		if got := DisplaySectionName(in); got != want {
+			t.Errorf("DisplaySectionName(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestCompetitionRanks(t *testing.T) {
+	got := CompetitionRanks([]float64{3, 2, 2, 2, 1, 1, 0})
+	want := []int{1, 2, 2, 2, 5, 5, 7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompetitionRanks() = %v; want %v", got, want)
+	}
+	if got := CompetitionRanks(nil); len(got) != 0 {
+		t.Errorf("CompetitionRanks(nil) = %v; want []", got)
+	}
+}
+
+func TestFormatScore(t *testing.T) {
+	tests := []struct {
+		score float64
+		style ScoreStyle
+		want  string
+	}{
+		{2.5, ScoreStyleFraction, "2½"},
+		{0.5, ScoreStyleFraction, "½"},
+		{3, ScoreStyleFraction, "3"},
+		{2.5, ScoreStyleDecimal, "2.5"},
+		{3, ScoreStyleDecimal, "3.0"},
+	}
+	for _, tc := range tests {
+		if got := FormatScore(tc.score, tc.style); got != tc.want {
+			t.Errorf("FormatScore(%v, %v) = %q; want %q", tc.score, tc.style,
+				got, tc.want)
+		}
+	}
+
+	SetenvForTest(t, ScoreFormatEnv, "Decimal")
+	if got := ScoreToString(1.5); got != "1.5" {
+		t.Errorf("ScoreToString(1.5) = %q with %v=Decimal; want \"1.5\"", got,
+			ScoreFormatEnv)
+	}
+	SetenvForTest(t, ScoreFormatEnv, "thirds")
+	if got := ScoreToString(1.5); got != "1½" {
+		t.Errorf("ScoreToString(1.5) = %q with an invalid style; want \"1½\"",
+			got)
+	}
+}
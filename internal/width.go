@@ -0,0 +1,100 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MinColumnWidth is the narrowest a shrunken table column may become.
+const MinColumnWidth = 6
+
+// DisplayWidth returns the number of columns s occupies in monospace output,
+// counting characters rather than bytes so that names with accents (e.g.
+// "José Peña") are measured as fmt pads them with %-*s.
+func DisplayWidth(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// TruncateToWidth shortens s to at most width characters, replacing its tail
+// with an ellipsis when it does not fit. A width of zero or less leaves s
+// unchanged.
+func TruncateToWidth(s string, width int) string {
+	if width <= 0 || DisplayWidth(s) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// WrapText word wraps each line of s so that none exceeds width characters.
+// Words longer than width are truncated. A width of zero or less leaves s
+// unchanged.
+func WrapText(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines {
+		lineLen := 0
+		for _, word := range strings.Fields(line) {
+			word = TruncateToWidth(word, width)
+			wordLen := DisplayWidth(word)
+			if lineLen > 0 && lineLen+1+wordLen > width {
+				sb.WriteString("\n")
+				lineLen = 0
+			} else if lineLen > 0 {
+				sb.WriteString(" ")
+				lineLen++
+			}
+			sb.WriteString(word)
+			lineLen += wordLen
+		}
+		if idx < len(lines)-1 {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// FitColumns returns a copy of the column widths of a table whose columns are
+// separated by sep characters, narrowed so that a line fits within maxWidth.
+// Only the columns listed in shrinkable are narrowed, widest first and no
+// further than MinColumnWidth, so the result may still exceed maxWidth. A
+// maxWidth of zero or less leaves the widths unchanged.
+func FitColumns(widths []int, sep int, maxWidth int, shrinkable ...int) []int {
+	fitted := make([]int, len(widths))
+	copy(fitted, widths)
+	if maxWidth <= 0 {
+		return fitted
+	}
+
+	total := sep * max(len(fitted)-1, 0)
+	for _, w := range fitted {
+		total += w
+	}
+	for total > maxWidth {
+		widest := -1
+		for _, idx := range shrinkable {
+			if fitted[idx] > MinColumnWidth &&
+				(widest < 0 || fitted[idx] > fitted[widest]) {
+				widest = idx
+			}
+		}
+		if widest < 0 {
+			break
+		}
+		fitted[widest]--
+		total--
+	}
+
+	return fitted
+}
@@ -0,0 +1,61 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTruncateToWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"Alice Smith", 0, "Alice Smith"},
+		{"Alice Smith", 11, "Alice Smith"},
+		{"Alice Smith", 8, "Alice S…"},
+		{"Alice Smith", 1, "…"},
+		{"Bob(1500 ½)", 10, "Bob(1500 …"},
+	}
+	for _, tc := range tests {
+		if got := TruncateToWidth(tc.s, tc.width); got != tc.want {
+			t.Errorf("TruncateToWidth(%q, %v) = %q; want %q", tc.s, tc.width,
+				got, tc.want)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	got := WrapText("pairings are tentative and subject to change", 16)
+	want := "pairings are\ntentative and\nsubject to\nchange"
+	if got != want {
+		t.Errorf("WrapText() = %q; want %q", got, want)
+	}
+}
+
+func TestFitColumns(t *testing.T) {
+	tests := []struct {
+		widths     []int
+		maxWidth   int
+		shrinkable []int
+		want       []int
+	}{
+		{[]int{5, 30, 5}, 0, []int{1}, []int{5, 30, 5}},
+		{[]int{5, 30, 5}, 50, []int{1}, []int{5, 30, 5}},
+		{[]int{5, 30, 5}, 30, []int{1}, []int{5, 16, 5}},
+		{[]int{5, 30, 5}, 10, []int{1}, []int{5, MinColumnWidth, 5}},
+		{[]int{5, 20, 12}, 33, []int{1, 2}, []int{5, 12, 12}},
+		{[]int{5, 20, 12}, 25, []int{1, 2}, []int{5, 8, 8}},
+	}
+	for _, tc := range tests {
+		got := FitColumns(tc.widths, 2, tc.maxWidth, tc.shrinkable...)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("FitColumns(%v, 2, %v, %v) = %v; want %v", tc.widths,
+				tc.maxWidth, tc.shrinkable, got, tc.want)
+		}
+	}
+}
@@ -27,6 +27,13 @@ import (
 	"github.com/aws/smithy-go"
 )
 
+// KeyVersion is included in each cache entry's object key. Bump it whenever
+// the handling of cached responses changes incompatibly so that entries
+// stored by older code are no longer served. Version 0 is the layout which
+// predates versioning, so entries stored before versioning was introduced
+// remain valid until the first bump.
+const KeyVersion = 0
+
 // Cache objects store and retrieve data using Amazon S3.
 type Cache struct {
 	// Config is the Amazon S3 configuration.
@@ -49,14 +56,34 @@ type Cache struct {
 	// LogErrors controls whether errors should be logged or not
 	logErrors bool
 
+	// keyVersion is the KeyVersion of the object keys used by this cache
+	keyVersion int
+
+	// readPreviousVersion makes Get fall back to the previous key version's
+	// entry on a miss
+	readPreviousVersion bool
+
+	// readOnly makes Set and Delete no-ops so that existing entries are
+	// served but none are added, replaced, or removed
+	readOnly bool
+
 	// The context to specify when initiating s3 requests
 	ctx context.Context
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
+	data, ok := c.get(c.objectKey(key, c.keyVersion))
+	if !ok && c.readPreviousVersion && c.keyVersion > 0 {
+		data, ok = c.get(c.objectKey(key, c.keyVersion-1))
+	}
+
+	return data, ok
+}
+
+func (c *Cache) get(objKey string) ([]byte, bool) {
 	input := &s3.GetObjectInput{
 		Bucket: aws.String(c.bucketName),
-		Key:    aws.String(c.cacheKeyToObjectKey(key)),
+		Key:    aws.String(objKey),
 	}
 
 	resp, err := c.Client.GetObject(c.ctx, input)
@@ -97,8 +124,30 @@ func (c *Cache) Get(key string) ([]byte, bool) {
 	return data, err == nil
 }
 
-// Set stores the provided data in the cache under the given key.
+// ErrReadOnly is returned by Put when the cache is read-only.
+var ErrReadOnly = errors.New("s3cache: cache is read-only")
+
+// Set stores the provided data in the cache under the given key. It does
+// nothing when the cache is read-only, and failures are only logged; use Put
+// to learn whether the data was stored.
 func (c *Cache) Set(key string, data []byte) {
+	if c.readOnly {
+		return
+	}
+
+	err := c.Put(key, data)
+	if err != nil && c.logErrors {
+		log.Printf("s3cache.set: %v", err)
+	}
+}
+
+// Put stores the provided data in the cache under the given key, returning
+// ErrReadOnly when the cache is read-only.
+func (c *Cache) Put(key string, data []byte) error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+
 	input := &s3.PutObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(c.cacheKeyToObjectKey(key)),
@@ -109,18 +158,12 @@ func (c *Cache) Set(key string, data []byte) {
 		var buf bytes.Buffer
 		gw := gzip.NewWriter(&buf)
 		if _, err := gw.Write(data); err != nil {
-			if c.logErrors {
-				log.Printf("s3cache.set: failed to gzip data for %v%v: %v",
-					*input.Bucket, *input.Key, err)
-			}
-			return
+			return fmt.Errorf("failed to gzip data for %v%v: %w",
+				*input.Bucket, *input.Key, err)
 		}
 		if err := gw.Close(); err != nil {
-			if c.logErrors {
-				log.Printf("s3cache.set: failed to close gzip writer for %v%v: %v",
-					*input.Bucket, *input.Key, err)
-			}
-			return
+			return fmt.Errorf("failed to close gzip writer for %v%v: %w",
+				*input.Bucket, *input.Key, err)
 		}
 		input.Body = &buf
 		input.ContentEncoding = aws.String("gzip")
@@ -128,14 +171,21 @@ func (c *Cache) Set(key string, data []byte) {
 
 	_, err := c.Client.PutObject(c.ctx, input)
 	if err != nil {
-		if c.logErrors {
-			log.Printf("s3cache.set: put failed for %v%v: %v", *input.Bucket,
-				*input.Key, err)
-		}
+		return fmt.Errorf("put failed for %v%v: %w", *input.Bucket,
+			*input.Key, err)
 	}
+
+	return nil
 }
 
+// Delete removes the cache entry for key. It does nothing in a read-only
+// cache, as httpcache deletes entries whose refetch fails; to remove entries
+// regardless, use a cache which is not read-only.
 func (c *Cache) Delete(key string) {
+	if c.readOnly {
+		return
+	}
+
 	input := &s3.DeleteObjectInput{
 		Bucket: aws.String(c.bucketName),
 		Key:    aws.String(c.cacheKeyToObjectKey(key)),
@@ -149,12 +199,44 @@ func (c *Cache) Delete(key string) {
 	}
 }
 
+// SetReadOnly controls whether the cache is read-only. A read-only cache
+// still serves existing entries from Get, but Set and Delete do nothing; this
+// freezes the cache contents, e.g. to avoid persisting bad upstream responses,
+// or losing good ones, during an incident.
+func (c *Cache) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// SetReadPreviousVersion controls whether Get falls back to an entry stored
+// under the previous KeyVersion when the current version has none. This
+// avoids a burst of origin fetches after a version bump when older entries
+// remain usable.
+func (c *Cache) SetReadPreviousVersion(readPrevious bool) {
+	c.readPreviousVersion = readPrevious
+}
+
+// ObjectKey returns the S3 object key under which the cache entry for key is
+// stored.
+func (c *Cache) ObjectKey(key string) string {
+	return c.cacheKeyToObjectKey(key)
+}
+
 func (c *Cache) cacheKeyToObjectKey(key string) string {
+	return c.objectKey(key, c.keyVersion)
+}
+
+// objectKey returns the object key of a cache entry under the given key
+// version. Version 0 keys predate versioning and have no version component.
+func (c *Cache) objectKey(key string, version int) string {
 	const PathPrefix = "s3cache"
 
 	h := md5.New()
 	io.WriteString(h, key)
 	objKey := fmt.Sprintf("/%v/%v", PathPrefix, hex.EncodeToString(h.Sum(nil)))
+	if version > 0 {
+		objKey = fmt.Sprintf("/%v/v%v/%v", PathPrefix, version,
+			hex.EncodeToString(h.Sum(nil)))
+	}
 	if c.gzip {
 		objKey += ".gz"
 	}
@@ -174,6 +256,7 @@ func New(ctxIn context.Context, bucketNameIn string, gzipIn bool,
 		bucketName: bucketNameIn,
 		gzip:       gzipIn,
 		logErrors:  logErrorsIn,
+		keyVersion: KeyVersion,
 	}
 }
 
@@ -5,10 +5,19 @@
 package s3cache
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gregjones/httpcache"
 	"github.com/gregjones/httpcache/test"
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 )
@@ -36,3 +45,191 @@ func TestS3CacheWithGzip(t *testing.T) {
 
 	test.Cache(t, cache)
 }
+
+// recordingDoer captures the request paths of S3 calls without contacting S3.
+type recordingDoer struct {
+	paths []string
+}
+
+func (d *recordingDoer) Do(req *http.Request) (*http.Response, error) {
+	d.paths = append(d.paths, req.URL.Path)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestObjectKeyMatchesSet(t *testing.T) {
+	const key = "https://beta.boylstonchess.org/api/event/1312"
+
+	for _, gzip := range []bool{false, true} {
+		doer := &recordingDoer{}
+		cache := New(context.Background(), "testbucket", gzip, true)
+		cache.Client = s3.New(s3.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: aws.String("https://s3.test"),
+			UsePathStyle: true,
+			HTTPClient:   doer,
+			Credentials:  aws.AnonymousCredentials{},
+		})
+
+		cache.Set(key, []byte("data"))
+		if len(doer.paths) != 1 {
+			t.Fatalf("gzip=%v: expected 1 request, got %v", gzip, len(doer.paths))
+		}
+		if want := "/testbucket/" + cache.ObjectKey(key); doer.paths[0] != want {
+			t.Errorf("gzip=%v: Set used path %q; want %q", gzip, doer.paths[0],
+				want)
+		}
+	}
+}
+
+func TestReadOnlySet(t *testing.T) {
+	doer := &recordingDoer{}
+	cache := New(context.Background(), "testbucket", false, true)
+	cache.Client = s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("https://s3.test"),
+		UsePathStyle: true,
+		HTTPClient:   doer,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	cache.SetReadOnly(true)
+
+	cache.Set("key", []byte("data"))
+	if len(doer.paths) != 0 {
+		t.Fatalf("read-only Set made %v request(s); want none", len(doer.paths))
+	}
+	if _, ok := cache.Get("key"); !ok {
+		t.Errorf("read-only Get failed")
+	}
+	if len(doer.paths) != 1 {
+		t.Errorf("read-only Get made %v request(s); want 1", len(doer.paths))
+	}
+}
+
+// objectDoer serves S3 GETs from objects, keyed by request path, and records
+// the method and path of every S3 call without contacting S3.
+type objectDoer struct {
+	objects map[string][]byte
+	calls   []string
+}
+
+func (d *objectDoer) Do(req *http.Request) (*http.Response, error) {
+	d.calls = append(d.calls, req.Method+" "+req.URL.Path)
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+	if req.Method != http.MethodGet {
+		return resp, nil
+	}
+	data, ok := d.objects[req.URL.Path]
+	if !ok {
+		resp.StatusCode = http.StatusNotFound
+		data = []byte("<Error><Code>NoSuchKey</Code></Error>")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	return resp, nil
+}
+
+func TestReadOnlyKeepsStaleEntryOnRefetchError(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer origin.Close()
+
+	doer := &objectDoer{objects: make(map[string][]byte)}
+	cache := New(context.Background(), "testbucket", false, true)
+	cache.Client = s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("https://s3.test"),
+		UsePathStyle: true,
+		HTTPClient:   doer,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	cache.SetReadOnly(true)
+
+	// an entry which expired an hour ago
+	stale := "HTTP/1.1 200 OK\r\n" +
+		"Cache-Control: max-age=60\r\n" +
+		"Date: " + time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat) +
+		"\r\nContent-Length: 6\r\n\r\nfrozen"
+	objPath := "/testbucket/" + cache.ObjectKey(origin.URL)
+	doer.objects[objPath] = []byte(stale)
+
+	client := &http.Client{Transport: httpcache.NewTransport(cache)}
+	resp, err := client.Get(origin.URL)
+	if err != nil {
+		t.Fatalf("Get() err = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Get() = %v; want the origin's %v", resp.StatusCode,
+			http.StatusServiceUnavailable)
+	}
+
+	for _, call := range doer.calls {
+		if !strings.HasPrefix(call, http.MethodGet+" ") {
+			t.Errorf("read-only cache made S3 call %q", call)
+		}
+	}
+	if _, ok := cache.Get(origin.URL); !ok {
+		t.Errorf("stale entry no longer served")
+	}
+}
+
+func TestObjectKeyVersions(t *testing.T) {
+	const key = "https://beta.boylstonchess.org/api/event/1312"
+	cache := New(context.Background(), "testbucket", false, true)
+
+	current := cache.objectKey(key, KeyVersion)
+	if current != cache.ObjectKey(key) {
+		t.Errorf("ObjectKey() = %q; want the current version's %q",
+			cache.ObjectKey(key), current)
+	}
+	if got := cache.objectKey(key, 0); !strings.HasPrefix(got, "/s3cache/") ||
+		strings.Count(got, "/") != 2 {
+
+		t.Errorf("version 0 object key = %q; want the unversioned layout", got)
+	}
+	seen := make(map[string]int)
+	for version := 0; version <= 2; version++ {
+		objKey := cache.objectKey(key, version)
+		if prev, ok := seen[objKey]; ok {
+			t.Errorf("versions %v and %v share object key %q", prev, version,
+				objKey)
+		}
+		seen[objKey] = version
+	}
+}
+
+func TestReadPreviousVersion(t *testing.T) {
+	const key = "https://beta.boylstonchess.org/api/event/1312"
+
+	doer := &objectDoer{objects: make(map[string][]byte)}
+	cache := New(context.Background(), "testbucket", false, true)
+	cache.Client = s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String("https://s3.test"),
+		UsePathStyle: true,
+		HTTPClient:   doer,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+	cache.keyVersion = 1
+	doer.objects["/testbucket/"+cache.objectKey(key, 0)] = []byte("legacy")
+
+	if _, ok := cache.Get(key); ok {
+		t.Errorf("Get() served the previous version's entry while disabled")
+	}
+	cache.SetReadPreviousVersion(true)
+	if data, ok := cache.Get(key); !ok || string(data) != "legacy" {
+		t.Errorf("Get() = %q, %v; want the previous version's entry", data, ok)
+	}
+}
@@ -0,0 +1,152 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// crossTableURLFmt is the US Chess MSA page listing an event's crosstables.
+const crossTableURLFmt = "https://www.uschess.org/msa/XtblMain.php?%v.0"
+
+// CrossTableURL returns the link to the US Chess crosstables of a rated event
+// (e.g. one returned by GetMultiAffiliateEvents).
+func CrossTableURL(eventID uschess.EventID) string {
+	return fmt.Sprintf(crossTableURLFmt, eventID)
+}
+
+// affiliateEventsLookup returns the rated events for a single affiliate.
+type affiliateEventsLookup func(ctx context.Context,
+	affiliateID uschess.AffiliateID) ([]uschess.RatedEvent, error)
+
+const (
+	affiliateEventsPageSize = 50
+	// bounds how many pages GetAffiliateEventsSince follows
+	maxAffiliateEventsPages = 20
+	// events that started this long before a cutoff are assumed to have
+	// ended before it
+	affiliateEventMaxSpan = 60 * 24 * time.Hour
+)
+
+// GetAffiliateEventsSince fetches the rated events of an affiliate that ended
+// on or after since, most recently started first. Unlike
+// GetAllAffiliateRatedEvents it stops following pages once they reach events
+// that started well before since, so a short window need not retrieve the
+// affiliate's full history. Rate limited requests are retried by the US Chess
+// client itself.
+func GetAffiliateEventsSince(ctx context.Context,
+	client *uschess.ClientWithResponses, affiliateID uschess.AffiliateID,
+	since time.Time) ([]uschess.RatedEvent, error) {
+
+	params := &uschess.GetAffiliateRatedEventsParams{
+		SortBy: uschess.AffiliateEventSortByStartDate,
+		Dir:    uschess.Desc,
+		Size:   affiliateEventsPageSize,
+	}
+	startCutoff := since.Add(-affiliateEventMaxSpan)
+	events := make([]uschess.RatedEvent, 0)
+	for pageNum := 0; pageNum < maxAffiliateEventsPages; pageNum++ {
+		resp, err := client.GetAffiliateRatedEventsWithResponse(ctx,
+			affiliateID, params)
+		if err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil {
+			return nil, fmt.Errorf("unable to fetch events page at offset %v: http status: %w",
+				params.Offset, internal.HTTPStatusError(resp.StatusCode()))
+		}
+		page := resp.JSON200
+		reachedCutoff := false
+		for _, ev := range page.Items {
+			if ev.StartDate.Time.Before(startCutoff) {
+				reachedCutoff = true
+				continue
+			}
+			if !ev.EndDate.Time.Before(since) {
+				events = append(events, ev)
+			}
+		}
+		if reachedCutoff || !page.HasNextPage || len(page.Items) == 0 {
+			return events, nil
+		}
+		params.Offset = page.Offset + int32(len(page.Items))
+	}
+
+	return events, nil
+}
+
+// GetMultiAffiliateEvents fetches rated events for each of affiliateIDs
+// concurrently and merges them into a single list ordered by end date, most
+// recent first. Events shared between affiliates are included once. An
+// affiliate that fails does not fail the whole query; the events from the
+// remaining affiliates are returned along with an error describing each
+// failure. A nonzero since restricts the results to events that ended on or
+// after it, fetching only as many pages of each affiliate's history as
+// needed.
+func GetMultiAffiliateEvents(ctx context.Context,
+	client *uschess.ClientWithResponses, affiliateIDs []uschess.AffiliateID,
+	since time.Time) ([]uschess.RatedEvent, error) {
+
+	return getMultiAffiliateEventsWithLookup(ctx, affiliateIDs,
+		func(ctx context.Context,
+			affiliateID uschess.AffiliateID) ([]uschess.RatedEvent, error) {
+
+			if since.IsZero() {
+				return client.GetAllAffiliateRatedEvents(ctx, affiliateID, nil)
+			}
+			return GetAffiliateEventsSince(ctx, client, affiliateID, since)
+		})
+}
+
+func getMultiAffiliateEventsWithLookup(ctx context.Context,
+	affiliateIDs []uschess.AffiliateID,
+	lookup affiliateEventsLookup) ([]uschess.RatedEvent, error) {
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs []error
+	seen := make(map[uschess.EventID]bool)
+	events := make([]uschess.RatedEvent, 0)
+
+	for _, aid := range affiliateIDs {
+		wg.Add(1)
+		go func(aid uschess.AffiliateID) {
+			defer wg.Done()
+			affEvents, err := lookup(ctx, aid)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("aid:%v: %w", aid, err))
+				return
+			}
+			for _, ev := range affEvents {
+				if seen[ev.Id] {
+					continue
+				}
+				seen[ev.Id] = true
+				events = append(events, ev)
+			}
+		}(aid)
+	}
+	wg.Wait()
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].EndDate.Time.Equal(events[j].EndDate.Time) {
+			return events[i].EndDate.Time.After(events[j].EndDate.Time)
+		}
+		return events[i].Id > events[j].Id
+	})
+
+	return events, errors.Join(errs...)
+}
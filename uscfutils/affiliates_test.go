@@ -0,0 +1,131 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func testRatedEvent(id string, day int) uschess.RatedEvent {
+	return uschess.RatedEvent{
+		Id:      uschess.EventID(id),
+		Name:    "Event " + id,
+		EndDate: openapi_types.Date{Time: time.Date(2026, time.March, day, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestGetMultiAffiliateEventsMergesAndDedupes(t *testing.T) {
+	byAid := map[uschess.AffiliateID][]uschess.RatedEvent{
+		"A1": {testRatedEvent("100", 1), testRatedEvent("300", 3)},
+		"A2": {testRatedEvent("300", 3), testRatedEvent("200", 2)},
+	}
+	events, err := getMultiAffiliateEventsWithLookup(context.Background(),
+		[]uschess.AffiliateID{"A1", "A2"},
+		func(_ context.Context, aid uschess.AffiliateID) ([]uschess.RatedEvent, error) {
+			return byAid[aid], nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	var ids []string
+	for _, ev := range events {
+		ids = append(ids, string(ev.Id))
+	}
+	if got, want := strings.Join(ids, ","), "300,200,100"; got != want {
+		t.Fatalf("event ids = %q; want %q", got, want)
+	}
+}
+
+func TestGetMultiAffiliateEventsPartialFailure(t *testing.T) {
+	events, err := getMultiAffiliateEventsWithLookup(context.Background(),
+		[]uschess.AffiliateID{"A1", "BAD"},
+		func(_ context.Context, aid uschess.AffiliateID) ([]uschess.RatedEvent, error) {
+			if aid == "BAD" {
+				return nil, errors.New("boom")
+			}
+			return []uschess.RatedEvent{testRatedEvent("100", 1)}, nil
+		})
+	if err == nil || !strings.Contains(err.Error(), "aid:BAD") {
+		t.Fatalf("err = %v; want failure for aid:BAD", err)
+	}
+	if len(events) != 1 || events[0].Id != "100" {
+		t.Fatalf("events = %v; want event 100 from A1", events)
+	}
+}
+
+func TestCrossTableURL(t *testing.T) {
+	got := CrossTableURL(uschess.EventID("202603151234"))
+	want := "https://www.uschess.org/msa/XtblMain.php?202603151234.0"
+	if got != want {
+		t.Fatalf("CrossTableURL() = %q; want %q", got, want)
+	}
+}
+
+func TestGetAffiliateEventsSincePaginates(t *testing.T) {
+	// three pages of history, most recently started first; the third page
+	// should never be requested since page 2 reaches the date cutoff
+	pages := []string{
+		`{"items":[
+			{"id":"e1","startDate":"2026-03-14","endDate":"2026-03-14"},
+			{"id":"e2","startDate":"2026-03-07","endDate":"2026-03-07"}],
+		 "offset":0,"pageSize":2,"hasNextPage":true}`,
+		`{"items":[
+			{"id":"e3","startDate":"2026-01-05","endDate":"2026-03-02"},
+			{"id":"e4","startDate":"2025-11-01","endDate":"2025-11-01"}],
+		 "offset":2,"pageSize":2,"hasNextPage":true}`,
+		`{"items":[
+			{"id":"e5","startDate":"2025-10-01","endDate":"2025-10-01"}],
+		 "offset":4,"pageSize":2,"hasNextPage":false}`,
+	}
+	var offsets []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		if r.URL.Path != "/api/v1/affiliates/A123/events" {
+			http.NotFound(w, r)
+			return
+		}
+		offset := r.URL.Query().Get("Offset")
+		offsets = append(offsets, offset)
+		idx, _ := strconv.Atoi(offset)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, pages[idx/2])
+	}))
+	defer srv.Close()
+
+	client, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	since := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	events, err := GetAffiliateEventsSince(context.Background(), client,
+		"A123", since)
+	if err != nil {
+		t.Fatalf("GetAffiliateEventsSince() err = %v", err)
+	}
+
+	var ids []string
+	for _, ev := range events {
+		ids = append(ids, string(ev.Id))
+	}
+	if got := strings.Join(ids, ","); got != "e1,e2,e3" {
+		t.Errorf("event ids = %v; want e1,e2,e3", got)
+	}
+	if got := strings.Join(offsets, ","); got != "0,2" {
+		t.Errorf("requested offsets = %v; want 0,2", got)
+	}
+}
@@ -0,0 +1,228 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// players with at most this many prior games are rated with the special
+	// formula
+	specialFormulaMaxGames = 8
+	// the bonus threshold per sqrt(games), as lowered in 2025
+	bonusThreshold = 10.0
+	// the bonus applies to events of at least bonusMinGames games, and is
+	// computed as if over at least bonusMinCountedGames
+	bonusMinGames        = 3
+	bonusMinCountedGames = 4
+	// the live ratings API only reports a game count for provisional
+	// ratings; established players' games are estimated per rated event
+	gamesPerRatedEvent = 4
+)
+
+// UnratedOpponentRatings supplies the ratings GetApproxRatingEstimate
+// substitutes for opponents who are unrated in Regular.
+type UnratedOpponentRatings struct {
+	// Reported holds ratings reported for opponents elsewhere, e.g. on their
+	// club event entry, and is preferred over Default.
+	Reported map[uschess.MemberID]int
+	// Default is used for any other unrated opponent; when not positive
+	// such opponents are an error as with uschess.GetRatingEstimate.
+	Default int
+}
+
+// rating returns the rating to substitute for the unrated opponent oppID.
+func (subs UnratedOpponentRatings) rating(oppID uschess.MemberID) (int, bool) {
+	if r := subs.Reported[oppID]; r > 0 {
+		return r, true
+	}
+	if subs.Default > 0 {
+		return subs.Default, true
+	}
+
+	return 0, false
+}
+
+// ApproxRatingEstimate is the result of GetApproxRatingEstimate.
+type ApproxRatingEstimate struct {
+	PostRating int
+	// Substituted maps each unrated opponent to the rating used in their
+	// place. The estimate is only approximate when it is not empty.
+	Substituted map[uschess.MemberID]int
+}
+
+// GetApproxRatingEstimate estimates memberID's post-event Regular rating
+// after scoring score against opponentIDs as uschess.GetRatingEstimate does,
+// except that an opponent who is unrated in Regular is counted at the
+// rating subs supplies for them rather than failing the estimate. US Chess
+// rates such opponents from their own results in the event, so the estimate
+// is only a ballpark whenever a rating was substituted. When none was, the
+// estimate is uschess.GetRatingEstimate's own.
+func GetApproxRatingEstimate(ctx context.Context,
+	client *uschess.ClientWithResponses, memberID uschess.MemberID,
+	opponentIDs []uschess.MemberID, score float64,
+	subs UnratedOpponentRatings) (*ApproxRatingEstimate, error) {
+
+	if score < 0 || score > float64(len(opponentIDs)) {
+		return nil, fmt.Errorf("score %v is not possible over %v games", score,
+			len(opponentIDs))
+	}
+
+	var myRating float64
+	var myGames int
+	fetched := make([]int, len(opponentIDs))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		player, err := client.GetPlayer(groupCtx, memberID,
+			&uschess.GetPlayerOptions{IncludeLiveRatings: true,
+				IncludeEvents: true})
+		if err != nil {
+			return err
+		}
+		ratings, err := player.LiveRatings()
+		if err != nil {
+			return err
+		}
+		j := regularSystemIndex(ratings)
+		if j < 0 {
+			return fmt.Errorf("member %v is unrated in %s", memberID,
+				uschess.RatingTypeR)
+		}
+		myRating = float64(ratings[j].Rating)
+		myGames = int(ratings[j].ProvisionalGameCount)
+		if myGames == 0 {
+			myGames = len(player.MemberEvents) * gamesPerRatedEvent
+		}
+		return nil
+	})
+	for i, oppID := range opponentIDs {
+		group.Go(func() error {
+			opp, err := client.GetPlayer(groupCtx, oppID,
+				&uschess.GetPlayerOptions{IncludeLiveRatings: true})
+			if err != nil {
+				return err
+			}
+			ratings, err := opp.LiveRatings()
+			if err != nil {
+				return err
+			}
+			if j := regularSystemIndex(ratings); j >= 0 {
+				fetched[i] = int(ratings[j].Rating)
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	opponentRatings, substituted, err := substituteUnratedOpponents(
+		opponentIDs, fetched, subs)
+	if err != nil {
+		return nil, err
+	}
+	if len(substituted) == 0 {
+		// every opponent is rated, so there's nothing to approximate
+		est, err := client.GetRatingEstimate(ctx, memberID, opponentIDs,
+			score, uschess.RatingTypeR)
+		if err != nil {
+			return nil, err
+		}
+		return &ApproxRatingEstimate{PostRating: int(est.PostRating)}, nil
+	}
+
+	return &ApproxRatingEstimate{
+		PostRating: int(math.Round(ratingEstimate(myRating, myGames, score,
+			opponentRatings))),
+		Substituted: substituted,
+	}, nil
+}
+
+// substituteUnratedOpponents returns the rating of each of opponentIDs,
+// given their fetched Regular ratings (0 when unrated), taking the ratings
+// of unrated opponents from subs.
+func substituteUnratedOpponents(opponentIDs []uschess.MemberID,
+	fetched []int, subs UnratedOpponentRatings) ([]float64,
+	map[uschess.MemberID]int, error) {
+
+	ratings := make([]float64, len(opponentIDs))
+	substituted := make(map[uschess.MemberID]int)
+	for i, oppID := range opponentIDs {
+		if fetched[i] > 0 {
+			ratings[i] = float64(fetched[i])
+			continue
+		}
+		r, ok := subs.rating(oppID)
+		if !ok {
+			return nil, nil, fmt.Errorf("opponent %v is unrated in %s", oppID,
+				uschess.RatingTypeR)
+		}
+		ratings[i] = float64(r)
+		substituted[oppID] = r
+	}
+
+	return ratings, substituted, nil
+}
+
+// ratingEstimate applies the US Chess rating formulas to a player rated
+// rating over priorGames games who scored score against opponentRatings:
+// the special formula for players with few games, and otherwise the
+// standard formula with bonus points.
+func ratingEstimate(rating float64, priorGames int, score float64,
+	opponentRatings []float64) float64 {
+
+	numGames := len(opponentRatings)
+	if numGames == 0 {
+		return rating
+	}
+	n0 := math.Min(nStarGames(rating), float64(priorGames))
+	if priorGames <= specialFormulaMaxGames {
+		return specialRatingEstimate(rating, n0, score, opponentRatings)
+	}
+
+	expected := 0.0
+	for _, opp := range opponentRatings {
+		expected += internal.ExpectedScore(rating, opp)
+	}
+	delta := uschess.KFactor(rating, n0, numGames, false) * (score - expected)
+	bonus := 0.0
+	if numGames >= bonusMinGames {
+		bonus = math.Max(0.0, delta-bonusThreshold*
+			math.Sqrt(float64(max(numGames, bonusMinCountedGames))))
+	}
+
+	return rating + delta + bonus
+}
+
+// BuildApproxRatingEstimateOutput formats est, flagging it as approximate
+// and listing the ratings substituted when any were.
+func BuildApproxRatingEstimateOutput(est *ApproxRatingEstimate) string {
+	if len(est.Substituted) == 0 {
+		return fmt.Sprintf("Estimated New Rating: %v\n", est.PostRating)
+	}
+
+	ids := make([]string, 0, len(est.Substituted))
+	for id := range est.Substituted {
+		ids = append(ids, string(id))
+	}
+	sort.Strings(ids)
+	subs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		subs = append(subs, fmt.Sprintf("%v at %v", id,
+			est.Substituted[uschess.MemberID(id)]))
+	}
+
+	return fmt.Sprintf("Estimated New Rating: ~%v (approximate)\n* Unrated opponents were counted at substituted ratings: %v. US Chess rates them from their own results in the event, so the actual rating may differ.\n",
+		est.PostRating, strings.Join(subs, ", "))
+}
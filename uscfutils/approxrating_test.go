@@ -0,0 +1,93 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestSubstituteUnratedOpponents(t *testing.T) {
+	opponentIDs := []uschess.MemberID{"111", "222", "333"}
+	// 222 and 333 are unrated; 222 reported a rating on their entry
+	fetched := []int{1600, 0, 0}
+
+	// strict by default
+	if _, _, err := substituteUnratedOpponents(opponentIDs, fetched,
+		UnratedOpponentRatings{}); err == nil {
+
+		t.Errorf("substituteUnratedOpponents() err = nil with unrated opponents")
+	}
+
+	subs := UnratedOpponentRatings{
+		Reported: map[uschess.MemberID]int{"222": 1450},
+		Default:  1200,
+	}
+	ratings, substituted, err := substituteUnratedOpponents(opponentIDs,
+		fetched, subs)
+	if err != nil {
+		t.Fatalf("substituteUnratedOpponents() err = %v", err)
+	}
+	if want := []float64{1600, 1450, 1200}; !reflect.DeepEqual(ratings, want) {
+		t.Errorf("ratings = %v; want %v", ratings, want)
+	}
+	if want := map[uschess.MemberID]int{"222": 1450,
+		"333": 1200}; !reflect.DeepEqual(substituted, want) {
+
+		t.Errorf("substituted = %v; want %v", substituted, want)
+	}
+
+	// a substituted opponent counts exactly as a rated one would
+	got := ratingEstimate(1500, 100, 2, ratings)
+	if want := ratingEstimate(1500, 100, 2, []float64{1600, 1450,
+		1200}); got != want {
+
+		t.Errorf("ratingEstimate() = %.2f; want %.2f", got, want)
+	}
+
+	output := BuildApproxRatingEstimateOutput(&ApproxRatingEstimate{
+		PostRating: 1534, Substituted: substituted})
+	for _, want := range []string{"~1534 (approximate)",
+		"222 at 1450, 333 at 1200."} {
+
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%v", want, output)
+		}
+	}
+	if output := BuildApproxRatingEstimateOutput(&ApproxRatingEstimate{
+		PostRating: 1534}); output != "Estimated New Rating: 1534\n" {
+
+		t.Errorf("BuildApproxRatingEstimateOutput(exact) = %q", output)
+	}
+}
+
+func TestRatingEstimate(t *testing.T) {
+	tests := []struct {
+		name      string
+		rating    float64
+		games     int
+		score     float64
+		opponents []float64
+		want      float64
+	}{
+		{"no games keeps rating", 1500, 100, 0, nil, 1500},
+		// N' = 16.57, K = 800/17.57
+		{"established win vs peer", 1500, 100, 1, []float64{1500}, 1522.77},
+		// 4 * (0.5 + (R-1000)/800) + (0.5 + (R-1200)/800) = 1 + 4/2
+		{"provisional beats stronger player", 1000, 4, 1,
+			[]float64{1200}, 1120},
+	}
+	for _, tc := range tests {
+		got := ratingEstimate(tc.rating, tc.games, tc.score, tc.opponents)
+		if math.Abs(got-tc.want) > 0.01 {
+			t.Errorf("%v: ratingEstimate() = %.2f; want %.2f", tc.name, got,
+				tc.want)
+		}
+	}
+}
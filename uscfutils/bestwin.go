@@ -0,0 +1,69 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// BestWin is a player's win over their highest rated opponent.
+type BestWin struct {
+	Opponent string `json:"opponent"`
+	// OpponentRating is the opponent's pre-event Regular rating.
+	OpponentRating int             `json:"opponentRating"`
+	EventID        uschess.EventID `json:"eventId"`
+	EventName      string          `json:"eventName"`
+	Date           time.Time       `json:"date"`
+}
+
+// findBestWin returns memberID's win over the highest rated opponent in the
+// sections of tournaments rated under one of ratingTypes, or nil when they
+// beat no rated opponent. Only games won over the board count; wins by
+// forfeit and wins over unrated opponents are skipped. tournaments are
+// ordered most recent first, so the most recent of equally rated wins is
+// chosen.
+func findBestWin(tournaments []*uschess.Tournament, memberID uschess.MemberID,
+	ratingTypes []uschess.RatingType) *BestWin {
+
+	var best *BestWin
+	for _, tournament := range tournaments {
+		for _, standings := range tournament.SectionStandings {
+			if _, rated := sectionRatedAs(standings, ratingTypes); !rated {
+				continue
+			}
+			for _, opp := range OpponentsOf(standings, memberID) {
+				switch opp.Outcome {
+				case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym:
+				default:
+					continue
+				}
+				if opp.PreRating <= 0 ||
+					(best != nil && int(opp.PreRating) <= best.OpponentRating) {
+					continue
+				}
+				best = &BestWin{
+					Opponent:       opp.Name,
+					OpponentRating: int(opp.PreRating),
+					EventID:        tournament.Id,
+					EventName:      tournament.Name,
+					Date:           tournament.EndDate.Time,
+				}
+			}
+		}
+	}
+
+	return best
+}
+
+// buildBestWinOutput formats a best win for the player report header, e.g.
+// "Best recent win: beat Alice Smith (2100) on 2025-06-24 in Summer Open".
+func buildBestWinOutput(w *BestWin) string {
+	return fmt.Sprintf("Best recent win: beat %s (%d) on %s in %s\n",
+		w.Opponent, w.OpponentRating, w.Date.Format("2006-01-02"),
+		w.EventName)
+}
@@ -0,0 +1,90 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+func TestFindBestWin(t *testing.T) {
+	entry := func(ordinal int32, first string, last string, rating int32,
+		outcomes ...uschess.StandingsRound) uschess.Standings {
+
+		return uschess.Standings{Ordinal: ordinal, FirstName: first,
+			LastName: last, MemberId: uschess.MemberID(first),
+			Ratings: []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+				PreRating: rating}},
+			RoundOutcomes: outcomes}
+	}
+	round := func(outcome uschess.PlayerOutcome,
+		opp int32) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome, OpponentOrdinal: opp}
+	}
+	tournament := func(id string, name string, day int,
+		standings uschess.StandingsOneSection) *uschess.Tournament {
+
+		tourney := &uschess.Tournament{
+			SectionStandings: []uschess.StandingsOneSection{standings},
+		}
+		tourney.Id = uschess.EventID(id)
+		tourney.Name = name
+		tourney.EndDate = openapi_types.Date{Time: time.Date(2025, time.June,
+			day, 0, 0, 0, 0, time.UTC)}
+		return tourney
+	}
+
+	// most recent first: Zed beat Smith (2100) over the board on the 24th;
+	// the forfeit win over a 2400 doesn't count and the earlier win over a
+	// 2050 is lower rated
+	tournaments := []*uschess.Tournament{
+		tournament("2", "Summer Open", 24, uschess.StandingsOneSection{
+			entry(1, "Zed", "Zane", 1800,
+				round(uschess.PlayerOutcomeWin, 2),
+				round(uschess.PlayerOutcomeWinForfeit, 3),
+				round(uschess.PlayerOutcomeLoss, 4)),
+			entry(2, "Alice", "Smith", 2100),
+			entry(3, "Bob", "Strong", 2400),
+			entry(4, "Carol", "Best", 2300),
+		}),
+		tournament("1", "June Swiss", 10, uschess.StandingsOneSection{
+			entry(1, "Dan", "Other", 2050),
+			entry(2, "Zed", "Zane", 1780,
+				round(uschess.PlayerOutcomeWin, 1),
+				round(uschess.PlayerOutcomeWin, 3)),
+			entry(3, "Eve", "Unrated", 0),
+		}),
+	}
+
+	best := findBestWin(tournaments, "Zed",
+		[]uschess.RatingType{uschess.RatingTypeR})
+	if best == nil {
+		t.Fatalf("findBestWin() = nil")
+	}
+	if best.Opponent != "Alice Smith" || best.OpponentRating != 2100 ||
+		best.EventID != "2" {
+		t.Errorf("findBestWin() = %+v", best)
+	}
+	if got, want := buildBestWinOutput(best),
+		"Best recent win: beat Alice Smith (2100) on 2025-06-24 in Summer Open\n"; got != want {
+		t.Errorf("buildBestWinOutput() = %q; want %q", got, want)
+	}
+
+	// a player without wins has no best win and no header line
+	if best := findBestWin(tournaments, "Carol",
+		[]uschess.RatingType{uschess.RatingTypeR}); best != nil {
+		t.Errorf("findBestWin(no wins) = %+v", best)
+	}
+	report := &PlayerReport{Name: "Carol Best", MemberID: "Carol"}
+	if output := BuildPlayerReportOutput(report, false); strings.Contains(
+		output, "Best recent win") {
+		t.Errorf("output has a best win without one:\n%s", output)
+	}
+}
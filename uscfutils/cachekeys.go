@@ -0,0 +1,56 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// recordingTransport records the URL of every request it forwards.
+type recordingTransport struct {
+	mu   sync.Mutex
+	seen map[string]bool
+	urls []string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if url := req.URL.String(); !t.seen[url] {
+		t.seen[url] = true
+		t.urls = append(t.urls, url)
+	}
+	t.mu.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// TournamentCacheKeys returns the http cache keys of every US Chess API page
+// fetched when retrieving the crosstables for eventID. The pages are fetched
+// directly from the origin (bypassing the cache) in order to discover them.
+func TournamentCacheKeys(ctx context.Context,
+	eventID uschess.EventID) ([]string, error) {
+
+	rt := &recordingTransport{seen: make(map[string]bool)}
+	client, err := uschess.NewDefaultClient(
+		uschess.WithHTTPClient(&http.Client{Transport: rt,
+			Timeout: internal.GetConfig().HTTPTimeout}),
+		uschess.WithUserAgent(internal.UserAgent),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := client.GetTournament(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.urls, nil
+}
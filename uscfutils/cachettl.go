@@ -0,0 +1,104 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+const (
+	clientCacheTTL = 24 * time.Hour
+	// crosstables of just completed events may still be getting filed, so
+	// the affiliate event list is refreshed more often shortly afterwards
+	affiliateEventsSettlingTTL    = time.Hour
+	affiliateEventsSettlingPeriod = 3 * 24 * time.Hour
+	// once every player's post-event rating is present a crosstable no
+	// longer changes
+	finalStandingsTTL = 365 * 24 * time.Hour
+)
+
+var (
+	affiliateEventsPathRe = regexp.MustCompile(`^/api/v1/affiliates/[^/]+/events$`)
+	standingsPathRe       = regexp.MustCompile(`^/api/v1/rated-events/[^/]+/sections/[^/]+/standings$`)
+)
+
+// clientResponseTTL determines how long a US Chess API response may be
+// cached.
+func clientResponseTTL(resp *http.Response) time.Duration {
+	if resp.StatusCode != http.StatusOK || resp.Request == nil {
+		return clientCacheTTL
+	}
+	path := resp.Request.URL.Path
+	if !affiliateEventsPathRe.MatchString(path) &&
+		!standingsPathRe.MatchString(path) {
+
+		return clientCacheTTL
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return clientCacheTTL
+	}
+
+	if standingsPathRe.MatchString(path) {
+		var page uschess.StandingsPage
+		if err := json.Unmarshal(body, &page); err != nil {
+			return clientCacheTTL
+		}
+		return standingsTTL(page.Items)
+	}
+
+	var page uschess.RatedEventPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return clientCacheTTL
+	}
+
+	return affiliateEventsTTL(page.Items, internal.Now())
+}
+
+// standingsTTL returns how long a page of a section's standings may be
+// cached. Standings in which every player has a post-event rating are
+// final and are cached effectively indefinitely; those of an event still
+// being rated are cached as usual.
+func standingsTTL(standings []uschess.Standings) time.Duration {
+	if len(standings) == 0 {
+		return clientCacheTTL
+	}
+	for _, s := range standings {
+		if !slices.ContainsFunc(s.Ratings, func(r uschess.RatingRecord) bool {
+			return r.PostRating > 0
+		}) {
+			return clientCacheTTL
+		}
+	}
+
+	return finalStandingsTTL
+}
+
+// affiliateEventsTTL returns how long a list of an affiliate's events may be
+// cached. Lists whose most recent event ended within the last few days are
+// cached briefly so that newly filed events appear promptly.
+func affiliateEventsTTL(events []uschess.RatedEvent, now time.Time) time.Duration {
+	for _, ev := range events {
+		if !ev.EndDate.Time.IsZero() &&
+			now.Sub(ev.EndDate.Time) < affiliateEventsSettlingPeriod {
+
+			return affiliateEventsSettlingTTL
+		}
+	}
+
+	return clientCacheTTL
+}
@@ -0,0 +1,100 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestAffiliateEventsTTL(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		events []uschess.RatedEvent
+		want   time.Duration
+	}{
+		{"no events", nil, clientCacheTTL},
+		{"ended yesterday", []uschess.RatedEvent{testRatedEvent("1", 9)},
+			affiliateEventsSettlingTTL},
+		{"ended two days ago", []uschess.RatedEvent{testRatedEvent("1", 8)},
+			affiliateEventsSettlingTTL},
+		{"ended a week ago", []uschess.RatedEvent{testRatedEvent("1", 3)},
+			clientCacheTTL},
+		{"recent event not first", []uschess.RatedEvent{
+			testRatedEvent("1", 1), testRatedEvent("2", 9)},
+			affiliateEventsSettlingTTL},
+	}
+	for _, tt := range tests {
+		if got := affiliateEventsTTL(tt.events, now); got != tt.want {
+			t.Errorf("%v: affiliateEventsTTL() = %v; want %v", tt.name, got,
+				tt.want)
+		}
+	}
+}
+
+func TestClientResponseTTL(t *testing.T) {
+	recent := time.Now().Format("2006-01-02")
+	body := `{"items":[{"id":"1","endDate":"` + recent + `"}]}`
+
+	newResp := func(path string) *http.Response {
+		rec := httptest.NewRecorder()
+		rec.WriteString(body)
+		resp := rec.Result()
+		resp.Request = httptest.NewRequest("GET", "https://ratings-api.uschess.org"+path, nil)
+		return resp
+	}
+
+	resp := newResp("/api/v1/affiliates/A5000408/events")
+	if got := clientResponseTTL(resp); got != affiliateEventsSettlingTTL {
+		t.Errorf("affiliate events TTL = %v; want %v", got,
+			affiliateEventsSettlingTTL)
+	}
+	remaining, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(remaining), recent) {
+		t.Errorf("response body was not preserved: %q", remaining)
+	}
+
+	if got := clientResponseTTL(newResp("/api/v1/members/12345678")); got != clientCacheTTL {
+		t.Errorf("member TTL = %v; want %v", got, clientCacheTTL)
+	}
+}
+
+func TestStandingsTTL(t *testing.T) {
+	rated := uschess.Standings{Ratings: []uschess.RatingRecord{
+		{RatingType: "R", PreRating: 1500, PostRating: 1512}}}
+	pending := uschess.Standings{Ratings: []uschess.RatingRecord{
+		{RatingType: "R", PreRating: 1600}}}
+
+	tests := []struct {
+		name      string
+		standings []uschess.Standings
+		want      time.Duration
+	}{
+		{"no standings", nil, clientCacheTTL},
+		{"finalized", []uschess.Standings{rated, rated}, finalStandingsTTL},
+		{"being rated", []uschess.Standings{rated, pending}, clientCacheTTL},
+	}
+	for _, tt := range tests {
+		if got := standingsTTL(tt.standings); got != tt.want {
+			t.Errorf("%v: standingsTTL() = %v; want %v", tt.name, got, tt.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	rec.WriteString(`{"items":[{"memberId":"12345678","ratings":[{"ratingSystem":"R","preRating":1500,"postRating":1512}]}]}`)
+	resp := rec.Result()
+	resp.Request = httptest.NewRequest("GET",
+		"https://ratings-api.uschess.org/api/v1/rated-events/202601131234/sections/1/standings", nil)
+	if got := clientResponseTTL(resp); got != finalStandingsTTL {
+		t.Errorf("final standings TTL = %v; want %v", got, finalStandingsTTL)
+	}
+}
@@ -0,0 +1,74 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	DefaultClubHistoryDays = 365
+	// bounds how far back GetAffiliatePlayers may look since each event's
+	// crosstables must be fetched
+	MaxClubHistoryDays = 730
+)
+
+// GetAffiliatePlayers returns the members who appear in the crosstables of
+// an affiliate's rated events which ended on or after since. Crosstables
+// are fetched concurrently and cached by the client, so repeated lookups
+// over the same window are cheap.
+func (c *Client) GetAffiliatePlayers(ctx context.Context,
+	affiliateID uschess.AffiliateID,
+	since time.Time) (map[uschess.MemberID]bool, error) {
+
+	events, err := GetAffiliateEventsSince(ctx, c.ClientWithResponses,
+		affiliateID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return getAffiliatePlayersWithLookup(ctx, events, c.GetCrossTables)
+}
+
+func getAffiliatePlayersWithLookup(ctx context.Context,
+	events []uschess.RatedEvent,
+	lookup tournamentLookup) (map[uschess.MemberID]bool, error) {
+
+	var mu sync.Mutex
+	players := make(map[uschess.MemberID]bool)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(internal.GetConfig().CrossTablesConcurrency)
+	for _, event := range events {
+		group.Go(func() error {
+			tournament, err := lookup(groupCtx, event.Id)
+			if err != nil {
+				return fmt.Errorf("fetching crosstables for event %s: %w",
+					event.Id, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, standings := range tournament.SectionStandings {
+				for _, entry := range standings {
+					if entry.MemberId != "" {
+						players[entry.MemberId] = true
+					}
+				}
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
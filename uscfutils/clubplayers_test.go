@@ -0,0 +1,52 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestGetAffiliatePlayers(t *testing.T) {
+	events := []uschess.RatedEvent{testRatedEvent("100", 1),
+		testRatedEvent("200", 2)}
+	members := map[uschess.EventID][]uschess.MemberID{
+		"100": {"1", "2"},
+		"200": {"2", "3", ""},
+	}
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		var standings uschess.StandingsOneSection
+		for _, id := range members[eventID] {
+			standings = append(standings, uschess.Standings{MemberId: id})
+		}
+		return &uschess.Tournament{
+			SectionStandings: []uschess.StandingsOneSection{standings},
+		}, nil
+	}
+
+	players, err := getAffiliatePlayersWithLookup(context.Background(), events,
+		lookup)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(players) != 3 || !players["1"] || !players["2"] || !players["3"] {
+		t.Errorf("players = %v; want 1, 2, and 3", players)
+	}
+
+	failing := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		return nil, errors.New("not found")
+	}
+	if _, err := getAffiliatePlayersWithLookup(context.Background(), events,
+		failing); err == nil {
+		t.Errorf("expected an error when crosstables cannot be fetched")
+	}
+}
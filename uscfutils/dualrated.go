@@ -0,0 +1,91 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// dualSecondaryTypes are the rating systems, in order of preference, which a
+// Regular-rated section may also be rated under.
+var dualSecondaryTypes = []uschess.RatingType{
+	uschess.RatingTypeQ,
+	uschess.RatingTypeB,
+}
+
+// sectionDualRating reports whether a section is dual-rated, i.e. its games
+// were rated under the Regular system as well as a secondary system, and if
+// so which secondary system.
+func sectionDualRating(standings uschess.StandingsOneSection) (uschess.RatingType,
+	bool) {
+
+	found := make(map[uschess.RatingType]bool)
+	for _, entry := range standings {
+		for _, rating := range entry.Ratings {
+			found[rating.RatingType] = true
+		}
+	}
+	if !found[uschess.RatingTypeR] {
+		return "", false
+	}
+	for _, secondary := range dualSecondaryTypes {
+		if found[secondary] {
+			return secondary, true
+		}
+	}
+
+	return "", false
+}
+
+// typedRating returns the formatted pre- and post-event ratings of the given
+// rating system, or empty strings when the player was not rated under it.
+func typedRating(ratings []uschess.RatingRecord,
+	ratingType uschess.RatingType) (string, string) {
+
+	for _, rating := range ratings {
+		if rating.RatingType == ratingType {
+			return formatRating(rating.PreRating, 0),
+				formatRating(rating.PostRating, rating.PostProvisionalGameCount)
+		}
+	}
+
+	return "", ""
+}
+
+// ratingTypeDual is the combined Regular/Quick rating system. Some members'
+// ratings are reported only under it, in which case it stands in for their
+// Regular rating.
+const ratingTypeDual = uschess.RatingType(uschess.SectionRatingTypeD)
+
+// regularIndex returns the index of the Regular rating among n ratings whose
+// types are given by typeAt. A dual rating is used when there is no pure
+// Regular rating. -1 is returned when there is neither.
+func regularIndex(n int, typeAt func(int) uschess.RatingType) int {
+	dual := -1
+	for i := 0; i < n; i++ {
+		switch typeAt(i) {
+		case uschess.RatingTypeR:
+			return i
+		case ratingTypeDual:
+			if dual < 0 {
+				dual = i
+			}
+		}
+	}
+
+	return dual
+}
+
+func regularRecordIndex(ratings []uschess.RatingRecord) int {
+	return regularIndex(len(ratings), func(i int) uschess.RatingType {
+		return ratings[i].RatingType
+	})
+}
+
+func regularSystemIndex(ratings []uschess.RatingSupplementSystem) int {
+	return regularIndex(len(ratings), func(i int) uschess.RatingType {
+		return ratings[i].RatingType
+	})
+}
@@ -0,0 +1,125 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestBuildCrossTableOutputDualRated(t *testing.T) {
+	// modeled on a G/30 d5 rapid, which US Chess rates under both the
+	// Regular and Quick systems
+	section := uschess.MinimalSection{Name: "Open"}
+	standings := uschess.StandingsOneSection{
+		{
+			Ordinal:   1,
+			FirstName: "Alice",
+			LastName:  "Player",
+			MemberId:  "1",
+			Score:     1,
+			Ratings: []uschess.RatingRecord{
+				{RatingType: uschess.RatingTypeR, PreRating: 1500,
+					PostRating: 1516},
+				{RatingType: uschess.RatingTypeQ, PreRating: 1450,
+					PostRating: 1470},
+			},
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeWin, OpponentOrdinal: 2,
+					Color: "White"},
+			},
+		},
+		{
+			Ordinal:   2,
+			FirstName: "Bob",
+			LastName:  "Player",
+			MemberId:  "2",
+			Score:     0,
+			Ratings: []uschess.RatingRecord{
+				{RatingType: uschess.RatingTypeR, PreRating: 1400,
+					PostRating: 1384},
+			},
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeLoss, OpponentOrdinal: 1,
+					Color: "Black"},
+			},
+		},
+	}
+
+	secondary, dual := sectionDualRating(standings)
+	if !dual || secondary != uschess.RatingTypeQ {
+		t.Fatalf("sectionDualRating() = %v, %v; want Q, true", secondary, dual)
+	}
+
+	output, _ := BuildCrossTableOutput(section, standings,
+		CrossTableOptions{})
+	for _, want := range []string{
+		"Open Section (Dual R/Q)",
+		"Quick Rating",
+		"1500->1516",
+		"1450->1470",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	// Bob has no Quick rating, which is left blank
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "Bob Player") &&
+			strings.Count(line, "->") != 1 {
+
+			t.Errorf("Bob's row has %v rating changes; want 1: %q",
+				strings.Count(line, "->"), line)
+		}
+	}
+
+	// a Regular-only section is unchanged
+	for i := range standings {
+		standings[i].Ratings = standings[i].Ratings[:1]
+	}
+	if _, dual := sectionDualRating(standings); dual {
+		t.Errorf("Regular-only section reported as dual-rated")
+	}
+	output, _ = BuildCrossTableOutput(section, standings,
+		CrossTableOptions{})
+	if strings.Contains(output, "Section") || strings.Contains(output,
+		"Quick Rating") {
+		t.Errorf("unexpected dual labeling:\n%s", output)
+	}
+}
+
+func TestDualRatingStandsInForRegular(t *testing.T) {
+	// a member whose only rating is under the dual Regular/Quick system
+	player := &uschess.Player{
+		RatingSupplements: []uschess.RatingSupplement{{
+			Ratings: []uschess.RatingSupplementSystem{
+				{RatingType: ratingTypeDual, Rating: 1432},
+			},
+		}},
+	}
+	if rating, _ := playerRegularSupplement(player); rating != "1432" {
+		t.Errorf("playerRegularSupplement() = %q, want 1432", rating)
+	}
+
+	records := []uschess.RatingRecord{
+		{RatingType: uschess.RatingTypeQ, PreRating: 1400, PostRating: 1410},
+		{RatingType: ratingTypeDual, PreRating: 1432, PostRating: 1440},
+	}
+	if pre, post := regularRating(records); pre != "1432" || post != "1440" {
+		t.Errorf("regularRating() = %q, %q, want 1432, 1440", pre, post)
+	}
+	if !sectionIsRegular(uschess.StandingsOneSection{{Ratings: records[1:]}}) {
+		t.Errorf("sectionIsRegular() = false for a dual-rated section")
+	}
+
+	// a pure Regular rating is preferred over a dual one
+	records = append(records, uschess.RatingRecord{
+		RatingType: uschess.RatingTypeR, PreRating: 1500, PostRating: 1510})
+	if pre, _ := regularRating(records); pre != "1500" {
+		t.Errorf("regularRating() = %q, want 1500", pre)
+	}
+}
@@ -0,0 +1,82 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// ForfeitPolicy decides whether games decided by forfeit count as games in a
+// player's stats: their win/draw/loss record, average opposition and
+// performance rating.
+type ForfeitPolicy int
+
+const (
+	// ExcludeForfeits leaves forfeits out of a player's games, per US Chess
+	// convention for performance ratings; the record tallies them
+	// separately. It is the default.
+	ExcludeForfeits ForfeitPolicy = iota
+	// IncludeForfeits counts a forfeit as a game won, drawn or lost against
+	// the scheduled opponent.
+	IncludeForfeits
+)
+
+// isForfeit reports whether a round's outcome was decided by forfeit.
+func isForfeit(outcome uschess.PlayerOutcome) bool {
+	switch outcome {
+	case uschess.PlayerOutcomeWinForfeit, uschess.PlayerOutcomeDrawForfeit,
+		uschess.PlayerOutcomeForfeit:
+		return true
+	}
+
+	return false
+}
+
+// gameScore returns the points a player earned from a round's outcome and
+// whether the round counts as a game under p. Byes never count.
+func (p ForfeitPolicy) gameScore(outcome uschess.PlayerOutcome) (float64,
+	bool) {
+
+	switch outcome {
+	case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym:
+		return 1, true
+	case uschess.PlayerOutcomeDraw, uschess.PlayerOutcomeDrawAsym:
+		return 0.5, true
+	case uschess.PlayerOutcomeLoss, uschess.PlayerOutcomeLossAsym:
+		return 0, true
+	case uschess.PlayerOutcomeWinForfeit:
+		return 1, p == IncludeForfeits
+	case uschess.PlayerOutcomeDrawForfeit:
+		return 0.5, p == IncludeForfeits
+	case uschess.PlayerOutcomeForfeit:
+		return 0, p == IncludeForfeits
+	}
+
+	return 0, false
+}
+
+// PerformanceRating returns memberID's performance rating in a section: the
+// average pre-event rating of their rated opponents, adjusted by 400 points
+// per game won and less 400 per game lost. Games decided by forfeit count
+// per policy. The 2nd return value is false when memberID played no rated
+// opponent.
+func PerformanceRating(standings uschess.StandingsOneSection,
+	memberID uschess.MemberID, policy ForfeitPolicy) (int, bool) {
+
+	total, rated := 0, 0
+	for _, opp := range opponentsOf(standings, memberID, policy) {
+		if opp.PreRating <= 0 {
+			continue
+		}
+		score, _ := policy.gameScore(opp.Outcome)
+		total += int(opp.PreRating) + int(800*score) - 400
+		rated++
+	}
+	if rated == 0 {
+		return 0, false
+	}
+
+	return (total + rated/2) / rated, true
+}
@@ -0,0 +1,67 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestForfeitPolicy(t *testing.T) {
+	rated := func(rating int32) []uschess.RatingRecord {
+		return []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+			PreRating: rating}}
+	}
+	// Alice beat a 1600 and drew a 1800 over the board, and won by forfeit
+	// against a 2400 who didn't show
+	standings := uschess.StandingsOneSection{
+		{Ordinal: 1, MemberId: "1", Ratings: rated(1700),
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeWin, OpponentOrdinal: 2},
+				{Outcome: uschess.PlayerOutcomeDraw, OpponentOrdinal: 3},
+				{Outcome: uschess.PlayerOutcomeWinForfeit, OpponentOrdinal: 4},
+				{Outcome: uschess.PlayerOutcomeByeFull},
+			}},
+		{Ordinal: 2, MemberId: "2", Ratings: rated(1600)},
+		{Ordinal: 3, MemberId: "3", Ratings: rated(1800)},
+		{Ordinal: 4, MemberId: "4", Ratings: rated(2400)},
+	}
+
+	// by default the forfeit is not a game: (2000 + 1800) / 2
+	if got, ok := PerformanceRating(standings, "1", ExcludeForfeits); !ok ||
+		got != 1900 {
+		t.Errorf("PerformanceRating(exclude) = %v, %v; want 1900", got, ok)
+	}
+	if avg, rated, games := averageOpposition(standings, "1",
+		ExcludeForfeits); avg != 1700 || rated != 2 || games != 2 {
+		t.Errorf("averageOpposition(exclude) = %v, %v, %v", avg, rated, games)
+	}
+	var record Record
+	record.addSection(standings, "1", ExcludeForfeits)
+	if want := (Record{Wins: 1, Draws: 1, Byes: 1, Forfeits: 1}); record !=
+		want {
+		t.Errorf("record(exclude) = %+v; want %+v", record, want)
+	}
+
+	// counted, the forfeit is a win over the 2400: (2000 + 1800 + 2800) / 3
+	if got, ok := PerformanceRating(standings, "1", IncludeForfeits); !ok ||
+		got != 2200 {
+		t.Errorf("PerformanceRating(include) = %v, %v; want 2200", got, ok)
+	}
+	if avg, rated, games := averageOpposition(standings, "1",
+		IncludeForfeits); avg != 1933 || rated != 3 || games != 3 {
+		t.Errorf("averageOpposition(include) = %v, %v, %v", avg, rated, games)
+	}
+	record = Record{}
+	record.addSection(standings, "1", IncludeForfeits)
+	if want := (Record{Wins: 2, Draws: 1, Byes: 1}); record != want {
+		t.Errorf("record(include) = %+v; want %+v", record, want)
+	}
+
+	if _, ok := PerformanceRating(standings, "2", ExcludeForfeits); ok {
+		t.Errorf("PerformanceRating() without games succeeded")
+	}
+}
@@ -0,0 +1,148 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+const DefaultGainersCount = 5
+
+// RatingChange is a player's rating change over a rated event.
+type RatingChange struct {
+	Name       string
+	MemberID   uschess.MemberID
+	Section    string
+	PreRating  int32
+	PostRating int32
+}
+
+// Delta returns the change from the player's pre- to post-event rating.
+func (rc RatingChange) Delta() int32 {
+	return rc.PostRating - rc.PreRating
+}
+
+// RatingChanges returns the rating change of each player of every section of
+// a rated event, greatest gain first. Each player's Regular rating is used
+// when they have one, else their first listed rating. Players without a
+// pre-event rating (i.e. newly rated players) or without a post-event rating
+// (e.g. because the event is not yet rated) are excluded; the latter are
+// counted in notYetRated.
+func RatingChanges(t *uschess.Tournament) (changes []RatingChange,
+	notYetRated int) {
+
+	for _, i := range SectionOrder(t) {
+		for _, entry := range t.SectionStandings[i] {
+			if len(entry.Ratings) == 0 {
+				continue
+			}
+			rc := entryRatingChange(entry, t.Sections[i].Name)
+			if rc.PostRating <= 0 {
+				notYetRated++
+				continue
+			}
+			if rc.PreRating <= 0 {
+				continue
+			}
+			changes = append(changes, rc)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Delta() > changes[j].Delta()
+	})
+
+	return changes, notYetRated
+}
+
+// RatingChangesByMember returns the rating change of each player of a rated
+// event who has a post-event rating, keyed by member id. Unlike
+// RatingChanges newly rated players are included, with a PreRating of 0.
+func RatingChangesByMember(t *uschess.Tournament) map[uschess.MemberID]RatingChange {
+	changes := make(map[uschess.MemberID]RatingChange)
+	for _, i := range SectionOrder(t) {
+		for _, entry := range t.SectionStandings[i] {
+			if len(entry.Ratings) == 0 || entry.MemberId == "" {
+				continue
+			}
+			if rc := entryRatingChange(entry, t.Sections[i].Name); rc.PostRating > 0 {
+				changes[entry.MemberId] = rc
+			}
+		}
+	}
+
+	return changes
+}
+
+// entryRatingChange returns the change in a crosstable entry's Regular
+// rating when they have one, else their first listed rating. The entry must
+// list at least one rating.
+func entryRatingChange(entry uschess.Standings, section string) RatingChange {
+	rating := entry.Ratings[0]
+	if idx := regularRecordIndex(entry.Ratings); idx >= 0 {
+		rating = entry.Ratings[idx]
+	}
+
+	return RatingChange{
+		Name:       internal.NormalizeName(entry.FirstName + " " + entry.LastName),
+		MemberID:   entry.MemberId,
+		Section:    section,
+		PreRating:  rating.PreRating,
+		PostRating: rating.PostRating,
+	}
+}
+
+// BuildGainersOutput formats the count biggest rating gainers and losers of
+// an event as returned by RatingChanges.
+func BuildGainersOutput(changes []RatingChange, notYetRated int,
+	count int) string {
+
+	var sb strings.Builder
+	var gainers, losers []RatingChange
+	for _, rc := range changes {
+		if rc.Delta() > 0 && len(gainers) < count {
+			gainers = append(gainers, rc)
+		}
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		if rc := changes[i]; rc.Delta() < 0 && len(losers) < count {
+			losers = append(losers, rc)
+		}
+	}
+
+	if len(gainers) == 0 && len(losers) == 0 {
+		sb.WriteString("No rating changes found.\n")
+	}
+	writeRatingChanges(&sb, "Biggest Gainers", gainers)
+	writeRatingChanges(&sb, "Biggest Losers", losers)
+	if notYetRated > 0 {
+		sb.WriteString(fmt.Sprintf("%d player(s) without a post-event rating are not included; the event may not be rated yet.\n",
+			notYetRated))
+	}
+
+	return sb.String()
+}
+
+func writeRatingChanges(sb *strings.Builder, title string,
+	changes []RatingChange) {
+
+	if len(changes) == 0 {
+		return
+	}
+	maxN := 0
+	for _, rc := range changes {
+		maxN = max(maxN, internal.DisplayWidth(rc.Name))
+	}
+	sb.WriteString(fmt.Sprintf("%s:\n", title))
+	for _, rc := range changes {
+		sb.WriteString(fmt.Sprintf("  %+5d  %-*s  %d->%d (%s)\n", rc.Delta(),
+			maxN, rc.Name, rc.PreRating, rc.PostRating, rc.Section))
+	}
+	sb.WriteString("\n")
+}
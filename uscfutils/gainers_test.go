@@ -0,0 +1,58 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestRatingChanges(t *testing.T) {
+	standing := func(first string, pre, post int32) uschess.Standings {
+		return uschess.Standings{FirstName: first, LastName: "PLAYER",
+			Ratings: []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+				PreRating: pre, PostRating: post}}}
+	}
+	tourney := &uschess.Tournament{
+		RatedEventDetail: uschess.RatedEventDetail{
+			Sections: []uschess.MinimalSection{
+				{Name: "Open", Number: 1}, {Name: "U1600", Number: 2},
+			},
+		},
+		SectionStandings: []uschess.StandingsOneSection{
+			{standing("Alice", 1800, 1812), standing("Bob", 1900, 1870),
+				standing("Carol", 1700, 1700)},
+			{standing("Dan", 1400, 1445), standing("Eve", 0, 1200),
+				standing("Fay", 1500, 0)},
+		},
+	}
+
+	changes, notYetRated := RatingChanges(tourney)
+	if notYetRated != 1 || len(changes) != 4 || changes[0].Name != "Dan Player" ||
+		changes[0].Delta() != 45 || changes[3].Name != "Bob Player" {
+		t.Fatalf("RatingChanges() = %+v, %v", changes, notYetRated)
+	}
+
+	output := BuildGainersOutput(changes, notYetRated, 5)
+	for _, want := range []string{
+		"Biggest Gainers:\n    +45  Dan Player    1400->1445 (U1600)\n    +12  Alice Player  1800->1812 (Open)\n\n",
+		"Biggest Losers:\n    -30  Bob Player  1900->1870 (Open)\n",
+		"1 player(s) without a post-event rating",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Carol") || strings.Contains(output, "Eve") {
+		t.Errorf("output includes unchanged or newly rated players:\n%s",
+			output)
+	}
+	if output := BuildGainersOutput(changes, 0, 1); strings.Contains(output,
+		"Alice") {
+		t.Errorf("output exceeds count:\n%s", output)
+	}
+}
@@ -0,0 +1,76 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestBuildCrossTableOutputLastRounds(t *testing.T) {
+	// a 9 round event between two players meeting every round
+	standings := make(uschess.StandingsOneSection, 0, 2)
+	for ordinal := int32(1); ordinal <= 2; ordinal++ {
+		entry := uschess.Standings{
+			Ordinal:   ordinal,
+			FirstName: fmt.Sprintf("Player%d", ordinal),
+			LastName:  "Test",
+			MemberId:  uschess.MemberID(fmt.Sprintf("%d", ordinal)),
+		}
+		for round := 1; round <= 9; round++ {
+			outcome := uschess.PlayerOutcomeDraw
+			if round == 9 {
+				outcome = uschess.PlayerOutcomeByeHalf
+			}
+			entry.RoundOutcomes = append(entry.RoundOutcomes,
+				uschess.StandingsRound{Outcome: outcome,
+					OpponentOrdinal: 3 - ordinal, Color: "White"})
+		}
+		standings = append(standings, entry)
+	}
+
+	output, _ := BuildCrossTableOutput(uschess.MinimalSection{Name: "Open"},
+		standings, CrossTableOptions{FilterPlayerID: "2", LastRounds: 3})
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("unexpected output:\n%s", output)
+	}
+	header := []rune(lines[0])
+	if strings.Contains(lines[0], "R6") || !strings.Contains(lines[0], "R7") ||
+		!strings.Contains(lines[0], "R9") || !strings.Contains(lines[0], "…") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(output, "**Player2 Test**") {
+		t.Errorf("filtered player not highlighted:\n%s", output)
+	}
+
+	// every column starts at the same position on every line
+	for _, col := range []string{"…", "R7", "R8", "R9"} {
+		pos := strings.Index(string(header), col)
+		runePos := len([]rune(lines[0][:pos]))
+		for _, line := range lines[1:] {
+			runes := []rune(line)
+			if runePos >= len(runes) || runes[runePos] == ' ' ||
+				runes[runePos-1] != ' ' {
+				t.Errorf("column %v misaligned in %q", col, line)
+			}
+		}
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasSuffix(strings.TrimRight(line, " "), "BYE(½)") {
+			t.Errorf("last round missing from %q", line)
+		}
+	}
+
+	// all rounds by default
+	output, _ = BuildCrossTableOutput(uschess.MinimalSection{Name: "Open"},
+		standings, CrossTableOptions{})
+	if !strings.Contains(output, "R1") || strings.Contains(output, "…") {
+		t.Errorf("unexpected full output:\n%s", output)
+	}
+}
@@ -0,0 +1,76 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// Opponent is a player faced over the board in one round of a section.
+type Opponent struct {
+	Round   int
+	Ordinal int32
+	// MemberID and Name are empty when the opponent's pair number is not
+	// listed in the section.
+	MemberID uschess.MemberID
+	Name     string
+	Outcome  uschess.PlayerOutcome
+	Color    uschess.ChessColor
+	// PreRating is the opponent's pre-event Regular rating, or 0 when they
+	// were unrated.
+	PreRating int32
+}
+
+// OpponentsOf returns the opponents memberID played in a section in round
+// order. Byes and games decided by forfeit are excluded. nil is returned
+// when memberID did not play in the section.
+func OpponentsOf(standings uschess.StandingsOneSection,
+	memberID uschess.MemberID) []Opponent {
+
+	return opponentsOf(standings, memberID, ExcludeForfeits)
+}
+
+// opponentsOf is OpponentsOf including the scheduled opponents of games
+// decided by forfeit when policy counts them.
+func opponentsOf(standings uschess.StandingsOneSection,
+	memberID uschess.MemberID, policy ForfeitPolicy) []Opponent {
+
+	byOrdinal := make(map[int32]uschess.Standings)
+	for _, entry := range standings {
+		byOrdinal[entry.Ordinal] = entry
+	}
+
+	for _, entry := range standings {
+		if entry.MemberId != memberID {
+			continue
+		}
+		opponents := make([]Opponent, 0, len(entry.RoundOutcomes))
+		for index, outcome := range entry.RoundOutcomes {
+			if outcome.OpponentOrdinal <= 0 {
+				continue
+			}
+			if policy == ExcludeForfeits && isForfeit(outcome.Outcome) {
+				continue
+			}
+			opponent := Opponent{
+				Round:   index + 1,
+				Ordinal: outcome.OpponentOrdinal,
+				Outcome: outcome.Outcome,
+				Color:   outcome.Color,
+			}
+			if opp, ok := byOrdinal[outcome.OpponentOrdinal]; ok {
+				opponent.MemberID = opp.MemberId
+				opponent.Name = internal.NormalizeName(opp.FirstName + " " +
+					opp.LastName)
+				opponent.PreRating = regularPreRating(opp.Ratings)
+			}
+			opponents = append(opponents, opponent)
+		}
+		return opponents
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestOpponentsOf(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(xtblFixture))
+	if err != nil {
+		t.Fatalf("NewDocumentFromReader() err = %v", err)
+	}
+	tourney, err := parseCrossTablesPage(doc, "202601131234")
+	if err != nil {
+		t.Fatalf("parseCrossTablesPage() err = %v", err)
+	}
+	open := tourney.SectionStandings[0]
+
+	// Bob won round 1 by forfeit, which is excluded
+	want := []Opponent{
+		{Round: 2, Ordinal: 1, MemberID: "12345678", Name: "Alice Smith",
+			Outcome: uschess.PlayerOutcomeDraw, Color: uschess.ChessColorWhite,
+			PreRating: 1800},
+		{Round: 3, Ordinal: 3, MemberID: "34567890", Name: "Carol White",
+			Outcome: uschess.PlayerOutcomeLoss, Color: uschess.ChessColorBlack},
+	}
+	if got := OpponentsOf(open, "23456789"); !reflect.DeepEqual(got, want) {
+		t.Errorf("OpponentsOf() = %+v; want %+v", got, want)
+	}
+
+	if got := OpponentsOf(tourney.SectionStandings[1], "45678901"); len(got) != 0 || got == nil {
+		t.Errorf("OpponentsOf() with only byes = %+v; want empty", got)
+	}
+	if got := OpponentsOf(open, "45678901"); got != nil {
+		t.Errorf("OpponentsOf() for another section's player = %+v; want nil",
+			got)
+	}
+}
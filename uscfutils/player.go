@@ -0,0 +1,116 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// GetPlayer retrieves a member's profile as uschess.ClientWithResponses'
+// GetPlayer does, except that concurrent requests for the same member with
+// the same options share a single fetch, and a failure to retrieve the
+// member's rated events is not fatal: the player is returned without events
+// so that their ratings can still be shown. When the profile lacks a name
+// it is taken from the member's MSA profile page instead. Callers must not
+// modify the returned Player since it may be shared.
+func (c *Client) GetPlayer(ctx context.Context, memberID uschess.MemberID,
+	opts *uschess.GetPlayerOptions) (*uschess.Player, error) {
+
+	key := playerFlightKey(memberID, opts)
+	player, err := shareFlight(ctx, &c.playerFlight, key,
+		func(ctx context.Context) (any, error) {
+			return c.getPlayer(ctx, memberID, opts)
+		})
+	if err != nil {
+		return nil, classifyAPIError(err)
+	}
+
+	return player.(*uschess.Player), nil
+}
+
+func (c *Client) getPlayer(ctx context.Context, memberID uschess.MemberID,
+	opts *uschess.GetPlayerOptions) (*uschess.Player, error) {
+
+	player, err := c.getPlayerProfile(ctx, memberID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(player.FirstName+player.LastName) == "" {
+		first, last, err := getPlayerNameViaWeb(ctx, memberID)
+		if err != nil {
+			log.Printf("uscfutils: player %v: no name available: %v", memberID,
+				err)
+		} else {
+			player.FirstName, player.LastName = first, last
+		}
+	}
+
+	return player, nil
+}
+
+func (c *Client) getPlayerProfile(ctx context.Context,
+	memberID uschess.MemberID,
+	opts *uschess.GetPlayerOptions) (*uschess.Player, error) {
+
+	if opts == nil {
+		defaultOpts := uschess.DefaultGetPlayerOptions()
+		opts = &defaultOpts
+	}
+	if !opts.IncludeEvents {
+		return c.ClientWithResponses.GetPlayer(ctx, memberID, opts)
+	}
+
+	// fetch the events separately so that their failure does not fail the
+	// profile
+	var events []uschess.RatedEvent
+	var eventsErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		events, eventsErr = c.GetAllMemberEvents(ctx, memberID)
+	}()
+	profileOpts := *opts
+	profileOpts.IncludeEvents = false
+	player, err := c.ClientWithResponses.GetPlayer(ctx, memberID, &profileOpts)
+	<-done
+	if err != nil {
+		return nil, err
+	}
+	if eventsErr != nil {
+		log.Printf("uscfutils: player %v: continuing without rated events: %v",
+			memberID, eventsErr)
+	} else {
+		player.MemberEvents = events
+	}
+
+	return player, nil
+}
+
+// playerFlightKey identifies a GetPlayer request by member and options. nil
+// options, which select the client's defaults, are distinct from zero
+// options.
+func playerFlightKey(memberID uschess.MemberID,
+	opts *uschess.GetPlayerOptions) string {
+
+	if opts == nil {
+		return string(memberID)
+	}
+	date := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	return fmt.Sprintf("%v/%t/%t/%t/%v/%v", memberID, opts.IncludeSupplements,
+		opts.IncludeEvents, opts.IncludeLiveRatings,
+		date(opts.RecentGamesOnOrAfter), date(opts.RecentSectionsOnOrAfter))
+}
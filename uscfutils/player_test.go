@@ -0,0 +1,190 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestGetPlayerSharesConcurrentFetches(t *testing.T) {
+	var requests atomic.Int32
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		if requests.Add(1) == 1 {
+			received <- struct{}{}
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"12345678","firstName":"Alice","lastName":"Smith",
+			"ratings":[{"ratingSystem":"R","rating":1500}]}`)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+	opts := &uschess.GetPlayerOptions{}
+
+	var wg sync.WaitGroup
+	players := make([]*uschess.Player, 2)
+	errs := make([]error, 2)
+	fetch := func(i int) {
+		defer wg.Done()
+		players[i], errs[i] = client.GetPlayer(context.Background(),
+			"12345678", opts)
+	}
+	wg.Add(2)
+	go fetch(0)
+	// hold the first fetch in flight until the second request joins it
+	<-received
+	go fetch(1)
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := range players {
+		if errs[i] != nil || players[i] == nil ||
+			players[i].LastName != "Smith" {
+			t.Fatalf("GetPlayer() #%d = %+v, %v", i, players[i], errs[i])
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("concurrent GetPlayer() calls made %d requests; want 1", n)
+	}
+
+	// later requests are not coalesced with completed ones
+	if _, err := client.GetPlayer(context.Background(), "12345678",
+		opts); err != nil || requests.Load() != 2 {
+		t.Errorf("GetPlayer() err = %v after %d requests", err, requests.Load())
+	}
+}
+
+func TestGetPlayerWithoutEvents(t *testing.T) {
+	eventsStatus := http.StatusInternalServerError
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			w.WriteHeader(eventsStatus)
+			if eventsStatus == http.StatusOK {
+				fmt.Fprint(w, `{"items":[{"id":"202601131234","name":"BCC Tuesday Night Swiss"}]}`)
+			}
+			return
+		}
+		fmt.Fprint(w, `{"id":"12345678","firstName":"Alice","lastName":"Smith",
+			"ratings":[{"ratingSystem":"R","rating":1500}]}`)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+	opts := &uschess.GetPlayerOptions{IncludeEvents: true}
+
+	player, err := client.GetPlayer(context.Background(), "12345678", opts)
+	if err != nil || player.LastName != "Smith" || len(player.Ratings) != 1 ||
+		len(player.MemberEvents) != 0 {
+		t.Fatalf("GetPlayer() = %+v, %v; want ratings without events", player,
+			err)
+	}
+
+	eventsStatus = http.StatusOK
+	player, err = client.GetPlayer(context.Background(), "12345678", opts)
+	if err != nil || len(player.MemberEvents) != 1 ||
+		player.MemberEvents[0].Name != "BCC Tuesday Night Swiss" {
+		t.Fatalf("GetPlayer() = %+v, %v; want events", player, err)
+	}
+}
+
+func TestPlayerFlightKey(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sinceCopy := since
+	a := playerFlightKey("1", &uschess.GetPlayerOptions{
+		RecentGamesOnOrAfter: &since})
+	b := playerFlightKey("1", &uschess.GetPlayerOptions{
+		RecentGamesOnOrAfter: &sinceCopy})
+	if a != b {
+		t.Errorf("equal options have different keys %q and %q", a, b)
+	}
+	for _, other := range []string{
+		playerFlightKey("1", nil),
+		playerFlightKey("2", &uschess.GetPlayerOptions{
+			RecentGamesOnOrAfter: &since}),
+		playerFlightKey("1", &uschess.GetPlayerOptions{IncludeEvents: true,
+			RecentGamesOnOrAfter: &since}),
+	} {
+		if other == a {
+			t.Errorf("distinct requests share key %q", a)
+		}
+	}
+}
+
+func TestGetPlayerNameFromProfilePage(t *testing.T) {
+	profileID := "12345678"
+	web := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		if r.URL.RawQuery != profileID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `<html><body><table><tr><td>
+			<font size=+1><b>12345678: ALICE MAE SMITH</b></font>
+			</td></tr></table></body></html>`)
+	}))
+	defer web.Close()
+	origFmt := memberProfileURLFmt
+	memberProfileURLFmt = web.URL + "/?%v"
+	defer func() { memberProfileURLFmt = origFmt }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"12345678","firstName":"","lastName":"",
+			"ratings":[{"ratingSystem":"R","rating":1500}]}`)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+
+	player, err := client.GetPlayer(context.Background(), "12345678",
+		&uschess.GetPlayerOptions{})
+	if err != nil || player.FirstName != "ALICE MAE" ||
+		player.LastName != "SMITH" {
+		t.Fatalf("GetPlayer() = %+v, %v; want name from profile page", player,
+			err)
+	}
+
+	// an unavailable profile page leaves the player unnamed
+	profileID = "87654321"
+	player, err = client.GetPlayer(context.Background(), "12345678",
+		&uschess.GetPlayerOptions{})
+	if err != nil || player.FirstName != "" || player.LastName != "" {
+		t.Fatalf("GetPlayer() = %+v, %v; want no name", player, err)
+	}
+}
@@ -0,0 +1,501 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxPlayerReportEvents bounds how many of a player's most recent events are
+// fetched while looking for Regular-rated events to report.
+const maxPlayerReportEvents = 30
+
+// MaxPlayerReportEventCount caps how many events a player report
+// summarizes. Larger counts passed to GetPlayerReportData are clamped to
+// it, as are record counts larger than MaxRecordEventCount, so that no
+// caller can make a report fetch more than maxPlayerReportEvents
+// crosstables.
+const MaxPlayerReportEventCount = 5
+
+// playerReportTimeout bounds a whole player report, including every retry of
+// its many fetches, so that a degraded US Chess cannot stall a report beyond
+// what a Discord interaction will wait for. Crosstables not fetched by then
+// are left out and the report is marked partial.
+var playerReportTimeout = 20 * time.Second
+
+// PlayerReport is the structured form of a player report: the player's
+// ratings, record, best win, and their most recent Regular-rated events.
+type PlayerReport struct {
+	Name             string           `json:"name"`
+	MemberID         uschess.MemberID `json:"memberId"`
+	LiveRating       string           `json:"liveRating"`
+	SupplementRating string           `json:"supplementRating"`
+	SupplementDate   time.Time        `json:"supplementDate"`
+	RatedEvents      int              `json:"ratedEvents"`
+	Record           Record           `json:"record"`
+	// BestWin is nil when the player beat no rated opponent in the events
+	// fetched for the report.
+	BestWin *BestWin `json:"bestWin,omitempty"`
+	// EventCount is the number of recent events requested; Events may hold
+	// fewer.
+	EventCount int                 `json:"eventCount"`
+	Events     []PlayerReportEvent `json:"events"`
+	// Partial is set when the report's deadline passed before all of the
+	// player's events could be fetched.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// PlayerReportEvent summarizes a player's participation in one event.
+type PlayerReportEvent struct {
+	EventID  uschess.EventID       `json:"eventId"`
+	Name     string                `json:"name"`
+	EndDate  time.Time             `json:"endDate"`
+	Sections []PlayerReportSection `json:"sections"`
+}
+
+// PlayerReportSection summarizes a player's results in one section of an
+// event. AvgOpp is the average pre-event rating of the RatedOpponents of
+// the player's Games; it is 0 when none were rated. Performance is the
+// player's PerformanceRating, also 0 when no opponent was rated. Which games
+// count follows the report's ForfeitPolicy. White and Black count
+// the games the player played with each color. Rounds lists the rounds the
+// player took part in, which tells apart the sections of a player who
+// switched sections partway through an event.
+type PlayerReportSection struct {
+	Name           string   `json:"name"`
+	PreRating      string   `json:"preRating"`
+	PostRating     string   `json:"postRating"`
+	Score          float64  `json:"score"`
+	Results        []string `json:"results"`
+	AvgOpp         int      `json:"avgOpp"`
+	RatedOpponents int      `json:"ratedOpponents"`
+	Performance    int      `json:"performance,omitempty"`
+	Games          int      `json:"games"`
+	White          int      `json:"white"`
+	Black          int      `json:"black"`
+	Rounds         []int    `json:"rounds,omitempty"`
+	section        uschess.MinimalSection
+	standings      uschess.StandingsOneSection
+}
+
+// PlayerReportOptions tunes which of a player's events a report covers.
+type PlayerReportOptions struct {
+	// RatingTypes lists the rating systems whose sections are included,
+	// e.g. uschess.RatingTypeB to include blitz sections. Only
+	// Regular-rated sections are included when it is empty.
+	RatingTypes []uschess.RatingType
+	// Forfeits decides whether games decided by forfeit count towards the
+	// player's record, average opposition and performance ratings. They
+	// don't by default.
+	Forfeits ForfeitPolicy
+}
+
+// ratingTypes returns the rating systems o includes.
+func (o PlayerReportOptions) ratingTypes() []uschess.RatingType {
+	if len(o.RatingTypes) == 0 {
+		return []uschess.RatingType{uschess.RatingTypeR}
+	}
+	return o.RatingTypes
+}
+
+// GetPlayerReportData retrieves a player's current rating, their
+// win/draw/loss record over their most recent recordEventCount events, and
+// summaries of their most recent eventCount Regular-rated events. The report
+// is bounded by playerReportTimeout or ctx's deadline, whichever is sooner.
+func GetPlayerReportData(ctx context.Context,
+	client *Client, memberID uschess.MemberID,
+	eventCount int, recordEventCount int) (*PlayerReport, error) {
+
+	return GetPlayerReportDataWithOptions(ctx, client, memberID, eventCount,
+		recordEventCount, PlayerReportOptions{})
+}
+
+// GetPlayerReportDataWithOptions is GetPlayerReportData covering the
+// sections of the rating systems listed in opts rather than only
+// Regular-rated ones. Both the record and the event summaries count only
+// those sections.
+func GetPlayerReportDataWithOptions(ctx context.Context,
+	client *Client, memberID uschess.MemberID, eventCount int,
+	recordEventCount int, opts PlayerReportOptions) (*PlayerReport, error) {
+
+	ratingTypes := opts.ratingTypes()
+	eventCount = min(max(eventCount, 0), MaxPlayerReportEventCount)
+	recordEventCount = min(max(recordEventCount, 0), MaxRecordEventCount)
+	ctx, cancel := context.WithTimeout(ctx, playerReportTimeout)
+	defer cancel()
+
+	playerOpts := &uschess.GetPlayerOptions{
+		IncludeSupplements: true,
+		IncludeEvents:      true,
+		IncludeLiveRatings: true,
+	}
+	player, err := client.GetPlayer(ctx, memberID, playerOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	liveRating, err := playerRegularLiveRating(player)
+	if err != nil {
+		return nil, err
+	}
+	report := &PlayerReport{
+		Name:        internal.NormalizeName(player.FirstName + " " + player.LastName),
+		MemberID:    player.Id,
+		LiveRating:  liveRating,
+		RatedEvents: len(player.MemberEvents),
+		EventCount:  eventCount,
+	}
+	report.SupplementRating, report.SupplementDate = playerRegularSupplement(player)
+
+	tournaments, partial, err := fetchPlayerReportCrossTables(ctx,
+		player.MemberEvents, memberID, eventCount, recordEventCount,
+		ratingTypes, client.GetCrossTables)
+	if err != nil {
+		return nil, err
+	}
+	report.Partial = partial
+	report.BestWin = findBestWin(tournaments, memberID, ratingTypes)
+
+	for _, tournament := range tournaments {
+		if report.Record.Events >= recordEventCount {
+			break
+		}
+		counted := false
+		for _, standings := range tournament.SectionStandings {
+			_, rated := sectionRatedAs(standings, ratingTypes)
+			if !rated || !sectionContainsPlayer(standings, memberID) {
+				continue
+			}
+			report.Record.addSection(standings, memberID, opts.Forfeits)
+			counted = true
+		}
+		if counted {
+			report.Record.Events++
+		}
+	}
+
+	for _, tournament := range tournaments {
+		if len(report.Events) >= eventCount {
+			break
+		}
+		event := PlayerReportEvent{
+			EventID: tournament.Id,
+			Name:    tournament.Name,
+			EndDate: tournament.EndDate.Time,
+		}
+		for index, standings := range tournament.SectionStandings {
+			ratingType, rated := sectionRatedAs(standings, ratingTypes)
+			if !rated || !sectionContainsPlayer(standings, memberID) {
+				continue
+			}
+			event.Sections = append(event.Sections,
+				newPlayerReportSection(tournament.Sections[index], standings,
+					memberID, ratingType, opts.Forfeits))
+		}
+		if len(event.Sections) == 0 {
+			continue
+		}
+		if len(report.Events) == 0 {
+			// the most recent event's post-event rating supersedes the
+			// live rating
+			report.LiveRating = event.Sections[0].PostRating
+		}
+		report.Events = append(report.Events, event)
+	}
+
+	return report, nil
+}
+
+// fetchPlayerReportCrossTables retrieves the crosstables of a player's most
+// recent events, which must be ordered most recent first. At least
+// max(eventCount, recordEventCount) events are retrieved when available.
+// Events not rated under one of ratingTypes (e.g. blitz events when only
+// Regular is requested) are left out of the report, so the window keeps
+// widening until eventCount such events are found, the player's events run
+// out, or maxPlayerReportEvents is reached. When ctx's deadline passes the
+// crosstables fetched so far are returned along with true.
+func fetchPlayerReportCrossTables(ctx context.Context,
+	events []uschess.RatedEvent, memberID uschess.MemberID, eventCount int,
+	recordEventCount int, ratingTypes []uschess.RatingType,
+	lookup tournamentLookup) ([]*uschess.Tournament, bool, error) {
+
+	window := min(len(events), max(eventCount, recordEventCount),
+		maxPlayerReportEvents)
+	tournaments, err := fetchRecentPlayerCrossTables(ctx, events[:window],
+		lookup)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return tournaments, true, nil
+		}
+		return nil, false, err
+	}
+
+	limit := max(window, min(len(events), maxPlayerReportEvents))
+	for countRatedEvents(tournaments, memberID, ratingTypes) < eventCount &&
+		window < limit {
+
+		next := min(max(window*2, window+1), limit)
+		more, err := fetchRecentPlayerCrossTables(ctx, events[window:next],
+			lookup)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return append(tournaments, more...), true, nil
+			}
+			// older events may no longer be available from the US Chess
+			// API; report what was found rather than failing
+			break
+		}
+		tournaments = append(tournaments, more...)
+		window = next
+	}
+
+	return tournaments, false, nil
+}
+
+// fetchRecentPlayerCrossTables concurrently retrieves the crosstables of
+// events, preserving their order. On error the crosstables which were
+// retrieved are returned along with it.
+func fetchRecentPlayerCrossTables(ctx context.Context,
+	events []uschess.RatedEvent,
+	lookup tournamentLookup) ([]*uschess.Tournament, error) {
+
+	tournaments := make([]*uschess.Tournament, len(events))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(internal.GetConfig().CrossTablesConcurrency)
+	for index, event := range events {
+		group.Go(func() error {
+			tournament, err := lookup(groupCtx, event.Id)
+			if err != nil {
+				return fmt.Errorf("fetching crosstables for event %s: %w",
+					event.Id, err)
+			}
+			tournaments[index] = tournament
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		fetched := make([]*uschess.Tournament, 0, len(tournaments))
+		for _, tournament := range tournaments {
+			if tournament != nil {
+				fetched = append(fetched, tournament)
+			}
+		}
+		return fetched, err
+	}
+
+	return tournaments, nil
+}
+
+// countRatedEvents returns the number of tournaments in which memberID
+// played in at least one section rated under one of ratingTypes.
+func countRatedEvents(tournaments []*uschess.Tournament,
+	memberID uschess.MemberID, ratingTypes []uschess.RatingType) int {
+
+	count := 0
+	for _, tournament := range tournaments {
+		for _, standings := range tournament.SectionStandings {
+			_, rated := sectionRatedAs(standings, ratingTypes)
+			if rated && sectionContainsPlayer(standings, memberID) {
+				count++
+				break
+			}
+		}
+	}
+
+	return count
+}
+
+// sectionRatedAs returns the first of ratingTypes which a section is rated
+// under, and whether there was one.
+func sectionRatedAs(standings uschess.StandingsOneSection,
+	ratingTypes []uschess.RatingType) (uschess.RatingType, bool) {
+
+	for _, ratingType := range ratingTypes {
+		if ratingType == uschess.RatingTypeR {
+			if sectionIsRegular(standings) {
+				return ratingType, true
+			}
+			continue
+		}
+		for _, entry := range standings {
+			for _, rating := range entry.Ratings {
+				if rating.RatingType == ratingType {
+					return ratingType, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// newPlayerReportSection summarizes memberID's results in a section,
+// reporting their ratings under ratingType and counting forfeits per policy.
+func newPlayerReportSection(section uschess.MinimalSection,
+	standings uschess.StandingsOneSection, memberID uschess.MemberID,
+	ratingType uschess.RatingType, policy ForfeitPolicy) PlayerReportSection {
+
+	summary := PlayerReportSection{
+		Name:      section.Name,
+		section:   section,
+		standings: standings,
+	}
+	summary.AvgOpp, summary.RatedOpponents, summary.Games =
+		averageOpposition(standings, memberID, policy)
+	summary.Performance, _ = PerformanceRating(standings, memberID, policy)
+	for _, entry := range standings {
+		if entry.MemberId != memberID {
+			continue
+		}
+		if ratingType != uschess.RatingTypeR {
+			summary.PreRating, summary.PostRating = typedRating(entry.Ratings,
+				ratingType)
+		}
+		if summary.PreRating == "" && summary.PostRating == "" {
+			summary.PreRating, summary.PostRating = regularRating(entry.Ratings)
+		}
+		summary.Score = float64(entry.Score)
+		summary.White, summary.Black = countColors(entry.RoundOutcomes)
+		summary.Rounds = participatedRounds(entry.RoundOutcomes)
+		for _, outcome := range entry.RoundOutcomes {
+			cell, _ := formatOutcome(outcome)
+			summary.Results = append(summary.Results, cell)
+		}
+		break
+	}
+
+	return summary
+}
+
+// BuildPlayerReportOutput formats a player report with a crosstable of each
+// recent event section the player played in. With maskID only the last 4
+// digits of the player's USCF id are shown, for output which is shared
+// publicly.
+func BuildPlayerReportOutput(report *PlayerReport, maskID bool) string {
+	memberID := string(report.MemberID)
+	if maskID {
+		memberID = internal.MaskMemberID(memberID)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Player: %s\n", report.Name))
+	sb.WriteString(fmt.Sprintf("USCF ID: %s\n", memberID))
+	sb.WriteString(fmt.Sprintf("Rating:\n\tLive: %s\n", report.LiveRating))
+	sb.WriteString(fmt.Sprintf("\t%s Supplement: %s\n", report.SupplementDate.Format("Jan"), report.SupplementRating))
+	sb.WriteString(fmt.Sprintf("Rated Events: %d\n", report.RatedEvents))
+	if report.Record.Events > 0 {
+		sb.WriteString(buildRecordOutput(report.Record))
+	}
+	if report.BestWin != nil {
+		sb.WriteString(buildBestWinOutput(report.BestWin))
+	}
+	if len(report.Events) > 0 {
+		sb.WriteString(fmt.Sprintf("Most Recent(%d) Classical Events:\n\n", report.EventCount))
+	}
+	for _, event := range report.Events {
+		first := event.Sections[0]
+		sb.WriteString(fmt.Sprintf("%s - %s%s\n",
+			event.EndDate.Format("2006-01-02"), event.Name,
+			formatAvgOpp(first.AvgOpp, first.RatedOpponents, first.Games)))
+		if len(event.Sections) > 1 {
+			sb.WriteString(buildMultiSectionOutput(event.Sections))
+		}
+		for _, sec := range event.Sections {
+			output, _ := BuildCrossTableOutput(sec.section, sec.standings,
+				CrossTableOptions{IncludeSectionHeader: true,
+					FilterPlayerID: report.MemberID})
+			sb.WriteString(output)
+		}
+	}
+	if report.Partial {
+		sb.WriteString("Note: report is partial; US Chess did not respond in time for every event.\n")
+	}
+
+	return sb.String()
+}
+
+// participatedRounds returns the rounds, numbered from 1, in which a player
+// was paired or took a bye.
+func participatedRounds(outcomes []uschess.StandingsRound) []int {
+	var rounds []int
+	for index, outcome := range outcomes {
+		switch outcome.Outcome {
+		case "", uschess.PlayerOutcomeUnpaired:
+			continue
+		}
+		round := index + 1
+		if outcome.RoundNumber > 0 {
+			round = int(outcome.RoundNumber)
+		}
+		rounds = append(rounds, round)
+	}
+
+	return rounds
+}
+
+// buildMultiSectionOutput explains a player's participation in more than one
+// section of an event, e.g. "Played in 2 sections: Open rounds 1-2, U1800
+// rounds 3-4".
+func buildMultiSectionOutput(sections []PlayerReportSection) string {
+	parts := make([]string, 0, len(sections))
+	for _, sec := range sections {
+		part := sec.Name
+		if len(sec.Rounds) > 0 {
+			part += " " + formatRoundRanges(sec.Rounds)
+		}
+		parts = append(parts, part)
+	}
+
+	return fmt.Sprintf("Played in %d sections: %s\n", len(sections),
+		strings.Join(parts, ", "))
+}
+
+// formatRoundRanges formats ascending round numbers collapsing consecutive
+// rounds into ranges, e.g. "round 3" or "rounds 1-2 & 4".
+func formatRoundRanges(rounds []int) string {
+	if len(rounds) == 1 {
+		return fmt.Sprintf("round %d", rounds[0])
+	}
+	var ranges []string
+	for start := 0; start < len(rounds); {
+		end := start
+		for end+1 < len(rounds) && rounds[end+1] == rounds[end]+1 {
+			end++
+		}
+		if end == start {
+			ranges = append(ranges, fmt.Sprintf("%d", rounds[start]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", rounds[start],
+				rounds[end]))
+		}
+		start = end + 1
+	}
+
+	return "rounds " + strings.Join(ranges, " & ")
+}
+
+// BuildPlayerReport retrieves and formats a player's current rating and recent
+// Regular-rated event crosstables. The header also includes the player's
+// win/draw/loss record over their most recent recordEventCount events. With
+// maskID the player's USCF id is partially hidden.
+func BuildPlayerReport(ctx context.Context, client *Client,
+	memberID uschess.MemberID, eventCount int, recordEventCount int,
+	maskID bool) (string, error) {
+
+	report, err := GetPlayerReportData(ctx, client, memberID, eventCount,
+		recordEventCount)
+	if err != nil {
+		return "", err
+	}
+
+	return BuildPlayerReportOutput(report, maskID), nil
+}
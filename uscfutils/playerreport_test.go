@@ -0,0 +1,453 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestBuildPlayerReportOutput(t *testing.T) {
+	standings := uschess.StandingsOneSection{
+		{
+			Ordinal:   1,
+			FirstName: "Alice",
+			LastName:  "Smith",
+			MemberId:  "1",
+			Score:     1,
+			Ratings: []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+				PreRating: 1500, PostRating: 1516}},
+			RoundOutcomes: []uschess.StandingsRound{{Outcome: uschess.PlayerOutcomeWin,
+				OpponentOrdinal: 2, Color: "White"}},
+		},
+		{
+			Ordinal:   2,
+			FirstName: "Bob",
+			LastName:  "Jones",
+			MemberId:  "2",
+			Ratings: []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+				PreRating: 1600, PostRating: 1584}},
+			RoundOutcomes: []uschess.StandingsRound{{Outcome: uschess.PlayerOutcomeLoss,
+				OpponentOrdinal: 1, Color: "Black"}},
+		},
+	}
+	section := newPlayerReportSection(uschess.MinimalSection{Name: "Open"},
+		standings, "1", uschess.RatingTypeR, ExcludeForfeits)
+	if section.PreRating != "1500" || section.PostRating != "1516" ||
+		section.Score != 1 || section.AvgOpp != 1600 || section.Games != 1 ||
+		len(section.Results) != 1 {
+		t.Fatalf("unexpected section summary: %+v", section)
+	}
+
+	report := &PlayerReport{
+		Name:        "Alice Smith",
+		MemberID:    "1",
+		LiveRating:  section.PostRating,
+		RatedEvents: 12,
+		Record:      Record{Wins: 1, Events: 1},
+		EventCount:  3,
+		Events: []PlayerReportEvent{{
+			EventID:  "202601010001",
+			Name:     "New Year Open",
+			EndDate:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sections: []PlayerReportSection{section},
+		}},
+	}
+	output := BuildPlayerReportOutput(report, false)
+	for _, want := range []string{
+		"Player: Alice Smith\n",
+		"Live: 1516\n",
+		"Rated Events: 12\n",
+		"Most Recent(3) Classical Events:\n\n",
+		"2026-01-01 - New Year Open (Avg Opp: 1600)\n",
+		"**Alice Smith**",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	report.MemberID = "12345678"
+	if output := BuildPlayerReportOutput(report, true); !strings.Contains(
+		output, "USCF ID: ****5678\n") {
+		t.Errorf("output does not mask the USCF id:\n%s", output)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded PlayerReport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if decoded.Record.Wins != 1 || len(decoded.Events) != 1 ||
+		decoded.Events[0].Sections[0].AvgOpp != 1600 ||
+		decoded.Events[0].Sections[0].Results[0] != section.Results[0] {
+		t.Errorf("unexpected round trip: %s", data)
+	}
+}
+
+func TestBuildPlayerReportOutputSectionSwitch(t *testing.T) {
+	round := func(outcome uschess.PlayerOutcome,
+		opponent int32) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome,
+			OpponentOrdinal: opponent}
+	}
+	unpaired := uschess.StandingsRound{Outcome: uschess.PlayerOutcomeUnpaired}
+	// Alice played rounds 1-2 in the Open and rounds 3-4 in the U1800
+	open := uschess.StandingsOneSection{
+		{Ordinal: 1, FirstName: "Alice", LastName: "Smith", MemberId: "1",
+			RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeLoss, 2),
+				round(uschess.PlayerOutcomeLoss, 2), unpaired, unpaired}},
+		{Ordinal: 2, FirstName: "Bob", LastName: "Jones", MemberId: "2",
+			RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeWin, 1),
+				round(uschess.PlayerOutcomeWin, 1)}},
+	}
+	u1800 := uschess.StandingsOneSection{
+		{Ordinal: 1, FirstName: "Alice", LastName: "Smith", MemberId: "1",
+			RoundOutcomes: []uschess.StandingsRound{unpaired, unpaired,
+				round(uschess.PlayerOutcomeWin, 2),
+				round(uschess.PlayerOutcomeByeHalf, 0)}},
+		{Ordinal: 2, FirstName: "Carol", LastName: "White", MemberId: "3",
+			RoundOutcomes: []uschess.StandingsRound{unpaired, unpaired,
+				round(uschess.PlayerOutcomeLoss, 1)}},
+	}
+
+	report := &PlayerReport{
+		Name:       "Alice Smith",
+		MemberID:   "1",
+		EventCount: 1,
+		Events: []PlayerReportEvent{{
+			EventID: "202601010001",
+			Name:    "New Year Open",
+			EndDate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sections: []PlayerReportSection{
+				newPlayerReportSection(uschess.MinimalSection{Name: "Open"},
+					open, "1", uschess.RatingTypeR,
+					ExcludeForfeits),
+				newPlayerReportSection(uschess.MinimalSection{Name: "U1800"},
+					u1800, "1", uschess.RatingTypeR,
+					ExcludeForfeits),
+			},
+		}},
+	}
+	output := BuildPlayerReportOutput(report, false)
+	want := "2026-01-01 - New Year Open\n" +
+		"Played in 2 sections: Open rounds 1-2, U1800 rounds 3-4\n"
+	if !strings.Contains(output, want) {
+		t.Errorf("output missing %q:\n%s", want, output)
+	}
+
+	// a single section needs no explanation
+	report.Events[0].Sections = report.Events[0].Sections[:1]
+	if output := BuildPlayerReportOutput(report, false); strings.Contains(
+		output, "Played in") {
+
+		t.Errorf("single section output annotated:\n%s", output)
+	}
+
+	if got := formatRoundRanges([]int{1, 2, 4}); got != "rounds 1-2 & 4" {
+		t.Errorf("formatRoundRanges() = %q; want \"rounds 1-2 & 4\"", got)
+	}
+	if got := formatRoundRanges([]int{3}); got != "round 3" {
+		t.Errorf("formatRoundRanges() = %q; want \"round 3\"", got)
+	}
+}
+
+func TestFetchPlayerReportCrossTablesMostlyBlitz(t *testing.T) {
+	regularOnly := PlayerReportOptions{}.ratingTypes()
+
+	// 20 events, most recent first; only every fifth event is Regular-rated
+	var events []uschess.RatedEvent
+	tournaments := make(map[uschess.EventID]*uschess.Tournament)
+	for i := 0; i < 20; i++ {
+		id := uschess.EventID(fmt.Sprintf("2026%08d", 20-i))
+		events = append(events, uschess.RatedEvent{Id: id})
+		ratingType := uschess.RatingTypeB
+		if i%5 == 4 {
+			ratingType = uschess.RatingTypeR
+		}
+		tournament := &uschess.Tournament{
+			SectionStandings: []uschess.StandingsOneSection{{{
+				MemberId: "1",
+				Ratings:  []uschess.RatingRecord{{RatingType: ratingType}},
+			}}},
+		}
+		tournament.Id = id
+		tournaments[id] = tournament
+	}
+	var fetched atomic.Int32
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		fetched.Add(1)
+		return tournaments[eventID], nil
+	}
+
+	result, _, err := fetchPlayerReportCrossTables(context.Background(), events,
+		"1", 2, 1, regularOnly, lookup)
+	if err != nil {
+		t.Fatalf("fetchPlayerReportCrossTables() err = %v", err)
+	}
+	if got := countRatedEvents(result, "1", regularOnly); got < 2 {
+		t.Errorf("countRatedEvents() = %d, want at least 2", got)
+	}
+	if len(result) >= len(events) || int(fetched.Load()) != len(result) {
+		t.Errorf("fetched %d of %d events, returned %d", fetched.Load(),
+			len(events), len(result))
+	}
+	for i, tournament := range result {
+		if tournament.Id != events[i].Id {
+			t.Fatalf("result[%d] = %s, want %s", i, tournament.Id, events[i].Id)
+		}
+	}
+
+	// asking for more Regular events than the player has exhausts their events
+	result, _, err = fetchPlayerReportCrossTables(context.Background(), events,
+		"1", 5, 1, regularOnly, lookup)
+	if err != nil {
+		t.Fatalf("fetchPlayerReportCrossTables() err = %v", err)
+	}
+	if len(result) != len(events) || countRatedEvents(result, "1", regularOnly) != 4 {
+		t.Errorf("got %d events with %d regular, want %d with 4", len(result),
+			countRatedEvents(result, "1", regularOnly), len(events))
+	}
+
+	// older events failing to fetch end the search without an error
+	failing := func(ctx context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		if eventID == events[len(events)-1].Id {
+			return nil, errors.New("not found")
+		}
+		return lookup(ctx, eventID)
+	}
+	result, _, err = fetchPlayerReportCrossTables(context.Background(), events,
+		"1", 5, 1, regularOnly, failing)
+	if err != nil || countRatedEvents(result, "1", regularOnly) != 2 {
+		t.Errorf("got %d regular events, err = %v", countRatedEvents(result,
+			"1", regularOnly), err)
+	}
+}
+
+func TestFetchPlayerReportCrossTablesBlitzRequested(t *testing.T) {
+	// most recent first: blitz, regular, blitz, regular
+	var events []uschess.RatedEvent
+	tournaments := make(map[uschess.EventID]*uschess.Tournament)
+	for i := 0; i < 4; i++ {
+		id := uschess.EventID(fmt.Sprintf("2026%08d", 4-i))
+		events = append(events, uschess.RatedEvent{Id: id})
+		ratingType := uschess.RatingTypeB
+		if i%2 == 1 {
+			ratingType = uschess.RatingTypeR
+		}
+		tournament := &uschess.Tournament{
+			SectionStandings: []uschess.StandingsOneSection{{{
+				MemberId: "1",
+				Ratings: []uschess.RatingRecord{{RatingType: ratingType,
+					PreRating: 1400, PostRating: 1410}},
+			}}},
+		}
+		tournament.Id = id
+		tournaments[id] = tournament
+	}
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		return tournaments[eventID], nil
+	}
+
+	blitz := []uschess.RatingType{uschess.RatingTypeB}
+	result, _, err := fetchPlayerReportCrossTables(context.Background(), events,
+		"1", 2, 1, blitz, lookup)
+	if err != nil {
+		t.Fatalf("fetchPlayerReportCrossTables() err = %v", err)
+	}
+	if got := countRatedEvents(result, "1", blitz); got != 2 {
+		t.Errorf("countRatedEvents(blitz) = %d; want 2", got)
+	}
+	// only one of the two most recent events is blitz, so the window
+	// widens to find a second
+	if len(result) != len(events) {
+		t.Errorf("fetched %d events; want %d", len(result), len(events))
+	}
+
+	standings := tournaments[events[0].Id].SectionStandings[0]
+	ratingType, ok := sectionRatedAs(standings, blitz)
+	if !ok || ratingType != uschess.RatingTypeB {
+		t.Fatalf("sectionRatedAs() = %v, %v; want blitz", ratingType, ok)
+	}
+	if _, ok := sectionRatedAs(standings,
+		PlayerReportOptions{}.ratingTypes()); ok {
+
+		t.Errorf("blitz section treated as Regular-rated")
+	}
+	section := newPlayerReportSection(uschess.MinimalSection{Name: "Blitz"},
+		standings, "1", ratingType, ExcludeForfeits)
+	if section.PreRating != "1400" || section.PostRating != "1410" {
+		t.Errorf("blitz section ratings %v -> %v; want 1400 -> 1410",
+			section.PreRating, section.PostRating)
+	}
+}
+
+func TestFetchPlayerReportCrossTablesBounded(t *testing.T) {
+	// a long tournament history with no Regular-rated events, so nothing
+	// ends the search early
+	var events []uschess.RatedEvent
+	for i := 0; i < 200; i++ {
+		events = append(events, uschess.RatedEvent{
+			Id: uschess.EventID(fmt.Sprintf("2026%08d", 200-i)),
+		})
+	}
+	var fetched atomic.Int32
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		fetched.Add(1)
+		tournament := &uschess.Tournament{}
+		tournament.Id = eventID
+		return tournament, nil
+	}
+
+	_, _, err := fetchPlayerReportCrossTables(context.Background(), events,
+		"1", 1000, 1000, PlayerReportOptions{}.ratingTypes(), lookup)
+	if err != nil {
+		t.Fatalf("fetchPlayerReportCrossTables() err = %v", err)
+	}
+	if got := fetched.Load(); got != maxPlayerReportEvents {
+		t.Errorf("fetched %d crosstables; want %d", got, maxPlayerReportEvents)
+	}
+}
+
+func TestFetchRecentPlayerCrossTablesConcurrency(t *testing.T) {
+	const limit = 2
+	internal.SetenvForTest(t, internal.CrossTablesConcurrencyEnv, fmt.Sprintf("%v", limit))
+
+	var events []uschess.RatedEvent
+	for i := 0; i < 12; i++ {
+		events = append(events, uschess.RatedEvent{
+			Id: uschess.EventID(fmt.Sprintf("2026%08d", i)),
+		})
+	}
+	var active, peak atomic.Int32
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		tournament := &uschess.Tournament{}
+		tournament.Id = eventID
+		return tournament, nil
+	}
+
+	result, err := fetchRecentPlayerCrossTables(context.Background(), events,
+		lookup)
+	if err != nil {
+		t.Fatalf("fetchRecentPlayerCrossTables() err = %v", err)
+	}
+	if len(result) != len(events) {
+		t.Errorf("got %d crosstables; want %d", len(result), len(events))
+	}
+	if got := peak.Load(); got > limit {
+		t.Errorf("%d concurrent fetches; want at most %d", got, limit)
+	}
+
+	internal.SetenvForTest(t, internal.CrossTablesConcurrencyEnv, "bogus")
+	if got := internal.GetConfig().CrossTablesConcurrency; got !=
+		internal.DefaultCrossTablesConcurrency {
+
+		t.Errorf("CrossTablesConcurrency = %d; want %d", got,
+			internal.DefaultCrossTablesConcurrency)
+	}
+	internal.SetenvForTest(t, internal.CrossTablesConcurrencyEnv, "1000")
+	if got := internal.GetConfig().CrossTablesConcurrency; got !=
+		internal.MaxCrossTablesConcurrency {
+
+		t.Errorf("CrossTablesConcurrency = %d; want %d", got,
+			internal.MaxCrossTablesConcurrency)
+	}
+}
+
+func TestGetPlayerReportDataDeadline(t *testing.T) {
+	origTimeout := playerReportTimeout
+	playerReportTimeout = 200 * time.Millisecond
+	defer func() { playerReportTimeout = origTimeout }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			fmt.Fprint(w, `{"items":[
+				{"id":"202601131234","name":"BCC Tuesday Night Swiss"},
+				{"id":"202601061234","name":"BCC Tuesday Night Swiss"}]}`)
+		case strings.Contains(r.URL.Path, "12345678"):
+			fmt.Fprint(w, `{"id":"12345678","firstName":"Alice","lastName":"Smith",
+				"ratings":[{"ratingSystem":"R","rating":1500}]}`)
+		default:
+			// a degraded crosstable endpoint
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+
+	start := time.Now()
+	report, err := GetPlayerReportData(context.Background(), client,
+		"12345678", 1000, DefaultRecordEventCount)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetPlayerReportData() took %v; want about %v", elapsed,
+			playerReportTimeout)
+	}
+	if err != nil {
+		t.Fatalf("GetPlayerReportData() err = %v", err)
+	}
+	if !report.Partial || report.Name != "Alice Smith" ||
+		len(report.Events) != 0 {
+
+		t.Errorf("GetPlayerReportData() = %+v; want a partial report", report)
+	}
+	if report.EventCount != MaxPlayerReportEventCount {
+		t.Errorf("EventCount = %d; want it clamped to %d", report.EventCount,
+			MaxPlayerReportEventCount)
+	}
+	if output := BuildPlayerReportOutput(report, false); !strings.Contains(
+		output, "report is partial") {
+
+		t.Errorf("output missing partial note:\n%s", output)
+	}
+}
@@ -0,0 +1,76 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// memberProfileURLFmt is the US Chess MSA page of a member's profile.
+var memberProfileURLFmt = "https://www.uschess.org/msa/MbrDtlMain.php?%v"
+
+// "12345678: JOHN SMITH"
+var profileNameRe = regexp.MustCompile(`^\s*(\d+)\s*:\s*(.+?)\s*$`)
+
+// getPlayerNameViaWeb fetches a member's first and last name from their MSA
+// profile page, for members whose API profile lacks a name.
+func getPlayerNameViaWeb(ctx context.Context,
+	memberID uschess.MemberID) (string, string, error) {
+
+	url := fmt.Sprintf(memberProfileURLFmt, memberID)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("User-Agent", internal.UserAgent)
+	resp, err := internal.ScrapeClient().Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("status %w fetching %s",
+			internal.HTTPStatusError(resp.StatusCode), url)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+
+	return parsePlayerName(doc, memberID)
+}
+
+// parsePlayerName parses a member's name from the "<id>: <FIRST> <LAST>"
+// heading of their MSA profile page. Everything but the last word is taken
+// as the first name.
+func parsePlayerName(doc *goquery.Document,
+	memberID uschess.MemberID) (string, string, error) {
+
+	var first, last string
+	doc.Find("b").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		m := profileNameRe.FindStringSubmatch(s.Text())
+		if m == nil || m[1] != string(memberID) {
+			return true
+		}
+		name := strings.Fields(m[2])
+		first = strings.Join(name[:len(name)-1], " ")
+		last = name[len(name)-1]
+		return false
+	})
+	if last == "" {
+		return "", "", fmt.Errorf("no name found on the profile of %s",
+			memberID)
+	}
+
+	return first, last, nil
+}
@@ -0,0 +1,146 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+// MaxRecentEvents bounds the number of recent events whose winners may be
+// requested at once.
+const MaxRecentEvents = 20
+
+// SectionWinners lists the top scorers of a single section. Players tied for
+// first are all included.
+type SectionWinners struct {
+	Section string
+	Score   float32
+	Winners []string
+}
+
+// EventWinners lists the winners of each section of a rated event.
+type EventWinners struct {
+	Event    uschess.RatedEvent
+	Sections []SectionWinners
+}
+
+type tournamentLookup func(ctx context.Context,
+	eventID uschess.EventID) (*uschess.Tournament, error)
+
+// GetRecentEventWinners returns the section winners of an affiliate's count
+// most recent rated events, most recent first.
+func GetRecentEventWinners(ctx context.Context,
+	client *uschess.ClientWithResponses, affiliateID uschess.AffiliateID,
+	count int) ([]EventWinners, error) {
+
+	events, err := client.GetAllAffiliateRatedEvents(ctx, affiliateID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRecentEventWinnersWithLookup(ctx, events, count,
+		func(ctx context.Context,
+			eventID uschess.EventID) (*uschess.Tournament, error) {
+
+			return client.GetTournament(ctx, eventID)
+		})
+}
+
+func getRecentEventWinnersWithLookup(ctx context.Context,
+	events []uschess.RatedEvent, count int,
+	lookup tournamentLookup) ([]EventWinners, error) {
+
+	events = append([]uschess.RatedEvent(nil), events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EndDate.Time.After(events[j].EndDate.Time)
+	})
+	if len(events) > count {
+		events = events[:count]
+	}
+
+	results := make([]EventWinners, len(events))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(internal.GetConfig().CrossTablesConcurrency)
+	for index, event := range events {
+		group.Go(func() error {
+			tournament, err := lookup(groupCtx, event.Id)
+			if err != nil {
+				return fmt.Errorf("fetching crosstables for event %s: %w",
+					event.Id, err)
+			}
+			results[index] = EventWinners{
+				Event:    event,
+				Sections: sectionWinners(tournament),
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// sectionWinners returns the top scorers of each section of a tournament in
+// section order.
+func sectionWinners(t *uschess.Tournament) []SectionWinners {
+	winners := make([]SectionWinners, 0, len(t.SectionStandings))
+	for _, i := range SectionOrder(t) {
+		standings := t.SectionStandings[i]
+		if len(standings) == 0 {
+			continue
+		}
+		sw := SectionWinners{Section: t.Sections[i].Name}
+		for _, entry := range standings {
+			if entry.Score > sw.Score {
+				sw.Score = entry.Score
+			}
+		}
+		for _, entry := range standings {
+			if entry.Score == sw.Score {
+				sw.Winners = append(sw.Winners,
+					internal.NormalizeName(entry.FirstName+" "+entry.LastName))
+			}
+		}
+		winners = append(winners, sw)
+	}
+
+	return winners
+}
+
+// BuildRecentEventsOutput formats recent event winners as a digest suitable
+// for a club recap.
+func BuildRecentEventsOutput(events []EventWinners) string {
+	var sb strings.Builder
+	if len(events) == 0 {
+		sb.WriteString("No recent rated events found.\n")
+		return sb.String()
+	}
+
+	for _, ev := range events {
+		sb.WriteString(fmt.Sprintf("%s - %s\n",
+			ev.Event.EndDate.Time.Format("2006-01-02"), ev.Event.Name))
+		for _, sec := range ev.Sections {
+			label := "Winner"
+			if len(sec.Winners) > 1 {
+				label = "Co-winners"
+			}
+			sb.WriteString(fmt.Sprintf("  %s: %s %s (%s)\n", sec.Section, label,
+				strings.Join(sec.Winners, ", "),
+				internal.ScoreToString(float64(sec.Score))))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
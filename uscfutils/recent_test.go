@@ -0,0 +1,67 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestGetRecentEventWinners(t *testing.T) {
+	events := []uschess.RatedEvent{
+		testRatedEvent("100", 1), testRatedEvent("300", 3),
+		testRatedEvent("200", 2),
+	}
+	standing := func(first string, score float32) uschess.Standings {
+		return uschess.Standings{FirstName: first, LastName: "PLAYER",
+			Score: score}
+	}
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		if eventID == "100" {
+			t.Errorf("fetched event %v beyond the requested count", eventID)
+		}
+		return &uschess.Tournament{
+			RatedEventDetail: uschess.RatedEventDetail{
+				Sections: []uschess.MinimalSection{
+					{Name: "U1800", Number: 2},
+					{Name: "Open", Number: 1},
+				},
+			},
+			SectionStandings: []uschess.StandingsOneSection{
+				{standing("Carol", 3), standing("Dan", 3), standing("Eve", 2)},
+				{standing("Alice", 2.5), standing("Bob", 4)},
+			},
+		}, nil
+	}
+
+	results, err := getRecentEventWinnersWithLookup(context.Background(),
+		events, 2, lookup)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(results) != 2 || results[0].Event.Id != "300" ||
+		results[1].Event.Id != "200" {
+		t.Fatalf("results = %+v; want events 300 and 200", results)
+	}
+
+	output := BuildRecentEventsOutput(results)
+	for _, want := range []string{
+		"2026-03-03 - Event 300",
+		"Open: Winner Bob Player (4)",
+		"U1800: Co-winners Carol Player, Dan Player (3)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "Open:") > strings.Index(output, "U1800:") {
+		t.Errorf("Open section should be listed first:\n%s", output)
+	}
+}
@@ -0,0 +1,137 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"strings"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+const (
+	DefaultRecordEventCount = 10
+	MaxRecordEventCount     = 20
+)
+
+// Record tallies a player's results over a set of events. Wins, Draws, and
+// Losses count games actually played, plus forfeits when they were added
+// under IncludeForfeits; otherwise forfeits are counted separately, as are
+// byes. White and Black count the played games by the player's color.
+type Record struct {
+	Wins     int `json:"wins"`
+	Draws    int `json:"draws"`
+	Losses   int `json:"losses"`
+	Byes     int `json:"byes"`
+	Forfeits int `json:"forfeits"`
+	Events   int `json:"events"`
+	White    int `json:"white"`
+	Black    int `json:"black"`
+}
+
+// addSection adds memberID's results in a section to the record, counting
+// forfeits per policy.
+func (r *Record) addSection(standings uschess.StandingsOneSection,
+	memberID uschess.MemberID, policy ForfeitPolicy) {
+
+	for _, entry := range standings {
+		if entry.MemberId != memberID {
+			continue
+		}
+		for _, outcome := range entry.RoundOutcomes {
+			if policy == IncludeForfeits && isForfeit(outcome.Outcome) {
+				switch score, _ := policy.gameScore(outcome.Outcome); score {
+				case 1:
+					r.Wins++
+				case 0.5:
+					r.Draws++
+				default:
+					r.Losses++
+				}
+				continue
+			}
+			switch outcome.Outcome {
+			case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym:
+				r.Wins++
+			case uschess.PlayerOutcomeDraw, uschess.PlayerOutcomeDrawAsym:
+				r.Draws++
+			case uschess.PlayerOutcomeLoss, uschess.PlayerOutcomeLossAsym:
+				r.Losses++
+			case uschess.PlayerOutcomeWinForfeit, uschess.PlayerOutcomeForfeit:
+				r.Forfeits++
+			case uschess.PlayerOutcomeByeFull, uschess.PlayerOutcomeByeHalf,
+				uschess.PlayerOutcomeUnpaired:
+				r.Byes++
+			}
+		}
+		white, black := countColors(entry.RoundOutcomes)
+		r.White += white
+		r.Black += black
+		return
+	}
+}
+
+// countColors returns how many of the games played in outcomes were played
+// with white and with black. Byes and forfeits, which are not played, are
+// not counted.
+func countColors(outcomes []uschess.StandingsRound) (int, int) {
+	white, black := 0, 0
+	for _, outcome := range outcomes {
+		switch outcome.Outcome {
+		case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym,
+			uschess.PlayerOutcomeDraw, uschess.PlayerOutcomeDrawAsym,
+			uschess.PlayerOutcomeLoss, uschess.PlayerOutcomeLossAsym:
+		default:
+			continue
+		}
+		switch strings.ToLower(string(outcome.Color)) {
+		case "white":
+			white++
+		case "black":
+			black++
+		}
+	}
+
+	return white, black
+}
+
+// formatColors formats a color count, e.g. "8W / 7B".
+func formatColors(white int, black int) string {
+	return fmt.Sprintf("%dW / %dB", white, black)
+}
+
+// buildRecordOutput formats a record for the player report header, e.g.
+// "Recent record: 12-3-5 (W-D-L) over 10 events; 1 bye, 2 forfeits".
+func buildRecordOutput(r Record) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Recent record: %d-%d-%d (W-D-L) over %d %s",
+		r.Wins, r.Draws, r.Losses, r.Events, plural(r.Events, "event")))
+	extras := make([]string, 0, 2)
+	if r.Byes > 0 {
+		extras = append(extras, fmt.Sprintf("%d %s", r.Byes, plural(r.Byes,
+			"bye")))
+	}
+	if r.Forfeits > 0 {
+		extras = append(extras, fmt.Sprintf("%d %s", r.Forfeits,
+			plural(r.Forfeits, "forfeit")))
+	}
+	if len(extras) > 0 {
+		sb.WriteString("; " + strings.Join(extras, ", "))
+	}
+	sb.WriteString("\n")
+	if r.White+r.Black > 0 {
+		sb.WriteString(fmt.Sprintf("Colors: %s\n", formatColors(r.White,
+			r.Black)))
+	}
+
+	return sb.String()
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
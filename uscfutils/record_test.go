@@ -0,0 +1,93 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestRecordAddSection(t *testing.T) {
+	section := func(outcomes ...uschess.PlayerOutcome) uschess.StandingsOneSection {
+		rounds := make([]uschess.StandingsRound, 0, len(outcomes))
+		for _, outcome := range outcomes {
+			rounds = append(rounds, uschess.StandingsRound{Outcome: outcome})
+		}
+		return uschess.StandingsOneSection{
+			{Ordinal: 1, MemberId: "2", RoundOutcomes: rounds[:1]},
+			{Ordinal: 2, MemberId: "1", RoundOutcomes: rounds},
+		}
+	}
+
+	var record Record
+	record.addSection(section(uschess.PlayerOutcomeWin,
+		uschess.PlayerOutcomeDrawAsym, uschess.PlayerOutcomeLoss,
+		uschess.PlayerOutcomeByeHalf), "1", ExcludeForfeits)
+	record.Events++
+	record.addSection(section(uschess.PlayerOutcomeWinAsym,
+		uschess.PlayerOutcomeWinForfeit, uschess.PlayerOutcomeForfeit,
+		uschess.PlayerOutcomeLossAsym), "1", ExcludeForfeits)
+	record.Events++
+
+	want := Record{Wins: 2, Draws: 1, Losses: 2, Byes: 1, Forfeits: 2,
+		Events: 2}
+	if record != want {
+		t.Fatalf("record = %+v; want %+v", record, want)
+	}
+
+	got := buildRecordOutput(record)
+	wantOutput := "Recent record: 2-1-2 (W-D-L) over 2 events; 1 bye, 2 forfeits\n"
+	if got != wantOutput {
+		t.Fatalf("buildRecordOutput() = %q; want %q", got, wantOutput)
+	}
+
+	got = buildRecordOutput(Record{Wins: 1, Events: 1})
+	wantOutput = "Recent record: 1-0-0 (W-D-L) over 1 event\n"
+	if got != wantOutput {
+		t.Fatalf("buildRecordOutput() = %q; want %q", got, wantOutput)
+	}
+}
+
+func TestRecordColors(t *testing.T) {
+	played := func(outcome uschess.PlayerOutcome,
+		color uschess.ChessColor) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome, Color: color}
+	}
+	// W D L W, then a bye and a forfeit win which have no color played
+	rounds := []uschess.StandingsRound{
+		played(uschess.PlayerOutcomeWin, uschess.ChessColorWhite),
+		played(uschess.PlayerOutcomeDraw, uschess.ChessColorBlack),
+		played(uschess.PlayerOutcomeLoss, uschess.ChessColorWhite),
+		played(uschess.PlayerOutcomeWinAsym, "white"),
+		played(uschess.PlayerOutcomeByeFull, ""),
+		played(uschess.PlayerOutcomeWinForfeit, uschess.ChessColorBlack),
+	}
+	standings := uschess.StandingsOneSection{
+		{Ordinal: 1, MemberId: "1", RoundOutcomes: rounds},
+	}
+
+	var record Record
+	record.addSection(standings, "1", ExcludeForfeits)
+	record.addSection(standings, "1", ExcludeForfeits)
+	record.Events = 2
+	if record.White != 6 || record.Black != 2 {
+		t.Fatalf("colors = %dW / %dB; want 6W / 2B", record.White,
+			record.Black)
+	}
+	want := "Recent record: 4-2-2 (W-D-L) over 2 events; 2 byes, 2 forfeits\n" +
+		"Colors: 6W / 2B\n"
+	if got := buildRecordOutput(record); got != want {
+		t.Errorf("buildRecordOutput() = %q; want %q", got, want)
+	}
+
+	section := newPlayerReportSection(uschess.MinimalSection{Name: "Open"},
+		standings, "1", uschess.RatingTypeR, ExcludeForfeits)
+	if section.White != 3 || section.Black != 1 {
+		t.Errorf("section colors = %dW / %dB; want 3W / 1B", section.White,
+			section.Black)
+	}
+}
@@ -0,0 +1,160 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+const liveRatingConcurrency = 8
+
+// GetActiveMembers returns, sorted, the members who played in the club's
+// rated events which ended on or after since.
+func (c *Client) GetActiveMembers(ctx context.Context,
+	since time.Time) ([]uschess.MemberID, error) {
+
+	return getActiveMembersWithLookup(ctx, c.ClientWithResponses, since,
+		c.GetCrossTables)
+}
+
+func getActiveMembersWithLookup(ctx context.Context,
+	client *uschess.ClientWithResponses, since time.Time,
+	lookup tournamentLookup) ([]uschess.MemberID, error) {
+
+	events, err := GetAffiliateEventsSince(ctx, client,
+		uschess.AffiliateID(internal.BccUSCFAffiliateID), since)
+	if err != nil {
+		return nil, err
+	}
+	players, err := getAffiliatePlayersWithLookup(ctx, events, lookup)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]uschess.MemberID, 0, len(players))
+	for memberID := range players {
+		members = append(members, memberID)
+	}
+	slices.Sort(members)
+
+	return members, nil
+}
+
+// RegularLiveRatings returns the live Regular rating of each of the given
+// members. Unrated members are returned as 0.
+func RegularLiveRatings(ctx context.Context, client *uschess.ClientWithResponses,
+	memberIDs []uschess.MemberID) ([]int, error) {
+
+	opts := &uschess.GetPlayerOptions{
+		IncludeLiveRatings: true,
+	}
+	ratings := make([]int, len(memberIDs))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(liveRatingConcurrency)
+	for index, memberID := range memberIDs {
+		index, memberID := index, memberID
+		group.Go(func() error {
+			player, err := client.GetPlayer(groupCtx, memberID, opts)
+			if err != nil {
+				return fmt.Errorf("fetching player %s: %w", memberID, err)
+			}
+			rating, err := regularLiveRatingValue(player)
+			if err != nil {
+				return fmt.Errorf("fetching player %s: %w", memberID, err)
+			}
+			ratings[index] = rating
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+func regularLiveRatingValue(player *uschess.Player) (int, error) {
+	ratings, err := player.LiveRatings()
+	if err != nil {
+		return 0, err
+	}
+	if i := regularSystemIndex(ratings); i >= 0 {
+		return int(ratings[i].Rating), nil
+	}
+	return 0, nil
+}
+
+// OfficialRegularRating returns the Regular rating of a player fetched by
+// FetchRatingsOnly, or 0 when they are unrated.
+func OfficialRegularRating(player *uschess.Player) int {
+	ratings := player.Ratings
+	i := regularIndex(len(ratings), func(i int) uschess.RatingType {
+		return ratings[i].RatingType
+	})
+	if i < 0 {
+		return 0
+	}
+
+	return int(ratings[i].Rating)
+}
+
+// FetchRatingsOnly retrieves the name and official (monthly supplement)
+// ratings of each of the given members. Unlike GetPlayer it requests only
+// the member profile, skipping the events, supplements, and sections
+// endpoints, which makes it cheaper for callers that only need current
+// ratings. The returned Players have only MemberDetail populated; their live
+// ratings are unavailable. Members US Chess does not know of are left out
+// rather than failing the whole fetch.
+func (c *Client) FetchRatingsOnly(ctx context.Context,
+	memberIDs []uschess.MemberID) (map[uschess.MemberID]*uschess.Player, error) {
+
+	players := make(map[uschess.MemberID]*uschess.Player, len(memberIDs))
+	var mu sync.Mutex
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(liveRatingConcurrency)
+	for _, memberID := range slices.Compact(slices.Sorted(
+		slices.Values(memberIDs))) {
+
+		group.Go(func() error {
+			response, err := c.GetMemberWithResponse(groupCtx, memberID)
+			if err != nil {
+				return fmt.Errorf("fetching player %s: %w", memberID, err)
+			}
+			if response.StatusCode() == http.StatusNotFound {
+				return nil
+			}
+			if response.JSON200 == nil {
+				return internal.WithStatus(fmt.Errorf(
+					"fetching player %s: unexpected response status %d", memberID,
+					response.StatusCode()), response.StatusCode())
+			}
+			member := response.JSON200
+			player := &uschess.Player{MemberDetail: uschess.MemberDetail{
+				Id:             member.Id,
+				FirstName:      member.FirstName,
+				LastName:       member.LastName,
+				Ratings:        member.Ratings,
+				ExpirationDate: member.ExpirationDate,
+			}}
+			mu.Lock()
+			players[memberID] = player
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return players, nil
+}
@@ -0,0 +1,125 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestFetchRatingsOnly(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		id, ok := strings.CutPrefix(r.URL.Path, "/api/v1/members/")
+		if !ok || strings.Contains(id, "/") || id == "99999999" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":%q,"firstName":"Player","lastName":%q,
+			"expirationDate":"2027-01-31",
+			"ratings":[{"ratingSystem":"R","rating":1500},
+				{"ratingSystem":"Q","rating":1400}]}`, id, id)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+	players, err := client.FetchRatingsOnly(context.Background(),
+		[]uschess.MemberID{"12345678", "87654321", "12345678", "99999999"})
+	if err != nil {
+		t.Fatalf("FetchRatingsOnly() err = %v", err)
+	}
+
+	if len(players) != 2 {
+		t.Fatalf("FetchRatingsOnly() returned %d players; want 2",
+			len(players))
+	}
+	player := players["87654321"]
+	if player == nil || player.LastName != "87654321" ||
+		len(player.Ratings) != 2 || player.Ratings[0].Rating != 1500 ||
+		player.ExpirationDate.Format("2006-01-02") != "2027-01-31" {
+		t.Errorf("unexpected player: %+v", player)
+	}
+	if rating := OfficialRegularRating(player); rating != 1500 {
+		t.Errorf("OfficialRegularRating() = %v; want 1500", rating)
+	}
+	// one profile request per distinct member and nothing else
+	if len(paths) != 3 {
+		t.Errorf("requested %v; want one profile request per member", paths)
+	}
+	for _, path := range paths {
+		if strings.Count(path, "/") != 4 {
+			t.Errorf("unexpected request for %v", path)
+		}
+	}
+}
+
+func TestGetActiveMembers(t *testing.T) {
+	// the club's history: e3 ended before the lookback window
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		if r.URL.Path != "/api/v1/affiliates/A5000408/events" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[
+			{"id":"e1","startDate":"2026-03-14","endDate":"2026-03-14"},
+			{"id":"e2","startDate":"2026-03-07","endDate":"2026-03-07"},
+			{"id":"e3","startDate":"2026-02-07","endDate":"2026-02-07"}],
+		 "offset":0,"pageSize":3,"hasNextPage":false}`)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	members := map[uschess.EventID][]uschess.MemberID{
+		"e1": {"30", "10"},
+		"e2": {"20", "10"},
+		"e3": {"40"},
+	}
+	lookup := func(_ context.Context,
+		eventID uschess.EventID) (*uschess.Tournament, error) {
+
+		var standings uschess.StandingsOneSection
+		for _, id := range members[eventID] {
+			standings = append(standings, uschess.Standings{MemberId: id})
+		}
+		return &uschess.Tournament{
+			SectionStandings: []uschess.StandingsOneSection{standings},
+		}, nil
+	}
+
+	since := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	got, err := getActiveMembersWithLookup(context.Background(), api, since,
+		lookup)
+	if err != nil {
+		t.Fatalf("getActiveMembersWithLookup() err = %v", err)
+	}
+	if fmt.Sprint(got) != "[10 20 30]" {
+		t.Errorf("active members = %v; want [10 20 30]", got)
+	}
+}
@@ -0,0 +1,238 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// RoundGame is one pairing of a round as reconstructed from a crosstable.
+type RoundGame struct {
+	White uschess.Standings
+	// Black is nil for a bye, or a forfeit without a listed opponent.
+	Black *uschess.Standings
+	// Outcome is White's result.
+	Outcome uschess.PlayerOutcome
+	// ColorsKnown is false when the crosstable does not record colors, in
+	// which case White is simply the player with the lower pair number.
+	ColorsKnown bool
+}
+
+// RoundGames reconstructs the pairings of round from a section's standings.
+// Crosstables do not record board numbers, so games are ordered as boards
+// usually are: by the higher of the two players' scores going into the
+// round, then by pair number. Byes follow the games. Players who were not
+// paired in the round (e.g. after withdrawing) are left out.
+func RoundGames(standings uschess.StandingsOneSection, round int) []RoundGame {
+	if round <= 0 {
+		return nil
+	}
+
+	byOrdinal := make(map[int32]int, len(standings))
+	before := make(map[int32]float64, len(standings))
+	for idx, entry := range standings {
+		byOrdinal[entry.Ordinal] = idx
+		for _, outcome := range entry.RoundOutcomes[:min(round-1,
+			len(entry.RoundOutcomes))] {
+			before[entry.Ordinal] += outcomePoints(outcome.Outcome)
+		}
+	}
+
+	var games, byes []RoundGame
+	seen := make(map[int32]bool)
+	for _, entry := range standings {
+		if seen[entry.Ordinal] || len(entry.RoundOutcomes) < round {
+			continue
+		}
+		outcome := entry.RoundOutcomes[round-1]
+		oppIdx, ok := byOrdinal[outcome.OpponentOrdinal]
+		if outcome.OpponentOrdinal == 0 || !ok {
+			switch outcome.Outcome {
+			case uschess.PlayerOutcomeByeFull, uschess.PlayerOutcomeByeHalf,
+				uschess.PlayerOutcomeWinForfeit, uschess.PlayerOutcomeForfeit:
+				byes = append(byes, RoundGame{White: entry,
+					Outcome: outcome.Outcome})
+			}
+			continue
+		}
+
+		opp := standings[oppIdx]
+		seen[entry.Ordinal], seen[opp.Ordinal] = true, true
+		game := RoundGame{White: entry, Black: &opp, Outcome: outcome.Outcome,
+			ColorsKnown: true}
+		switch strings.ToLower(string(outcome.Color)) {
+		case "white":
+		case "black":
+			game = swapRoundGame(game, opp, entry, round)
+		default:
+			game.ColorsKnown = false
+			if opp.Ordinal < entry.Ordinal {
+				game = swapRoundGame(game, opp, entry, round)
+			}
+		}
+		games = append(games, game)
+	}
+
+	sort.SliceStable(games, func(i, j int) bool {
+		a, b := games[i], games[j]
+		aTop := max(before[a.White.Ordinal], before[a.Black.Ordinal])
+		bTop := max(before[b.White.Ordinal], before[b.Black.Ordinal])
+		if aTop != bTop {
+			return aTop > bTop
+		}
+		return min(a.White.Ordinal, a.Black.Ordinal) <
+			min(b.White.Ordinal, b.Black.Ordinal)
+	})
+	sort.SliceStable(byes, func(i, j int) bool {
+		return byes[i].White.Ordinal < byes[j].White.Ordinal
+	})
+
+	return append(games, byes...)
+}
+
+// swapRoundGame returns game with white and black as given, taking the
+// outcome from white's own record of the round.
+func swapRoundGame(game RoundGame, white uschess.Standings,
+	black uschess.Standings, round int) RoundGame {
+
+	game.White, game.Black = white, &black
+	game.Outcome = invertOutcome(game.Outcome)
+	if len(white.RoundOutcomes) >= round {
+		game.Outcome = white.RoundOutcomes[round-1].Outcome
+	}
+
+	return game
+}
+
+// invertOutcome returns the outcome of a game for the opponent.
+func invertOutcome(outcome uschess.PlayerOutcome) uschess.PlayerOutcome {
+	switch outcome {
+	case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym:
+		return uschess.PlayerOutcomeLoss
+	case uschess.PlayerOutcomeLoss, uschess.PlayerOutcomeLossAsym:
+		return uschess.PlayerOutcomeWin
+	case uschess.PlayerOutcomeWinForfeit:
+		return uschess.PlayerOutcomeForfeit
+	case uschess.PlayerOutcomeForfeit:
+		return uschess.PlayerOutcomeWinForfeit
+	}
+
+	return outcome
+}
+
+// formatRoundResult formats a game's result from white's side, e.g. "1-0",
+// "½-½", or "+/-" for a forfeit, or a bye's value for byes.
+func formatRoundResult(game RoundGame) string {
+	if game.Black == nil {
+		switch game.Outcome {
+		case uschess.PlayerOutcomeByeFull:
+			return "bye (1)"
+		case uschess.PlayerOutcomeByeHalf:
+			return "bye (½)"
+		case uschess.PlayerOutcomeWinForfeit:
+			return "forfeit win"
+		default:
+			return "forfeit loss"
+		}
+	}
+
+	switch game.Outcome {
+	case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym:
+		return "1-0"
+	case uschess.PlayerOutcomeLoss, uschess.PlayerOutcomeLossAsym:
+		return "0-1"
+	case uschess.PlayerOutcomeDraw, uschess.PlayerOutcomeDrawAsym:
+		return "½-½"
+	case uschess.PlayerOutcomeWinForfeit:
+		return "+/-"
+	case uschess.PlayerOutcomeForfeit:
+		return "-/+"
+	default:
+		return "?"
+	}
+}
+
+// roundGamePlayer formats a player of a reconstructed game, e.g.
+// "Alice Smith (1800)".
+func roundGamePlayer(entry uschess.Standings) string {
+	name := internal.NormalizeName(entry.FirstName + " " + entry.LastName)
+	if pre, _ := regularRating(entry.Ratings); pre != "" {
+		return fmt.Sprintf("%v (%v)", name, pre)
+	}
+
+	return name
+}
+
+// BuildRoundPairingsOutput formats the pairings and results of round, as
+// reconstructed by RoundGames, for each section of t matching section per
+// internal.SectionMatches.
+func BuildRoundPairingsOutput(t *uschess.Tournament, section string,
+	round int) string {
+
+	var sb strings.Builder
+	var names []string
+	matched := false
+	colorsKnown := true
+	for _, i := range SectionOrder(t) {
+		name := t.Sections[i].Name
+		names = append(names, name)
+		if !internal.SectionMatches(name, section) {
+			continue
+		}
+		matched = true
+		if MultiSection(t) {
+			sb.WriteString(internal.DisplaySectionName(name) + "\n")
+		}
+		if t.SectionStandings[i] == nil {
+			sb.WriteString("data unavailable\n\n")
+			continue
+		}
+		games := RoundGames(t.SectionStandings[i], round)
+		if len(games) == 0 {
+			sb.WriteString(fmt.Sprintf("No round %v games\n\n", round))
+			continue
+		}
+
+		type row struct{ white, result, black string }
+		rows := []row{{"White", "Result", "Black"}}
+		for _, game := range games {
+			r := row{white: roundGamePlayer(game.White),
+				result: formatRoundResult(game)}
+			if game.Black != nil {
+				r.black = roundGamePlayer(*game.Black)
+				colorsKnown = colorsKnown && game.ColorsKnown
+			}
+			rows = append(rows, r)
+		}
+		maxW, maxR := 0, 0
+		for _, r := range rows {
+			maxW = max(maxW, internal.DisplayWidth(r.white))
+			maxR = max(maxR, internal.DisplayWidth(r.result))
+		}
+		for _, r := range rows {
+			sb.WriteString(strings.TrimRight(fmt.Sprintf("%-*s  %-*s  %s",
+				maxW, r.white, maxR, r.result, r.black), " ") + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	if !matched {
+		return fmt.Sprintf("No section matches %q; sections are: %v\n", section,
+			strings.Join(names, ", "))
+	}
+
+	header := fmt.Sprintf("Round %v pairings (via US Chess crosstable):\n\n",
+		round)
+	if !colorsKnown {
+		header = fmt.Sprintf("Round %v pairings (via US Chess crosstable; colors not reported):\n\n",
+			round)
+	}
+
+	return header + sb.String()
+}
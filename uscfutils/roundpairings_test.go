@@ -0,0 +1,104 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestRoundGames(t *testing.T) {
+	round := func(outcome uschess.PlayerOutcome, opp int32,
+		color uschess.ChessColor) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome, OpponentOrdinal: opp,
+			Color: color}
+	}
+	rating := func(pre int32) []uschess.RatingRecord {
+		return []uschess.RatingRecord{{RatingType: "R", PreRating: pre}}
+	}
+	white, black := uschess.ChessColorWhite, uschess.ChessColorBlack
+	standings := uschess.StandingsOneSection{
+		{Ordinal: 1, FirstName: "Alice", LastName: "Smith",
+			Ratings: rating(1800), RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeLoss, 2, black),
+				round(uschess.PlayerOutcomeWinForfeit, 3, white),
+				round(uschess.PlayerOutcomeWin, 4, black)}},
+		{Ordinal: 2, FirstName: "Bob", LastName: "Jones",
+			Ratings: rating(1700), RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeWin, 1, white),
+				round(uschess.PlayerOutcomeByeHalf, 0, ""),
+				round(uschess.PlayerOutcomeUnpaired, 0, "")}},
+		{Ordinal: 3, FirstName: "Carol", LastName: "Lee",
+			Ratings: rating(1600), RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeDraw, 4, white),
+				round(uschess.PlayerOutcomeForfeit, 1, black),
+				round(uschess.PlayerOutcomeByeHalf, 0, "")}},
+		{Ordinal: 4, FirstName: "Dan", LastName: "Wu",
+			RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeDraw, 3, black),
+				round(uschess.PlayerOutcomeUnpaired, 0, ""),
+				round(uschess.PlayerOutcomeLoss, 1, white)}},
+	}
+	format := func(games []RoundGame) string {
+		var lines []string
+		for _, game := range games {
+			line := roundGamePlayer(game.White) + " " + formatRoundResult(game)
+			if game.Black != nil {
+				line += " " + roundGamePlayer(*game.Black)
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(lines, "; ")
+	}
+
+	tests := []struct {
+		round int
+		want  string
+	}{
+		{1, "Bob Jones (1700) 1-0 Alice Smith (1800); Carol Lee (1600) ½-½ Dan Wu"},
+		{2, "Alice Smith (1800) +/- Carol Lee (1600); Bob Jones (1700) bye (½)"},
+		// Bob was unpaired
+		{3, "Dan Wu 0-1 Alice Smith (1800); Carol Lee (1600) bye (½)"},
+		{4, ""},
+	}
+	for _, tc := range tests {
+		if got := format(RoundGames(standings, tc.round)); got != tc.want {
+			t.Errorf("round %v: RoundGames() = %q; want %q", tc.round, got,
+				tc.want)
+		}
+	}
+
+	tourney := &uschess.Tournament{
+		SectionStandings: []uschess.StandingsOneSection{standings}}
+	tourney.Sections = []uschess.MinimalSection{{Name: "Open", Number: 1}}
+	output := BuildRoundPairingsOutput(tourney, "", 3)
+	want := "Round 3 pairings (via US Chess crosstable):\n\n" +
+		"White             Result   Black\n" +
+		"Dan Wu            0-1      Alice Smith (1800)\n" +
+		"Carol Lee (1600)  bye (½)\n\n"
+	if output != want {
+		t.Errorf("BuildRoundPairingsOutput() =\n%v\nwant\n%v", output, want)
+	}
+
+	// without colors the lower pair number is listed first
+	for i := range standings {
+		for r := range standings[i].RoundOutcomes {
+			standings[i].RoundOutcomes[r].Color = ""
+		}
+	}
+	if got, want := format(RoundGames(standings, 3)),
+		"Alice Smith (1800) 1-0 Dan Wu; Carol Lee (1600) bye (½)"; got != want {
+
+		t.Errorf("RoundGames() without colors = %q; want %q", got, want)
+	}
+	if output := BuildRoundPairingsOutput(tourney, "", 3); !strings.Contains(
+		output, "colors not reported") {
+
+		t.Errorf("output does not note missing colors:\n%v", output)
+	}
+}
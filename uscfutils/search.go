@@ -0,0 +1,97 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+const (
+	// MinMemberSearchLen is the shortest name searched for; shorter
+	// queries match too many members to be useful.
+	MinMemberSearchLen = 3
+	// MaxMemberSearchResults bounds the members returned by a search.
+	MaxMemberSearchResults = 25
+	// searches are repeated as the user types, so recent results are kept
+	// in memory to spare US Chess repeated identical requests
+	memberSearchCacheTTL  = 10 * time.Minute
+	memberSearchCacheSize = 1000
+)
+
+type memberSearchResult struct {
+	members []uschess.MemberDetail
+	expires time.Time
+}
+
+// SearchMembers returns up to MaxMemberSearchResults US Chess members whose
+// names fuzzily match query, e.g. "smith, john" or "john smith". Queries
+// shorter than MinMemberSearchLen return no members. Results are cached
+// briefly and concurrent identical searches share a single fetch.
+func (c *Client) SearchMembers(ctx context.Context,
+	query string) ([]uschess.MemberDetail, error) {
+
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if len(query) < MinMemberSearchLen {
+		return nil, nil
+	}
+
+	now := internal.Now()
+	c.memberSearchMu.Lock()
+	cached, ok := c.memberSearches[query]
+	c.memberSearchMu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.members, nil
+	}
+
+	members, err := shareFlight(ctx, &c.memberSearchFlight, query,
+		func(ctx context.Context) (any, error) {
+			return c.searchMembers(ctx, query)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	c.memberSearchMu.Lock()
+	defer c.memberSearchMu.Unlock()
+	if c.memberSearches == nil ||
+		len(c.memberSearches) >= memberSearchCacheSize {
+
+		c.memberSearches = make(map[string]memberSearchResult)
+	}
+	c.memberSearches[query] = memberSearchResult{
+		members: members.([]uschess.MemberDetail),
+		expires: now.Add(memberSearchCacheTTL),
+	}
+
+	return members.([]uschess.MemberDetail), nil
+}
+
+func (c *Client) searchMembers(ctx context.Context,
+	query string) ([]uschess.MemberDetail, error) {
+
+	resp, err := c.GetMembersPageWithResponse(ctx, &uschess.GetMembersPageParams{
+		Fuzzy: &query,
+		Size:  MaxMemberSearchResults,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("member search for %q failed: %v", query,
+			resp.Status())
+	}
+	members := resp.JSON200.Items
+	if len(members) > MaxMemberSearchResults {
+		members = members[:MaxMemberSearchResults]
+	}
+
+	return members, nil
+}
@@ -0,0 +1,55 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestSearchMembers(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		requests.Add(1)
+		if got := r.URL.Query().Get("Fuzzy"); got != "alice smith" {
+			t.Errorf("Fuzzy = %q; want %q", got, "alice smith")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"items":[
+			{"id":"12345678","firstName":"Alice","lastName":"Smith"},
+			{"id":"12345679","firstName":"Alicia","lastName":"Smithers"}]}`)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+
+	members, err := client.SearchMembers(context.Background(), "Alice  Smith")
+	if err != nil || len(members) != 2 || members[0].Id != "12345678" {
+		t.Fatalf("SearchMembers() = %+v, %v", members, err)
+	}
+	// repeated searches are served from memory
+	if _, err := client.SearchMembers(context.Background(),
+		"alice smith"); err != nil || requests.Load() != 1 {
+		t.Errorf("SearchMembers() err = %v after %d requests", err,
+			requests.Load())
+	}
+	// short queries are not searched
+	if members, err := client.SearchMembers(context.Background(),
+		"al"); err != nil || len(members) != 0 || requests.Load() != 1 {
+		t.Errorf("SearchMembers(%q) = %+v, %v", "al", members, err)
+	}
+}
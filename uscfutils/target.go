@@ -0,0 +1,65 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// ratingEstimator returns the estimated post-event rating for a given score.
+type ratingEstimator func(ctx context.Context, score float64) (int32, error)
+
+// ScoreNeededForRating returns the minimum score (in half point increments)
+// memberID must achieve against opponentIDs in order to reach goal as an
+// estimated post-event Regular rating. The returned bool is false when goal
+// cannot be reached even with a perfect score, in which case the returned
+// score is the perfect score.
+func ScoreNeededForRating(ctx context.Context,
+	client *uschess.ClientWithResponses, memberID uschess.MemberID,
+	opponentIDs []uschess.MemberID, goal int) (float64, bool, error) {
+
+	return minScoreForRating(ctx, len(opponentIDs), goal,
+		func(ctx context.Context, score float64) (int32, error) {
+			est, err := client.GetRatingEstimate(ctx, memberID, opponentIDs,
+				score, uschess.RatingTypeR)
+			if err != nil {
+				return 0, err
+			}
+			return est.PostRating, nil
+		})
+}
+
+// minScoreForRating binary searches over the possible scores of a numGames
+// event for the smallest one whose estimated rating is at least goal.
+func minScoreForRating(ctx context.Context, numGames int, goal int,
+	estimate ratingEstimator) (float64, bool, error) {
+
+	// search over half points so that every candidate is a valid score
+	lo, hi := 0, 2*numGames
+	best, err := estimate(ctx, float64(hi)/2)
+	if err != nil {
+		return 0, false, err
+	}
+	if int(best) < goal {
+		return float64(hi) / 2, false, nil
+	}
+
+	for lo < hi {
+		mid := (lo + hi) / 2
+		rating, err := estimate(ctx, float64(mid)/2)
+		if err != nil {
+			return 0, false, err
+		}
+		if int(rating) >= goal {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	return float64(lo) / 2, true, nil
+}
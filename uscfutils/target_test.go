@@ -0,0 +1,45 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"testing"
+)
+
+// linearEstimator models a player rated 1700 who gains 40 points per half
+// point scored above 2.
+func linearEstimator(ctx context.Context, score float64) (int32, error) {
+	return int32(1700 + (score-2)*80), nil
+}
+
+func TestMinScoreForRating(t *testing.T) {
+	cases := []struct {
+		name           string
+		numGames       int
+		goal           int
+		wantScore      float64
+		wantAchievable bool
+	}{
+		{name: "half point needed", numGames: 4, goal: 1740, wantScore: 2.5, wantAchievable: true},
+		{name: "exact boundary", numGames: 4, goal: 1780, wantScore: 3, wantAchievable: true},
+		{name: "already there", numGames: 4, goal: 1500, wantScore: 0, wantAchievable: true},
+		{name: "perfect score", numGames: 4, goal: 1860, wantScore: 4, wantAchievable: true},
+		{name: "not achievable", numGames: 4, goal: 1900, wantScore: 4, wantAchievable: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			score, ok, err := minScoreForRating(context.Background(),
+				c.numGames, c.goal, linearEstimator)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			if score != c.wantScore || ok != c.wantAchievable {
+				t.Fatalf("minScoreForRating() = %v, %v; want %v, %v", score, ok,
+					c.wantScore, c.wantAchievable)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// StandingsAfterRound reconstructs a section's standings as they stood after
+// round by summing each player's results through that round. Byes and
+// forfeits count for the points they awarded. Entries are returned in
+// standings order with ties broken as in OrderByStandings, and each entry's
+// RoundOutcomes are truncated to the rounds played so far.
+func StandingsAfterRound(standings uschess.StandingsOneSection,
+	round int) uschess.StandingsOneSection {
+
+	result := make(uschess.StandingsOneSection, 0, len(standings))
+	for _, entry := range standings {
+		outcomes := entry.RoundOutcomes
+		if len(outcomes) > round {
+			outcomes = outcomes[:max(round, 0)]
+		}
+		score := 0.0
+		for _, outcome := range outcomes {
+			score += outcomePoints(outcome.Outcome)
+		}
+		entry.RoundOutcomes = outcomes
+		entry.Score = float32(score)
+		result = append(result, entry)
+	}
+
+	return sortStandings(result, OrderByStandings)
+}
+
+// TournamentAfterRound returns a copy of t whose section standings are
+// reconstructed through round via StandingsAfterRound.
+func TournamentAfterRound(t *uschess.Tournament, round int) *uschess.Tournament {
+	after := *t
+	after.SectionStandings = make([]uschess.StandingsOneSection,
+		len(t.SectionStandings))
+	for i, standings := range t.SectionStandings {
+		after.SectionStandings[i] = StandingsAfterRound(standings, round)
+	}
+
+	return &after
+}
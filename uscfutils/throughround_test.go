@@ -0,0 +1,71 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestStandingsAfterRound(t *testing.T) {
+	round := func(outcome uschess.PlayerOutcome,
+		opp int32) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome, OpponentOrdinal: opp}
+	}
+	standings := uschess.StandingsOneSection{
+		{Ordinal: 1, MemberId: "1", Score: 2, RoundOutcomes: []uschess.StandingsRound{
+			round(uschess.PlayerOutcomeLoss, 2),
+			round(uschess.PlayerOutcomeWinForfeit, 3),
+			round(uschess.PlayerOutcomeWin, 4)}},
+		{Ordinal: 2, MemberId: "2", Score: 1.5, RoundOutcomes: []uschess.StandingsRound{
+			round(uschess.PlayerOutcomeWin, 1),
+			round(uschess.PlayerOutcomeByeHalf, 0),
+			round(uschess.PlayerOutcomeUnpaired, 0)}},
+		{Ordinal: 3, MemberId: "3", Score: 1, RoundOutcomes: []uschess.StandingsRound{
+			round(uschess.PlayerOutcomeDraw, 4),
+			round(uschess.PlayerOutcomeForfeit, 1),
+			round(uschess.PlayerOutcomeByeHalf, 0)}},
+		{Ordinal: 4, MemberId: "4", Score: 0.5, RoundOutcomes: []uschess.StandingsRound{
+			round(uschess.PlayerOutcomeDraw, 3),
+			round(uschess.PlayerOutcomeUnpaired, 0),
+			round(uschess.PlayerOutcomeLoss, 1)}},
+	}
+
+	type want struct {
+		ordinal int32
+		score   float32
+	}
+	tests := []struct {
+		round int
+		want  []want
+	}{
+		{1, []want{{2, 1}, {3, 0.5}, {4, 0.5}, {1, 0}}},
+		{2, []want{{2, 1.5}, {1, 1}, {3, 0.5}, {4, 0.5}}},
+		{3, []want{{1, 2}, {2, 1.5}, {3, 1}, {4, 0.5}}},
+		{9, []want{{1, 2}, {2, 1.5}, {3, 1}, {4, 0.5}}},
+	}
+	for _, tc := range tests {
+		got := StandingsAfterRound(standings, tc.round)
+		if len(got) != len(tc.want) {
+			t.Fatalf("round %v: len = %v; want %v", tc.round, len(got),
+				len(tc.want))
+		}
+		for i, w := range tc.want {
+			if got[i].Ordinal != w.ordinal || got[i].Score != w.score {
+				t.Errorf("round %v: place %v = #%v %v; want #%v %v", tc.round,
+					i+1, got[i].Ordinal, got[i].Score, w.ordinal, w.score)
+			}
+			if len(got[i].RoundOutcomes) > tc.round {
+				t.Errorf("round %v: #%v has %v outcomes", tc.round,
+					got[i].Ordinal, len(got[i].RoundOutcomes))
+			}
+		}
+	}
+	if standings[0].Score != 2 || len(standings[0].RoundOutcomes) != 3 {
+		t.Fatalf("StandingsAfterRound modified its input")
+	}
+}
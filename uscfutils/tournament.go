@@ -0,0 +1,189 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+// GetTournament retrieves eventID's details and the standings of each of its
+// sections. Unlike uschess.ClientWithResponses.GetTournament, one section's
+// standings failing to load does not fail the whole event: a section which
+// has no number, or whose standings cannot be retrieved by its number, is
+// retried by its position among the event's sections. A section which still
+// cannot be loaded is left with nil standings; see UnavailableSections. An
+// error is returned only when no section's standings could be loaded.
+func (c *Client) GetTournament(ctx context.Context,
+	eventID uschess.EventID) (*uschess.Tournament, error) {
+
+	detail, err := c.getRatedEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &uschess.Tournament{
+		RatedEventDetail: *detail,
+		SectionStandings: make([]uschess.StandingsOneSection,
+			len(detail.Sections)),
+	}
+	errs := make([]error, len(t.Sections))
+	var group errgroup.Group
+	group.SetLimit(internal.GetConfig().CrossTablesConcurrency)
+	for index, section := range t.Sections {
+		group.Go(func() error {
+			t.SectionStandings[index], errs[index] = c.getSectionStandings(ctx,
+				eventID, section, int32(index+1))
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	loaded := 0
+	for index, err := range errs {
+		if err == nil {
+			loaded++
+			continue
+		}
+		log.Printf("uscfutils: event %v section %q standings unavailable: %v",
+			eventID, t.Sections[index].Name, err)
+	}
+	if loaded == 0 && len(t.Sections) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return t, nil
+}
+
+// getRatedEvent retrieves eventID's details, including its sections.
+func (c *Client) getRatedEvent(ctx context.Context,
+	eventID uschess.EventID) (*uschess.RatedEventDetail, error) {
+
+	resp, err := c.GetRatedEventWithResponse(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("GetRatedEvent: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, internal.WithStatus(fmt.Errorf(
+			"GetRatedEvent: unexpected response status %d: %s",
+			resp.StatusCode(), resp.Body), resp.StatusCode())
+	}
+
+	return resp.JSON200, nil
+}
+
+// ErrNoSectionMatch is returned by FetchSectionCrossTable when its query
+// matches none, or more than one, of an event's sections.
+var ErrNoSectionMatch = errors.New("no single section matches")
+
+// FetchSectionCrossTable retrieves eventID's details and the standings of
+// only the section matching sectionQuery per internal.SectionMatches, sparing
+// the standings requests for the event's other sections. A section whose name
+// equals the query (ignoring case) is preferred when the query matches
+// several. The returned tournament holds just the matched section.
+func (c *Client) FetchSectionCrossTable(ctx context.Context,
+	eventID uschess.EventID, sectionQuery string) (*uschess.Tournament, error) {
+
+	detail, err := c.getRatedEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches, exact []int
+	var names []string
+	for index, section := range detail.Sections {
+		names = append(names, section.Name)
+		if !internal.SectionMatches(section.Name, sectionQuery) {
+			continue
+		}
+		matches = append(matches, index)
+		if strings.EqualFold(strings.TrimSpace(section.Name),
+			strings.TrimSpace(sectionQuery)) {
+			exact = append(exact, index)
+		}
+	}
+	if len(exact) == 1 {
+		matches = exact
+	}
+	if len(matches) != 1 || sectionQuery == "" {
+		return nil, fmt.Errorf("%w %q in event %v; sections are: %v",
+			ErrNoSectionMatch, sectionQuery, eventID, strings.Join(names, ", "))
+	}
+
+	index := matches[0]
+	section := detail.Sections[index]
+	standings, err := c.getSectionStandings(ctx, eventID, section,
+		int32(index+1))
+	if err != nil {
+		return nil, err
+	}
+	t := &uschess.Tournament{
+		RatedEventDetail: *detail,
+		SectionStandings: []uschess.StandingsOneSection{standings},
+	}
+	t.Sections = []uschess.MinimalSection{section}
+	t.SectionCount = int32(len(detail.Sections))
+
+	return t, nil
+}
+
+// MultiSection reports whether a tournament's event has more than one
+// section, including one fetched by FetchSectionCrossTable which holds only
+// one of them.
+func MultiSection(t *uschess.Tournament) bool {
+	return len(t.SectionStandings) > 1 || t.SectionCount > 1
+}
+
+// getSectionStandings retrieves the standings of one section of eventID,
+// falling back to the section's position when its number is missing or
+// unknown to the API. The returned standings are never nil on success.
+func (c *Client) getSectionStandings(ctx context.Context,
+	eventID uschess.EventID, section uschess.MinimalSection,
+	position int32) (uschess.StandingsOneSection, error) {
+
+	var standings []uschess.Standings
+	err := errors.New("section has no number")
+	if section.Number != 0 {
+		standings, err = c.GetAllRatedEventStandings(ctx, eventID,
+			section.Number)
+	}
+	if err != nil && position != section.Number && ctx.Err() == nil {
+		var posErr error
+		standings, posErr = c.GetAllRatedEventStandings(ctx, eventID, position)
+		if posErr == nil {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetRatedEventStandings section %d: %w",
+			section.Number, classifyAPIError(err))
+	}
+	if standings == nil {
+		standings = uschess.StandingsOneSection{}
+	}
+
+	return standings, nil
+}
+
+// UnavailableSections returns the names of a tournament's sections whose
+// standings could not be loaded, so that output can say so rather than
+// silently leaving them out.
+func UnavailableSections(t *uschess.Tournament) []string {
+	var names []string
+	for index, standings := range t.SectionStandings {
+		if standings == nil && index < len(t.Sections) {
+			names = append(names, t.Sections[index].Name)
+		}
+	}
+
+	return names
+}
@@ -0,0 +1,183 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestGetTournamentSectionUnavailable(t *testing.T) {
+	const eventPath = "/api/v1/rated-events/202601131234"
+	failAll := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case eventPath:
+			// the U1400 section is missing its number
+			fmt.Fprint(w, `{"id":"202601131234","name":"BCC Tuesday Night Swiss",
+				"sections":[{"name":"Open","number":1},{"name":"U1800","number":2},
+				{"name":"U1400"}]}`)
+		case eventPath + "/sections/1/standings",
+			eventPath + "/sections/3/standings":
+			if failAll {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"items":[{"ordinal":1,"firstName":"Alice",
+				"lastName":"Smith","memberId":"12345678","score":1}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+
+	tourney, err := client.GetTournament(context.Background(), "202601131234")
+	if err != nil {
+		t.Fatalf("GetTournament() err = %v", err)
+	}
+	if len(tourney.SectionStandings) != 3 ||
+		len(tourney.SectionStandings[0]) != 1 ||
+		len(tourney.SectionStandings[2]) != 1 {
+
+		t.Fatalf("GetTournament() standings = %+v", tourney.SectionStandings)
+	}
+	if got := UnavailableSections(tourney); !reflect.DeepEqual(got,
+		[]string{"U1800"}) {
+
+		t.Errorf("UnavailableSections() = %v; want [U1800]", got)
+	}
+	output := BuildAllCrossTablesOutput(tourney, CrossTableOptions{})
+	for _, want := range []string{"Alice Smith", "U1800 Section data unavailable\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	failAll = true
+	if _, err := client.GetTournament(context.Background(),
+		"202601131234"); err == nil {
+
+		t.Errorf("GetTournament() err = nil with no section available")
+	}
+}
+
+func TestFetchSectionCrossTable(t *testing.T) {
+	const eventPath = "/api/v1/rated-events/202601131234"
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case eventPath:
+			fmt.Fprint(w, `{"id":"202601131234","name":"BCC Tuesday Night Swiss",
+				"sections":[{"name":"Open","number":1},{"name":"U1800","number":2},
+				{"name":"U1400","number":3}]}`)
+		case eventPath + "/sections/2/standings":
+			fmt.Fprint(w, `{"items":[{"ordinal":1,"firstName":"Bob",
+				"lastName":"Jones","memberId":"23456789","score":2}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+
+	tourney, err := client.FetchSectionCrossTable(context.Background(),
+		"202601131234", "u18")
+	if err != nil {
+		t.Fatalf("FetchSectionCrossTable() err = %v", err)
+	}
+	if len(tourney.Sections) != 1 || tourney.Sections[0].Name != "U1800" ||
+		len(tourney.SectionStandings) != 1 ||
+		tourney.SectionStandings[0][0].LastName != "Jones" {
+
+		t.Fatalf("FetchSectionCrossTable() = %+v", tourney)
+	}
+	want := []string{eventPath, eventPath + "/sections/2/standings"}
+	if !reflect.DeepEqual(requested, want) {
+		t.Errorf("requested %v; want only %v", requested, want)
+	}
+	if output := BuildAllCrossTablesOutput(tourney,
+		CrossTableOptions{}); !strings.Contains(output, "U1800 Section\n") {
+
+		t.Errorf("output missing section header:\n%s", output)
+	}
+
+	// "u1" matches both U1800 and U1400
+	_, err = client.FetchSectionCrossTable(context.Background(),
+		"202601131234", "u1")
+	if !errors.Is(err, ErrNoSectionMatch) {
+		t.Errorf("FetchSectionCrossTable(u1) err = %v; want ErrNoSectionMatch",
+			err)
+	}
+}
+
+func TestGetTournamentErrorsByStatus(t *testing.T) {
+	status := http.StatusNotFound
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/standings") {
+			w.WriteHeader(status)
+			return
+		}
+		if r.URL.Path != "/api/v1/rated-events/202601131234" {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"id":"202601131234","sections":[{"name":"Open","number":1}]}`)
+	}))
+	defer srv.Close()
+
+	api, err := uschess.NewClientWithResponses(srv.URL)
+	if err != nil {
+		t.Fatalf("NewClientWithResponses() err = %v", err)
+	}
+	client := &Client{ClientWithResponses: api}
+
+	for _, tc := range []struct {
+		status int
+		want   error
+	}{
+		{http.StatusNotFound, internal.ErrNotFound},
+		{http.StatusServiceUnavailable, internal.ErrUpstreamUnavailable},
+	} {
+		status = tc.status
+		// an unknown event, and a known event whose standings fail
+		for _, eventID := range []uschess.EventID{"999", "202601131234"} {
+			_, err := client.GetTournament(context.Background(), eventID)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("status %d event %v: err = %v; want %v", tc.status,
+					eventID, err, tc.want)
+			}
+		}
+	}
+}
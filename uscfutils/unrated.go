@@ -0,0 +1,155 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// initial ratings of unrated players are 50 per year of age, bounded to
+	// [minInitialRating, maxInitialRating]
+	initialRatingPerYear = 50
+	minInitialRating     = 100
+	maxInitialRating     = 1300
+	// the age based initial rating is weighted as if it had been earned over
+	// at most this many games
+	unratedPriorGamesCap = 4
+	// ratings produced by the special formula are bounded to this range
+	minSpecialRating = 100
+	maxSpecialRating = 2700
+)
+
+// InitialRatingForAge returns the age based initial rating US Chess assumes
+// for an unrated player: 50 per year of age, from 100 (age 2 and younger) up
+// to 1300 (age 26 and older).
+func InitialRatingForAge(age int) int {
+	return min(max(initialRatingPerYear*age, minInitialRating),
+		maxInitialRating)
+}
+
+// nStarGames returns N*, the most games a prior rating is weighted as if it
+// had been earned over.
+func nStarGames(rating float64) float64 {
+	if rating > 2355 {
+		return 50.0
+	}
+
+	return 50.0 / math.Sqrt(0.662+0.00000739*math.Pow(2569.0-rating, 2))
+}
+
+// unratedPriorGames returns the effective number of games N' the initial
+// rating of an unrated player is weighted by.
+func unratedPriorGames(initialRating float64) float64 {
+	return math.Min(nStarGames(initialRating), unratedPriorGamesCap)
+}
+
+// provisionalExpectancy is the winning expectancy the special rating formula
+// uses for a player rated r against an opponent rated opp.
+func provisionalExpectancy(r float64, opp float64) float64 {
+	return math.Min(math.Max(0.5+(r-opp)/800.0, 0.0), 1.0)
+}
+
+// unratedRatingEstimate applies the special rating formula to an unrated
+// player of the given age who scored score against opponentRatings, seeded
+// with their age based initial rating.
+func unratedRatingEstimate(age int, score float64,
+	opponentRatings []float64) float64 {
+
+	initial := float64(InitialRatingForAge(age))
+
+	return specialRatingEstimate(initial, unratedPriorGames(initial), score,
+		opponentRatings)
+}
+
+// specialRatingEstimate applies the special rating formula to a player rated
+// prior over n0 effective games who scored score against opponentRatings. It
+// finds the rating R whose total winning expectancy over the n0 prior games
+// (at prior) and the event's games equals the player's score over the same
+// games.
+func specialRatingEstimate(prior float64, n0 float64, score float64,
+	opponentRatings []float64) float64 {
+
+	if len(opponentRatings) == 0 {
+		return prior
+	}
+	target := score + n0/2.0
+	excess := func(r float64) float64 {
+		sum := n0 * provisionalExpectancy(r, prior)
+		for _, opp := range opponentRatings {
+			sum += provisionalExpectancy(r, opp)
+		}
+		return sum - target
+	}
+
+	// expectancies saturate 400 points from every rating involved
+	lo, hi := prior-400.0, prior+400.0
+	for _, opp := range opponentRatings {
+		lo = math.Min(lo, opp-400.0)
+		hi = math.Max(hi, opp+400.0)
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2.0
+		if excess(mid) < 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return math.Min(math.Max((lo+hi)/2.0, minSpecialRating), maxSpecialRating)
+}
+
+// GetRatingEstimateForUnrated estimates the post-event Regular rating of an
+// unrated player of age playerAge who scored score against opponentIDs.
+// Unlike uschess.GetRatingEstimate, which refuses unrated players, it seeds
+// the special rating formula with the player's age based initial rating.
+// Every opponent must hold a Regular rating.
+func GetRatingEstimateForUnrated(ctx context.Context,
+	client *uschess.ClientWithResponses, playerAge int,
+	opponentIDs []uschess.MemberID, score float64) (int, error) {
+
+	if playerAge <= 0 {
+		return 0, fmt.Errorf("invalid age %v", playerAge)
+	}
+	if score < 0 || score > float64(len(opponentIDs)) {
+		return 0, fmt.Errorf("score %v is not possible over %v games", score,
+			len(opponentIDs))
+	}
+
+	opponentRatings := make([]float64, len(opponentIDs))
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i, oppID := range opponentIDs {
+		i, oppID := i, oppID
+		group.Go(func() error {
+			opp, err := client.GetPlayer(groupCtx, oppID,
+				&uschess.GetPlayerOptions{IncludeLiveRatings: true})
+			if err != nil {
+				return err
+			}
+			ratings, err := opp.LiveRatings()
+			if err != nil {
+				return err
+			}
+			if j := regularSystemIndex(ratings); j >= 0 {
+				opponentRatings[i] = float64(ratings[j].Rating)
+				return nil
+			}
+			return fmt.Errorf("opponent %v is unrated in %s", oppID,
+				uschess.RatingTypeR)
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return 0, err
+	}
+
+	return int(math.Round(unratedRatingEstimate(playerAge, score,
+		opponentRatings))), nil
+}
@@ -0,0 +1,56 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInitialRatingForAge(t *testing.T) {
+	tests := []struct {
+		age  int
+		want int
+	}{
+		{1, 100},
+		{2, 100},
+		{8, 400},
+		{10, 500},
+		{16, 800},
+		{26, 1300},
+		{45, 1300},
+	}
+	for _, tc := range tests {
+		if got := InitialRatingForAge(tc.age); got != tc.want {
+			t.Errorf("InitialRatingForAge(%v) = %v; want %v", tc.age, got,
+				tc.want)
+		}
+	}
+}
+
+func TestUnratedRatingEstimate(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       int
+		score     float64
+		opponents []float64
+		want      float64
+	}{
+		{"no games keeps initial rating", 10, 0, nil, 500},
+		{"even score vs peers", 10, 1, []float64{500, 500}, 500},
+		// 6 * (0.5 + (R-500)/800) = 2 + 4/2
+		{"perfect score vs peers", 10, 2, []float64{500, 500}, 633.33},
+		// 4 * (0.5 + (R-1300)/800) + (0.5 + (R-1500)/800) = 0.5 + 4/2
+		{"adult draws a stronger player", 30, 0.5, []float64{1500}, 1340},
+		{"bounded below", 2, 0, []float64{100, 100, 100}, 100},
+	}
+	for _, tc := range tests {
+		got := unratedRatingEstimate(tc.age, tc.score, tc.opponents)
+		if math.Abs(got-tc.want) > 0.01 {
+			t.Errorf("%v: unratedRatingEstimate() = %.2f; want %.2f", tc.name,
+				got, tc.want)
+		}
+	}
+}
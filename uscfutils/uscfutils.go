@@ -10,37 +10,148 @@ package uscfutils
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
 	"github.com/mikeb26/boylstonchessclub-tdbot/internal/httpcache"
 	uschess "github.com/mikeb26/uschess-go"
-	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/singleflight"
 )
 
+// apiStatusRe matches the unexpected response status uschess-go reports
+// when the US Chess API responds with an error.
+var apiStatusRe = regexp.MustCompile(`unexpected response status (\d+)`)
+
+// classifyAPIError makes an error returned by uschess-go, which reports
+// unexpected response statuses only in its message, match the status's
+// internal.StatusClass (e.g. internal.ErrNotFound) with errors.Is.
+func classifyAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+	m := apiStatusRe.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	status, _ := strconv.Atoi(m[1])
+
+	return internal.WithStatus(err, status)
+}
+
+// Client is a US Chess API client along with the resources it holds on to
+// for its lifetime. Long running programs should Close it on shutdown.
+type Client struct {
+	*uschess.ClientWithResponses
+
+	httpClient *http.Client
+	closeOnce  sync.Once
+
+	// share in-flight fetches among concurrent identical requests
+	crossTablesFlight  singleflight.Group
+	playerFlight       singleflight.Group
+	memberSearchFlight singleflight.Group
+
+	memberSearchMu sync.Mutex
+	memberSearches map[string]memberSearchResult
+}
+
+// flightTimeout bounds a fetch shared by concurrent identical requests, which
+// runs apart from the deadline of any one of them.
+const flightTimeout = time.Minute
+
+// shareFlight runs fn once among concurrent callers with the same key, as
+// g.Do does. fn runs under a context which keeps ctx's values but not its
+// cancellation, so that a caller who gives up, e.g. on its own deadline,
+// fails only itself and not every caller waiting on the same fetch; each
+// caller waits until the fetch completes or its own ctx is done.
+func shareFlight(ctx context.Context, g *singleflight.Group, key string,
+	fn func(ctx context.Context) (any, error)) (any, error) {
+
+	ch := g.DoChan(key, func() (any, error) {
+		flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx),
+			flightTimeout)
+		defer cancel()
+		return fn(flightCtx)
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // NewClient creates a US Chess API client using the application's S3-backed
 // HTTP cache.
-func NewClient(ctx context.Context) (*uschess.ClientWithResponses, error) {
-	httpClient := httpcache.NewCachedHttpClient(ctx, 24*time.Hour)
-	return uschess.NewDefaultClient(
+func NewClient(ctx context.Context) (*Client, error) {
+	httpClient := httpcache.NewCachedHttpClientWithTTL(ctx, clientResponseTTL)
+	client, err := uschess.NewDefaultClient(
 		uschess.WithHTTPClient(httpClient),
 		uschess.WithUserAgent(internal.UserAgent),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		ClientWithResponses: client,
+		httpClient:          httpClient,
+	}, nil
 }
 
-// BuildCrossTableOutput formats one section's standings as a monospace table.
-// A nonempty filterPlayerID includes that player and their opponents only.
+// Close releases the resources held by the client, e.g. idle connections.
+// The client must not be used after Close. Close is safe to call more than
+// once; calls after the first do nothing.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		c.httpClient.CloseIdleConnections()
+	})
+}
+
+// elidedRounds stands in for the earlier rounds of a crosstable limited to
+// its most recent rounds.
+const elidedRounds = "…"
+
+// CrossTableOptions controls how crosstables are rendered. The zero value
+// renders every player and round, in pair number order and the compact
+// style, without a section header.
+type CrossTableOptions struct {
+	// IncludeSectionHeader heads the table with the section's name.
+	// Dual-rated sections are always headed so.
+	IncludeSectionHeader bool
+	// FilterPlayerID, when nonempty, includes only that player and their
+	// opponents.
+	FilterPlayerID uschess.MemberID
+	// Order lists the entries; OrderByStandings adds a place column.
+	Order CrossTableOrder
+	// LastRounds, when positive, shows only that many of the most recent
+	// rounds, with a "…" column standing in for the earlier ones.
+	LastRounds int
+	// Style renders each round's result.
+	Style CrossTableStyle
+}
+
+// BuildCrossTableOutput formats one section's standings as a monospace table
+// per opts. When opts filters by a player who is not in the section nothing
+// is rendered and both returned strings are empty. Dual-rated sections are
+// labeled as such, e.g. "Open Section (Dual R/Q)", and include a column of
+// each player's secondary ratings.
 func BuildCrossTableOutput(section uschess.MinimalSection,
-	standings uschess.StandingsOneSection, includeSectionHeader bool,
-	filterPlayerID uschess.MemberID) (string, string) {
+	standings uschess.StandingsOneSection,
+	opts CrossTableOptions) (string, string) {
 
 	var includeSet map[int32]bool
 	var filteredOrdinal int32
-	if filterPlayerID != "" {
+	if opts.FilterPlayerID != "" {
 		includeSet = make(map[int32]bool)
 		for _, entry := range standings {
-			if entry.MemberId != filterPlayerID {
+			if entry.MemberId != opts.FilterPlayerID {
 				continue
 			}
 			filteredOrdinal = entry.Ordinal
@@ -52,11 +163,23 @@ func BuildCrossTableOutput(section uschess.MinimalSection,
 			}
 			break
 		}
+		if len(includeSet) == 0 {
+			// the player did not play in this section
+			return "", ""
+		}
 	}
 
+	// dual-rated sections are always labeled so that readers know to expect
+	// both ratings
+	secondaryType, dualRated := sectionDualRating(standings)
 	var sb strings.Builder
-	if includeSectionHeader {
-		sb.WriteString(fmt.Sprintf("Section %s\n", section.Name))
+	if opts.IncludeSectionHeader || dualRated {
+		sb.WriteString(internal.DisplaySectionName(section.Name))
+		if dualRated {
+			sb.WriteString(fmt.Sprintf(" (Dual %s/%s)",
+				string(uschess.RatingTypeR), string(secondaryType)))
+		}
+		sb.WriteString("\n")
 	}
 
 	numRounds := 0
@@ -65,15 +188,33 @@ func BuildCrossTableOutput(section uschess.MinimalSection,
 			numRounds = len(entry.RoundOutcomes)
 		}
 	}
-	headers := []string{"No", "Name", "Rating", "Pts"}
-	for round := 1; round <= numRounds; round++ {
+	headers := []string{"No", "Name", "Rating"}
+	if dualRated {
+		headers = append(headers, fmt.Sprintf("%s Rating", secondaryType))
+	}
+	headers = append(headers, "Pts")
+	if opts.Order == OrderByStandings {
+		headers = append([]string{"Pl"}, headers...)
+	}
+	firstRound := 1
+	if opts.LastRounds > 0 && numRounds > opts.LastRounds {
+		firstRound = numRounds - opts.LastRounds + 1
+		headers = append(headers, elidedRounds)
+	}
+	for round := firstRound; round <= numRounds; round++ {
 		headers = append(headers, fmt.Sprintf("R%d", round))
 	}
 
 	ratingPost := "<unknown>"
 	forfeitFound := false
 	rows := make([][]string, 0, len(standings))
-	for _, entry := range standings {
+	sorted := sortStandings(standings, opts.Order)
+	scores := make([]float64, len(sorted))
+	for idx, entry := range sorted {
+		scores[idx] = float64(entry.Score)
+	}
+	places := internal.CompetitionRanks(scores)
+	for index, entry := range sorted {
 		if includeSet != nil && !includeSet[entry.Ordinal] {
 			continue
 		}
@@ -89,11 +230,26 @@ func BuildCrossTableOutput(section uschess.MinimalSection,
 			fmt.Sprintf("%d.", entry.Ordinal),
 			name,
 			fmt.Sprintf("%s->%s", preRating, postRating),
-			internal.ScoreToString(float64(entry.Score)),
 		}
-		for _, outcome := range entry.RoundOutcomes {
-			cell, isForfeit := formatOutcome(outcome)
-			forfeitFound = forfeitFound || isForfeit
+		if dualRated {
+			row = append(row, formatRatingChange(typedRating(entry.Ratings,
+				secondaryType)))
+		}
+		row = append(row, internal.ScoreToString(float64(entry.Score)))
+		if opts.Order == OrderByStandings {
+			row = append([]string{fmt.Sprintf("%d", places[index])}, row...)
+		}
+		if firstRound > 1 {
+			row = append(row, elidedRounds)
+		}
+		for round := firstRound; round <= numRounds; round++ {
+			cell := ""
+			if round <= len(entry.RoundOutcomes) {
+				var isForfeit bool
+				cell, isForfeit = formatResult(entry.RoundOutcomes[round-1],
+					opts.Style)
+				forfeitFound = forfeitFound || isForfeit
+			}
 			row = append(row, cell)
 		}
 		rows = append(rows, row)
@@ -105,8 +261,8 @@ func BuildCrossTableOutput(section uschess.MinimalSection,
 	}
 	for _, row := range rows {
 		for i, cell := range row {
-			if len(cell) > widths[i] {
-				widths[i] = len(cell)
+			if w := internal.DisplayWidth(cell); w > widths[i] {
+				widths[i] = w
 			}
 		}
 	}
@@ -128,92 +284,125 @@ func BuildCrossTableOutput(section uschess.MinimalSection,
 	return sb.String(), ratingPost
 }
 
-// BuildPlayerReport retrieves and formats a player's current rating and recent
-// Regular-rated event crosstables.
-func BuildPlayerReport(ctx context.Context, client *uschess.ClientWithResponses,
-	memberID uschess.MemberID, eventCount int) (string, error) {
+// BuildAllCrossTablesOutput formats the standings of every section of a
+// tournament per opts. Each section is headed with its name when there are
+// several, and opts.FilterPlayerID is ignored.
+func BuildAllCrossTablesOutput(t *uschess.Tournament,
+	opts CrossTableOptions) string {
+
+	return BuildCrossTablesOutput(t, "", opts)
+}
+
+// BuildCrossTablesOutput is like BuildAllCrossTablesOutput but only includes
+// the sections matching section per internal.SectionMatches. When no section
+// matches the available sections are listed instead. Sections whose
+// standings could not be loaded are noted as unavailable.
+func BuildCrossTablesOutput(t *uschess.Tournament, section string,
+	opts CrossTableOptions) string {
 
-	opts := &uschess.GetPlayerOptions{
-		IncludeSupplements: true,
-		IncludeEvents:      true,
-		IncludeLiveRatings: true,
+	return buildCrossTablesOutput(t, SectionOrder(t), section, opts)
+}
+
+// buildCrossTablesOutput formats the crosstables of the sections of t
+// matching section, in the order of sectionIdxs.
+func buildCrossTablesOutput(t *uschess.Tournament, sectionIdxs []int,
+	section string, opts CrossTableOptions) string {
+
+	opts.IncludeSectionHeader = MultiSection(t)
+	opts.FilterPlayerID = ""
+	var sb strings.Builder
+	var names []string
+	for _, i := range sectionIdxs {
+		names = append(names, t.Sections[i].Name)
+		if !internal.SectionMatches(t.Sections[i].Name, section) {
+			continue
+		}
+		if t.SectionStandings[i] == nil {
+			sb.WriteString(fmt.Sprintf("%v data unavailable\n\n",
+				internal.DisplaySectionName(t.Sections[i].Name)))
+			continue
+		}
+		output, _ := BuildCrossTableOutput(t.Sections[i], t.SectionStandings[i],
+			opts)
+		sb.WriteString(output)
 	}
-	player, err := client.GetPlayer(ctx, memberID, opts)
-	if err != nil {
-		return "", err
+	if sb.Len() == 0 && section != "" {
+		return fmt.Sprintf("No section matches %q; sections are: %v\n", section,
+			strings.Join(names, ", "))
 	}
 
-	liveRating, err := playerRegularLiveRating(player)
-	if err != nil {
-		return "", err
+	return sb.String()
+}
+
+// SectionOrder returns the indexes of a tournament's sections ordered by
+// their USCF section numbers, which follow the order the club filed them in
+// (e.g. Open first).
+func SectionOrder(t *uschess.Tournament) []int {
+	order := make([]int, 0, len(t.SectionStandings))
+	for i := range t.SectionStandings {
+		if i < len(t.Sections) {
+			order = append(order, i)
+		}
 	}
-	supplementRating, supplementDate := playerRegularSupplement(player)
-
-	// MemberEvents are ordered most recent first. Only retrieve the events the
-	// caller requested for the report; older events may no longer be available
-	// from the US Chess API.
-	events := player.MemberEvents
-	if len(events) > eventCount {
-		events = events[:eventCount]
-	}
-	tournaments := make([]*uschess.Tournament, len(events))
-	group, groupCtx := errgroup.WithContext(ctx)
-	for index, event := range events {
-		index, event := index, event
-		group.Go(func() error {
-			tournament, err := client.GetTournament(groupCtx, event.Id)
-			if err != nil {
-				return fmt.Errorf("fetching crosstables for event %s: %w", event.Id, err)
-			}
-			tournaments[index] = tournament
-			return nil
-		})
+	sort.SliceStable(order, func(i, j int) bool {
+		return t.Sections[order[i]].Number < t.Sections[order[j]].Number
+	})
+
+	return order
+}
+
+// averageOpposition returns the average pre-event rating of the opponents
+// memberID played in a section, along with the number of rated opponents the
+// average is based on and the total number of games played. Byes are
+// excluded, as are forfeits unless policy counts them.
+func averageOpposition(standings uschess.StandingsOneSection,
+	memberID uschess.MemberID, policy ForfeitPolicy) (int, int, int) {
+
+	opponents := opponentsOf(standings, memberID, policy)
+	total, rated := 0, 0
+	for _, opp := range opponents {
+		if opp.PreRating > 0 {
+			total += int(opp.PreRating)
+			rated++
+		}
 	}
-	if err := group.Wait(); err != nil {
-		return "", err
+	if rated == 0 {
+		return 0, 0, len(opponents)
 	}
 
-	var eventOutput strings.Builder
-	outputCount := 0
-	firstEvent := true
-	for _, tournament := range tournaments {
-		if outputCount >= eventCount {
-			break
-		}
+	return (total + rated/2) / rated, rated, len(opponents)
+}
 
-		wroteEvent := false
-		for index, standings := range tournament.SectionStandings {
-			if !sectionIsRegular(standings) || !sectionContainsPlayer(standings, memberID) {
-				continue
-			}
-			if !wroteEvent {
-				eventOutput.WriteString(fmt.Sprintf("%s - %s\n",
-					tournament.EndDate.Time.Format("2006-01-02"), tournament.Name))
-				outputCount++
-				wroteEvent = true
-			}
-			section := tournament.Sections[index]
-			output, postRating := BuildCrossTableOutput(section, standings, true, memberID)
-			if firstEvent {
-				liveRating = postRating
-				firstEvent = false
-			}
-			eventOutput.WriteString(output)
-		}
+// buildAvgOppOutput formats the average opposition of memberID in a section
+// for an event header line.
+func buildAvgOppOutput(standings uschess.StandingsOneSection,
+	memberID uschess.MemberID) string {
+
+	return formatAvgOpp(averageOpposition(standings, memberID,
+		ExcludeForfeits))
+}
+
+// formatAvgOpp formats an average opposition rating based on rated of games
+// opponents for an event header line.
+func formatAvgOpp(avg int, rated int, games int) string {
+	if rated == 0 {
+		return ""
+	}
+	if rated < games {
+		return fmt.Sprintf(" (Avg Opp: %d, %d of %d opponents rated)", avg,
+			rated, games)
 	}
+	return fmt.Sprintf(" (Avg Opp: %d)", avg)
+}
 
-	name := internal.NormalizeName(player.FirstName + " " + player.LastName)
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Player: %s\n", name))
-	sb.WriteString(fmt.Sprintf("USCF ID: %s\n", player.Id))
-	sb.WriteString(fmt.Sprintf("Rating:\n\tLive: %s\n", liveRating))
-	sb.WriteString(fmt.Sprintf("\t%s Supplement: %s\n", supplementDate.Format("Jan"), supplementRating))
-	sb.WriteString(fmt.Sprintf("Rated Events: %d\n", len(player.MemberEvents)))
-	if eventOutput.Len() > 0 {
-		sb.WriteString(fmt.Sprintf("Most Recent(%d) Classical Events:\n\n", eventCount))
-	}
-	sb.WriteString(eventOutput.String())
-	return sb.String(), nil
+func regularPreRating(ratings []uschess.RatingRecord) int32 {
+	if i := regularRecordIndex(ratings); i >= 0 {
+		return ratings[i].PreRating
+	}
+	if len(ratings) == 0 {
+		return 0
+	}
+	return ratings[0].PreRating
 }
 
 func sectionContainsPlayer(standings uschess.StandingsOneSection, memberID uschess.MemberID) bool {
@@ -230,7 +419,8 @@ func sectionIsRegular(standings uschess.StandingsOneSection) bool {
 	for _, entry := range standings {
 		for _, rating := range entry.Ratings {
 			foundRating = true
-			if rating.RatingType == uschess.RatingTypeR {
+			if rating.RatingType == uschess.RatingTypeR ||
+				rating.RatingType == ratingTypeDual {
 				return true
 			}
 		}
@@ -241,11 +431,10 @@ func sectionIsRegular(standings uschess.StandingsOneSection) bool {
 }
 
 func regularRating(ratings []uschess.RatingRecord) (string, string) {
-	for _, rating := range ratings {
-		if rating.RatingType == uschess.RatingTypeR {
-			return formatRating(rating.PreRating, 0),
-				formatRating(rating.PostRating, rating.PostProvisionalGameCount)
-		}
+	if i := regularRecordIndex(ratings); i >= 0 {
+		return formatRating(ratings[i].PreRating, 0),
+			formatRating(ratings[i].PostRating,
+				ratings[i].PostProvisionalGameCount)
 	}
 	if len(ratings) == 0 {
 		return "", ""
@@ -255,6 +444,17 @@ func regularRating(ratings []uschess.RatingRecord) (string, string) {
 		formatRating(rating.PostRating, rating.PostProvisionalGameCount)
 }
 
+// formatRatingChange formats a player's pre- and post-event ratings as
+// "pre->post", or as "" when the player has neither, e.g. when they are not
+// rated in a dual-rated section's secondary system.
+func formatRatingChange(pre string, post string) string {
+	if pre == "" && post == "" {
+		return ""
+	}
+
+	return pre + "->" + post
+}
+
 func formatRating(rating, provisionalGames int32) string {
 	if rating == 0 {
 		return ""
@@ -270,10 +470,8 @@ func playerRegularLiveRating(player *uschess.Player) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	for _, rating := range ratings {
-		if rating.RatingType == uschess.RatingTypeR {
-			return formatRating(rating.Rating, rating.ProvisionalGameCount), nil
-		}
+	if i := regularSystemIndex(ratings); i >= 0 {
+		return formatRating(ratings[i].Rating, ratings[i].ProvisionalGameCount), nil
 	}
 	return "<unrated>", nil
 }
@@ -283,10 +481,9 @@ func playerRegularSupplement(player *uschess.Player) (string, time.Time) {
 		return "<unrated>", time.Time{}
 	}
 	supplement := player.RatingSupplements[0]
-	for _, rating := range supplement.Ratings {
-		if rating.RatingType == uschess.RatingTypeR {
-			return formatRating(rating.Rating, rating.ProvisionalGameCount), supplement.RatingSupplementDate.Time
-		}
+	if i := regularSystemIndex(supplement.Ratings); i >= 0 {
+		rating := supplement.Ratings[i]
+		return formatRating(rating.Rating, rating.ProvisionalGameCount), supplement.RatingSupplementDate.Time
 	}
 	return "<unrated>", supplement.RatingSupplementDate.Time
 }
@@ -5,10 +5,15 @@
 package uscfutils
 
 import (
+	"context"
+	"errors"
+	"net/http"
 	"strings"
+	"sync"
 	"testing"
 
 	uschess "github.com/mikeb26/uschess-go"
+	"golang.org/x/sync/singleflight"
 )
 
 func TestBuildCrossTableOutput(t *testing.T) {
@@ -46,9 +51,10 @@ func TestBuildCrossTableOutput(t *testing.T) {
 		},
 	}
 
-	output, ratingPost := BuildCrossTableOutput(section, standings, true, "1")
+	output, ratingPost := BuildCrossTableOutput(section, standings,
+		CrossTableOptions{IncludeSectionHeader: true, FilterPlayerID: "1"})
 	for _, want := range []string{
-		"Section Open",
+		"Open Section",
 		"**Alice Player**",
 		"W2(w)",
 		"BYE(½)",
@@ -95,7 +101,8 @@ func TestBuildCrossTableOutputFiltersByOrdinal(t *testing.T) {
 		},
 	}
 
-	output, _ := BuildCrossTableOutput(section, standings, false, "1")
+	output, _ := BuildCrossTableOutput(section, standings,
+		CrossTableOptions{FilterPlayerID: "1"})
 	for _, want := range []string{"1.  **Target Player**", "2.  Actual Opponent", "W2(w)"} {
 		if !strings.Contains(output, want) {
 			t.Fatalf("output missing %q:\n%s", want, output)
@@ -105,3 +112,231 @@ func TestBuildCrossTableOutputFiltersByOrdinal(t *testing.T) {
 		t.Fatalf("output included a row selected by pairing number:\n%s", output)
 	}
 }
+
+func TestBuildCrossTableOutputFilteredPlayerNotFound(t *testing.T) {
+	section := uschess.MinimalSection{Name: "U1800"}
+	standings := uschess.StandingsOneSection{
+		{
+			Ordinal:   1,
+			FirstName: "Other",
+			LastName:  "Player",
+			MemberId:  "2",
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeByeFull},
+			},
+		},
+	}
+
+	output, ratingPost := BuildCrossTableOutput(section, standings,
+		CrossTableOptions{IncludeSectionHeader: true, FilterPlayerID: "1"})
+	if output != "" || ratingPost != "" {
+		t.Fatalf("BuildCrossTableOutput() = %q, %q; want empty", output,
+			ratingPost)
+	}
+}
+
+func TestBuildAllCrossTablesOutputSectionOrder(t *testing.T) {
+	entry := func(name string) uschess.StandingsOneSection {
+		return uschess.StandingsOneSection{{Ordinal: 1, FirstName: name,
+			LastName: "Player", MemberId: "1"}}
+	}
+	tourney := &uschess.Tournament{
+		RatedEventDetail: uschess.RatedEventDetail{
+			Sections: []uschess.MinimalSection{
+				{Name: "U1600", Number: 3},
+				{Name: "Open", Number: 1},
+				{Name: "U2000", Number: 2},
+			},
+		},
+		SectionStandings: []uschess.StandingsOneSection{
+			entry("Charlie"), entry("Alice"), entry("Bob"),
+		},
+	}
+
+	output := BuildAllCrossTablesOutput(tourney, CrossTableOptions{})
+	last := -1
+	for _, want := range []string{"Open Section", "U2000 Section",
+		"U1600 Section"} {
+
+		idx := strings.Index(output, want)
+		if idx <= last {
+			t.Fatalf("%q missing or out of order:\n%s", want, output)
+		}
+		last = idx
+	}
+}
+
+func TestBuildCrossTablesOutputSectionFilter(t *testing.T) {
+	entry := func(name string) uschess.StandingsOneSection {
+		return uschess.StandingsOneSection{{Ordinal: 1, FirstName: name,
+			LastName: "Player", MemberId: "1"}}
+	}
+	tourney := &uschess.Tournament{
+		RatedEventDetail: uschess.RatedEventDetail{
+			Sections: []uschess.MinimalSection{
+				{Name: "Open", Number: 1},
+				{Name: "U1800", Number: 2},
+			},
+		},
+		SectionStandings: []uschess.StandingsOneSection{
+			entry("Alice"), entry("Bob"),
+		},
+	}
+
+	output := BuildCrossTablesOutput(tourney, "u18", CrossTableOptions{})
+	if !strings.Contains(output, "U1800 Section") ||
+		strings.Contains(output, "Open Section") ||
+		strings.Contains(output, "Alice") {
+
+		t.Errorf("output not narrowed to U1800:\n%s", output)
+	}
+
+	output = BuildCrossTablesOutput(tourney, "u2000", CrossTableOptions{})
+	if want := "No section matches \"u2000\"; sections are: Open, U1800\n"; output != want {
+		t.Errorf("output = %q; want %q", output, want)
+	}
+}
+
+type closeCountingTransport struct {
+	http.RoundTripper
+	closes int
+}
+
+func (t *closeCountingTransport) CloseIdleConnections() {
+	t.closes++
+}
+
+func TestClientCloseOnce(t *testing.T) {
+	transport := &closeCountingTransport{}
+	client := &Client{httpClient: &http.Client{Transport: transport}}
+
+	client.Close()
+	client.Close()
+	if transport.closes != 1 {
+		t.Fatalf("CloseIdleConnections called %v times; want 1",
+			transport.closes)
+	}
+}
+
+func TestShareFlightSurvivesCancelledCaller(t *testing.T) {
+	var g singleflight.Group
+	started := make(chan struct{})
+	var startOnce sync.Once
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (any, error) {
+		startOnce.Do(func() { close(started) })
+		<-release
+		// the fetch outlives the caller who started it
+		return "result", ctx.Err()
+	}
+
+	firstCtx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := shareFlight(firstCtx, &g, "key", fetch)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan any)
+	go func() {
+		val, err := shareFlight(context.Background(), &g, "key", fetch)
+		if err != nil {
+			t.Errorf("waiting caller err = %v", err)
+		}
+		second <- val
+	}()
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled caller err = %v; want context.Canceled", err)
+	}
+	close(release)
+	if val := <-second; val != "result" {
+		t.Errorf("waiting caller got %v; want the shared result", val)
+	}
+}
+
+func TestBuildAvgOppOutput(t *testing.T) {
+	rated := func(r int32) []uschess.RatingRecord {
+		return []uschess.RatingRecord{{RatingType: uschess.RatingTypeR,
+			PreRating: r}}
+	}
+	standings := uschess.StandingsOneSection{
+		{
+			Ordinal:  1,
+			MemberId: "1",
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeWin, OpponentOrdinal: 2},
+				{Outcome: uschess.PlayerOutcomeDraw, OpponentOrdinal: 3},
+				{Outcome: uschess.PlayerOutcomeLoss, OpponentOrdinal: 4},
+				{Outcome: uschess.PlayerOutcomeWinForfeit, OpponentOrdinal: 5},
+				{Outcome: uschess.PlayerOutcomeByeHalf},
+			},
+		},
+		{Ordinal: 2, MemberId: "2", Ratings: rated(1500)},
+		{Ordinal: 3, MemberId: "3", Ratings: rated(1700)},
+		{Ordinal: 4, MemberId: "4"},
+		{Ordinal: 5, MemberId: "5", Ratings: rated(2400)},
+	}
+
+	got := buildAvgOppOutput(standings, "1")
+	want := " (Avg Opp: 1600, 2 of 3 opponents rated)"
+	if got != want {
+		t.Fatalf("buildAvgOppOutput() = %q; want %q", got, want)
+	}
+
+	standings[3].Ratings = rated(1900)
+	got = buildAvgOppOutput(standings, "1")
+	want = " (Avg Opp: 1700)"
+	if got != want {
+		t.Fatalf("buildAvgOppOutput() = %q; want %q", got, want)
+	}
+
+	if got := buildAvgOppOutput(standings, "2"); got != "" {
+		t.Fatalf("buildAvgOppOutput() without games = %q; want empty", got)
+	}
+}
+
+func TestBuildCrossTableOutputAccentedNames(t *testing.T) {
+	section := uschess.MinimalSection{Name: "Open"}
+	standings := uschess.StandingsOneSection{
+		{
+			Ordinal:       1,
+			PairingNumber: 1,
+			FirstName:     "José",
+			LastName:      "Peña",
+			MemberId:      "1",
+			Score:         1,
+			Ratings: []uschess.RatingRecord{{
+				RatingType: uschess.RatingTypeR, PreRating: 1500, PostRating: 1510,
+			}},
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeWin, OpponentOrdinal: 2, Color: "White"},
+			},
+		},
+		{
+			Ordinal:       2,
+			PairingNumber: 2,
+			FirstName:     "Al",
+			LastName:      "Bee",
+			MemberId:      "2",
+			Score:         0,
+			Ratings: []uschess.RatingRecord{{
+				RatingType: uschess.RatingTypeR, PreRating: 1400, PostRating: 1390,
+			}},
+			RoundOutcomes: []uschess.StandingsRound{
+				{Outcome: uschess.PlayerOutcomeLoss, OpponentOrdinal: 1, Color: "Black"},
+			},
+		},
+	}
+
+	output, _ := BuildCrossTableOutput(section, standings,
+		CrossTableOptions{})
+	// the name column is sized by characters rather than bytes
+	for _, want := range []string{"Name       Rating", "José Peña  1500"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+var (
+	// "Section 1 - Open"
+	xtblSectionRe = regexp.MustCompile(`^\s*Section\s+(\d+)\s*-\s*(.+?)\s*$`)
+	// "    1 | JOHN SMITH                      |3.5  |W   4|D   2|H    |"
+	xtblPlayerRe = regexp.MustCompile(`^\s*(\d+)\s*\|\s*(.*?)\s*\|\s*(\d+(?:\.\d+)?)\s*\|(.*)$`)
+	// "   MA | 12345678 / R: 1800   ->1812     |     |W    |B    |     |"
+	xtblRatingRe = regexp.MustCompile(`^\s*([A-Z]*)\s*\|\s*(\d+)\s*/\s*([A-Z]+):\s*(\S*)\s*->\s*(\S*)\s*\|[^|]*\|(.*)$`)
+	// "      |            Q: 1750   ->1760     |"
+	xtblExtraRatingRe = regexp.MustCompile(`^\s*\|\s*([A-Z]+):\s*(\S*)\s*->\s*(\S*)\s*\|`)
+	// "1800", "1450P12", or "Unrated"
+	xtblRatingValueRe = regexp.MustCompile(`^(\d+)(?:P(\d+))?`)
+)
+
+// GetCrossTables retrieves the crosstables of a rated event. The US Chess
+// ratings API is preferred; when it fails the classic MSA crosstable page
+// is scraped instead. Concurrent requests for the same event share a single
+// fetch.
+func (c *Client) GetCrossTables(ctx context.Context,
+	eventID uschess.EventID) (*uschess.Tournament, error) {
+
+	t, err := shareFlight(ctx, &c.crossTablesFlight, string(eventID),
+		func(ctx context.Context) (any, error) {
+			return c.getCrossTables(ctx, eventID)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return t.(*uschess.Tournament), nil
+}
+
+func (c *Client) getCrossTables(ctx context.Context,
+	eventID uschess.EventID) (*uschess.Tournament, error) {
+
+	t, err := c.GetTournament(ctx, eventID)
+	if err == nil {
+		return t, nil
+	}
+	tViaWeb, webErr := getCrossTablesViaWeb(ctx, eventID)
+	if webErr != nil {
+		// prefer the api error
+		return nil, err
+	}
+	log.Printf("uscfutils: crosstables: using website after api failure: %v",
+		err)
+
+	return tViaWeb, nil
+}
+
+// getCrossTablesViaWeb fetches and parses the MSA crosstable page of an event.
+func getCrossTablesViaWeb(ctx context.Context,
+	eventID uschess.EventID) (*uschess.Tournament, error) {
+
+	req, err := http.NewRequestWithContext(ctx, "GET", CrossTableURL(eventID),
+		nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", internal.UserAgent)
+	resp, err := internal.ScrapeClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %w fetching %s",
+			internal.HTTPStatusError(resp.StatusCode), CrossTableURL(eventID))
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCrossTablesPage(doc, eventID)
+}
+
+// parseCrossTablesPage parses the text crosstables of an MSA crosstable page
+// into the same form the ratings API returns. Each section's table follows a
+// "Section N - Name" heading and lists each player on two or more lines: the
+// pair number, name, total, and results, then the state, USCF id, ratings,
+// and colors. Further lines may hold additional (e.g. Quick) ratings.
+func parseCrossTablesPage(doc *goquery.Document,
+	eventID uschess.EventID) (*uschess.Tournament, error) {
+
+	t := &uschess.Tournament{}
+	t.Id = eventID
+	title := strings.TrimSpace(doc.Find("title").Text())
+	if idx := strings.Index(title, "("+string(eventID)+")"); idx > 0 {
+		t.Name = strings.TrimSpace(title[:idx])
+	}
+
+	var entry *uschess.Standings
+	for _, line := range strings.Split(doc.Find("body").Text(), "\n") {
+		if m := xtblSectionRe.FindStringSubmatch(line); m != nil {
+			number, _ := strconv.Atoi(m[1])
+			t.Sections = append(t.Sections, uschess.MinimalSection{
+				Name:   m[2],
+				Number: int32(number),
+			})
+			t.SectionStandings = append(t.SectionStandings,
+				uschess.StandingsOneSection{})
+			entry = nil
+			continue
+		}
+		if len(t.Sections) == 0 {
+			continue
+		}
+		section := &t.SectionStandings[len(t.SectionStandings)-1]
+
+		if m := xtblPlayerRe.FindStringSubmatch(line); m != nil {
+			*section = append(*section, parseCrossTablePlayer(m))
+			entry = &(*section)[len(*section)-1]
+			continue
+		}
+		if entry == nil {
+			continue
+		}
+		if m := xtblRatingRe.FindStringSubmatch(line); m != nil {
+			entry.StateRep = m[1]
+			entry.MemberId = uschess.MemberID(m[2])
+			entry.Ratings = append(entry.Ratings,
+				parseCrossTableRating(m[3], m[4], m[5]))
+			for round, cell := range strings.Split(m[6], "|") {
+				if round >= len(entry.RoundOutcomes) {
+					break
+				}
+				switch strings.TrimSpace(cell) {
+				case "W":
+					entry.RoundOutcomes[round].Color = uschess.ChessColorWhite
+				case "B":
+					entry.RoundOutcomes[round].Color = uschess.ChessColorBlack
+				}
+			}
+		} else if m := xtblExtraRatingRe.FindStringSubmatch(line); m != nil {
+			entry.Ratings = append(entry.Ratings,
+				parseCrossTableRating(m[1], m[2], m[3]))
+		} else {
+			entry = nil
+		}
+	}
+	if len(t.Sections) == 0 {
+		return nil, fmt.Errorf("no crosstables found for event %s", eventID)
+	}
+
+	return t, nil
+}
+
+// parseCrossTablePlayer builds a player's standings from the first line of
+// their crosstable entry, as matched by xtblPlayerRe.
+func parseCrossTablePlayer(m []string) uschess.Standings {
+	pairNumber, _ := strconv.Atoi(m[1])
+	score, _ := strconv.ParseFloat(m[3], 32)
+	entry := uschess.Standings{
+		Ordinal:       int32(pairNumber),
+		PairingNumber: int32(pairNumber),
+		Score:         float32(score),
+	}
+	name := strings.Fields(m[2])
+	if len(name) > 0 {
+		entry.FirstName = strings.Join(name[:len(name)-1], " ")
+		entry.LastName = name[len(name)-1]
+	}
+
+	cells := strings.Split(m[4], "|")
+	// the line ends with a separator
+	if last := len(cells) - 1; last >= 0 && strings.TrimSpace(cells[last]) == "" {
+		cells = cells[:last]
+	}
+	for _, cell := range cells {
+		entry.RoundOutcomes = append(entry.RoundOutcomes,
+			parseCrossTableResult(cell))
+	}
+
+	return entry
+}
+
+// parseCrossTableResult parses one round's result cell, e.g. "W   4" for a
+// win against pair number 4, "H" for a half point bye, or "X" for a win by
+// forfeit.
+func parseCrossTableResult(cell string) uschess.StandingsRound {
+	var round uschess.StandingsRound
+	fields := strings.Fields(cell)
+	if len(fields) == 0 {
+		round.Outcome = uschess.PlayerOutcomeUnpaired
+		return round
+	}
+	if len(fields) > 1 {
+		opponent, _ := strconv.Atoi(fields[1])
+		round.OpponentOrdinal = int32(opponent)
+	}
+
+	switch fields[0] {
+	case "W":
+		round.Outcome = uschess.PlayerOutcomeWin
+	case "L":
+		round.Outcome = uschess.PlayerOutcomeLoss
+	case "D":
+		round.Outcome = uschess.PlayerOutcomeDraw
+	case "X":
+		round.Outcome = uschess.PlayerOutcomeWinForfeit
+	case "F":
+		round.Outcome = uschess.PlayerOutcomeForfeit
+	case "B":
+		round.Outcome = uschess.PlayerOutcomeByeFull
+	case "H":
+		round.Outcome = uschess.PlayerOutcomeByeHalf
+	default:
+		round.Outcome = uschess.PlayerOutcomeUnpaired
+	}
+
+	return round
+}
+
+// parseCrossTableRating parses a crosstable rating such as "R: 1450P12
+// ->1472P16". Unrated players' pre-event ratings are 0.
+func parseCrossTableRating(ratingType string, pre string,
+	post string) uschess.RatingRecord {
+
+	record := uschess.RatingRecord{RatingType: uschess.RatingType(ratingType)}
+	record.PreRating, _ = parseCrossTableRatingValue(pre)
+	record.PostRating, record.PostProvisionalGameCount =
+		parseCrossTableRatingValue(post)
+
+	return record
+}
+
+func parseCrossTableRatingValue(s string) (int32, int32) {
+	m := xtblRatingValueRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0
+	}
+	rating, _ := strconv.Atoi(m[1])
+	provisional, _ := strconv.Atoi(m[2])
+
+	return int32(rating), int32(provisional)
+}
@@ -0,0 +1,107 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// modeled on https://www.uschess.org/msa/XtblMain.php?<eventid>.0
+const xtblFixture = `<html><head>
+<title>BCC Tuesday Night Swiss (202601131234)</title></head>
+<body>
+<table><tr><td><b>Section 1 - Open</b></td></tr></table>
+<pre>
+-----------------------------------------------------------------------------
+ Pair | Player Name                     |Total|Round|Round|Round|
+ Num  | USCF ID / Rtg (Pre->Post)       | Pts |  1  |  2  |  3  |
+-----------------------------------------------------------------------------
+    1 | <a href=XtblPlr.php?202601131234-001-12345678>ALICE SMITH</a>                     |2.5  |W   3|D   2|H    |
+   MA | 12345678 / R: 1800   ->1812     |     |W    |B    |     |
+      |            Q: 1750   ->1760     |     |     |     |     |
+-----------------------------------------------------------------------------
+    2 | <a href=XtblPlr.php?202601131234-001-23456789>BOB VAN JONES</a>                   |1.5  |X    |D   1|L   3|
+   NH | 23456789 / R: 1600P12->1620P15  |     |     |W    |B    |
+-----------------------------------------------------------------------------
+    3 | <a href=XtblPlr.php?202601131234-001-34567890>CAROL WHITE</a>                     |1.0  |L   1|U    |W   2|
+   MA | 34567890 / R: Unrated->1400P3   |     |B    |     |W    |
+-----------------------------------------------------------------------------
+</pre>
+<table><tr><td><b>Section 2 - U1600</b></td></tr></table>
+<pre>
+-----------------------------------------------------------------------------
+ Pair | Player Name                     |Total|Round|Round|Round|
+ Num  | USCF ID / Rtg (Pre->Post)       | Pts |  1  |  2  |  3  |
+-----------------------------------------------------------------------------
+    1 | <a href=XtblPlr.php?202601131234-002-45678901>DAN BROWN</a>                       |3.0  |B    |B    |B    |
+   RI | 45678901 / R: 1500   ->1500     |     |     |     |     |
+-----------------------------------------------------------------------------
+</pre>
+</body></html>`
+
+func TestParseCrossTablesPage(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(xtblFixture))
+	if err != nil {
+		t.Fatalf("NewDocumentFromReader() err = %v", err)
+	}
+	tourney, err := parseCrossTablesPage(doc, "202601131234")
+	if err != nil {
+		t.Fatalf("parseCrossTablesPage() err = %v", err)
+	}
+
+	if tourney.Name != "BCC Tuesday Night Swiss" || len(tourney.Sections) != 2 ||
+		tourney.Sections[1].Name != "U1600" || tourney.Sections[1].Number != 2 {
+		t.Fatalf("unexpected tournament: %+v", tourney.RatedEventDetail)
+	}
+	open := tourney.SectionStandings[0]
+	if len(open) != 3 || len(tourney.SectionStandings[1]) != 1 {
+		t.Fatalf("unexpected standings: %+v", tourney.SectionStandings)
+	}
+
+	alice := open[0]
+	if alice.FirstName != "ALICE" || alice.LastName != "SMITH" ||
+		alice.MemberId != "12345678" || alice.StateRep != "MA" ||
+		alice.Score != 2.5 || len(alice.Ratings) != 2 ||
+		alice.Ratings[1].RatingType != uschess.RatingTypeQ ||
+		alice.Ratings[0].PostRating != 1812 {
+		t.Errorf("unexpected player: %+v", alice)
+	}
+	if r := alice.RoundOutcomes; len(r) != 3 ||
+		r[0].Outcome != uschess.PlayerOutcomeWin || r[0].OpponentOrdinal != 3 ||
+		r[0].Color != uschess.ChessColorWhite ||
+		r[1].Color != uschess.ChessColorBlack ||
+		r[2].Outcome != uschess.PlayerOutcomeByeHalf {
+		t.Errorf("unexpected rounds: %+v", r)
+	}
+	bob := open[1]
+	if bob.FirstName != "BOB VAN" || bob.RoundOutcomes[0].Outcome !=
+		uschess.PlayerOutcomeWinForfeit || bob.Ratings[0].PreRating != 1600 ||
+		bob.Ratings[0].PostProvisionalGameCount != 15 {
+		t.Errorf("unexpected player: %+v", bob)
+	}
+	if carol := open[2]; carol.Ratings[0].PreRating != 0 ||
+		carol.RoundOutcomes[1].Outcome != uschess.PlayerOutcomeUnpaired {
+		t.Errorf("unexpected player: %+v", carol)
+	}
+
+	// the parsed crosstables render like those from the API
+	output := BuildAllCrossTablesOutput(tourney, CrossTableOptions{})
+	for _, want := range []string{"Open Section (Dual R/Q)", "Alice Smith",
+		"1800->1812", "W3(w)", "BYE(½)", "W*", "U1600 Section"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	doc, _ = goquery.NewDocumentFromReader(strings.NewReader(
+		"<html><body>No crosstables</body></html>"))
+	if _, err := parseCrossTablesPage(doc, "1"); err == nil {
+		t.Errorf("parseCrossTablesPage() of an empty page succeeded")
+	}
+}
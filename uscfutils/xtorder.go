@@ -0,0 +1,147 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"sort"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// CrossTableOrder selects how the entries of a crosstable are ordered.
+type CrossTableOrder int
+
+const (
+	// OrderByPairNumber lists entries in the order US Chess vends them, by
+	// pair number (ordinal).
+	OrderByPairNumber CrossTableOrder = iota
+	// OrderByStandings lists entries by total points and then by tiebreaks,
+	// with a displayed place, so that the crosstable reads as a standings
+	// table.
+	OrderByStandings
+)
+
+// Tiebreaks holds a player's US Chess tiebreak values in the order they are
+// applied: modified median, Solkoff, and cumulative.
+type Tiebreaks struct {
+	Median     float64
+	Solkoff    float64
+	Cumulative float64
+}
+
+// outcomePoints returns the points a player earned for a round outcome.
+func outcomePoints(outcome uschess.PlayerOutcome) float64 {
+	switch outcome {
+	case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym,
+		uschess.PlayerOutcomeWinForfeit, uschess.PlayerOutcomeByeFull:
+		return 1
+	case uschess.PlayerOutcomeDraw, uschess.PlayerOutcomeDrawAsym,
+		uschess.PlayerOutcomeDrawForfeit, uschess.PlayerOutcomeByeHalf:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// computeTiebreaks calculates each entry's tiebreaks keyed by ordinal.
+// Unplayed rounds (byes and forfeits) are counted as games against an
+// opponent with no points.
+func computeTiebreaks(standings uschess.StandingsOneSection) map[int32]Tiebreaks {
+	scores := make(map[int32]float64)
+	numRounds := 0
+	for _, entry := range standings {
+		scores[entry.Ordinal] = float64(entry.Score)
+		numRounds = max(numRounds, len(entry.RoundOutcomes))
+	}
+
+	result := make(map[int32]Tiebreaks)
+	for _, entry := range standings {
+		var tb Tiebreaks
+		oppScores := make([]float64, 0, numRounds)
+		running := 0.0
+		for _, outcome := range entry.RoundOutcomes {
+			running += outcomePoints(outcome.Outcome)
+			tb.Cumulative += running
+
+			_, forfeit := formatOutcome(outcome)
+			if outcome.OpponentOrdinal > 0 && !forfeit {
+				oppScores = append(oppScores, scores[outcome.OpponentOrdinal])
+			} else {
+				oppScores = append(oppScores, 0)
+			}
+		}
+		for len(oppScores) < numRounds {
+			oppScores = append(oppScores, 0)
+		}
+
+		sort.Float64s(oppScores)
+		for _, s := range oppScores {
+			tb.Solkoff += s
+		}
+		tb.Median = tb.Solkoff
+		if len(oppScores) > 0 {
+			half := float64(numRounds) / 2
+			score := float64(entry.Score)
+			if score >= half {
+				tb.Median -= oppScores[0]
+			}
+			if score <= half {
+				tb.Median -= oppScores[len(oppScores)-1]
+			}
+		}
+		result[entry.Ordinal] = tb
+	}
+
+	return result
+}
+
+// TiebreaksByMember returns the tiebreaks of each player of an event who has
+// a member id, keyed by member id. Each player's tiebreaks are computed
+// within their section.
+func TiebreaksByMember(t *uschess.Tournament) map[uschess.MemberID]Tiebreaks {
+	result := make(map[uschess.MemberID]Tiebreaks)
+	for _, standings := range t.SectionStandings {
+		tbs := computeTiebreaks(standings)
+		for _, entry := range standings {
+			if entry.MemberId != "" {
+				result[entry.MemberId] = tbs[entry.Ordinal]
+			}
+		}
+	}
+
+	return result
+}
+
+// sortStandings returns a copy of standings ordered per order.
+func sortStandings(standings uschess.StandingsOneSection,
+	order CrossTableOrder) uschess.StandingsOneSection {
+
+	sorted := make(uschess.StandingsOneSection, len(standings))
+	copy(sorted, standings)
+	if order != OrderByStandings {
+		return sorted
+	}
+
+	tbs := computeTiebreaks(standings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		tbA, tbB := tbs[a.Ordinal], tbs[b.Ordinal]
+		if tbA.Median != tbB.Median {
+			return tbA.Median > tbB.Median
+		}
+		if tbA.Solkoff != tbB.Solkoff {
+			return tbA.Solkoff > tbB.Solkoff
+		}
+		if tbA.Cumulative != tbB.Cumulative {
+			return tbA.Cumulative > tbB.Cumulative
+		}
+		return a.Ordinal < b.Ordinal
+	})
+
+	return sorted
+}
@@ -0,0 +1,102 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestSortStandingsBreaksTies(t *testing.T) {
+	round := func(outcome uschess.PlayerOutcome,
+		opp int32) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome, OpponentOrdinal: opp}
+	}
+	// 2 and 3 tie on points and modified median; 3 faced stronger
+	// opposition and so wins on Solkoff
+	standings := uschess.StandingsOneSection{
+		{Ordinal: 1, FirstName: "Ann", LastName: "Able", MemberId: "1",
+			Score: 1, RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeLoss, 3),
+				round(uschess.PlayerOutcomeWin, 4)}},
+		{Ordinal: 2, FirstName: "Bob", LastName: "Baker", MemberId: "2",
+			Score: 1.5, RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeWin, 4),
+				round(uschess.PlayerOutcomeDraw, 3)}},
+		{Ordinal: 3, FirstName: "Cal", LastName: "Cole", MemberId: "3",
+			Score: 1.5, RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeWin, 1),
+				round(uschess.PlayerOutcomeDraw, 2)}},
+		{Ordinal: 4, FirstName: "Dee", LastName: "Dunn", MemberId: "4",
+			Score: 0, RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeLoss, 2),
+				round(uschess.PlayerOutcomeLoss, 1)}},
+	}
+
+	ordinals := func(s uschess.StandingsOneSection) []int32 {
+		result := make([]int32, 0, len(s))
+		for _, entry := range s {
+			result = append(result, entry.Ordinal)
+		}
+		return result
+	}
+	if got := ordinals(sortStandings(standings, OrderByPairNumber)); !reflect.DeepEqual(got, []int32{1, 2, 3, 4}) {
+		t.Fatalf("pair number order = %v; want [1 2 3 4]", got)
+	}
+	if got := ordinals(sortStandings(standings, OrderByStandings)); !reflect.DeepEqual(got, []int32{3, 2, 1, 4}) {
+		t.Fatalf("standings order = %v; want [3 2 1 4]", got)
+	}
+	if got := ordinals(standings); !reflect.DeepEqual(got, []int32{1, 2, 3, 4}) {
+		t.Fatalf("sortStandings modified its input: %v", got)
+	}
+	tbs := TiebreaksByMember(&uschess.Tournament{
+		SectionStandings: []uschess.StandingsOneSection{standings}})
+	if want := (Tiebreaks{Median: 1.5, Solkoff: 2.5, Cumulative: 2.5}); tbs["3"] != want {
+		t.Errorf("TiebreaksByMember()[3] = %+v; want %+v", tbs["3"], want)
+	}
+
+	output, _ := BuildCrossTableOutput(uschess.MinimalSection{Name: "Open"},
+		standings, CrossTableOptions{Order: OrderByStandings})
+	lines := strings.Split(output, "\n")
+	if !strings.HasPrefix(lines[0], "Pl  No") {
+		t.Fatalf("header = %q; want place column first", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "1   3.  Cal Cole") {
+		t.Fatalf("first row = %q; want Cal Cole in 1st place", lines[1])
+	}
+}
+
+func TestBuildCrossTableOutputSharedPlaces(t *testing.T) {
+	entry := func(ordinal int32, name string,
+		score float32) uschess.Standings {
+
+		return uschess.Standings{Ordinal: ordinal, FirstName: name,
+			LastName: "Player", MemberId: uschess.MemberID(name),
+			Score: score}
+	}
+	// 2, 3, and 4 tie on points and share 2nd place; 5 is then 5th
+	standings := uschess.StandingsOneSection{
+		entry(1, "Ann", 3),
+		entry(2, "Bob", 2),
+		entry(3, "Cal", 2),
+		entry(4, "Dee", 2),
+		entry(5, "Eve", 1),
+	}
+
+	output, _ := BuildCrossTableOutput(uschess.MinimalSection{Name: "Open"},
+		standings, CrossTableOptions{Order: OrderByStandings})
+	lines := strings.Split(output, "\n")
+	want := []string{"1 ", "2 ", "2 ", "2 ", "5 "}
+	for idx, place := range want {
+		if !strings.HasPrefix(lines[idx+1], place) {
+			t.Errorf("row %v = %q; want place %v", idx+1, lines[idx+1],
+				strings.TrimSpace(place))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"strings"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// CrossTableStyle selects how each round's result is rendered in a
+// crosstable.
+type CrossTableStyle int
+
+const (
+	// StyleCompact renders results as e.g. "W8(w)", "L*" for a forfeit loss,
+	// or "BYE(½)".
+	StyleCompact CrossTableStyle = iota
+	// StyleClassic renders results as on a US Chess crosstable, e.g. "W 8",
+	// "X---" for a win by forfeit, or "H---" for a half point bye.
+	StyleClassic
+)
+
+// ParseCrossTableStyle returns the style named by s ("compact" or
+// "classic").
+func ParseCrossTableStyle(s string) (CrossTableStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "compact":
+		return StyleCompact, nil
+	case "classic":
+		return StyleClassic, nil
+	default:
+		return StyleCompact, fmt.Errorf("unknown crosstable style %q", s)
+	}
+}
+
+// formatResult renders a round's result in the given style. The returned
+// bool reports whether the result is marked as decided by forfeit, which
+// only the compact style does.
+func formatResult(outcome uschess.StandingsRound,
+	style CrossTableStyle) (string, bool) {
+
+	if style == StyleClassic {
+		return formatClassicOutcome(outcome), false
+	}
+
+	return formatOutcome(outcome)
+}
+
+// formatClassicOutcome renders a round's result using US Chess crosstable
+// codes: W, L, or D followed by the opponent's pair number for games played;
+// X and F for wins and losses by forfeit; and B, H, and U for full point,
+// half point, and zero point byes.
+func formatClassicOutcome(outcome uschess.StandingsRound) string {
+	opponent := "---"
+	if outcome.OpponentOrdinal > 0 {
+		opponent = fmt.Sprintf(" %d", outcome.OpponentOrdinal)
+	}
+
+	switch outcome.Outcome {
+	case uschess.PlayerOutcomeWin, uschess.PlayerOutcomeWinAsym:
+		return "W" + opponent
+	case uschess.PlayerOutcomeLoss, uschess.PlayerOutcomeLossAsym:
+		return "L" + opponent
+	case uschess.PlayerOutcomeDraw, uschess.PlayerOutcomeDrawAsym:
+		return "D" + opponent
+	case uschess.PlayerOutcomeWinForfeit:
+		return "X" + opponent
+	case uschess.PlayerOutcomeForfeit:
+		return "F" + opponent
+	case uschess.PlayerOutcomeByeFull:
+		return "B---"
+	case uschess.PlayerOutcomeByeHalf:
+		return "H---"
+	case uschess.PlayerOutcomeUnpaired:
+		return "U---"
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,76 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"strings"
+	"testing"
+
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestBuildCrossTableOutputStyles(t *testing.T) {
+	round := func(outcome uschess.PlayerOutcome, opp int32,
+		color uschess.ChessColor) uschess.StandingsRound {
+
+		return uschess.StandingsRound{Outcome: outcome, OpponentOrdinal: opp,
+			Color: color}
+	}
+	standings := uschess.StandingsOneSection{
+		{Ordinal: 1, FirstName: "Ann", LastName: "Able", Score: 2.5,
+			RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeWin, 2, uschess.ChessColorWhite),
+				round(uschess.PlayerOutcomeByeHalf, 0, ""),
+				round(uschess.PlayerOutcomeWinForfeit, 3, "")}},
+		{Ordinal: 2, FirstName: "Bob", LastName: "Baker", Score: 1.5,
+			RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeLoss, 1, uschess.ChessColorBlack),
+				round(uschess.PlayerOutcomeDraw, 3, uschess.ChessColorWhite),
+				round(uschess.PlayerOutcomeByeFull, 0, "")}},
+		{Ordinal: 3, FirstName: "Cal", LastName: "Cole", Score: 0.5,
+			RoundOutcomes: []uschess.StandingsRound{
+				round(uschess.PlayerOutcomeUnpaired, 0, ""),
+				round(uschess.PlayerOutcomeDraw, 2, uschess.ChessColorBlack),
+				round(uschess.PlayerOutcomeForfeit, 1, "")}},
+	}
+
+	// each row's round results
+	tests := []struct {
+		style   CrossTableStyle
+		want    [][]string
+		forfeit bool
+	}{
+		{StyleCompact, [][]string{{"W2(w)", "BYE(½)", "W*"},
+			{"L1(b)", "D3(w)", "BYE(1)"}, {"BYE(0)", "D2(b)", "L*"}}, true},
+		{StyleClassic, [][]string{{"W", "2", "H---", "X", "3"},
+			{"L", "1", "D", "3", "B---"}, {"U---", "D", "2", "F", "1"}}, false},
+	}
+	for _, tc := range tests {
+		output, _ := BuildCrossTableOutput(uschess.MinimalSection{Name: "Open"},
+			standings, CrossTableOptions{Style: tc.style})
+		lines := strings.Split(output, "\n")
+		for i, want := range tc.want {
+			// skip the header and the number, name, rating, and points
+			fields := strings.Fields(lines[i+1])[5:]
+			if strings.Join(fields, " ") != strings.Join(want, " ") {
+				t.Errorf("style %v: row %d results = %q; want %q", tc.style,
+					i+1, fields, want)
+			}
+		}
+		if got := strings.Contains(output, "decided by forfeit"); got != tc.forfeit {
+			t.Errorf("style %v: forfeit note = %v; want %v", tc.style, got,
+				tc.forfeit)
+		}
+	}
+
+	for _, name := range []string{"compact", "Classic"} {
+		if _, err := ParseCrossTableStyle(name); err != nil {
+			t.Errorf("ParseCrossTableStyle(%q) err = %v", name, err)
+		}
+	}
+	if _, err := ParseCrossTableStyle("fancy"); err == nil {
+		t.Errorf("ParseCrossTableStyle(\"fancy\") succeeded")
+	}
+}
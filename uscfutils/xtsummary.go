@@ -0,0 +1,79 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+// CombinedSectionOrder returns the indexes of a tournament's sections in
+// the order the club lists them (see internal.SectionLess), e.g. Open before
+// U1800, whatever order they were filed with US Chess in.
+func CombinedSectionOrder(t *uschess.Tournament) []int {
+	order := SectionOrder(t)
+	sort.SliceStable(order, func(i, j int) bool {
+		return internal.SectionLess(t.Sections[order[i]].Name,
+			t.Sections[order[j]].Name)
+	})
+
+	return order
+}
+
+// BuildCrossTablesSummary summarizes a whole event ahead of its sections'
+// crosstables: how many players played in how many sections, and who had the
+// top score across all sections, e.g.
+//
+//	Event: 42 players in 3 sections
+//	Top score (4): Alice Smith (Open)
+//
+// Every player tied for the top score is listed.
+func BuildCrossTablesSummary(t *uschess.Tournament) string {
+	order := CombinedSectionOrder(t)
+	players := 0
+	var topScore float32
+	var leaders []string
+	for _, i := range order {
+		for _, entry := range t.SectionStandings[i] {
+			players++
+			leader := fmt.Sprintf("%v (%v)",
+				internal.NormalizeName(entry.FirstName+" "+entry.LastName),
+				t.Sections[i].Name)
+			switch {
+			case len(leaders) == 0 || entry.Score > topScore:
+				topScore = entry.Score
+				leaders = []string{leader}
+			case entry.Score == topScore:
+				leaders = append(leaders, leader)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Event: %v players in %v sections\n", players,
+		len(order)))
+	if len(leaders) > 0 {
+		sb.WriteString(fmt.Sprintf("Top score (%v): %v\n",
+			internal.ScoreToString(float64(topScore)),
+			strings.Join(leaders, ", ")))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// BuildCombinedCrossTablesOutput is like BuildCrossTablesOutput but opens
+// with BuildCrossTablesSummary of the whole event and lists the sections in
+// CombinedSectionOrder.
+func BuildCombinedCrossTablesOutput(t *uschess.Tournament, section string,
+	opts CrossTableOptions) string {
+
+	return BuildCrossTablesSummary(t) + buildCrossTablesOutput(t,
+		CombinedSectionOrder(t), section, opts)
+}
@@ -0,0 +1,66 @@
+/* Copyright © 2026 Mike Brown. All Rights Reserved.
+ *
+ * See LICENSE file at the root of this repository for license terms
+ */
+package uscfutils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mikeb26/boylstonchessclub-tdbot/internal"
+	uschess "github.com/mikeb26/uschess-go"
+)
+
+func TestBuildCombinedCrossTablesOutput(t *testing.T) {
+	standing := func(ordinal int32, first string,
+		score float32) uschess.Standings {
+
+		return uschess.Standings{Ordinal: ordinal, FirstName: first,
+			LastName: "PLAYER", Score: score}
+	}
+	// filed with US Chess with the lowest section first
+	tourney := &uschess.Tournament{
+		RatedEventDetail: uschess.RatedEventDetail{
+			Sections: []uschess.MinimalSection{
+				{Name: "U1200", Number: 1}, {Name: "Open", Number: 2},
+				{Name: "U1800", Number: 3},
+			},
+		},
+		SectionStandings: []uschess.StandingsOneSection{
+			{standing(1, "Dan", 3), standing(2, "Eve", 1)},
+			{standing(1, "Alice", 2.5), standing(2, "Bob", 2)},
+			{standing(1, "Carol", 3)},
+		},
+	}
+
+	if got, want := CombinedSectionOrder(tourney), []int{1, 2, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CombinedSectionOrder() = %v; want %v", got, want)
+	}
+	internal.SetenvForTest(t, internal.ScoreFormatEnv, "fraction")
+	summary := BuildCrossTablesSummary(tourney)
+	if want := "Event: 5 players in 3 sections\nTop score (3): Carol Player (U1800), Dan Player (U1200)\n\n"; summary != want {
+		t.Errorf("BuildCrossTablesSummary() = %q; want %q", summary, want)
+	}
+
+	output := BuildCombinedCrossTablesOutput(tourney, "",
+		CrossTableOptions{})
+	if !strings.HasPrefix(output, summary) {
+		t.Errorf("output does not open with the summary:\n%s", output)
+	}
+	open := strings.Index(output, "Open Section")
+	u1800 := strings.Index(output, "U1800 Section")
+	u1200 := strings.Index(output, "U1200 Section")
+	if open < 0 || !(open < u1800 && u1800 < u1200) {
+		t.Errorf("sections not in Open, U1800, U1200 order:\n%s", output)
+	}
+
+	// the summary covers the whole event even when one section is shown
+	output = BuildCombinedCrossTablesOutput(tourney, "U1800",
+		CrossTableOptions{})
+	if !strings.HasPrefix(output, summary) ||
+		strings.Contains(output, "Open Section") {
+		t.Errorf("filtered output:\n%s", output)
+	}
+}